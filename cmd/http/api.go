@@ -1,60 +1,220 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 	"time"
 
 	"github.com/SyamSolution/ticket-management-service/config"
 	"github.com/SyamSolution/ticket-management-service/config/middleware"
+	"github.com/SyamSolution/ticket-management-service/helper"
 	"github.com/SyamSolution/ticket-management-service/internal/consumer"
+	"github.com/SyamSolution/ticket-management-service/internal/currency"
 	"github.com/SyamSolution/ticket-management-service/internal/handler"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/publisher"
 	"github.com/SyamSolution/ticket-management-service/internal/repository"
+	"github.com/SyamSolution/ticket-management-service/internal/ticketcode"
 	"github.com/SyamSolution/ticket-management-service/internal/usecase"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/pprof"
 	"github.com/gofiber/fiber/v2/middleware/recover"
-	"github.com/joho/godotenv"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
 )
 
 func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	baseDep := config.NewBaseDep()
-	loadEnv(baseDep.Logger)
-	DB, err := config.NewDbPool(baseDep.Logger)
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		dbCfg, err := config.LoadDatabase()
+		if err != nil {
+			baseDep.Logger.Error("failed to load database configuration", zap.Error(err))
+			os.Exit(1)
+		}
+		if err := runMigrate(ctx, *dbCfg, baseDep.Logger, os.Args[2:]); err != nil {
+			baseDep.Logger.Error("migration failed", zap.Error(err))
+			os.Exit(1)
+		}
+		return
+	}
+
+	cfg, err := config.Load()
 	if err != nil {
+		baseDep.Logger.Error("failed to load configuration", zap.Error(err))
 		os.Exit(1)
 	}
+	baseDep.Logger.Info("configuration loaded", zap.Any("config", cfg))
+
+	if cfg.Database.MigrateOnStart {
+		if err := runMigrate(ctx, cfg.Database, baseDep.Logger, []string{"up"}); err != nil {
+			baseDep.Logger.Error("migration failed", zap.Error(err))
+			os.Exit(1)
+		}
+	}
+
+	tracerProvider, err := config.NewTracerProvider(ctx, cfg.Tracing, baseDep.Logger)
+	if err != nil {
+		os.Exit(1)
+	}
+
+	DB, err := config.NewDbPool(cfg.Database, baseDep.Logger)
+	if err != nil {
+		os.Exit(1)
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "reconcile" {
+		reconciliationUsecase := usecase.NewInventoryReconciliationUsecase(
+			repository.NewInventoryLedgerRepository(DB, baseDep.Logger, cfg.Database.QueryTimeout), baseDep.Logger)
+		if err := runReconcile(config.WithActor(ctx, "cli:reconcile"), reconciliationUsecase, os.Args[2:]); err != nil {
+			baseDep.Logger.Error("reconciliation failed", zap.Error(err))
+			os.Exit(1)
+		}
+		return
+	}
 
 	dbCollector := middleware.NewStatsCollector("assesment", DB)
 	prometheus.MustRegister(dbCollector)
 	fiberProm := middleware.NewWithRegistry(prometheus.DefaultRegisterer, "ticket-management-service", "", "", map[string]string{})
 
+	rates := currency.NewStaticRates("", nil)
+	if cfg.Currency.RatesFile != "" {
+		if rates, err = currency.LoadStaticRates(cfg.Currency.RatesFile); err != nil {
+			baseDep.Logger.Error("failed to load exchange rates", zap.Error(err))
+			os.Exit(1)
+		}
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.AWS.Region))
+	if err != nil {
+		baseDep.Logger.Error("failed to load aws configuration", zap.Error(err))
+		os.Exit(1)
+	}
+	messagePublisher := publisher.NewSQSPublisher(sqs.NewFromConfig(awsCfg), map[string]string{
+		util.TOPIC_EVENT_CANCELLED: cfg.SQS.EventCancelledURL,
+		util.TOPIC_WAITLIST_OFFER:  cfg.SQS.WaitlistOfferURL,
+	})
+
+	signer, err := ticketcode.NewSigner(cfg.Ticket.SigningKey.Value())
+	if err != nil {
+		baseDep.Logger.Error("failed to load ticket signing key", zap.Error(err))
+		os.Exit(1)
+	}
+
 	//=== repository lists start ===//
-	ticketRepo := repository.NewTicketRepository(DB, baseDep.Logger)
+	ticketRepo := repository.NewTicketRepository(DB, baseDep.Logger, cfg.Database.QueryTimeout)
+	reservationRepo := repository.NewReservationRepository(DB, baseDep.Logger, cfg.Database.QueryTimeout)
+	geographyRepo := repository.NewGeographyRepository(DB, baseDep.Logger, cfg.Database.QueryTimeout)
+	seatRepo := repository.NewSeatRepository(DB, baseDep.Logger, cfg.Database.QueryTimeout)
+	promoRepo := repository.NewPromoRepository(DB, baseDep.Logger, cfg.Database.QueryTimeout)
+	eventRepo := repository.NewEventRepository(DB, baseDep.Logger, cfg.Database.QueryTimeout)
+	issuedTicketRepo := repository.NewIssuedTicketRepository(DB, baseDep.Logger, cfg.Database.QueryTimeout)
+	transferRepo := repository.NewTransferRepository(DB, baseDep.Logger, cfg.Database.QueryTimeout)
+	resaleRepo := repository.NewResaleRepository(DB, baseDep.Logger, cfg.Database.QueryTimeout)
+	waitlistRepo := repository.NewWaitlistRepository(DB, baseDep.Logger, cfg.Database.QueryTimeout)
+	ledgerRepo := repository.NewInventoryLedgerRepository(DB, baseDep.Logger, cfg.Database.QueryTimeout)
 	//=== repository lists end ===//
 
+	prometheus.MustRegister(middleware.NewInventoryCollector(ticketRepo, baseDep.Logger))
+	usecase.RegisterMetrics(prometheus.DefaultRegisterer)
+	consumer.RegisterMetrics(prometheus.DefaultRegisterer)
+
 	//=== usecase lists start ===//
-	ticketUsecase := usecase.NewTicketUsecase(ticketRepo, baseDep.Logger)
+	waitlistOfferer := usecase.NewWaitlistOfferer(waitlistRepo, messagePublisher, baseDep.Logger, cfg.Ticket.WaitlistOfferTTL)
+	ticketUsecase := usecase.NewTicketUsecase(ticketRepo, reservationRepo, seatRepo, promoRepo, issuedTicketRepo, resaleRepo,
+		waitlistRepo, waitlistOfferer, rates, baseDep.Logger, cfg.Ticket.MaxPerUser, cfg.Ticket.ReservationHoldDuration)
+	geographyUsecase := usecase.NewGeographyUsecase(geographyRepo, baseDep.Logger)
+	seatUsecase := usecase.NewSeatUsecase(seatRepo, ticketUsecase, baseDep.Logger, cfg.Ticket.SeatHoldDuration)
+	promoUsecase := usecase.NewPromoUsecase(promoRepo, ticketRepo, rates, baseDep.Logger)
+	eventUsecase := usecase.NewEventUsecase(eventRepo, reservationRepo, issuedTicketRepo, resaleRepo, ticketUsecase, messagePublisher, baseDep.Logger)
+	issuedTicketUsecase := usecase.NewIssuedTicketUsecase(issuedTicketRepo, signer, baseDep.Logger)
+	transferUsecase := usecase.NewTransferUsecase(transferRepo, issuedTicketRepo, baseDep.Logger, cfg.Ticket.TransferTTL)
+	resaleUsecase := usecase.NewResaleUsecase(resaleRepo, issuedTicketRepo, reservationRepo, eventRepo, ticketUsecase, baseDep.Logger,
+		cfg.Ticket.ResaleFeeBasisPoints)
+	waitlistUsecase := usecase.NewWaitlistUsecase(waitlistRepo, ticketRepo, ticketUsecase, waitlistOfferer, baseDep.Logger)
+	ledgerUsecase := usecase.NewInventoryLedgerUsecase(ledgerRepo, baseDep.Logger)
+	reconciliationUsecase := usecase.NewInventoryReconciliationUsecase(ledgerRepo, baseDep.Logger)
 	//=== usecase lists end ===//
 
 	//=== handler lists start ===//
 	ticketHandler := handler.NewTicketHandler(ticketUsecase, baseDep.Logger)
+	geographyHandler := handler.NewGeographyHandler(geographyUsecase, baseDep.Logger)
+	seatHandler := handler.NewSeatHandler(seatUsecase, baseDep.Logger)
+	promoHandler := handler.NewPromoHandler(promoUsecase, baseDep.Logger)
+	eventHandler := handler.NewEventHandler(eventUsecase, baseDep.Logger)
+	issuedTicketHandler := handler.NewIssuedTicketHandler(issuedTicketUsecase, baseDep.Logger)
+	transferHandler := handler.NewTransferHandler(transferUsecase, baseDep.Logger)
+	resaleHandler := handler.NewResaleHandler(resaleUsecase, baseDep.Logger)
+	waitlistHandler := handler.NewWaitlistHandler(waitlistUsecase, baseDep.Logger)
+	ledgerHandler := handler.NewInventoryLedgerHandler(ledgerUsecase, baseDep.Logger)
+	reconciliationHandler := handler.NewInventoryReconciliationHandler(reconciliationUsecase, baseDep.Logger)
 	//=== handler lists end ===//
 
-	go consumer.StartConsumer(ticketUsecase)
+	consumerMonitor := consumer.NewMonitor(cfg.Health.ConsumerStaleAfter)
+	consumerDone := make(chan struct{})
+	go func() {
+		consumer.StartConsumer(ctx, cfg.SQS, cfg.AWS.Region, ticketUsecase, baseDep.Logger, consumerMonitor)
+		close(consumerDone)
+	}()
+
+	//=== scheduled jobs ===//
+	go runEvery(ctx, cfg.Ticket.ReservationHoldSweepInterval, "release expired reservations", baseDep.Logger, func(ctx context.Context) error {
+		_, err := ticketUsecase.ReleaseExpiredReservations(ctx)
+		return err
+	})
+	go runEvery(ctx, cfg.Ticket.SeatHoldSweepInterval, "release expired seat holds", baseDep.Logger, func(ctx context.Context) error {
+		_, err := seatUsecase.ReleaseExpiredHolds(ctx)
+		return err
+	})
+	go runEvery(ctx, cfg.Event.StatusSweepInterval, "transition event statuses", baseDep.Logger, func(ctx context.Context) error {
+		_, err := eventUsecase.TransitionEvents(ctx)
+		return err
+	})
+	go runEvery(ctx, cfg.Ticket.TransferSweepInterval, "expire ticket transfers", baseDep.Logger, func(ctx context.Context) error {
+		_, err := transferUsecase.ExpireTransfers(ctx)
+		return err
+	})
+	go runEvery(ctx, cfg.Ticket.WaitlistOfferSweepInterval, "process ticket waitlists", baseDep.Logger, waitlistUsecase.ProcessWaitlists)
+	go runEvery(ctx, cfg.Ticket.InventoryCheckInterval, "check inventory drift", baseDep.Logger, func(ctx context.Context) error {
+		_, err := reconciliationUsecase.CheckInventory(ctx)
+		return err
+	})
+
+	cacher := config.NewCacher(cfg.Cacher, baseDep.Logger)
+	healthHandler := handler.NewHealthHandler(
+		[]handler.HealthCheck{{Name: "consumer", Check: consumerMonitor.Check}},
+		[]handler.HealthCheck{{Name: "database", Check: DB.PingContext}, {Name: "cache", Check: cacher.Ping}},
+		cfg.Health.CheckTimeout,
+	)
 
-	app := fiber.New()
+	app := fiber.New(fiber.Config{
+		ErrorHandler: handler.ErrorHandler(baseDep.Logger),
+	})
 
 	app.Use(recover.New())
+	app.Use(middleware.RequestID())
+	app.Use(middleware.Tracing())
+	app.Use(middleware.RequestContext(cfg.App.RequestTimeout))
 	app.Use(cors.New())
 	app.Use(pprof.New())
 	app.Use(logger.New(logger.Config{
 		// Format: "[${time}] ${status} - ${latency} ${method} ${path}\n",
-		Format:       `${time} {"router_activity" : [${status},"${latency}","${method}","${path}"], "query_param":${queryParams}, "body_param":${body}}` + "\n",
+		Format:       `${time} {"request_id":"${locals:request_id}", "router_activity" : [${status},"${latency}","${method}","${path}"], "query_param":${queryParams}, "body_param":${body}}` + "\n",
 		TimeInterval: time.Millisecond,
 		TimeFormat:   "02-01-2006 15:04:05",
 		TimeZone:     "Indonesia/Jakarta",
@@ -64,30 +224,250 @@ func main() {
 		return c.SendString("Hello, World!")
 	})
 
+	//=== health routes ===//
+	app.Get("/healthz", healthHandler.Liveness)
+	app.Get("/readyz", healthHandler.Readiness)
+
 	//=== metrics route
 	fiberProm.RegisterAt(app, "/metrics")
 	app.Use(fiberProm.Middleware)
 
+	//=== geography routes ===//
+	app.Get("/continents", geographyHandler.GetContinents)
+	app.Get("/continents/:code/countries", geographyHandler.GetCountriesByContinent)
+	app.Get("/countries/:code/cities", geographyHandler.GetCitiesByCountry)
+	app.Get("/cities/:city_id/venues", geographyHandler.GetVenuesByCity)
+
+	//=== event routes ===//
+	app.Get("/events", eventHandler.GetEvents)
+	app.Get("/events/:event_id", eventHandler.GetEvent)
+
+	//=== seat routes ===//
+	app.Get("/events/:event_id/seats", seatHandler.GetSeatMap)
+
+	//=== resale routes ===//
+	app.Get("/events/:event_id/resale-listings", resaleHandler.GetEventListings)
+
 	//=== ticket routes ===//
 	app.Get("/continent/tickets/:continent", ticketHandler.GetTicketByContinent)
 	app.Get("/tickets/continent-stock", ticketHandler.GetStockTicketGroupByContinent)
 	app.Get("/event/ticket/:ticket_id", ticketHandler.GetTicketEventByTicketID)
-	app.Group("/", middleware.Auth())
+	app.Get("/tickets/verification-key", issuedTicketHandler.GetVerificationKey)
+	app.Group("/", middleware.Auth(helper.Cognito{Region: cfg.AWS.Region, UserPoolID: cfg.AWS.CognitoUserPoolID}))
 	app.Get("/tickets/continent/:continent", ticketHandler.GetAvailableTicketByContinent)
 	app.Get("/tickets/type/:type", ticketHandler.GetAvailableTicketByType)
+	app.Post("/tickets/reserve", ticketHandler.ReserveTicket)
+	app.Post("/events/:event_id/seats/reserve", seatHandler.ReserveSeats)
+	app.Get("/me/tickets", issuedTicketHandler.GetMyTickets)
+
+	//=== transfer routes ===//
+	app.Post("/me/tickets/:issued_ticket_id/transfer", transferHandler.InitiateTransfer)
+	app.Get("/me/transfers", transferHandler.GetMyTransfers)
+	app.Post("/me/transfers/:transfer_id/accept", transferHandler.AcceptTransfer)
+	app.Post("/me/transfers/:transfer_id/decline", transferHandler.DeclineTransfer)
+	app.Post("/me/transfers/:transfer_id/cancel", transferHandler.CancelTransfer)
+
+	//=== resale routes ===//
+	app.Post("/me/tickets/:issued_ticket_id/resale", resaleHandler.CreateListing)
+	app.Get("/me/resale-listings", resaleHandler.GetMyListings)
+	app.Post("/me/resale-listings/:listing_id/withdraw", resaleHandler.WithdrawListing)
+	app.Post("/resale-listings/:listing_id/purchase", resaleHandler.PurchaseListing)
+
+	//=== waitlist routes ===//
+	app.Post("/tickets/:ticket_id/waitlist", waitlistHandler.JoinWaitlist)
+	app.Get("/me/waitlist", waitlistHandler.GetMyWaitlist)
+	app.Post("/me/waitlist/:waitlist_id/leave", waitlistHandler.LeaveWaitlist)
+	app.Post("/me/waitlist/:waitlist_id/claim", waitlistHandler.ClaimOffer)
+
+	//=== promo routes ===//
+	app.Post("/quote", promoHandler.Quote)
+	admin := app.Group("/admin", middleware.RequireGroup(cfg.AWS.CognitoAdminGroup))
+	admin.Post("/promos", promoHandler.CreatePromo)
+	admin.Get("/promos", promoHandler.GetPromos)
+	admin.Get("/promos/:promo_id", promoHandler.GetPromo)
+	admin.Put("/promos/:promo_id", promoHandler.UpdatePromo)
+	admin.Delete("/promos/:promo_id", promoHandler.DeletePromo)
+
+	//=== event admin routes ===//
+	admin.Put("/events/:event_id/status", eventHandler.UpdateEventStatus)
+	admin.Put("/events/:event_id/sale-window", eventHandler.UpdateSaleWindow)
+	admin.Post("/events/:event_id/cancel", eventHandler.CancelEvent)
+	admin.Put("/events/:event_id/resale-cap", eventHandler.UpdateResaleCap)
+	admin.Get("/tickets/:issued_ticket_id/transfers", transferHandler.GetTicketTransfers)
+	admin.Get("/tickets/:ticket_id/ledger", ledgerHandler.GetTicketLedger)
+	admin.Get("/inventory/reconciliation", reconciliationHandler.CheckInventory)
+	admin.Post("/inventory/reconciliation/repair", reconciliationHandler.RepairInventory)
+
+	//=== check-in routes ===//
+	staff := app.Group("/staff", middleware.RequireGroup(cfg.AWS.CognitoStaffGroup, cfg.AWS.CognitoAdminGroup))
+	staff.Post("/events/:event_id/check-in", issuedTicketHandler.CheckIn)
+	staff.Post("/events/:event_id/check-in/sync", issuedTicketHandler.SyncCheckIns)
 
 	//=== listen port ===//
-	if err := app.Listen(fmt.Sprintf(":%s", os.Getenv("APP_PORT"))); err != nil {
-		log.Fatal(err)
+	go func() {
+		if err := app.Listen(fmt.Sprintf(":%s", cfg.App.Port)); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+	baseDep.Logger.Info("shutting down")
+	healthHandler.Shutdown()
+	time.Sleep(cfg.App.ShutdownDelay)
+	if err := app.ShutdownWithTimeout(cfg.App.ShutdownTimeout); err != nil {
+		baseDep.Logger.Error("failed to shutdown http server", zap.Error(err))
+	}
+	<-consumerDone
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := tracerProvider.Shutdown(shutdownCtx); err != nil {
+		baseDep.Logger.Error("failed to flush traces", zap.Error(err))
+	}
+}
+
+// runEvery runs job every interval until ctx is cancelled, acting as
+// "job:<name>"; failures are logged and retried on the next tick.
+func runEvery(ctx context.Context, interval time.Duration, name string, logger config.Logger, job func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	ctx = config.WithActor(ctx, "job:"+name)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := job(ctx); err != nil {
+				logger.WithContext(ctx).Error("scheduled job failed", zap.String("job", name), zap.Error(err))
+			}
+		}
 	}
 }
 
-func loadEnv(logger config.Logger) {
-	_, err := os.Stat(".env")
-	if err == nil {
-		err = godotenv.Load()
+// runMigrate implements "migrate up [N]", "migrate down N|all",
+// "migrate version" and "migrate force V" against the embedded migrations.
+func runMigrate(ctx context.Context, cfg config.DatabaseConfig, logger config.Logger, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: migrate up [N] | down N|all | version | force V")
+	}
+
+	migrator, err := config.NewMigrator(ctx, cfg, logger)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	argument := func() (int, error) {
+		if len(args) < 2 {
+			return 0, fmt.Errorf("migrate %s: missing argument", args[0])
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil {
+			return 0, fmt.Errorf("migrate %s: invalid argument %q", args[0], args[1])
+		}
+		return n, nil
+	}
+
+	switch args[0] {
+	case "up":
+		if len(args) == 1 {
+			return migrator.Up()
+		}
+		n, err := argument()
 		if err != nil {
-			logger.Error("no .env files provided")
+			return err
+		}
+		return migrator.Steps(n)
+	case "down":
+		if len(args) == 2 && args[1] == "all" {
+			return migrator.Down()
+		}
+		n, err := argument()
+		if err != nil {
+			return err
+		}
+		return migrator.Steps(-n)
+	case "force":
+		version, err := argument()
+		if err != nil {
+			return err
+		}
+		return migrator.Force(version)
+	case "version":
+		version, dirty, err := migrator.Version()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("version %d, dirty %t\n", version, dirty)
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+}
+
+// runReconcile prints the inventory drift report as JSON. Without arguments it
+// is a dry run; "repair" followed by the file a dry run report was saved to
+// and ticket ids from it repairs those tickets as the report shows them.
+func runReconcile(ctx context.Context, reconciliationUsecase usecase.InventoryReconciliationExecutor, args []string) error {
+	var (
+		report model.InventoryReport
+		err    error
+	)
+	switch {
+	case len(args) == 0:
+		report, err = reconciliationUsecase.CheckInventory(ctx)
+	case args[0] == "repair" && len(args) > 2:
+		var request model.InventoryRepairRequest
+		request, err = reconcileRepairRequest(args[1], args[2:])
+		if err == nil {
+			report, err = reconciliationUsecase.RepairInventory(ctx, request)
+		}
+	default:
+		return errors.New("usage: reconcile [repair REPORT_FILE TICKET_ID...]")
+	}
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// reconcileRepairRequest names the tickets of the dry run report saved at path
+// to repair, with the counters the report shows for them.
+func reconcileRepairRequest(path string, args []string) (model.InventoryRepairRequest, error) {
+	var (
+		request model.InventoryRepairRequest
+		dryRun  model.InventoryReport
+	)
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return request, fmt.Errorf("reconcile repair: %w", err)
+	}
+	if err := json.Unmarshal(body, &dryRun); err != nil || !dryRun.DryRun {
+		return request, fmt.Errorf("reconcile repair: %s is not a dry run report", path)
+	}
+
+	reported := make(map[int]model.InventoryDrift, len(dryRun.Tickets))
+	for _, drift := range dryRun.Tickets {
+		reported[drift.TicketID] = drift
+	}
+	for _, arg := range args {
+		ticketID, err := strconv.Atoi(arg)
+		if err != nil || ticketID <= 0 {
+			return request, fmt.Errorf("reconcile repair: invalid ticket id %q", arg)
+		}
+		drift, ok := reported[ticketID]
+		if !ok {
+			return request, fmt.Errorf("reconcile repair: ticket %d is not in %s", ticketID, path)
 		}
+		request.Tickets = append(request.Tickets, model.InventoryRepairTicket{
+			TicketID: drift.TicketID,
+			Current:  drift.Current,
+			Expected: drift.Expected,
+		})
 	}
+	return request, nil
 }
@@ -3,7 +3,6 @@ package config
 import (
 	"context"
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -20,21 +19,21 @@ type Cacher interface {
 	SetNX(ctx context.Context, key string, value interface{}, duration time.Duration) error
 	Get(ctx context.Context, key string) (string, error)
 	Del(ctx context.Context, key string) error
+	Ping(ctx context.Context) error
 }
 
-func NewCacher(logger Logger) Cacher {
-	address := fmt.Sprintf("%s:%s", os.Getenv("CACHER_HOST"), os.Getenv("CACHER_PORT"))
-	duration, _ := time.ParseDuration(os.Getenv("CACHER_DEFAULT_EXP"))
+func NewCacher(cfg CacherConfig, logger Logger) Cacher {
+	address := fmt.Sprintf("%s:%s", cfg.Host, cfg.Port)
 	cacher := redis.NewClient(&redis.Options{
 		Addr:     address,
-		Password: os.Getenv("CACHER_PASSWORD"),
+		Password: cfg.Password.Value(),
 		DB:       0,
 	})
 
 	return &Cache{
 		db:         cacher,
-		service:    os.Getenv("CACHER_SERVICE"),
-		defaultExp: duration,
+		service:    cfg.Service,
+		defaultExp: cfg.DefaultExp,
 	}
 }
 
@@ -83,3 +82,7 @@ func (c *Cache) Del(ctx context.Context, key string) error {
 
 	return nil
 }
+
+func (c *Cache) Ping(ctx context.Context) error {
+	return c.db.Ping(ctx).Err()
+}
@@ -0,0 +1,315 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/caarlos0/env/v10"
+	"github.com/go-playground/validator/v10"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+const defaultConfigFile = "config.yaml"
+
+// Config is the whole service configuration. Load fills it from, in increasing
+// precedence, the defaults below, a YAML file, .env and the process
+// environment; the env tags are the variable names listed in env_example.
+type Config struct {
+	App      AppConfig      `yaml:"app"`
+	Database DatabaseConfig `yaml:"database"`
+	Cacher   CacherConfig   `yaml:"cacher"`
+	Ticket   TicketConfig   `yaml:"ticket"`
+	Event    EventConfig    `yaml:"event"`
+	Currency CurrencyConfig `yaml:"currency"`
+	AWS      AWSConfig      `yaml:"aws"`
+	SQS      SQSConfig      `yaml:"sqs"`
+	Tracing  TracingConfig  `yaml:"tracing"`
+	Health   HealthConfig   `yaml:"health"`
+}
+
+type AppConfig struct {
+	Port            string        `yaml:"port" env:"APP_PORT" validate:"required"`
+	RequestTimeout  time.Duration `yaml:"request_timeout" env:"APP_REQUEST_TIMEOUT" validate:"min=0"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"APP_SHUTDOWN_TIMEOUT" validate:"min=0"`
+	ShutdownDelay   time.Duration `yaml:"shutdown_delay" env:"APP_SHUTDOWN_DELAY" validate:"min=0"`
+}
+
+type DatabaseConfig struct {
+	User            string        `yaml:"user" env:"DATABASE_USER" validate:"required"`
+	Password        Secret        `yaml:"password" env:"DATABASE_PASSWORD"`
+	Host            string        `yaml:"host" env:"DATABASE_HOST" validate:"required"`
+	Port            string        `yaml:"port" env:"DATABASE_PORT" validate:"required"`
+	Schema          string        `yaml:"schema" env:"DATABASE_SCHEMA" validate:"required"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DATABASE_CONN_MAX_LIFETIME" validate:"min=0"`
+	MaxOpenConn     int           `yaml:"max_open_conn" env:"DATABASE_MAX_OPEN_CONN" validate:"min=0"`
+	MaxIdleConn     int           `yaml:"max_idle_conn" env:"DATABASE_MAX_IDLE_CONN" validate:"min=0"`
+	QueryTimeout    time.Duration `yaml:"query_timeout" env:"DATABASE_QUERY_TIMEOUT" validate:"min=0"`
+	// MigrateOnStart applies pending migrations before the service starts.
+	MigrateOnStart     bool          `yaml:"migrate_on_start" env:"DATABASE_MIGRATE_ON_START"`
+	MigrateLockTimeout time.Duration `yaml:"migrate_lock_timeout" env:"DATABASE_MIGRATE_LOCK_TIMEOUT" validate:"gt=0"`
+}
+
+type CacherConfig struct {
+	Host       string        `yaml:"host" env:"CACHER_HOST" validate:"required"`
+	Port       string        `yaml:"port" env:"CACHER_PORT" validate:"required"`
+	Password   Secret        `yaml:"password" env:"CACHER_PASSWORD"`
+	Service    string        `yaml:"service" env:"CACHER_SERVICE"`
+	DefaultExp time.Duration `yaml:"default_exp" env:"CACHER_DEFAULT_EXP" validate:"min=0"`
+}
+
+type TicketConfig struct {
+	MaxPerUser int `yaml:"max_per_user" env:"TICKET_MAX_PER_USER" validate:"min=0"`
+	// ReservationHoldDuration is how long a reservation keeps its tickets for
+	// an unpaid order before they go back on sale and to the waitlist. Zero
+	// keeps them until the order settles; a payment that lands after its hold
+	// ran out is refused, so it must be longer than the payment window.
+	ReservationHoldDuration      time.Duration `yaml:"reservation_hold_duration" env:"TICKET_RESERVATION_HOLD_DURATION" validate:"gte=0"`
+	ReservationHoldSweepInterval time.Duration `yaml:"reservation_hold_sweep_interval" env:"TICKET_RESERVATION_HOLD_SWEEP_INTERVAL" validate:"gt=0"`
+	// SeatHoldDuration is how long chosen seats stay held for an unpaid order.
+	SeatHoldDuration      time.Duration `yaml:"seat_hold_duration" env:"TICKET_SEAT_HOLD_DURATION" validate:"gt=0"`
+	SeatHoldSweepInterval time.Duration `yaml:"seat_hold_sweep_interval" env:"TICKET_SEAT_HOLD_SWEEP_INTERVAL" validate:"gt=0"`
+	// SigningKey is the base64 encoded 32 byte Ed25519 seed issued ticket QR
+	// payloads are signed with.
+	SigningKey Secret `yaml:"signing_key" env:"TICKET_SIGNING_KEY" validate:"required,base64"`
+	// TransferTTL is how long the recipient of a ticket transfer has to
+	// accept it.
+	TransferTTL           time.Duration `yaml:"transfer_ttl" env:"TICKET_TRANSFER_TTL" validate:"gt=0"`
+	TransferSweepInterval time.Duration `yaml:"transfer_sweep_interval" env:"TICKET_TRANSFER_SWEEP_INTERVAL" validate:"gt=0"`
+	// ResaleFeeBasisPoints is the platform fee resale buyers pay on top of
+	// the listed price, in hundredths of a percent.
+	ResaleFeeBasisPoints int `yaml:"resale_fee_basis_points" env:"TICKET_RESALE_FEE_BASIS_POINTS" validate:"min=0,max=10000"`
+	// WaitlistOfferTTL is how long tickets set aside for a waitlisted user
+	// stay claimable.
+	WaitlistOfferTTL           time.Duration `yaml:"waitlist_offer_ttl" env:"TICKET_WAITLIST_OFFER_TTL" validate:"gt=0"`
+	WaitlistOfferSweepInterval time.Duration `yaml:"waitlist_offer_sweep_interval" env:"TICKET_WAITLIST_OFFER_SWEEP_INTERVAL" validate:"gt=0"`
+	// InventoryCheckInterval is how often the stock of every ticket is
+	// checked for drift. The check only reports; repairs are made by hand.
+	InventoryCheckInterval time.Duration `yaml:"inventory_check_interval" env:"TICKET_INVENTORY_CHECK_INTERVAL" validate:"gt=0"`
+}
+
+// EventConfig sets how often due event status changes, such as an event
+// passing its date or selling out, are applied.
+type EventConfig struct {
+	StatusSweepInterval time.Duration `yaml:"status_sweep_interval" env:"EVENT_STATUS_SWEEP_INTERVAL" validate:"gt=0"`
+}
+
+// CurrencyConfig points at the static exchange rate table; without one prices
+// are only shown in their own currency.
+type CurrencyConfig struct {
+	RatesFile string `yaml:"rates_file" env:"CURRENCY_RATES_FILE"`
+}
+
+type AWSConfig struct {
+	Region            string `yaml:"region" env:"AWS_REGION" validate:"required"`
+	CognitoUserPoolID string `yaml:"cognito_user_pool_id" env:"AWS_COGNITO_USER_POOL_ID" validate:"required"`
+	// CognitoAdminGroup is the user pool group allowed on /admin routes.
+	CognitoAdminGroup string `yaml:"cognito_admin_group" env:"AWS_COGNITO_ADMIN_GROUP" validate:"required"`
+	// CognitoStaffGroup is the group of the event staff that check tickets
+	// in at the gates.
+	CognitoStaffGroup string `yaml:"cognito_staff_group" env:"AWS_COGNITO_STAFF_GROUP" validate:"required"`
+}
+
+// SQSConfig holds the queue URLs; the dead letter queues are optional and are
+// not consumed when left empty. EventCancelledURL is the queue event
+// cancellation notices are published to.
+type SQSConfig struct {
+	TicketURL           string `yaml:"ticket_url" env:"SQS_TICKET_URL" validate:"required,url"`
+	TicketFailedURL     string `yaml:"ticket_failed_url" env:"SQS_TICKET_FAILED_URL" validate:"required,url"`
+	TicketSuccessURL    string `yaml:"ticket_success_url" env:"SQS_TICKET_SUCCESS_URL" validate:"required,url"`
+	TicketDLQURL        string `yaml:"ticket_dlq_url" env:"SQS_TICKET_DLQ_URL" validate:"omitempty,url"`
+	TicketFailedDLQURL  string `yaml:"ticket_failed_dlq_url" env:"SQS_TICKET_FAILED_DLQ_URL" validate:"omitempty,url"`
+	TicketSuccessDLQURL string `yaml:"ticket_success_dlq_url" env:"SQS_TICKET_SUCCESS_DLQ_URL" validate:"omitempty,url"`
+	WorkerCount         int    `yaml:"worker_count" env:"SQS_WORKER_COUNT" validate:"min=1"`
+	EventCancelledURL   string `yaml:"event_cancelled_url" env:"SQS_EVENT_CANCELLED_URL" validate:"omitempty,url"`
+	WaitlistOfferURL    string `yaml:"waitlist_offer_url" env:"SQS_WAITLIST_OFFER_URL" validate:"omitempty,url"`
+}
+
+// TracingConfig selects the span exporter; the OTLP exporter itself reads the
+// standard OTEL_EXPORTER_OTLP_* variables.
+type TracingConfig struct {
+	Exporter string `yaml:"exporter" env:"OTEL_TRACES_EXPORTER" validate:"omitempty,oneof=otlp none"`
+}
+
+type HealthConfig struct {
+	CheckTimeout       time.Duration `yaml:"check_timeout" env:"HEALTH_CHECK_TIMEOUT" validate:"gt=0"`
+	ConsumerStaleAfter time.Duration `yaml:"consumer_stale_after" env:"HEALTH_CONSUMER_STALE_AFTER" validate:"gt=0"`
+}
+
+// Secret is a string that never shows up in logs or fmt output; use Value to
+// get the real content.
+type Secret string
+
+const redacted = "[REDACTED]"
+
+func (s Secret) Value() string {
+	return string(s)
+}
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+func (s Secret) GoString() string {
+	return s.String()
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf("%q", s.String())), nil
+}
+
+func defaultConfig() Config {
+	return Config{
+		App: AppConfig{
+			Port:            "8080",
+			ShutdownTimeout: 30 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Port:            "3306",
+			ConnMaxLifetime: 5 * time.Minute,
+			MaxOpenConn:     10,
+			MaxIdleConn:     10,
+			// MigrateLockTimeout is how long a replica waits for another one
+			// to finish migrating.
+			MigrateLockTimeout: 5 * time.Minute,
+		},
+		Cacher: CacherConfig{
+			Port: "6379",
+		},
+		AWS: AWSConfig{
+			CognitoAdminGroup: "admin",
+			CognitoStaffGroup: "staff",
+		},
+		Ticket: TicketConfig{
+			ReservationHoldSweepInterval: time.Minute,
+			SeatHoldDuration:             10 * time.Minute,
+			SeatHoldSweepInterval:        time.Minute,
+			TransferTTL:                  48 * time.Hour,
+			TransferSweepInterval:        time.Minute,
+			ResaleFeeBasisPoints:         500,
+			WaitlistOfferTTL:             30 * time.Minute,
+			WaitlistOfferSweepInterval:   time.Minute,
+			InventoryCheckInterval:       15 * time.Minute,
+		},
+		Event: EventConfig{
+			StatusSweepInterval: time.Minute,
+		},
+		SQS: SQSConfig{
+			WorkerCount: 5,
+		},
+		Health: HealthConfig{
+			CheckTimeout:       2 * time.Second,
+			ConsumerStaleAfter: time.Minute,
+		},
+	}
+}
+
+// Load reads the configuration and fails on the first parse error or on any
+// missing or invalid field, so a misconfigured instance never starts. The YAML
+// file is CONFIG_FILE, or config.yaml when that exists; .env never overrides
+// variables already set in the environment.
+func Load() (*Config, error) {
+	cfg, err := read()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := env.Parse(&cfg); err != nil {
+		return nil, fmt.Errorf("parse environment: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// LoadDatabase reads only the database section the same way as Load, for
+// commands such as migrate that need nothing else configured.
+func LoadDatabase() (*DatabaseConfig, error) {
+	cfg, err := read()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := env.Parse(&cfg.Database); err != nil {
+		return nil, fmt.Errorf("parse environment: %w", err)
+	}
+
+	if err := validateFields(cfg.Database); err != nil {
+		return nil, err
+	}
+
+	return &cfg.Database, nil
+}
+
+// read returns the defaults overridden by the YAML file, and loads .env into
+// the environment for the caller to parse.
+func read() (Config, error) {
+	cfg := defaultConfig()
+
+	path := os.Getenv("CONFIG_FILE")
+	if path == "" {
+		if _, err := os.Stat(defaultConfigFile); err == nil {
+			path = defaultConfigFile
+		}
+	}
+	if path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, fmt.Errorf("read config file: %w", err)
+		}
+		if err = yaml.Unmarshal(raw, &cfg); err != nil {
+			return cfg, fmt.Errorf("parse config file %s: %w", path, err)
+		}
+	}
+
+	if _, err := os.Stat(".env"); err == nil {
+		if err = godotenv.Load(); err != nil {
+			return cfg, fmt.Errorf("load .env: %w", err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// Validate reports every invalid field by its environment variable name.
+func (c Config) Validate() error {
+	return validateFields(c)
+}
+
+func validateFields(section interface{}) error {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return field.Tag.Get("env")
+	})
+
+	err := validate.Struct(section)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+
+	messages := make([]string, 0, len(validationErrors))
+	for _, fieldError := range validationErrors {
+		if fieldError.Tag() == "required" {
+			messages = append(messages, fmt.Sprintf("%s is required", fieldError.Field()))
+			continue
+		}
+		messages = append(messages, fmt.Sprintf("%s is invalid (%s)", fieldError.Field(), fieldError.ActualTag()))
+	}
+
+	return fmt.Errorf("invalid configuration: %s", strings.Join(messages, ", "))
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setRequiredEnv(t *testing.T) {
+	for key, value := range map[string]string{
+		"DATABASE_USER":            "ticket",
+		"DATABASE_PASSWORD":        "s3cr3t",
+		"DATABASE_HOST":            "localhost",
+		"DATABASE_SCHEMA":          "ticket_management",
+		"CACHER_HOST":              "localhost",
+		"AWS_REGION":               "ap-southeast-3",
+		"AWS_COGNITO_USER_POOL_ID": "pool",
+		"SQS_TICKET_URL":           "https://sqs.example.com/ticket",
+		"SQS_TICKET_FAILED_URL":    "https://sqs.example.com/ticket-failed",
+		"SQS_TICKET_SUCCESS_URL":   "https://sqs.example.com/ticket-success",
+		"TICKET_SIGNING_KEY":       "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=",
+	} {
+		t.Setenv(key, value)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("defaults and environment", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("DATABASE_QUERY_TIMEOUT", "3s")
+
+		cfg, err := Load()
+		assert.NoError(t, err)
+		assert.Equal(t, "8080", cfg.App.Port)
+		assert.Equal(t, "3306", cfg.Database.Port)
+		assert.Equal(t, 3*time.Second, cfg.Database.QueryTimeout)
+		assert.Equal(t, "s3cr3t", cfg.Database.Password.Value())
+		assert.Equal(t, "ap-southeast-3", cfg.AWS.Region)
+		assert.Equal(t, 5, cfg.SQS.WorkerCount)
+		assert.Zero(t, cfg.Ticket.ReservationHoldDuration)
+	})
+
+	t.Run("yaml file is overridden by environment", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		setRequiredEnv(t)
+		t.Setenv("CONFIG_FILE", path)
+		t.Setenv("APP_PORT", "9000")
+		assert.NoError(t, os.WriteFile(path,
+			[]byte("app:\n  port: \"7000\"\n  request_timeout: 5s\nticket:\n  max_per_user: 4\n"), 0o600))
+
+		cfg, err := Load()
+		assert.NoError(t, err)
+		assert.Equal(t, "9000", cfg.App.Port)
+		assert.Equal(t, 5*time.Second, cfg.App.RequestTimeout)
+		assert.Equal(t, 4, cfg.Ticket.MaxPerUser)
+	})
+
+	t.Run("unparsable value fails", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("DATABASE_QUERY_TIMEOUT", "3 seconds")
+
+		_, err := Load()
+		assert.ErrorContains(t, err, "QueryTimeout")
+	})
+
+	t.Run("missing required fields fail", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("DATABASE_USER", "")
+		t.Setenv("SQS_TICKET_URL", "not a url")
+
+		_, err := Load()
+		assert.EqualError(t, err, "invalid configuration: DATABASE_USER is required, SQS_TICKET_URL is invalid (url)")
+	})
+}
+
+func TestLoadDatabase(t *testing.T) {
+	t.Run("ignores the other sections", func(t *testing.T) {
+		t.Setenv("DATABASE_USER", "ticket")
+		t.Setenv("DATABASE_HOST", "localhost")
+		t.Setenv("DATABASE_SCHEMA", "ticket_management")
+		t.Setenv("TICKET_SIGNING_KEY", "")
+		t.Setenv("SQS_TICKET_URL", "not a url")
+
+		cfg, err := LoadDatabase()
+		assert.NoError(t, err)
+		assert.Equal(t, "localhost", cfg.Host)
+		assert.Equal(t, "3306", cfg.Port)
+	})
+
+	t.Run("missing required fields fail", func(t *testing.T) {
+		t.Setenv("DATABASE_USER", "ticket")
+		t.Setenv("DATABASE_HOST", "")
+		t.Setenv("DATABASE_SCHEMA", "ticket_management")
+
+		_, err := LoadDatabase()
+		assert.EqualError(t, err, "invalid configuration: DATABASE_HOST is required")
+	})
+}
+
+func TestSecretRedaction(t *testing.T) {
+	cfg := DatabaseConfig{User: "ticket", Password: "s3cr3t"}
+
+	raw, err := json.Marshal(cfg)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(raw), "s3cr3t")
+	assert.Contains(t, string(raw), redacted)
+
+	assert.NotContains(t, fmt.Sprintf("%v %+v %#v", cfg, cfg, cfg), "s3cr3t")
+}
@@ -0,0 +1,43 @@
+package config
+
+import "context"
+
+type contextKey string
+
+const (
+	requestIDKey contextKey = "request_id"
+	actorKey     contextKey = "actor"
+)
+
+// WithRequestID returns a copy of ctx carrying the request or correlation id
+// that Logger.WithContext attaches to every log line.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFromContext returns the request id stored by WithRequestID, or an
+// empty string when there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// WithActor returns a copy of ctx carrying who acts in it: the signed in user,
+// the queue a message came from or the scheduled job that runs. The inventory
+// ledger records it with every stock change.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// ActorFromContext returns the actor stored by WithActor, or an empty string
+// when there is none.
+func ActorFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	actor, _ := ctx.Value(actorKey).(string)
+	return actor
+}
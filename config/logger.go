@@ -1,6 +1,9 @@
 package config
 
 import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -12,6 +15,7 @@ type LoggerConf struct {
 type Logger interface {
 	Info(msg string, fields ...zapcore.Field)
 	Error(msg string, fields ...zapcore.Field)
+	WithContext(ctx context.Context) Logger
 }
 
 func SetupLogger() Logger {
@@ -24,6 +28,13 @@ func SetupLogger() Logger {
 	}
 }
 
+// NewNopLogger returns a Logger that discards everything, for tests.
+func NewNopLogger() Logger {
+	return &LoggerConf{
+		dep: zap.NewNop(),
+	}
+}
+
 func (l *LoggerConf) Info(msg string, fields ...zapcore.Field) {
 	l.dep.Info(msg, fields...)
 }
@@ -31,3 +42,21 @@ func (l *LoggerConf) Info(msg string, fields ...zapcore.Field) {
 func (l *LoggerConf) Error(msg string, fields ...zapcore.Field) {
 	l.dep.Error(msg, fields...)
 }
+
+// WithContext returns a logger that tags every line with the request id and
+// trace id carried by ctx, if any.
+func (l *LoggerConf) WithContext(ctx context.Context) Logger {
+	var fields []zapcore.Field
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		fields = append(fields, zap.String("request_id", requestID))
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		fields = append(fields, zap.String("trace_id", spanContext.TraceID().String()))
+	}
+	if len(fields) == 0 {
+		return l
+	}
+	return &LoggerConf{
+		dep: l.dep.With(fields...),
+	}
+}
@@ -1,23 +1,58 @@
 package middleware
 
 import (
+	"github.com/SyamSolution/ticket-management-service/config"
 	"github.com/SyamSolution/ticket-management-service/helper"
 	"github.com/gofiber/fiber/v2"
 )
 
-func Auth() fiber.Handler {
+func Auth(cognito helper.Cognito) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		tokenString := c.Get("Authorization")
 
-		email, err := helper.VerifyToken(tokenString, "email")
+		claims, err := cognito.VerifyTokenClaims(tokenString)
 		if err != nil {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"message": "Unauthorized",
 			})
 		}
 
-		c.Locals("email", email)
+		c.Locals("email", claims["email"])
+		c.Locals("groups", tokenGroups(claims["cognito:groups"]))
+		if email, ok := claims["email"].(string); ok && email != "" {
+			c.SetUserContext(config.WithActor(c.UserContext(), "user:"+email))
+		}
 
 		return c.Next()
 	}
 }
+
+// RequireGroup only lets through users whose token lists one of groups in its
+// cognito:groups claim. It must run after Auth.
+func RequireGroup(groups ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		members, _ := c.Locals("groups").([]string)
+		for _, member := range members {
+			for _, group := range groups {
+				if member == group {
+					return c.Next()
+				}
+			}
+		}
+
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "Forbidden",
+		})
+	}
+}
+
+func tokenGroups(claim interface{}) []string {
+	values, _ := claim.([]interface{})
+	groups := make([]string, 0, len(values))
+	for _, value := range values {
+		if group, ok := value.(string); ok {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
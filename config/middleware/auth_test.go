@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireGroup(t *testing.T) {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("groups", tokenGroups([]interface{}{c.Get("X-Group")}))
+		return c.Next()
+	})
+	app.Get("/admin", RequireGroup("admin"), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "/admin", nil)
+	req.Header.Set("X-Group", "admin")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	req = httptest.NewRequest("GET", "/admin", nil)
+	req.Header.Set("X-Group", "customer")
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 403, resp.StatusCode)
+
+	app.Get("/staff", RequireGroup("staff", "admin"), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	for group, status := range map[string]int{"staff": 200, "admin": 200, "customer": 403} {
+		req = httptest.NewRequest("GET", "/staff", nil)
+		req.Header.Set("X-Group", group)
+		resp, err = app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, status, resp.StatusCode, group)
+	}
+}
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const inventoryNamespace = "ticket_inventory"
+
+type InventoryGetter interface {
+	GetTicketInventory(ctx context.Context) ([]model.Ticket, error)
+}
+
+// InventoryCollector exports the stock counters of every ticket_detail row as
+// gauges, read from the database on each scrape.
+type InventoryCollector struct {
+	ig      InventoryGetter
+	logger  config.Logger
+	timeout time.Duration
+
+	totalDesc     *prometheus.Desc
+	availableDesc *prometheus.Desc
+	heldDesc      *prometheus.Desc
+	soldDesc      *prometheus.Desc
+}
+
+func NewInventoryCollector(ig InventoryGetter, logger config.Logger) *InventoryCollector {
+	labels := []string{"ticket_id", "type", "continent"}
+	return &InventoryCollector{
+		ig:      ig,
+		logger:  logger,
+		timeout: 5 * time.Second,
+		totalDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(inventoryNamespace, "", "total"),
+			"Tickets put on sale, the sum of the available, held and sold ones.",
+			labels,
+			nil,
+		),
+		availableDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(inventoryNamespace, "", "available"),
+			"Tickets still available for reservation.",
+			labels,
+			nil,
+		),
+		heldDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(inventoryNamespace, "", "held"),
+			"Tickets held by unpaid orders and open waitlist offers.",
+			labels,
+			nil,
+		),
+		soldDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(inventoryNamespace, "", "sold"),
+			"Tickets sold by paid orders.",
+			labels,
+			nil,
+		),
+	}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c InventoryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.totalDesc
+	ch <- c.availableDesc
+	ch <- c.heldDesc
+	ch <- c.soldDesc
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c InventoryCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	tickets, err := c.ig.GetTicketInventory(ctx)
+	if err != nil {
+		c.logger.Error("failed to collect ticket inventory", zap.Error(err))
+		return
+	}
+
+	for _, ticket := range tickets {
+		labels := []string{strconv.Itoa(ticket.TicketID), ticket.Type, ticket.ContinentName}
+		ch <- prometheus.MustNewConstMetric(c.totalDesc, prometheus.GaugeValue, float64(ticket.Total), labels...)
+		ch <- prometheus.MustNewConstMetric(c.availableDesc, prometheus.GaugeValue, float64(ticket.Available), labels...)
+		ch <- prometheus.MustNewConstMetric(c.heldDesc, prometheus.GaugeValue, float64(ticket.Held), labels...)
+		ch <- prometheus.MustNewConstMetric(c.soldDesc, prometheus.GaugeValue, float64(ticket.Sold), labels...)
+	}
+}
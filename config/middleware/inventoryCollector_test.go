@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type inventoryGetterStub []model.Ticket
+
+func (s inventoryGetterStub) GetTicketInventory(context.Context) ([]model.Ticket, error) {
+	return s, nil
+}
+
+func TestInventoryCollector(t *testing.T) {
+	collector := NewInventoryCollector(inventoryGetterStub{
+		{TicketID: 1, Type: "VIP", ContinentName: "Asia", Total: 10, Available: 6, Held: 3, Sold: 1},
+	}, config.NewNopLogger())
+
+	expected := `
+# HELP ticket_inventory_available Tickets still available for reservation.
+# TYPE ticket_inventory_available gauge
+ticket_inventory_available{continent="Asia",ticket_id="1",type="VIP"} 6
+# HELP ticket_inventory_held Tickets held by unpaid orders and open waitlist offers.
+# TYPE ticket_inventory_held gauge
+ticket_inventory_held{continent="Asia",ticket_id="1",type="VIP"} 3
+`
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"ticket_inventory_available", "ticket_inventory_held")
+	assert.NoError(t, err)
+}
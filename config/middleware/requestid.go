@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+const HeaderRequestID = "X-Request-ID"
+
+// maxRequestIDLength bounds the caller's X-Request-ID, which ends up in every
+// log line and response of the request.
+const maxRequestIDLength = 128
+
+// validRequestID reports whether the caller's requestID is safe to log and
+// echo: at most maxRequestIDLength letters, digits, '-', '_' or '.'.
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range requestID {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// RequestID accepts the caller's X-Request-ID when valid or generates one,
+// stores it in the request's user context for config.Logger.WithContext,
+// exposes it to the access log and handlers through locals and echoes it in
+// the response.
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID := c.Get(HeaderRequestID)
+		if !validRequestID(requestID) {
+			requestID = utils.UUIDv4()
+		}
+
+		c.SetUserContext(config.WithRequestID(c.UserContext(), requestID))
+		c.Locals("request_id", requestID)
+		c.Locals("user_identity", model.UserIdentity{
+			RequestID:    requestID,
+			CacheControl: c.Get(fiber.HeaderCacheControl),
+		})
+		c.Set(HeaderRequestID, requestID)
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{name: "keeps a valid id", header: "req-1_a.B", keep: true},
+		{name: "replaces a missing id", header: ""},
+		{name: "replaces an id with other characters", header: "req 1\nforged=true"},
+		{name: "replaces an id that is too long", header: strings.Repeat("a", maxRequestIDLength+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(RequestID())
+			var stored string
+			app.Get("/", func(c *fiber.Ctx) error {
+				stored = config.RequestIDFromContext(c.UserContext())
+				return nil
+			})
+
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				req.Header.Set(HeaderRequestID, tt.header)
+			}
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+
+			echoed := resp.Header.Get(HeaderRequestID)
+			assert.Equal(t, stored, echoed)
+			assert.True(t, validRequestID(echoed))
+			assert.Equal(t, tt.keep, echoed == tt.header)
+		})
+	}
+}
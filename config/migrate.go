@@ -0,0 +1,174 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SyamSolution/ticket-management-service/db"
+	"github.com/go-sql-driver/mysql"
+	"github.com/golang-migrate/migrate/v4"
+	migratemysql "github.com/golang-migrate/migrate/v4/database/mysql"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"go.uber.org/zap"
+)
+
+// migrationLockName is the MySQL advisory lock held for the whole migration
+// run so that replicas starting together migrate one after another; the ones
+// that follow find nothing left to apply.
+const migrationLockName = ServiceName + ":migrate"
+
+// Migrator applies the embedded migrations while holding the advisory lock.
+// It uses its own connection pool because migration files may contain several
+// statements, which the service DSN does not allow.
+type Migrator struct {
+	migrate *migrate.Migrate
+	db      *sql.DB
+	conn    *sql.Conn
+	logger  Logger
+}
+
+// NewMigrator connects to the database and blocks until it holds the
+// migration lock or cfg.MigrateLockTimeout passes. Close releases the lock.
+func NewMigrator(ctx context.Context, cfg DatabaseConfig, logger Logger) (*Migrator, error) {
+	dsn := mysql.NewConfig()
+	dsn.User = cfg.User
+	dsn.Passwd = cfg.Password.Value()
+	dsn.Net = "tcp"
+	dsn.Addr = fmt.Sprintf("%s:%s", cfg.Host, cfg.Port)
+	dsn.DBName = cfg.Schema
+	dsn.ParseTime = true
+	dsn.MultiStatements = true
+
+	database, err := sql.Open("mysql", dsn.FormatDSN())
+	if err != nil {
+		logger.Error("failed to connect DB for migration", zap.Error(err))
+		return nil, err
+	}
+
+	conn, err := database.Conn(ctx)
+	if err != nil {
+		database.Close()
+		logger.Error("failed to connect DB for migration", zap.Error(err))
+		return nil, err
+	}
+
+	if err = acquireLock(ctx, conn, cfg.MigrateLockTimeout); err != nil {
+		conn.Close()
+		database.Close()
+		logger.Error("failed to acquire migration lock", zap.Error(err))
+		return nil, err
+	}
+
+	m, err := newMigrate(database)
+	if err != nil {
+		releaseLock(conn)
+		conn.Close()
+		database.Close()
+		logger.Error("failed to create migration", zap.Error(err))
+		return nil, err
+	}
+
+	return &Migrator{migrate: m, db: database, conn: conn, logger: logger}, nil
+}
+
+func newMigrate(database *sql.DB) (*migrate.Migrate, error) {
+	source, err := iofs.New(db.Migrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := migratemysql.WithInstance(database, &migratemysql.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.NewWithInstance("iofs", source, "mysql", driver)
+}
+
+func acquireLock(ctx context.Context, conn *sql.Conn, timeout time.Duration) error {
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", migrationLockName, int(timeout.Seconds())).Scan(&acquired); err != nil {
+		return err
+	}
+	if !acquired.Valid || acquired.Int64 != 1 {
+		return fmt.Errorf("migration lock %q still held by another instance after %s", migrationLockName, timeout)
+	}
+
+	return nil
+}
+
+func releaseLock(conn *sql.Conn) {
+	_, _ = conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", migrationLockName)
+}
+
+// Up applies every pending migration; an up to date schema is not an error.
+func (m *Migrator) Up() error {
+	if err := m.migrate.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		m.logger.Error("failed to run migration", zap.Error(err))
+		return err
+	}
+
+	return m.logVersion()
+}
+
+// Steps applies n migrations, rolling back when n is negative.
+func (m *Migrator) Steps(n int) error {
+	if err := m.migrate.Steps(n); err != nil {
+		m.logger.Error("failed to run migration", zap.Int("steps", n), zap.Error(err))
+		return err
+	}
+
+	return m.logVersion()
+}
+
+// Down rolls back every applied migration.
+func (m *Migrator) Down() error {
+	if err := m.migrate.Down(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		m.logger.Error("failed to rollback migration", zap.Error(err))
+		return err
+	}
+
+	return m.logVersion()
+}
+
+// Force records version as applied and clean without running anything, to
+// recover from a migration that failed half way.
+func (m *Migrator) Force(version int) error {
+	if err := m.migrate.Force(version); err != nil {
+		m.logger.Error("failed to force migration version", zap.Int("version", version), zap.Error(err))
+		return err
+	}
+
+	return m.logVersion()
+}
+
+func (m *Migrator) Version() (version uint, dirty bool, err error) {
+	version, dirty, err = m.migrate.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+
+	return version, dirty, err
+}
+
+func (m *Migrator) logVersion() error {
+	version, dirty, err := m.Version()
+	if err != nil {
+		return err
+	}
+	m.logger.Info("database schema version", zap.Uint("version", version), zap.Bool("dirty", dirty))
+
+	return nil
+}
+
+// Close releases the migration lock and closes the migration connections.
+func (m *Migrator) Close() error {
+	releaseLock(m.conn)
+	m.conn.Close()
+	sourceErr, databaseErr := m.migrate.Close()
+
+	return errors.Join(sourceErr, databaseErr)
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/SyamSolution/ticket-management-service/db"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmbeddedMigrations(t *testing.T) {
+	source, err := iofs.New(db.Migrations, "migrations")
+	assert.NoError(t, err)
+	defer source.Close()
+
+	first, err := source.First()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(20240502130532), first)
+
+	files, err := fs.Glob(db.Migrations, "migrations/*.up.sql")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, files)
+	for _, up := range files {
+		_, err := fs.Stat(db.Migrations, strings.TrimSuffix(up, ".up.sql")+".down.sql")
+		assert.NoError(t, err, "missing down migration for %s", up)
+	}
+}
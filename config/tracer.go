@@ -0,0 +1,47 @@
+package config
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.uber.org/zap"
+)
+
+const ServiceName = "ticket-management-service"
+
+// NewTracerProvider installs the global tracer provider and the W3C trace
+// context propagator. The "otlp" exporter sends spans over OTLP/HTTP,
+// configured through the standard OTEL_EXPORTER_OTLP_* variables; any other
+// value keeps tracing in process only so spans are created and propagated but
+// never exported. Extra options, e.g. an in-memory syncer in tests, are
+// appended as is.
+func NewTracerProvider(ctx context.Context, cfg TracingConfig, logger Logger, opts ...sdktrace.TracerProviderOption) (*sdktrace.TracerProvider, error) {
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(ServiceName)))
+	if err != nil {
+		logger.Error("failed to create tracing resource", zap.Error(err))
+		return nil, err
+	}
+
+	options := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+	if cfg.Exporter == "otlp" {
+		exporter, err := otlptracehttp.New(ctx)
+		if err != nil {
+			logger.Error("failed to create otlp trace exporter", zap.Error(err))
+			return nil, err
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+	}
+	options = append(options, opts...)
+
+	provider := sdktrace.NewTracerProvider(options...)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider, nil
+}
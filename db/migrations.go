@@ -0,0 +1,10 @@
+// Package db ships the SQL migrations inside the service binary.
+package db
+
+import "embed"
+
+// Migrations holds every db/migrations/*.sql file, read with the directory
+// "migrations" as root.
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
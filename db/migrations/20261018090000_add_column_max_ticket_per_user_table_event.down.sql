@@ -0,0 +1 @@
+ALTER TABLE event DROP COLUMN max_ticket_per_user;
@@ -0,0 +1 @@
+ALTER TABLE event ADD COLUMN max_ticket_per_user INT NOT NULL DEFAULT 0;
@@ -0,0 +1,15 @@
+-- reservation records the tickets an order holds. An order has at most one
+-- reservation; reservations placed without an order leave order_id NULL.
+CREATE TABLE reservation (
+    reservation_id INT AUTO_INCREMENT PRIMARY KEY,
+    order_id VARCHAR(100),
+    ticket_detail_id INT NOT NULL,
+    event_id INT NOT NULL DEFAULT 0,
+    email VARCHAR(255) NOT NULL,
+    quantity INT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_reservation_event_email (event_id, email),
+    UNIQUE KEY uq_reservation_order_id (order_id)
+);
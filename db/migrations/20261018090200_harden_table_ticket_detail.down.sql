@@ -0,0 +1,30 @@
+ALTER TABLE reservation
+    MODIFY updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+
+ALTER TABLE event
+    MODIFY updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+
+ALTER TABLE ticket_detail DROP FOREIGN KEY fk_ticket_detail_event;
+
+ALTER TABLE ticket_detail
+    DROP INDEX fk_ticket_detail_event,
+    DROP INDEX idx_ticket_detail_type,
+    DROP INDEX idx_ticket_detail_continent_name,
+    DROP CHECK chk_ticket_detail_stock,
+    MODIFY stock_ticket INT,
+    MODIFY stock INT,
+    MODIFY stock_ordered INT,
+    MODIFY created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    MODIFY updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+
+UPDATE ticket_detail td
+    JOIN ticket_detail_orphan_event o ON o.ticket_detail_id = td.ticket_detail_id
+    SET td.event_id = o.event_id;
+
+DROP TABLE ticket_detail_orphan_event;
+
+UPDATE ticket_detail td
+    JOIN ticket_detail_negative_stock n ON n.ticket_detail_id = td.ticket_detail_id
+    SET td.stock = n.stock;
+
+DROP TABLE ticket_detail_negative_stock;
@@ -0,0 +1,59 @@
+UPDATE ticket_detail SET
+    stock_ticket = COALESCE(stock_ticket, 0),
+    stock = COALESCE(stock, 0),
+    stock_ordered = COALESCE(stock_ordered, 0),
+    created_at = COALESCE(created_at, CURRENT_TIMESTAMP),
+    updated_at = COALESCE(updated_at, CURRENT_TIMESTAMP);
+
+-- tickets pointing at an event that no longer exists would fail the foreign
+-- key below. Their event_id is copied to ticket_detail_orphan_event before it
+-- is cleared, so nothing is lost: the rows can be relinked or reviewed by
+-- hand, and the down migration puts them back.
+CREATE TABLE ticket_detail_orphan_event (
+    ticket_detail_id INT NOT NULL PRIMARY KEY,
+    event_id INT NOT NULL,
+    detached_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO ticket_detail_orphan_event (ticket_detail_id, event_id)
+    SELECT td.ticket_detail_id, td.event_id FROM ticket_detail td
+    LEFT JOIN event e ON td.event_id = e.event_id
+    WHERE td.event_id IS NOT NULL AND e.event_id IS NULL;
+
+UPDATE ticket_detail td
+    JOIN ticket_detail_orphan_event o ON o.ticket_detail_id = td.ticket_detail_id
+    SET td.event_id = NULL;
+
+-- the unguarded stock updates before could take stock below zero, which the
+-- check below rejects. Those rows keep their stock in
+-- ticket_detail_negative_stock before it is clamped to zero, for review by
+-- hand, and the down migration puts it back.
+CREATE TABLE ticket_detail_negative_stock (
+    ticket_detail_id INT NOT NULL PRIMARY KEY,
+    stock INT NOT NULL,
+    clamped_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO ticket_detail_negative_stock (ticket_detail_id, stock)
+    SELECT ticket_detail_id, stock FROM ticket_detail WHERE stock < 0;
+
+UPDATE ticket_detail td
+    JOIN ticket_detail_negative_stock n ON n.ticket_detail_id = td.ticket_detail_id
+    SET td.stock = 0;
+
+ALTER TABLE ticket_detail
+    MODIFY stock_ticket INT NOT NULL DEFAULT 0,
+    MODIFY stock INT NOT NULL DEFAULT 0,
+    MODIFY stock_ordered INT NOT NULL DEFAULT 0,
+    MODIFY created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    MODIFY updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    ADD CONSTRAINT chk_ticket_detail_stock CHECK (stock >= 0),
+    ADD INDEX idx_ticket_detail_continent_name (continent_name),
+    ADD INDEX idx_ticket_detail_type (type),
+    ADD CONSTRAINT fk_ticket_detail_event FOREIGN KEY (event_id) REFERENCES event (event_id);
+
+ALTER TABLE event
+    MODIFY updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP;
+
+ALTER TABLE reservation
+    MODIFY updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP;
@@ -0,0 +1,17 @@
+ALTER TABLE ticket_detail DROP FOREIGN KEY fk_ticket_detail_venue;
+
+ALTER TABLE ticket_detail DROP FOREIGN KEY fk_ticket_detail_continent;
+
+ALTER TABLE ticket_detail
+    DROP INDEX fk_ticket_detail_venue,
+    DROP INDEX idx_ticket_detail_continent_id,
+    DROP COLUMN venue_id,
+    DROP COLUMN continent_id;
+
+DROP TABLE IF EXISTS venue;
+
+DROP TABLE IF EXISTS city;
+
+DROP TABLE IF EXISTS country;
+
+DROP TABLE IF EXISTS continent;
@@ -0,0 +1,343 @@
+CREATE TABLE continent (
+    continent_id INT AUTO_INCREMENT PRIMARY KEY,
+    code CHAR(2) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_continent_code (code),
+    UNIQUE KEY uq_continent_name (name)
+);
+
+CREATE TABLE country (
+    country_id INT AUTO_INCREMENT PRIMARY KEY,
+    continent_id INT NOT NULL,
+    code CHAR(2) NOT NULL,
+    code_alpha3 CHAR(3) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_country_code (code),
+    UNIQUE KEY uq_country_code_alpha3 (code_alpha3),
+    CONSTRAINT fk_country_continent FOREIGN KEY (continent_id) REFERENCES continent (continent_id)
+);
+
+CREATE TABLE city (
+    city_id INT AUTO_INCREMENT PRIMARY KEY,
+    country_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_city_country_name (country_id, name),
+    CONSTRAINT fk_city_country FOREIGN KEY (country_id) REFERENCES country (country_id)
+);
+
+CREATE TABLE venue (
+    venue_id INT AUTO_INCREMENT PRIMARY KEY,
+    city_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_venue_city_name (city_id, name),
+    CONSTRAINT fk_venue_city FOREIGN KEY (city_id) REFERENCES city (city_id)
+);
+
+INSERT INTO continent (continent_id, code, name) VALUES
+    (1, 'AF', 'Africa'),
+    (2, 'AN', 'Antarctica'),
+    (3, 'AS', 'Asia'),
+    (4, 'EU', 'Europe'),
+    (5, 'NA', 'North America'),
+    (6, 'OC', 'Oceania'),
+    (7, 'SA', 'South America');
+
+-- ISO 3166-1 countries.
+INSERT INTO country (continent_id, code, code_alpha3, name) VALUES
+    (4, 'AD', 'AND', 'Andorra'),
+    (3, 'AE', 'ARE', 'United Arab Emirates'),
+    (3, 'AF', 'AFG', 'Afghanistan'),
+    (5, 'AG', 'ATG', 'Antigua and Barbuda'),
+    (5, 'AI', 'AIA', 'Anguilla'),
+    (4, 'AL', 'ALB', 'Albania'),
+    (3, 'AM', 'ARM', 'Armenia'),
+    (1, 'AO', 'AGO', 'Angola'),
+    (2, 'AQ', 'ATA', 'Antarctica'),
+    (7, 'AR', 'ARG', 'Argentina'),
+    (6, 'AS', 'ASM', 'American Samoa'),
+    (4, 'AT', 'AUT', 'Austria'),
+    (6, 'AU', 'AUS', 'Australia'),
+    (5, 'AW', 'ABW', 'Aruba'),
+    (4, 'AX', 'ALA', 'Åland Islands'),
+    (3, 'AZ', 'AZE', 'Azerbaijan'),
+    (4, 'BA', 'BIH', 'Bosnia and Herzegovina'),
+    (5, 'BB', 'BRB', 'Barbados'),
+    (3, 'BD', 'BGD', 'Bangladesh'),
+    (4, 'BE', 'BEL', 'Belgium'),
+    (1, 'BF', 'BFA', 'Burkina Faso'),
+    (4, 'BG', 'BGR', 'Bulgaria'),
+    (3, 'BH', 'BHR', 'Bahrain'),
+    (1, 'BI', 'BDI', 'Burundi'),
+    (1, 'BJ', 'BEN', 'Benin'),
+    (5, 'BL', 'BLM', 'Saint Barthélemy'),
+    (5, 'BM', 'BMU', 'Bermuda'),
+    (3, 'BN', 'BRN', 'Brunei Darussalam'),
+    (7, 'BO', 'BOL', 'Bolivia'),
+    (5, 'BQ', 'BES', 'Bonaire, Sint Eustatius and Saba'),
+    (7, 'BR', 'BRA', 'Brazil'),
+    (5, 'BS', 'BHS', 'Bahamas'),
+    (3, 'BT', 'BTN', 'Bhutan'),
+    (2, 'BV', 'BVT', 'Bouvet Island'),
+    (1, 'BW', 'BWA', 'Botswana'),
+    (4, 'BY', 'BLR', 'Belarus'),
+    (5, 'BZ', 'BLZ', 'Belize'),
+    (5, 'CA', 'CAN', 'Canada'),
+    (3, 'CC', 'CCK', 'Cocos (Keeling) Islands'),
+    (1, 'CD', 'COD', 'Congo, The Democratic Republic of the'),
+    (1, 'CF', 'CAF', 'Central African Republic'),
+    (1, 'CG', 'COG', 'Congo'),
+    (4, 'CH', 'CHE', 'Switzerland'),
+    (1, 'CI', 'CIV', 'Côte d''Ivoire'),
+    (6, 'CK', 'COK', 'Cook Islands'),
+    (7, 'CL', 'CHL', 'Chile'),
+    (1, 'CM', 'CMR', 'Cameroon'),
+    (3, 'CN', 'CHN', 'China'),
+    (7, 'CO', 'COL', 'Colombia'),
+    (5, 'CR', 'CRI', 'Costa Rica'),
+    (5, 'CU', 'CUB', 'Cuba'),
+    (1, 'CV', 'CPV', 'Cabo Verde'),
+    (5, 'CW', 'CUW', 'Curaçao'),
+    (3, 'CX', 'CXR', 'Christmas Island'),
+    (3, 'CY', 'CYP', 'Cyprus'),
+    (4, 'CZ', 'CZE', 'Czechia'),
+    (4, 'DE', 'DEU', 'Germany'),
+    (1, 'DJ', 'DJI', 'Djibouti'),
+    (4, 'DK', 'DNK', 'Denmark'),
+    (5, 'DM', 'DMA', 'Dominica'),
+    (5, 'DO', 'DOM', 'Dominican Republic'),
+    (1, 'DZ', 'DZA', 'Algeria'),
+    (7, 'EC', 'ECU', 'Ecuador'),
+    (4, 'EE', 'EST', 'Estonia'),
+    (1, 'EG', 'EGY', 'Egypt'),
+    (1, 'EH', 'ESH', 'Western Sahara'),
+    (1, 'ER', 'ERI', 'Eritrea'),
+    (4, 'ES', 'ESP', 'Spain'),
+    (1, 'ET', 'ETH', 'Ethiopia'),
+    (4, 'FI', 'FIN', 'Finland'),
+    (6, 'FJ', 'FJI', 'Fiji'),
+    (7, 'FK', 'FLK', 'Falkland Islands (Malvinas)'),
+    (6, 'FM', 'FSM', 'Micronesia, Federated States of'),
+    (4, 'FO', 'FRO', 'Faroe Islands'),
+    (4, 'FR', 'FRA', 'France'),
+    (1, 'GA', 'GAB', 'Gabon'),
+    (4, 'GB', 'GBR', 'United Kingdom'),
+    (5, 'GD', 'GRD', 'Grenada'),
+    (3, 'GE', 'GEO', 'Georgia'),
+    (7, 'GF', 'GUF', 'French Guiana'),
+    (4, 'GG', 'GGY', 'Guernsey'),
+    (1, 'GH', 'GHA', 'Ghana'),
+    (4, 'GI', 'GIB', 'Gibraltar'),
+    (5, 'GL', 'GRL', 'Greenland'),
+    (1, 'GM', 'GMB', 'Gambia'),
+    (1, 'GN', 'GIN', 'Guinea'),
+    (5, 'GP', 'GLP', 'Guadeloupe'),
+    (1, 'GQ', 'GNQ', 'Equatorial Guinea'),
+    (4, 'GR', 'GRC', 'Greece'),
+    (7, 'GS', 'SGS', 'South Georgia and the South Sandwich Islands'),
+    (5, 'GT', 'GTM', 'Guatemala'),
+    (6, 'GU', 'GUM', 'Guam'),
+    (1, 'GW', 'GNB', 'Guinea-Bissau'),
+    (7, 'GY', 'GUY', 'Guyana'),
+    (3, 'HK', 'HKG', 'Hong Kong'),
+    (2, 'HM', 'HMD', 'Heard Island and McDonald Islands'),
+    (5, 'HN', 'HND', 'Honduras'),
+    (4, 'HR', 'HRV', 'Croatia'),
+    (5, 'HT', 'HTI', 'Haiti'),
+    (4, 'HU', 'HUN', 'Hungary'),
+    (3, 'ID', 'IDN', 'Indonesia'),
+    (4, 'IE', 'IRL', 'Ireland'),
+    (3, 'IL', 'ISR', 'Israel'),
+    (4, 'IM', 'IMN', 'Isle of Man'),
+    (3, 'IN', 'IND', 'India'),
+    (3, 'IO', 'IOT', 'British Indian Ocean Territory'),
+    (3, 'IQ', 'IRQ', 'Iraq'),
+    (3, 'IR', 'IRN', 'Iran'),
+    (4, 'IS', 'ISL', 'Iceland'),
+    (4, 'IT', 'ITA', 'Italy'),
+    (4, 'JE', 'JEY', 'Jersey'),
+    (5, 'JM', 'JAM', 'Jamaica'),
+    (3, 'JO', 'JOR', 'Jordan'),
+    (3, 'JP', 'JPN', 'Japan'),
+    (1, 'KE', 'KEN', 'Kenya'),
+    (3, 'KG', 'KGZ', 'Kyrgyzstan'),
+    (3, 'KH', 'KHM', 'Cambodia'),
+    (6, 'KI', 'KIR', 'Kiribati'),
+    (1, 'KM', 'COM', 'Comoros'),
+    (5, 'KN', 'KNA', 'Saint Kitts and Nevis'),
+    (3, 'KP', 'PRK', 'North Korea'),
+    (3, 'KR', 'KOR', 'South Korea'),
+    (3, 'KW', 'KWT', 'Kuwait'),
+    (5, 'KY', 'CYM', 'Cayman Islands'),
+    (3, 'KZ', 'KAZ', 'Kazakhstan'),
+    (3, 'LA', 'LAO', 'Laos'),
+    (3, 'LB', 'LBN', 'Lebanon'),
+    (5, 'LC', 'LCA', 'Saint Lucia'),
+    (4, 'LI', 'LIE', 'Liechtenstein'),
+    (3, 'LK', 'LKA', 'Sri Lanka'),
+    (1, 'LR', 'LBR', 'Liberia'),
+    (1, 'LS', 'LSO', 'Lesotho'),
+    (4, 'LT', 'LTU', 'Lithuania'),
+    (4, 'LU', 'LUX', 'Luxembourg'),
+    (4, 'LV', 'LVA', 'Latvia'),
+    (1, 'LY', 'LBY', 'Libya'),
+    (1, 'MA', 'MAR', 'Morocco'),
+    (4, 'MC', 'MCO', 'Monaco'),
+    (4, 'MD', 'MDA', 'Moldova'),
+    (4, 'ME', 'MNE', 'Montenegro'),
+    (5, 'MF', 'MAF', 'Saint Martin (French part)'),
+    (1, 'MG', 'MDG', 'Madagascar'),
+    (6, 'MH', 'MHL', 'Marshall Islands'),
+    (4, 'MK', 'MKD', 'North Macedonia'),
+    (1, 'ML', 'MLI', 'Mali'),
+    (3, 'MM', 'MMR', 'Myanmar'),
+    (3, 'MN', 'MNG', 'Mongolia'),
+    (3, 'MO', 'MAC', 'Macao'),
+    (6, 'MP', 'MNP', 'Northern Mariana Islands'),
+    (5, 'MQ', 'MTQ', 'Martinique'),
+    (1, 'MR', 'MRT', 'Mauritania'),
+    (5, 'MS', 'MSR', 'Montserrat'),
+    (4, 'MT', 'MLT', 'Malta'),
+    (1, 'MU', 'MUS', 'Mauritius'),
+    (3, 'MV', 'MDV', 'Maldives'),
+    (1, 'MW', 'MWI', 'Malawi'),
+    (5, 'MX', 'MEX', 'Mexico'),
+    (3, 'MY', 'MYS', 'Malaysia'),
+    (1, 'MZ', 'MOZ', 'Mozambique'),
+    (1, 'NA', 'NAM', 'Namibia'),
+    (6, 'NC', 'NCL', 'New Caledonia'),
+    (1, 'NE', 'NER', 'Niger'),
+    (6, 'NF', 'NFK', 'Norfolk Island'),
+    (1, 'NG', 'NGA', 'Nigeria'),
+    (5, 'NI', 'NIC', 'Nicaragua'),
+    (4, 'NL', 'NLD', 'Netherlands'),
+    (4, 'NO', 'NOR', 'Norway'),
+    (3, 'NP', 'NPL', 'Nepal'),
+    (6, 'NR', 'NRU', 'Nauru'),
+    (6, 'NU', 'NIU', 'Niue'),
+    (6, 'NZ', 'NZL', 'New Zealand'),
+    (3, 'OM', 'OMN', 'Oman'),
+    (5, 'PA', 'PAN', 'Panama'),
+    (7, 'PE', 'PER', 'Peru'),
+    (6, 'PF', 'PYF', 'French Polynesia'),
+    (6, 'PG', 'PNG', 'Papua New Guinea'),
+    (3, 'PH', 'PHL', 'Philippines'),
+    (3, 'PK', 'PAK', 'Pakistan'),
+    (4, 'PL', 'POL', 'Poland'),
+    (5, 'PM', 'SPM', 'Saint Pierre and Miquelon'),
+    (6, 'PN', 'PCN', 'Pitcairn'),
+    (5, 'PR', 'PRI', 'Puerto Rico'),
+    (3, 'PS', 'PSE', 'Palestine, State of'),
+    (4, 'PT', 'PRT', 'Portugal'),
+    (6, 'PW', 'PLW', 'Palau'),
+    (7, 'PY', 'PRY', 'Paraguay'),
+    (3, 'QA', 'QAT', 'Qatar'),
+    (1, 'RE', 'REU', 'Réunion'),
+    (4, 'RO', 'ROU', 'Romania'),
+    (4, 'RS', 'SRB', 'Serbia'),
+    (4, 'RU', 'RUS', 'Russian Federation'),
+    (1, 'RW', 'RWA', 'Rwanda'),
+    (3, 'SA', 'SAU', 'Saudi Arabia'),
+    (6, 'SB', 'SLB', 'Solomon Islands'),
+    (1, 'SC', 'SYC', 'Seychelles'),
+    (1, 'SD', 'SDN', 'Sudan'),
+    (4, 'SE', 'SWE', 'Sweden'),
+    (3, 'SG', 'SGP', 'Singapore'),
+    (1, 'SH', 'SHN', 'Saint Helena, Ascension and Tristan da Cunha'),
+    (4, 'SI', 'SVN', 'Slovenia'),
+    (4, 'SJ', 'SJM', 'Svalbard and Jan Mayen'),
+    (4, 'SK', 'SVK', 'Slovakia'),
+    (1, 'SL', 'SLE', 'Sierra Leone'),
+    (4, 'SM', 'SMR', 'San Marino'),
+    (1, 'SN', 'SEN', 'Senegal'),
+    (1, 'SO', 'SOM', 'Somalia'),
+    (7, 'SR', 'SUR', 'Suriname'),
+    (1, 'SS', 'SSD', 'South Sudan'),
+    (1, 'ST', 'STP', 'Sao Tome and Principe'),
+    (5, 'SV', 'SLV', 'El Salvador'),
+    (5, 'SX', 'SXM', 'Sint Maarten (Dutch part)'),
+    (3, 'SY', 'SYR', 'Syria'),
+    (1, 'SZ', 'SWZ', 'Eswatini'),
+    (5, 'TC', 'TCA', 'Turks and Caicos Islands'),
+    (1, 'TD', 'TCD', 'Chad'),
+    (2, 'TF', 'ATF', 'French Southern Territories'),
+    (1, 'TG', 'TGO', 'Togo'),
+    (3, 'TH', 'THA', 'Thailand'),
+    (3, 'TJ', 'TJK', 'Tajikistan'),
+    (6, 'TK', 'TKL', 'Tokelau'),
+    (3, 'TL', 'TLS', 'Timor-Leste'),
+    (3, 'TM', 'TKM', 'Turkmenistan'),
+    (1, 'TN', 'TUN', 'Tunisia'),
+    (6, 'TO', 'TON', 'Tonga'),
+    (4, 'TR', 'TUR', 'Türkiye'),
+    (5, 'TT', 'TTO', 'Trinidad and Tobago'),
+    (6, 'TV', 'TUV', 'Tuvalu'),
+    (3, 'TW', 'TWN', 'Taiwan'),
+    (1, 'TZ', 'TZA', 'Tanzania'),
+    (4, 'UA', 'UKR', 'Ukraine'),
+    (1, 'UG', 'UGA', 'Uganda'),
+    (6, 'UM', 'UMI', 'United States Minor Outlying Islands'),
+    (5, 'US', 'USA', 'United States'),
+    (7, 'UY', 'URY', 'Uruguay'),
+    (3, 'UZ', 'UZB', 'Uzbekistan'),
+    (4, 'VA', 'VAT', 'Holy See (Vatican City State)'),
+    (5, 'VC', 'VCT', 'Saint Vincent and the Grenadines'),
+    (7, 'VE', 'VEN', 'Venezuela'),
+    (5, 'VG', 'VGB', 'Virgin Islands, British'),
+    (5, 'VI', 'VIR', 'Virgin Islands, U.S.'),
+    (3, 'VN', 'VNM', 'Vietnam'),
+    (6, 'VU', 'VUT', 'Vanuatu'),
+    (6, 'WF', 'WLF', 'Wallis and Futuna'),
+    (6, 'WS', 'WSM', 'Samoa'),
+    (3, 'YE', 'YEM', 'Yemen'),
+    (1, 'YT', 'MYT', 'Mayotte'),
+    (1, 'ZA', 'ZAF', 'South Africa'),
+    (1, 'ZM', 'ZMB', 'Zambia'),
+    (1, 'ZW', 'ZWE', 'Zimbabwe');
+
+ALTER TABLE ticket_detail
+    ADD COLUMN continent_id INT NULL,
+    ADD COLUMN venue_id INT NULL,
+    ADD INDEX idx_ticket_detail_continent_id (continent_id),
+    ADD CONSTRAINT fk_ticket_detail_continent FOREIGN KEY (continent_id) REFERENCES continent (continent_id),
+    ADD CONSTRAINT fk_ticket_detail_venue FOREIGN KEY (venue_id) REFERENCES venue (venue_id);
+
+-- Link existing tickets by matching the free text case-insensitively against
+-- the canonical names and codes, and rewrite the text in its canonical form.
+-- Rows that match nothing keep their text and stay unlinked; the service
+-- reads that text back for them and counts them as "Unknown" by continent.
+UPDATE ticket_detail td
+    JOIN continent c ON LOWER(TRIM(td.continent_name)) IN (LOWER(c.name), LOWER(c.code))
+    SET td.continent_id = c.continent_id, td.continent_name = c.name;
+
+INSERT IGNORE INTO city (country_id, name)
+    SELECT DISTINCT co.country_id, TRIM(td.country_city)
+    FROM ticket_detail td
+    JOIN country co ON LOWER(TRIM(td.country_name)) IN (LOWER(co.name), LOWER(co.code), LOWER(co.code_alpha3))
+    WHERE TRIM(COALESCE(td.country_city, '')) <> '';
+
+INSERT IGNORE INTO venue (city_id, name)
+    SELECT DISTINCT ci.city_id, TRIM(td.country_place)
+    FROM ticket_detail td
+    JOIN country co ON LOWER(TRIM(td.country_name)) IN (LOWER(co.name), LOWER(co.code), LOWER(co.code_alpha3))
+    JOIN city ci ON ci.country_id = co.country_id AND ci.name = TRIM(td.country_city)
+    WHERE TRIM(COALESCE(td.country_place, '')) <> '';
+
+UPDATE ticket_detail td
+    JOIN country co ON LOWER(TRIM(td.country_name)) IN (LOWER(co.name), LOWER(co.code), LOWER(co.code_alpha3))
+    JOIN city ci ON ci.country_id = co.country_id AND ci.name = TRIM(td.country_city)
+    JOIN venue v ON v.city_id = ci.city_id AND v.name = TRIM(td.country_place)
+    SET td.venue_id = v.venue_id, td.country_name = co.name, td.country_city = ci.name, td.country_place = v.name;
+
+UPDATE ticket_detail td
+    JOIN country co ON LOWER(TRIM(td.country_name)) IN (LOWER(co.name), LOWER(co.code), LOWER(co.code_alpha3))
+    JOIN continent c ON c.continent_id = co.continent_id
+    SET td.continent_id = c.continent_id, td.continent_name = c.name
+    WHERE td.continent_id IS NULL;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS event_seat;
+
+DROP TABLE IF EXISTS venue_seat;
+
+DROP TABLE IF EXISTS venue_section;
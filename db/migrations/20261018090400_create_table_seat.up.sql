@@ -0,0 +1,43 @@
+CREATE TABLE venue_section (
+    section_id INT AUTO_INCREMENT PRIMARY KEY,
+    venue_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_venue_section_venue_name (venue_id, name),
+    CONSTRAINT fk_venue_section_venue FOREIGN KEY (venue_id) REFERENCES venue (venue_id)
+);
+
+CREATE TABLE venue_seat (
+    seat_id INT AUTO_INCREMENT PRIMARY KEY,
+    section_id INT NOT NULL,
+    row_label VARCHAR(10) NOT NULL,
+    seat_number INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_venue_seat_position (section_id, row_label, seat_number),
+    CONSTRAINT fk_venue_seat_section FOREIGN KEY (section_id) REFERENCES venue_section (section_id)
+);
+
+-- event_seat is the sellable inventory of a seated event. Every seat belongs to
+-- the ticket_detail tier it is priced and counted under, so holding seats also
+-- moves that tier's stock like any count-based reservation.
+CREATE TABLE event_seat (
+    event_seat_id INT AUTO_INCREMENT PRIMARY KEY,
+    event_id INT NOT NULL,
+    seat_id INT NOT NULL,
+    ticket_detail_id INT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'available',
+    order_id VARCHAR(100) NULL,
+    email VARCHAR(255) NULL,
+    hold_expires_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_event_seat_event_seat (event_id, seat_id),
+    INDEX idx_event_seat_order_id (order_id),
+    INDEX idx_event_seat_status_expiry (status, hold_expires_at),
+    CONSTRAINT chk_event_seat_status CHECK (status IN ('available', 'held', 'sold')),
+    CONSTRAINT fk_event_seat_event FOREIGN KEY (event_id) REFERENCES event (event_id),
+    CONSTRAINT fk_event_seat_seat FOREIGN KEY (seat_id) REFERENCES venue_seat (seat_id),
+    CONSTRAINT fk_event_seat_ticket_detail FOREIGN KEY (ticket_detail_id) REFERENCES ticket_detail (ticket_detail_id)
+);
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS ticket_price;
+
+ALTER TABLE ticket_detail DROP CONSTRAINT chk_ticket_detail_price;
+
+UPDATE ticket_detail SET price = price DIV 100;
+
+ALTER TABLE ticket_detail
+    DROP COLUMN currency,
+    MODIFY price INT NULL;
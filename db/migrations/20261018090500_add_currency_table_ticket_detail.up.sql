@@ -0,0 +1,31 @@
+-- Prices become amounts in the minor unit of an ISO 4217 currency. Existing
+-- prices were whole rupiah, so they are scaled to sen and tagged IDR. A
+-- ticket without a price is set to 0 first: MODIFY to NOT NULL would fail on
+-- it in strict mode and coerce it silently otherwise. Such a ticket could not
+-- be read before, and now reads as free until it is priced.
+UPDATE ticket_detail SET price = 0 WHERE price IS NULL;
+
+ALTER TABLE ticket_detail
+    MODIFY price BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR' AFTER price;
+
+UPDATE ticket_detail SET price = price * 100;
+
+ALTER TABLE ticket_detail
+    ADD CONSTRAINT chk_ticket_detail_price CHECK (price >= 0);
+
+-- ticket_price is the per-region price list: buyers in country_id pay amount
+-- in currency instead of the ticket_detail list price.
+CREATE TABLE ticket_price (
+    ticket_price_id INT AUTO_INCREMENT PRIMARY KEY,
+    ticket_detail_id INT NOT NULL,
+    country_id INT NOT NULL,
+    amount BIGINT NOT NULL,
+    currency CHAR(3) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_ticket_price_ticket_country (ticket_detail_id, country_id),
+    CONSTRAINT chk_ticket_price_amount CHECK (amount >= 0),
+    CONSTRAINT fk_ticket_price_ticket_detail FOREIGN KEY (ticket_detail_id) REFERENCES ticket_detail (ticket_detail_id),
+    CONSTRAINT fk_ticket_price_country FOREIGN KEY (country_id) REFERENCES country (country_id)
+);
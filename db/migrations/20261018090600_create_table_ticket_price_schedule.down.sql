@@ -0,0 +1,6 @@
+ALTER TABLE reservation
+    DROP COLUMN price_tier,
+    DROP COLUMN currency,
+    DROP COLUMN unit_price;
+
+DROP TABLE IF EXISTS ticket_price_schedule;
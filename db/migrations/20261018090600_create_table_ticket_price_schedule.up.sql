@@ -0,0 +1,33 @@
+-- ticket_price_schedule holds the price tiers of a ticket type, e.g. early-bird,
+-- regular and last-minute. A tier is active while now is inside its optional
+-- [effective_from, effective_to) window and the remaining stock is inside its
+-- optional [min_stock, max_stock] range; the active tier with the highest
+-- priority replaces the list price. Prices are in the ticket's currency.
+CREATE TABLE ticket_price_schedule (
+    schedule_id INT AUTO_INCREMENT PRIMARY KEY,
+    ticket_detail_id INT NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    price BIGINT NOT NULL,
+    effective_from TIMESTAMP NULL,
+    effective_to TIMESTAMP NULL,
+    min_stock INT NULL,
+    max_stock INT NULL,
+    priority INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_ticket_price_schedule_ticket (ticket_detail_id, priority),
+    CONSTRAINT chk_ticket_price_schedule_price CHECK (price >= 0),
+    CONSTRAINT chk_ticket_price_schedule_window CHECK (effective_to IS NULL OR effective_from IS NULL OR effective_to > effective_from),
+    CONSTRAINT fk_ticket_price_schedule_ticket_detail FOREIGN KEY (ticket_detail_id) REFERENCES ticket_detail (ticket_detail_id)
+);
+
+-- reservations record the unit price locked in when they were made. Existing
+-- rows are backfilled with their ticket's current list price.
+ALTER TABLE reservation
+    ADD COLUMN unit_price BIGINT NOT NULL DEFAULT 0 AFTER quantity,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR' AFTER unit_price,
+    ADD COLUMN price_tier VARCHAR(50) NULL AFTER currency;
+
+UPDATE reservation r
+    JOIN ticket_detail td ON r.ticket_detail_id = td.ticket_detail_id
+    SET r.unit_price = td.price, r.currency = td.currency;
@@ -0,0 +1,7 @@
+ALTER TABLE reservation
+    DROP COLUMN discount,
+    DROP COLUMN promo_code;
+
+DROP TABLE IF EXISTS promo_redemption;
+
+DROP TABLE IF EXISTS promo_code;
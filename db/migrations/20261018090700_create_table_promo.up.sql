@@ -0,0 +1,49 @@
+-- promo_code is a discount code. Percent discounts store the percentage in
+-- discount_value; fixed discounts store an amount in the minor unit of
+-- currency. A NULL event_id makes the code valid for every event.
+CREATE TABLE promo_code (
+    promo_id INT AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(50) NOT NULL,
+    discount_type VARCHAR(10) NOT NULL,
+    discount_value BIGINT NOT NULL,
+    currency CHAR(3) NULL,
+    event_id INT NULL,
+    max_uses INT NULL,
+    used_count INT NOT NULL DEFAULT 0,
+    once_per_user BOOLEAN NOT NULL DEFAULT TRUE,
+    starts_at TIMESTAMP NULL,
+    expires_at TIMESTAMP NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_promo_code_code (code),
+    CONSTRAINT chk_promo_code_discount CHECK (
+        (discount_type = 'percent' AND discount_value BETWEEN 1 AND 100)
+        OR (discount_type = 'fixed' AND discount_value > 0 AND currency IS NOT NULL)
+    ),
+    CONSTRAINT chk_promo_code_used_count CHECK (used_count >= 0 AND (max_uses IS NULL OR used_count <= max_uses)),
+    CONSTRAINT fk_promo_code_event FOREIGN KEY (event_id) REFERENCES event (event_id)
+);
+
+-- promo_redemption records each use of a code by an order. A use is held while
+-- the order is pending and counts against max_uses; it is redeemed when the
+-- order is paid and released, giving the use back, when the order fails.
+CREATE TABLE promo_redemption (
+    redemption_id INT AUTO_INCREMENT PRIMARY KEY,
+    promo_id INT NOT NULL,
+    order_id VARCHAR(100) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    discount BIGINT NOT NULL,
+    currency CHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_promo_redemption_order (order_id),
+    INDEX idx_promo_redemption_promo_email (promo_id, email),
+    CONSTRAINT chk_promo_redemption_status CHECK (status IN ('held', 'redeemed', 'released')),
+    CONSTRAINT fk_promo_redemption_promo FOREIGN KEY (promo_id) REFERENCES promo_code (promo_id)
+);
+
+ALTER TABLE reservation
+    ADD COLUMN promo_code VARCHAR(50) NULL AFTER price_tier,
+    ADD COLUMN discount BIGINT NOT NULL DEFAULT 0 AFTER promo_code;
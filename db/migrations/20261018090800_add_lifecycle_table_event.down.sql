@@ -0,0 +1,7 @@
+ALTER TABLE event
+    DROP CONSTRAINT chk_event_sale_window,
+    DROP CONSTRAINT chk_event_status,
+    DROP INDEX idx_event_status,
+    DROP COLUMN sale_ends_at,
+    DROP COLUMN sale_starts_at,
+    DROP COLUMN status;
@@ -0,0 +1,18 @@
+-- event status drives whether its tickets can be bought:
+--   draft     not published yet
+--   on_sale   tickets can be bought inside the optional
+--             [sale_starts_at, sale_ends_at) window
+--   paused    sales stopped by an operator
+--   sold_out  no stock left on any ticket type, reopens when stock returns
+--   cancelled the event will not take place
+--   past      the event date has passed
+-- Existing events were all on sale, so they start on_sale or past.
+ALTER TABLE event
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'draft' AFTER description,
+    ADD COLUMN sale_starts_at TIMESTAMP NULL AFTER status,
+    ADD COLUMN sale_ends_at TIMESTAMP NULL AFTER sale_starts_at,
+    ADD INDEX idx_event_status (status),
+    ADD CONSTRAINT chk_event_status CHECK (status IN ('draft', 'on_sale', 'paused', 'sold_out', 'cancelled', 'past')),
+    ADD CONSTRAINT chk_event_sale_window CHECK (sale_ends_at IS NULL OR sale_starts_at IS NULL OR sale_ends_at > sale_starts_at);
+
+UPDATE event SET status = IF(date IS NOT NULL AND date <= CURRENT_TIMESTAMP, 'past', 'on_sale');
@@ -0,0 +1,6 @@
+ALTER TABLE reservation
+    DROP INDEX idx_reservation_event_status;
+
+ALTER TABLE event
+    DROP COLUMN cancelled_at,
+    DROP COLUMN cancel_reason;
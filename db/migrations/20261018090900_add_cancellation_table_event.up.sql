@@ -0,0 +1,8 @@
+-- a cancelled event keeps why and when it was cancelled. Its confirmed
+-- reservations move to refund_pending until the payment service refunds them.
+ALTER TABLE event
+    ADD COLUMN cancel_reason VARCHAR(500) NULL AFTER sale_ends_at,
+    ADD COLUMN cancelled_at TIMESTAMP NULL AFTER cancel_reason;
+
+ALTER TABLE reservation
+    ADD INDEX idx_reservation_event_status (event_id, status);
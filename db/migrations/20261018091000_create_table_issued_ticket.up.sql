@@ -0,0 +1,22 @@
+-- issued_ticket is one admission per confirmed unit of a reservation. The code
+-- is random and unique; the QR payload built from it is signed by the service,
+-- so it is never stored. unit_no numbers the units of a reservation and keeps
+-- issuing idempotent when a confirmation is delivered twice.
+CREATE TABLE issued_ticket (
+    issued_ticket_id INT AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(32) NOT NULL,
+    reservation_id INT NOT NULL,
+    unit_no INT NOT NULL,
+    order_id VARCHAR(100) NOT NULL,
+    ticket_detail_id INT NOT NULL,
+    event_id INT NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'valid',
+    issued_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_issued_ticket_code (code),
+    UNIQUE KEY uq_issued_ticket_reservation_unit (reservation_id, unit_no),
+    INDEX idx_issued_ticket_email (email),
+    CONSTRAINT chk_issued_ticket_status CHECK (status IN ('valid', 'void')),
+    CONSTRAINT fk_issued_ticket_reservation FOREIGN KEY (reservation_id) REFERENCES reservation (reservation_id)
+);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS ticket_scan;
+
+ALTER TABLE issued_ticket
+    DROP COLUMN checked_in_gate,
+    DROP COLUMN checked_in_at;
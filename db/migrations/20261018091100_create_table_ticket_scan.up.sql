@@ -0,0 +1,22 @@
+-- an issued ticket is checked in by the first accepted scan. Every scan,
+-- accepted or not, is kept in ticket_scan; scan_ref is the id a scanner gives
+-- a scan taken offline so uploading the same batch twice records it once.
+ALTER TABLE issued_ticket
+    ADD COLUMN checked_in_at TIMESTAMP NULL AFTER status,
+    ADD COLUMN checked_in_gate VARCHAR(50) NULL AFTER checked_in_at;
+
+CREATE TABLE ticket_scan (
+    scan_id INT AUTO_INCREMENT PRIMARY KEY,
+    scan_ref VARCHAR(64) NULL,
+    code VARCHAR(32) NULL,
+    event_id INT NOT NULL,
+    gate VARCHAR(50) NOT NULL,
+    result VARCHAR(20) NOT NULL,
+    offline BOOLEAN NOT NULL DEFAULT FALSE,
+    scanned_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_ticket_scan_scan_ref (scan_ref),
+    INDEX idx_ticket_scan_code (code),
+    INDEX idx_ticket_scan_event_scanned (event_id, scanned_at),
+    CONSTRAINT chk_ticket_scan_result CHECK (result IN ('accepted', 'duplicate', 'wrong_event', 'invalid', 'void'))
+);
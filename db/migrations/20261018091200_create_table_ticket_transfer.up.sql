@@ -0,0 +1,19 @@
+-- ticket_transfer is the ownership history of issued tickets: every offer to
+-- hand a ticket to another user is kept with how and when it ended. An
+-- accepted transfer moves the ticket to to_email under a new code.
+CREATE TABLE ticket_transfer (
+    transfer_id INT AUTO_INCREMENT PRIMARY KEY,
+    issued_ticket_id INT NOT NULL,
+    from_email VARCHAR(255) NOT NULL,
+    to_email VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    expires_at TIMESTAMP NOT NULL,
+    responded_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_ticket_transfer_ticket_status (issued_ticket_id, status),
+    INDEX idx_ticket_transfer_from_email (from_email),
+    INDEX idx_ticket_transfer_to_email (to_email),
+    INDEX idx_ticket_transfer_status_expiry (status, expires_at),
+    CONSTRAINT chk_ticket_transfer_status CHECK (status IN ('pending', 'accepted', 'declined', 'cancelled', 'expired')),
+    CONSTRAINT fk_ticket_transfer_issued_ticket FOREIGN KEY (issued_ticket_id) REFERENCES issued_ticket (issued_ticket_id)
+);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS resale_listing;
+
+ALTER TABLE event
+    DROP COLUMN resale_cap_percent;
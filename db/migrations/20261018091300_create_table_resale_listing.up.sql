@@ -0,0 +1,32 @@
+-- resale_cap_percent caps the resale price of an event's tickets at that
+-- percentage of what the seller paid for them; NULL keeps resale closed.
+ALTER TABLE event
+    ADD COLUMN resale_cap_percent SMALLINT UNSIGNED NULL AFTER max_ticket_per_user;
+
+-- resale_listing offers an issued ticket for resale. price goes to the seller
+-- and fee to the platform; the buyer pays both. A buyer reserves an active
+-- listing under an order, and once the order is paid the ticket moves to the
+-- buyer under a new code and the listing is sold.
+CREATE TABLE resale_listing (
+    listing_id INT AUTO_INCREMENT PRIMARY KEY,
+    issued_ticket_id INT NOT NULL,
+    event_id INT NOT NULL,
+    ticket_detail_id INT NOT NULL,
+    seller_email VARCHAR(255) NOT NULL,
+    price BIGINT NOT NULL,
+    fee BIGINT NOT NULL,
+    currency CHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    order_id VARCHAR(100) NULL,
+    buyer_email VARCHAR(255) NULL,
+    sold_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_resale_listing_order_id (order_id),
+    INDEX idx_resale_listing_event_status (event_id, status),
+    INDEX idx_resale_listing_ticket_status (issued_ticket_id, status),
+    INDEX idx_resale_listing_seller_email (seller_email),
+    CONSTRAINT chk_resale_listing_status CHECK (status IN ('active', 'reserved', 'sold', 'cancelled')),
+    CONSTRAINT chk_resale_listing_price CHECK (price > 0 AND fee >= 0),
+    CONSTRAINT fk_resale_listing_issued_ticket FOREIGN KEY (issued_ticket_id) REFERENCES issued_ticket (issued_ticket_id)
+);
@@ -0,0 +1,22 @@
+-- ticket_waitlist queues users for a sold out ticket type. When stock comes
+-- back it is set aside for the entries at the head of the queue, which are
+-- offered it until offer_expires_at. An offer that is not claimed in time
+-- returns its stock, which is then offered to the next entries.
+CREATE TABLE ticket_waitlist (
+    waitlist_id INT AUTO_INCREMENT PRIMARY KEY,
+    ticket_detail_id INT NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    quantity INT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'waiting',
+    offered_at TIMESTAMP NULL,
+    offer_expires_at TIMESTAMP NULL,
+    order_id VARCHAR(100) NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_ticket_waitlist_ticket_status (ticket_detail_id, status, waitlist_id),
+    INDEX idx_ticket_waitlist_email (email),
+    INDEX idx_ticket_waitlist_status_expiry (status, offer_expires_at),
+    CONSTRAINT chk_ticket_waitlist_status CHECK (status IN ('waiting', 'offered', 'claimed', 'expired', 'left')),
+    CONSTRAINT chk_ticket_waitlist_quantity CHECK (quantity > 0),
+    CONSTRAINT fk_ticket_waitlist_ticket_detail FOREIGN KEY (ticket_detail_id) REFERENCES ticket_detail (ticket_detail_id)
+);
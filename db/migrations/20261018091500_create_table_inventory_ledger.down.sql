@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trg_inventory_ledger_no_delete;
+DROP TRIGGER IF EXISTS trg_inventory_ledger_no_update;
+DROP TABLE IF EXISTS inventory_ledger;
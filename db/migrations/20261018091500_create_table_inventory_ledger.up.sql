@@ -0,0 +1,31 @@
+-- inventory_ledger records every change of the stock counters of
+-- ticket_detail, written in the same transaction as the change itself, so
+-- summing the deltas of a ticket gives back its counters. The counters as
+-- they stand now are recorded as an opening entry per ticket.
+CREATE TABLE inventory_ledger (
+    entry_id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    ticket_detail_id INT NOT NULL,
+    stock_ticket_delta INT NOT NULL DEFAULT 0,
+    stock_delta INT NOT NULL DEFAULT 0,
+    stock_ordered_delta INT NOT NULL DEFAULT 0,
+    reason VARCHAR(30) NOT NULL,
+    order_id VARCHAR(100) NULL,
+    waitlist_id INT NULL,
+    request_id VARCHAR(100) NULL,
+    actor VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_inventory_ledger_ticket (ticket_detail_id, entry_id),
+    INDEX idx_inventory_ledger_order (order_id),
+    CONSTRAINT fk_inventory_ledger_ticket_detail FOREIGN KEY (ticket_detail_id) REFERENCES ticket_detail (ticket_detail_id)
+);
+
+INSERT INTO inventory_ledger (ticket_detail_id, stock_ticket_delta, stock_delta, stock_ordered_delta, reason, actor)
+    SELECT ticket_detail_id, stock_ticket, stock, stock_ordered, 'opening', 'migration' FROM ticket_detail;
+
+-- the ledger is append-only: entries are corrected by new entries, never
+-- changed or removed.
+CREATE TRIGGER trg_inventory_ledger_no_update BEFORE UPDATE ON inventory_ledger FOR EACH ROW
+    SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'inventory_ledger is append-only';
+
+CREATE TRIGGER trg_inventory_ledger_no_delete BEFORE DELETE ON inventory_ledger FOR EACH ROW
+    SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'inventory_ledger is append-only';
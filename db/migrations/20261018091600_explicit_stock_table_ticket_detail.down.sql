@@ -0,0 +1,44 @@
+ALTER TABLE ticket_detail
+    ADD COLUMN stock_ticket INT NOT NULL DEFAULT 0 AFTER continent_name,
+    ADD COLUMN stock INT NOT NULL DEFAULT 0 AFTER stock_ticket,
+    ADD COLUMN stock_ordered INT NOT NULL DEFAULT 0 AFTER stock;
+
+UPDATE ticket_detail SET
+    stock_ticket = available + held,
+    stock = available,
+    stock_ordered = held + sold;
+
+ALTER TABLE ticket_detail DROP CHECK chk_ticket_detail_stock;
+
+ALTER TABLE ticket_detail
+    DROP INDEX idx_ticket_detail_available,
+    DROP COLUMN total,
+    DROP COLUMN available,
+    DROP COLUMN held,
+    DROP COLUMN sold,
+    ADD CONSTRAINT chk_ticket_detail_stock CHECK (stock >= 0);
+
+DROP TRIGGER trg_inventory_ledger_no_update;
+DROP TRIGGER trg_inventory_ledger_no_delete;
+
+ALTER TABLE inventory_ledger
+    ADD COLUMN stock_ticket_delta INT NOT NULL DEFAULT 0 AFTER ticket_detail_id,
+    ADD COLUMN stock_delta INT NOT NULL DEFAULT 0 AFTER stock_ticket_delta,
+    ADD COLUMN stock_ordered_delta INT NOT NULL DEFAULT 0 AFTER stock_delta;
+
+UPDATE inventory_ledger SET
+    stock_ticket_delta = available_delta + held_delta,
+    stock_delta = available_delta,
+    stock_ordered_delta = held_delta + sold_delta;
+
+ALTER TABLE inventory_ledger
+    DROP COLUMN total_delta,
+    DROP COLUMN available_delta,
+    DROP COLUMN held_delta,
+    DROP COLUMN sold_delta;
+
+CREATE TRIGGER trg_inventory_ledger_no_update BEFORE UPDATE ON inventory_ledger FOR EACH ROW
+    SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'inventory_ledger is append-only';
+
+CREATE TRIGGER trg_inventory_ledger_no_delete BEFORE DELETE ON inventory_ledger FOR EACH ROW
+    SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'inventory_ledger is append-only';
@@ -0,0 +1,56 @@
+-- The stock of a ticket is split into explicit counts, with
+--     total = available + held + sold
+-- where held is taken by unpaid orders and open waitlist offers and sold by
+-- paid ones. The old counters map onto them linearly: stock is available,
+-- stock_ticket - stock is held and stock_ordered - held is sold. Rows whose
+-- old counters had drifted are clamped to zero; their ledger keeps the
+-- unclamped sums, so reconciliation reports them.
+ALTER TABLE ticket_detail
+    ADD COLUMN total INT NOT NULL DEFAULT 0 AFTER continent_name,
+    ADD COLUMN available INT NOT NULL DEFAULT 0 AFTER total,
+    ADD COLUMN held INT NOT NULL DEFAULT 0 AFTER available,
+    ADD COLUMN sold INT NOT NULL DEFAULT 0 AFTER held;
+
+UPDATE ticket_detail SET
+    available = GREATEST(stock, 0),
+    held = GREATEST(stock_ticket - stock, 0),
+    sold = GREATEST(stock_ordered - stock_ticket + stock, 0),
+    total = available + held + sold;
+
+ALTER TABLE ticket_detail DROP CHECK chk_ticket_detail_stock;
+
+ALTER TABLE ticket_detail
+    DROP COLUMN stock_ticket,
+    DROP COLUMN stock,
+    DROP COLUMN stock_ordered,
+    ADD CONSTRAINT chk_ticket_detail_stock CHECK (available >= 0 AND held >= 0 AND sold >= 0
+        AND total = available + held + sold),
+    ADD INDEX idx_ticket_detail_available (available);
+
+-- the ledger is rewritten in the same terms; the mapping is linear, so every
+-- entry keeps the change it recorded.
+DROP TRIGGER trg_inventory_ledger_no_update;
+DROP TRIGGER trg_inventory_ledger_no_delete;
+
+ALTER TABLE inventory_ledger
+    ADD COLUMN total_delta INT NOT NULL DEFAULT 0 AFTER ticket_detail_id,
+    ADD COLUMN available_delta INT NOT NULL DEFAULT 0 AFTER total_delta,
+    ADD COLUMN held_delta INT NOT NULL DEFAULT 0 AFTER available_delta,
+    ADD COLUMN sold_delta INT NOT NULL DEFAULT 0 AFTER held_delta;
+
+UPDATE inventory_ledger SET
+    total_delta = stock_delta + stock_ordered_delta,
+    available_delta = stock_delta,
+    held_delta = stock_ticket_delta - stock_delta,
+    sold_delta = stock_ordered_delta - stock_ticket_delta + stock_delta;
+
+ALTER TABLE inventory_ledger
+    DROP COLUMN stock_ticket_delta,
+    DROP COLUMN stock_delta,
+    DROP COLUMN stock_ordered_delta;
+
+CREATE TRIGGER trg_inventory_ledger_no_update BEFORE UPDATE ON inventory_ledger FOR EACH ROW
+    SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'inventory_ledger is append-only';
+
+CREATE TRIGGER trg_inventory_ledger_no_delete BEFORE DELETE ON inventory_ledger FOR EACH ROW
+    SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'inventory_ledger is append-only';
@@ -0,0 +1,7 @@
+DELETE FROM ticket_transfer WHERE listing_id IS NOT NULL;
+
+ALTER TABLE ticket_transfer DROP FOREIGN KEY fk_ticket_transfer_resale_listing;
+
+ALTER TABLE ticket_transfer
+    DROP INDEX fk_ticket_transfer_resale_listing,
+    DROP COLUMN listing_id;
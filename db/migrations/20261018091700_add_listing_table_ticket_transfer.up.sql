@@ -0,0 +1,11 @@
+-- A resale hands a ticket over as well, so it is recorded in the ownership
+-- history as an accepted transfer of the listing. Listings sold before are
+-- added with their sale time.
+ALTER TABLE ticket_transfer
+    ADD COLUMN listing_id INT NULL AFTER issued_ticket_id,
+    ADD CONSTRAINT fk_ticket_transfer_resale_listing FOREIGN KEY (listing_id) REFERENCES resale_listing (listing_id);
+
+INSERT INTO ticket_transfer (issued_ticket_id, listing_id, from_email, to_email, status, expires_at, responded_at, created_at)
+    SELECT issued_ticket_id, listing_id, seller_email, buyer_email, 'accepted', sold_at, sold_at, sold_at
+    FROM resale_listing
+    WHERE status = 'sold';
@@ -0,0 +1,10 @@
+-- reservation_buyer has one row per buyer and event. A reservation locks the
+-- row of its buyer while it counts the tickets they already reserved for the
+-- event, so concurrent orders of one buyer can not together exceed the
+-- per-user limit.
+CREATE TABLE reservation_buyer (
+    event_id INT NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (event_id, email)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE reservation
+    DROP INDEX idx_reservation_status_expiry,
+    DROP COLUMN expires_at;
@@ -0,0 +1,6 @@
+-- A pending reservation holds its tickets until expires_at, after which it is
+-- released like an order whose payment failed. Reservations placed before
+-- have no expiry and keep waiting for their order to settle.
+ALTER TABLE reservation
+    ADD COLUMN expires_at TIMESTAMP NULL AFTER status,
+    ADD INDEX idx_reservation_status_expiry (status, expires_at);
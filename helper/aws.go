@@ -8,16 +8,15 @@ import (
 	"log"
 	"math/big"
 	"net/http"
-	"os"
 
 	"github.com/golang-jwt/jwt/v4"
-	_ "github.com/joho/godotenv/autoload"
 )
 
-var (
-	AwsRegion            = os.Getenv("AWS_REGION")
-	AwsCognitoUserPoolID = os.Getenv("AWS_COGNITO_USER_POOL_ID")
-)
+// Cognito verifies tokens issued by a Cognito user pool.
+type Cognito struct {
+	Region     string
+	UserPoolID string
+}
 
 type JWKKey struct {
 	Keys []struct {
@@ -63,8 +62,8 @@ func findRSAPublicKey(jwkKey JWKKey, kid string) (*rsa.PublicKey, error) {
 	return nil, fmt.Errorf("key not found")
 }
 
-func fetchJWKS() (*JWKKey, error) {
-	jwksURL := fmt.Sprintf("https://cognito-idp.%s.amazonaws.com/%s/.well-known/jwks.json", AwsRegion, AwsCognitoUserPoolID)
+func (c Cognito) fetchJWKS() (*JWKKey, error) {
+	jwksURL := fmt.Sprintf("https://cognito-idp.%s.amazonaws.com/%s/.well-known/jwks.json", c.Region, c.UserPoolID)
 	response, err := http.Get(jwksURL)
 	if err != nil {
 		return nil, err
@@ -79,8 +78,18 @@ func fetchJWKS() (*JWKKey, error) {
 	return &jwks, nil
 }
 
-func VerifyToken(tokenString string, attribute string) (interface{}, error) {
-	jwks, err := fetchJWKS()
+func (c Cognito) VerifyToken(tokenString string, attribute string) (interface{}, error) {
+	claims, err := c.VerifyTokenClaims(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	return claims[attribute], nil
+}
+
+// VerifyTokenClaims checks the token signature against the user pool keys and
+// returns all of its claims.
+func (c Cognito) VerifyTokenClaims(tokenString string) (jwt.MapClaims, error) {
+	jwks, err := c.fetchJWKS()
 	if err != nil {
 		log.Printf("Error fetching JWKS: %v", err)
 		return nil, err
@@ -106,11 +115,9 @@ func VerifyToken(tokenString string, attribute string) (interface{}, error) {
 	}
 
 	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		//log.Printf("Token is valid, %s: %s", attribute, claims[attribute])
-		return claims[attribute], nil
-	} else {
-		log.Printf("Token is invalid")
-		return nil, err
+		return claims, nil
 	}
 
+	log.Printf("Token is invalid")
+	return nil, fmt.Errorf("invalid token")
 }
@@ -1,6 +1,7 @@
 package helper
 
 import (
+	"fmt"
 	"github.com/IBM/sarama"
 	"log"
 )
@@ -39,3 +40,8 @@ func Consume(consumer sarama.Consumer, topics []string) (<-chan *sarama.Consumer
 
 	return messages, errors
 }
+
+// KafkaMessageID identifies a consumed message by its topic, partition and offset.
+func KafkaMessageID(msg *sarama.ConsumerMessage) string {
+	return fmt.Sprintf("%s-%d-%d", msg.Topic, msg.Partition, msg.Offset)
+}
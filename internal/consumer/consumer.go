@@ -1,63 +1,95 @@
 package consumer
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"time"
+
 	"github.com/IBM/sarama"
+	appconfig "github.com/SyamSolution/ticket-management-service/config"
 	"github.com/SyamSolution/ticket-management-service/helper"
 	"github.com/SyamSolution/ticket-management-service/internal/model"
 	"github.com/SyamSolution/ticket-management-service/internal/usecase"
-	"log"
-	"os"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
 )
 
-func Consumer(master sarama.Consumer, doneCh chan struct{}, ticketUsecase usecase.TicketExecutor) {
+var topicStatus = map[string]string{
+	"order-ticket":         "create",
+	"success-order-ticket": "success",
+	"failed-order-ticket":  "failed",
+}
+
+// kafkaContext attaches the message's correlation id header, falling back to
+// its topic/partition/offset, for the logs, and extracts the producer's trace
+// context from the record headers.
+func kafkaContext(ctx context.Context, msg *sarama.ConsumerMessage) context.Context {
+	carrier := kafkaHeaderCarrier(msg.Headers)
+	correlationID := carrier.Get(correlationIDAttribute)
+	if correlationID == "" {
+		correlationID = helper.KafkaMessageID(msg)
+	}
+	ctx = appconfig.WithRequestID(ctx, correlationID)
+	return otel.GetTextMapPropagator().Extract(ctx, carrier)
+}
+
+func Consumer(ctx context.Context, master sarama.Consumer, doneCh chan struct{}, ticketUsecase usecase.TicketExecutor, logger appconfig.Logger) {
 	consumer, errors := helper.Consume(master, []string{"order-ticket", "success-order-ticket", "failed-order-ticket"})
 
-	signals := make(chan os.Signal, 1)
 	for {
 		select {
 		case msg := <-consumer:
-			switch msg.Topic {
-			case "order-ticket":
-				var message model.MessageOrderTicket
-				if err := json.Unmarshal(msg.Value, &message); err != nil {
-					log.Printf("Error unmarshalling message: %s\n", err)
-				}
-
-				if err := ticketUsecase.UpdateStockTicket(message.TicketID, message.Order, "create"); err != nil {
-					log.Printf("Error when ordering ticket: %s\n", err)
-				} else {
-					log.Printf("Order ticket with ticketID: %d and order: %d success\n", message.TicketID, message.Order)
-				}
-			case "success-order-ticket":
-				var message model.MessageOrderTicket
-				if err := json.Unmarshal(msg.Value, &message); err != nil {
-					log.Printf("Error unmarshalling message: %s\n", err)
-				}
-
-				if err := ticketUsecase.UpdateStockTicket(message.TicketID, message.Order, "success"); err != nil {
-					log.Printf("Error when success ordering ticket: %s\n", err)
-				} else {
-					log.Printf("Success order ticket with ticketID: %d and order: %d success\n", message.TicketID, message.Order)
-				}
-			case "failed-order-ticket":
-				var message model.MessageOrderTicket
-				if err := json.Unmarshal(msg.Value, &message); err != nil {
-					log.Printf("Error unmarshalling message: %s\n", err)
-				}
-
-				if err := ticketUsecase.UpdateStockTicket(message.TicketID, message.Order, "failed"); err != nil {
-					log.Printf("Error when failed ordering ticket: %s\n", err)
-				} else {
-					log.Printf("Failed order ticket with ticketID: %d and order: %d success\n", message.TicketID, message.Order)
-				}
+			status, ok := topicStatus[msg.Topic]
+			if !ok {
+				continue
 			}
+
+			_ = processKafkaMessage(kafkaContext(ctx, msg), logger, ticketUsecase, msg, status)
 		case consumerError := <-errors:
-			fmt.Println("Received consumer error", (consumerError).Error())
-		case <-signals:
-			fmt.Println("Interrupt is detected")
+			logger.Error("Received consumer error", zap.Error(consumerError))
+		case <-ctx.Done():
+			logger.Info("Interrupt is detected")
 			doneCh <- struct{}{}
+			return
 		}
 	}
 }
+
+func processKafkaMessage(ctx context.Context, logger appconfig.Logger, ticketUsecase usecase.TicketExecutor, msg *sarama.ConsumerMessage, status string) (err error) {
+	start := time.Now()
+	defer func() { observeMessage(msg.Topic, start, err) }()
+
+	ctx, span := tracer.Start(ctx, msg.Topic+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingDestinationName(msg.Topic),
+			semconv.MessagingMessageID(helper.KafkaMessageID(msg)),
+		),
+	)
+	defer span.End()
+	ctx = appconfig.WithActor(ctx, "topic:"+msg.Topic)
+
+	logger = logger.WithContext(ctx)
+
+	var message model.MessageOrderTicket
+	if err = json.Unmarshal(msg.Value, &message); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid message body")
+		logger.Error("Error unmarshalling message", zap.String("topic", msg.Topic), zap.Error(err))
+		return err
+	}
+
+	if err = ticketUsecase.UpdateStockTicket(ctx, message, status); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		logger.Error("Error when update status ticket", zap.String("status", status), zap.Error(err))
+		return err
+	}
+	logger.Info("order ticket updated", zap.String("status", status), zap.Int("ticket_id", message.TicketID),
+		zap.Int("order", message.Order))
+	return nil
+}
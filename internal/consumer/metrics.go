@@ -0,0 +1,35 @@
+package consumer
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	messageDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "ticket_consumer",
+		Name:      "message_duration_seconds",
+		Help:      "Time spent processing a consumed message by queue and outcome.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"queue", "outcome"})
+
+	deadLetterMessagesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "ticket_consumer",
+		Name:      "dead_letter_messages_total",
+		Help:      "Count of messages consumed from dead letter queues.",
+	}, []string{"queue"})
+)
+
+// RegisterMetrics registers the consumer metrics on registry.
+func RegisterMetrics(registry prometheus.Registerer) {
+	registry.MustRegister(messageDuration, deadLetterMessagesTotal)
+}
+
+func observeMessage(queue string, start time.Time, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	messageDuration.WithLabelValues(queue, outcome).Observe(time.Since(start).Seconds())
+}
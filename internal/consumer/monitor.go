@@ -0,0 +1,59 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Monitor records when the workers of each queue last made progress so the
+// liveness probe can tell a stuck or exited consumer from an idle one. An idle
+// worker still beats once per receive loop, so staleAfter must be longer than
+// the long-poll wait plus the time to process a full batch.
+type Monitor struct {
+	mu         sync.RWMutex
+	lastBeat   map[string]time.Time
+	staleAfter time.Duration
+	now        func() time.Time
+}
+
+func NewMonitor(staleAfter time.Duration) *Monitor {
+	return &Monitor{
+		lastBeat:   map[string]time.Time{},
+		staleAfter: staleAfter,
+		now:        time.Now,
+	}
+}
+
+func (m *Monitor) beat(queue string) {
+	m.mu.Lock()
+	m.lastBeat[queue] = m.now()
+	m.mu.Unlock()
+}
+
+// Check returns an error naming every queue whose workers have not beaten
+// within staleAfter, or when no worker has started yet.
+func (m *Monitor) Check(_ context.Context) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if len(m.lastBeat) == 0 {
+		return fmt.Errorf("no consumer workers running")
+	}
+
+	var stale []string
+	for queue, beat := range m.lastBeat {
+		if m.now().Sub(beat) > m.staleAfter {
+			stale = append(stale, queue)
+		}
+	}
+	if len(stale) > 0 {
+		sort.Strings(stale)
+		return fmt.Errorf("workers stalled for queues: %s", strings.Join(stale, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package consumer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonitorCheck(t *testing.T) {
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	monitor := NewMonitor(30 * time.Second)
+	monitor.now = func() time.Time { return now }
+
+	assert.EqualError(t, monitor.Check(context.Background()), "no consumer workers running")
+
+	monitor.beat("ticket")
+	monitor.beat("ticket-success")
+	assert.NoError(t, monitor.Check(context.Background()))
+
+	now = now.Add(20 * time.Second)
+	monitor.beat("ticket-success")
+	now = now.Add(20 * time.Second)
+	assert.EqualError(t, monitor.Check(context.Background()), "workers stalled for queues: ticket")
+}
@@ -3,159 +3,163 @@ package consumer
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"log"
-	"os"
 	"sync"
 	"time"
 
+	appconfig "github.com/SyamSolution/ticket-management-service/config"
 	"github.com/SyamSolution/ticket-management-service/internal/model"
 	"github.com/SyamSolution/ticket-management-service/internal/usecase"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
 )
 
-const workerCount = 5
-func processTicketSuccessUpdate(message types.Message) {
-    fmt.Printf("Update Ticket Success Queue - Message ID: %s\n", *message.MessageId)
-    fmt.Printf("Update Ticket Success Queue - Message Body: %s\n", *message.Body)
+// correlationIDAttribute is the SQS message attribute (and Kafka header) the
+// order service uses to carry its request id across the queue.
+const correlationIDAttribute = "correlation_id"
+
+// processContext detaches message processing from the shutdown signal so a
+// message that was already received is finished and deleted instead of being
+// cut off half way; the repositories still bound every statement by their
+// query timeout. The message's correlation id, or its message id when the
+// producer did not send one, is attached for the logs, and the producer's
+// trace context is extracted from the message attributes.
+func processContext(ctx context.Context, message types.Message) context.Context {
+	correlationID := aws.ToString(message.MessageId)
+	if attr, ok := message.MessageAttributes[correlationIDAttribute]; ok && aws.ToString(attr.StringValue) != "" {
+		correlationID = aws.ToString(attr.StringValue)
+	}
+	ctx = appconfig.WithRequestID(context.WithoutCancel(ctx), correlationID)
+	return otel.GetTextMapPropagator().Extract(ctx, sqsAttributeCarrier(message.MessageAttributes))
 }
 
-func processDeadLetterMessage(message types.Message) {
-    fmt.Printf("Dead Letter Queue - Message ID: %s\n", *message.MessageId)
-    fmt.Printf("Dead Letter Queue - Message Body: %s\n", *message.Body)
+func processMessage(ctx context.Context, logger appconfig.Logger, ticketUsecase usecase.TicketExecutor, message types.Message, queue, status string) (err error) {
+	start := time.Now()
+	defer func() { observeMessage(queue, start, err) }()
+
+	ctx, span := tracer.Start(ctx, queue+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemAWSSqs,
+			semconv.MessagingDestinationName(queue),
+			semconv.MessagingMessageID(aws.ToString(message.MessageId)),
+		),
+	)
+	defer span.End()
+	ctx = appconfig.WithActor(ctx, "queue:"+queue)
+
+	logger = logger.WithContext(ctx)
+	logger.Info("consume ticket message", zap.String("queue", queue), zap.String("status", status),
+		zap.String("message_id", aws.ToString(message.MessageId)))
+
+	var msg model.MessageOrderTicket
+	if err = json.Unmarshal([]byte(aws.ToString(message.Body)), &msg); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid message body")
+		logger.Error("Error unmarshalling message", zap.String("queue", queue), zap.Error(err))
+		return err
+	}
+
+	if err = ticketUsecase.UpdateStockTicket(ctx, msg, status); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		logger.Error("Error when update status ticket", zap.String("queue", queue), zap.String("status", status), zap.Error(err))
+		return err
+	}
+	logger.Info("order ticket updated", zap.String("status", status), zap.Int("ticket_id", msg.TicketID), zap.Int("order", msg.Order))
+	return nil
 }
 
-func workerDeadLetter(client *sqs.Client, queueURL string, wg *sync.WaitGroup, ticketUsecase usecase.TicketExecutor, status string) {
-    defer wg.Done()
-    for {
-        result, err := client.ReceiveMessage(context.TODO(), &sqs.ReceiveMessageInput{
-            QueueUrl:            &queueURL,
-            MaxNumberOfMessages: 10,
-            WaitTimeSeconds:     10,
-        })
-        if err != nil {
-            log.Printf("failed to receive messages from Dead Letter queue, %v", err)
-            continue
-        }
-
-        if len(result.Messages) == 0 {
-            time.Sleep(1 * time.Second)
-            continue
-        }
-
-        for _, message := range result.Messages {
-
-            var msg model.MessageOrderTicket
-            err := json.Unmarshal([]byte(*message.Body), &msg)
-            if err != nil {
-                fmt.Println("Error unmarshalling message", err)
-            }else{
-                log.Printf("consume DLQ %s ticket", status)
-                if err := ticketUsecase.UpdateStockTicket(msg.TicketID, msg.Order, status); err != nil {
-					log.Printf("Error when update status %s ticket: %s\n", status, err)
-				} else {
-					log.Printf("%s order ticket with ticketID: %d and order: %d success\n", status, msg.TicketID, msg.Order)
-				}
-            }
-  
-            processDeadLetterMessage(message)
-
-            _, err = client.DeleteMessage(context.TODO(), &sqs.DeleteMessageInput{
-                QueueUrl:      &queueURL,
-                ReceiptHandle: message.ReceiptHandle,
-            })
-            if err != nil {
-                log.Printf("failed to delete message from Dead Letter queue, %v", err)
-            }
-        }
-    }
+func worker(ctx context.Context, client *sqs.Client, queueURL, queue string, deadLetter bool, wg *sync.WaitGroup, ticketUsecase usecase.TicketExecutor, logger appconfig.Logger, status string, monitor *Monitor) {
+	defer wg.Done()
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+		monitor.beat(queue)
+
+		result, err := client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:              &queueURL,
+			MaxNumberOfMessages:   10,
+			WaitTimeSeconds:       10,
+			MessageAttributeNames: []string{"All"},
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Error("failed to receive messages", zap.String("queue", queue), zap.Error(err))
+			continue
+		}
+
+		if len(result.Messages) == 0 {
+			time.Sleep(1 * time.Second)
+			continue
+		}
+
+		for _, message := range result.Messages {
+			monitor.beat(queue)
+			if deadLetter {
+				deadLetterMessagesTotal.WithLabelValues(queue).Inc()
+			}
+
+			msgCtx := processContext(ctx, message)
+			_ = processMessage(msgCtx, logger, ticketUsecase, message, queue, status)
+
+			_, err = client.DeleteMessage(msgCtx, &sqs.DeleteMessageInput{
+				QueueUrl:      &queueURL,
+				ReceiptHandle: message.ReceiptHandle,
+			})
+			if err != nil {
+				logger.WithContext(msgCtx).Error("failed to delete message", zap.String("queue", queue), zap.Error(err))
+			}
+		}
+	}
 }
 
-func workerTicket(client *sqs.Client, queueURL string, wg *sync.WaitGroup, ticketUsecase usecase.TicketExecutor, status string) {
-    defer wg.Done()
-    for {
-        result, err := client.ReceiveMessage(context.TODO(), &sqs.ReceiveMessageInput{
-            QueueUrl:            &queueURL,
-            MaxNumberOfMessages: 10,
-            WaitTimeSeconds:     10,
-        })
-        if err != nil {
-            log.Printf("failed to receive messages from SendEmailPdf queue, %v", err)
-            continue
-        }
-
-        if len(result.Messages) == 0 {
-            time.Sleep(1 * time.Second)
-            continue
-        }
-
-        for _, message := range result.Messages {
-            var msg model.MessageOrderTicket
-            err := json.Unmarshal([]byte(*message.Body), &msg)
-            if err != nil {
-                fmt.Println("Error unmarshalling message", err)
-            }else{
-                log.Printf("consume %s ticket", status)
-                if err := ticketUsecase.UpdateStockTicket(msg.TicketID, msg.Order, status); err != nil {
-                    log.Printf("Error when update status %s ticket: %s\n", status, err)
-                } else {
-					log.Printf("%s order ticket with ticketID: %d and order: %d success\n", status, msg.TicketID, msg.Order)
-				}
-            }
-            processTicketSuccessUpdate(message)
-
-            _, err = client.DeleteMessage(context.TODO(), &sqs.DeleteMessageInput{
-                QueueUrl:      &queueURL,
-                ReceiptHandle: message.ReceiptHandle,
-            })
-            if err != nil {
-                log.Printf("failed to delete message from SendEmailPdf queue, %v", err)
-            }
-        }
-    }
-}
-
-func StartConsumer(ticketUsecase usecase.TicketExecutor) {
-    cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion("ap-southeast-1"))
-    if err != nil {
-        log.Fatalf("unable to load SDK config, %v", err)
-    }
-
-    client := sqs.NewFromConfig(cfg)
-
-    var wg sync.WaitGroup
-
-    for i := 0; i < workerCount; i++ {
-        wg.Add(1)
-        go workerTicket(client, os.Getenv("SQS_TICKET_SUCCESS_URL"), &wg, ticketUsecase, "success")
-    }
-
-    for i := 0; i < workerCount; i++ {
-        wg.Add(1)
-        go workerTicket(client, os.Getenv("SQS_TICKET_FAILED_URL"), &wg, ticketUsecase, "failed")
-   }
-
-    for i := 0; i < workerCount; i++ {
-        wg.Add(1)
-        go workerTicket(client, os.Getenv("SQS_TICKET_URL"), &wg, ticketUsecase, "create")
-    }
-
-    for i := 0; i < workerCount; i++ {
-        wg.Add(1)
-        go workerDeadLetter(client, os.Getenv("SQS_TICKET_DLQ_URL"), &wg, ticketUsecase, "create")
-    }
-
-    for i := 0; i < workerCount; i++ {
-        wg.Add(1)
-        go workerDeadLetter(client, os.Getenv("SQS_TICKET_FAILED_DLQ_URL"), &wg, ticketUsecase,  "failed")
-    }
-
-    for i := 0; i < workerCount; i++ {
-        wg.Add(1)
-        go workerDeadLetter(client, os.Getenv("SQS_TICKET_SUCCESS_DLQ_URL"), &wg, ticketUsecase,  "success")
-    }
-
-    wg.Wait()
+// StartConsumer runs the SQS workers until ctx is cancelled and returns once
+// every worker finished the batch it was processing. Workers report progress
+// to monitor for the liveness probe.
+func StartConsumer(ctx context.Context, cfg appconfig.SQSConfig, region string, ticketUsecase usecase.TicketExecutor, logger appconfig.Logger, monitor *Monitor) {
+	awsCfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("unable to load SDK config, %v", err)
+	}
+
+	client := sqs.NewFromConfig(awsCfg)
+
+	queues := []struct {
+		name       string
+		url        string
+		status     string
+		deadLetter bool
+	}{
+		{"ticket-success", cfg.TicketSuccessURL, "success", false},
+		{"ticket-failed", cfg.TicketFailedURL, "failed", false},
+		{"ticket", cfg.TicketURL, "create", false},
+		{"ticket-dlq", cfg.TicketDLQURL, "create", true},
+		{"ticket-failed-dlq", cfg.TicketFailedDLQURL, "failed", true},
+		{"ticket-success-dlq", cfg.TicketSuccessDLQURL, "success", true},
+	}
+
+	var wg sync.WaitGroup
+
+	for _, queue := range queues {
+		if queue.url == "" {
+			continue
+		}
+		for i := 0; i < cfg.WorkerCount; i++ {
+			wg.Add(1)
+			go worker(ctx, client, queue.url, queue.name, queue.deadLetter, &wg, ticketUsecase, logger, queue.status, monitor)
+		}
+	}
+
+	wg.Wait()
 }
@@ -0,0 +1,60 @@
+package consumer
+
+import (
+	"context"
+	"testing"
+
+	appconfig "github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/mock"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/mock/gomock"
+)
+
+func TestProcessMessage(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTicketUsecase := mock.NewMockTicketExecutor(ctrl)
+
+	message := types.Message{
+		MessageId: aws.String("message-1"),
+		Body:      aws.String(`{"ticket_id":1,"order":2,"order_id":"order-1"}`),
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			"traceparent":    {DataType: aws.String("String"), StringValue: aws.String("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")},
+			"correlation_id": {DataType: aws.String("String"), StringValue: aws.String("correlation-1")},
+		},
+	}
+
+	mockTicketUsecase.EXPECT().
+		UpdateStockTicket(gomock.Any(), model.MessageOrderTicket{TicketID: 1, Order: 2, OrderID: "order-1"}, "success").
+		DoAndReturn(func(ctx context.Context, _ model.MessageOrderTicket, _ string) error {
+			assert.Equal(t, "correlation-1", appconfig.RequestIDFromContext(ctx))
+			return nil
+		})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := processMessage(processContext(ctx, message), appconfig.NewNopLogger(), mockTicketUsecase, message, "ticket-success", "success")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, testutil.CollectAndCount(messageDuration, "ticket_consumer_message_duration_seconds"))
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "ticket-success process", spans[0].Name)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent.SpanID().String())
+}
@@ -0,0 +1,60 @@
+package consumer
+
+import (
+	"github.com/IBM/sarama"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("github.com/SyamSolution/ticket-management-service/internal/consumer")
+
+// sqsAttributeCarrier adapts SQS message attributes to a
+// propagation.TextMapCarrier so producers can pass W3C trace context as
+// string attributes (traceparent, tracestate).
+type sqsAttributeCarrier map[string]types.MessageAttributeValue
+
+func (c sqsAttributeCarrier) Get(key string) string {
+	if value, ok := c[key]; ok {
+		return aws.ToString(value.StringValue)
+	}
+	return ""
+}
+
+func (c sqsAttributeCarrier) Set(key, value string) {
+	c[key] = types.MessageAttributeValue{
+		DataType:    aws.String("String"),
+		StringValue: aws.String(value),
+	}
+}
+
+func (c sqsAttributeCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// kafkaHeaderCarrier adapts Kafka record headers to a propagation.TextMapCarrier.
+type kafkaHeaderCarrier []*sarama.RecordHeader
+
+func (c kafkaHeaderCarrier) Get(key string) string {
+	for _, header := range c {
+		if string(header.Key) == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+// Set is a no-op, consumed records are never re-published.
+func (c kafkaHeaderCarrier) Set(string, string) {}
+
+func (c kafkaHeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for _, header := range c {
+		keys = append(keys, string(header.Key))
+	}
+	return keys
+}
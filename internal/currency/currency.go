@@ -0,0 +1,92 @@
+// Package currency converts model.Money between ISO 4217 currencies.
+package currency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+)
+
+// ErrUnsupported is returned for malformed currency codes and for pairs a
+// RateProvider has no rate for.
+var ErrUnsupported = errors.New("unsupported currency")
+
+// RateProvider returns how many units of to one unit of from is worth.
+type RateProvider interface {
+	Rate(ctx context.Context, from, to string) (*big.Rat, error)
+}
+
+var codePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Normalize upper-cases code and checks it looks like an ISO 4217 code.
+func Normalize(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !codePattern.MatchString(code) {
+		return "", fmt.Errorf("currency %q: %w", code, ErrUnsupported)
+	}
+	return code, nil
+}
+
+// Exponent is the number of decimal places of the currency's minor unit.
+func Exponent(code string) int {
+	return model.CurrencyExponent(code)
+}
+
+// Convert expresses m in currency to, rounding half away from zero to the
+// minor unit of to.
+func Convert(ctx context.Context, rates RateProvider, m model.Money, to string) (model.Money, error) {
+	if m.Currency == to {
+		return m, nil
+	}
+
+	rate, err := rates.Rate(ctx, m.Currency, to)
+	if err != nil {
+		return model.Money{}, err
+	}
+
+	amount := new(big.Rat).SetInt64(m.Amount)
+	amount.Mul(amount, rate)
+	amount.Mul(amount, pow10(Exponent(to)-Exponent(m.Currency)))
+
+	return model.Money{Amount: round(amount), Currency: to}, nil
+}
+
+// Scale multiplies m by num/den, rounding half away from zero to its minor
+// unit. den must not be zero.
+func Scale(m model.Money, num, den int64) model.Money {
+	amount := new(big.Rat).SetFrac(big.NewInt(m.Amount), big.NewInt(den))
+	amount.Mul(amount, new(big.Rat).SetInt64(num))
+	return model.Money{Amount: round(amount), Currency: m.Currency}
+}
+
+func pow10(n int) *big.Rat {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(n))), nil)
+	if n < 0 {
+		return new(big.Rat).SetFrac(big.NewInt(1), scale)
+	}
+	return new(big.Rat).SetInt(scale)
+}
+
+func round(r *big.Rat) int64 {
+	num := new(big.Int).Abs(r.Num())
+	quotient, remainder := new(big.Int).QuoRem(num, r.Denom(), new(big.Int))
+	if remainder.Mul(remainder, big.NewInt(2)).Cmp(r.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	if r.Sign() < 0 {
+		quotient.Neg(quotient)
+	}
+	return quotient.Int64()
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
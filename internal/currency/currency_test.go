@@ -0,0 +1,61 @@
+package currency
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvert(t *testing.T) {
+	rates, err := LoadStaticRates("testdata/rates.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		from model.Money
+		to   string
+		want model.Money
+	}{
+		{"same currency", model.Money{Amount: 1050, Currency: "USD"}, "USD", model.Money{Amount: 1050, Currency: "USD"}},
+		{"from base", model.Money{Amount: 1000, Currency: "USD"}, "EUR", model.Money{Amount: 920, Currency: "EUR"}},
+		{"to zero exponent", model.Money{Amount: 1000, Currency: "USD"}, "JPY", model.Money{Amount: 1513, Currency: "JPY"}},
+		{"to three exponent", model.Money{Amount: 1000, Currency: "USD"}, "KWD", model.Money{Amount: 3070, Currency: "KWD"}},
+		{"cross rate rounds half up", model.Money{Amount: 150000000, Currency: "IDR"}, "EUR", model.Money{Amount: 8492, Currency: "EUR"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert(context.Background(), rates, tt.from, tt.to)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err = Convert(context.Background(), rates, model.Money{Amount: 1, Currency: "USD"}, "GBP")
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestNormalize(t *testing.T) {
+	code, err := Normalize(" eur ")
+	assert.NoError(t, err)
+	assert.Equal(t, "EUR", code)
+
+	_, err = Normalize("EURO")
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestScale(t *testing.T) {
+	assert.Equal(t, model.Money{Amount: 7500, Currency: "USD"}, Scale(model.Money{Amount: 10000, Currency: "USD"}, 150000, 200000))
+	assert.Equal(t, model.Money{Amount: 3, Currency: "JPY"}, Scale(model.Money{Amount: 5, Currency: "JPY"}, 1, 2))
+}
+
+func TestRound(t *testing.T) {
+	assert.Equal(t, int64(3), round(big.NewRat(5, 2)))
+	assert.Equal(t, int64(-3), round(big.NewRat(-5, 2)))
+	assert.Equal(t, int64(2), round(big.NewRat(7, 3)))
+}
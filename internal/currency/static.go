@@ -0,0 +1,92 @@
+package currency
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// StaticRates is a RateProvider over a fixed table of rates against one base
+// currency, typically loaded from a file with LoadStaticRates.
+type StaticRates struct {
+	base  string
+	rates map[string]*big.Rat
+}
+
+type staticRatesFile struct {
+	Base  string            `yaml:"base"`
+	Rates map[string]string `yaml:"rates"`
+}
+
+// NewStaticRates builds a provider where rates[code] is the value of one unit
+// of base in code. A nil table only converts a currency to itself.
+func NewStaticRates(base string, rates map[string]*big.Rat) *StaticRates {
+	table := make(map[string]*big.Rat, len(rates)+1)
+	for code, rate := range rates {
+		table[code] = rate
+	}
+	if base != "" {
+		table[base] = big.NewRat(1, 1)
+	}
+	return &StaticRates{base: base, rates: table}
+}
+
+// LoadStaticRates reads a YAML (or JSON) file of the form
+//
+//	base: USD
+//	rates:
+//	  EUR: "0.92"
+//	  IDR: "16250.5"
+//
+// Rates are decimal strings so they are parsed exactly.
+func LoadStaticRates(path string) (*StaticRates, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read exchange rates: %w", err)
+	}
+
+	var file staticRatesFile
+	if err = yaml.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("parse exchange rates %s: %w", path, err)
+	}
+
+	base, err := Normalize(file.Base)
+	if err != nil {
+		return nil, fmt.Errorf("exchange rates base: %w", err)
+	}
+
+	rates := make(map[string]*big.Rat, len(file.Rates))
+	for code, value := range file.Rates {
+		normalized, err := Normalize(code)
+		if err != nil {
+			return nil, fmt.Errorf("exchange rates: %w", err)
+		}
+		rate, ok := new(big.Rat).SetString(value)
+		if !ok || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("exchange rate of %s: invalid value %q", normalized, value)
+		}
+		rates[normalized] = rate
+	}
+
+	return NewStaticRates(base, rates), nil
+}
+
+func (s *StaticRates) Rate(_ context.Context, from, to string) (*big.Rat, error) {
+	if from == to {
+		return big.NewRat(1, 1), nil
+	}
+
+	fromRate, ok := s.rates[from]
+	if !ok {
+		return nil, fmt.Errorf("no exchange rate for %s: %w", from, ErrUnsupported)
+	}
+	toRate, ok := s.rates[to]
+	if !ok {
+		return nil, fmt.Errorf("no exchange rate for %s: %w", to, ErrUnsupported)
+	}
+
+	return new(big.Rat).Quo(toRate, fromRate), nil
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/usecase"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// ErrorHandler is the central fiber error handler. Handlers return errors as is
+// and this maps usecase domain errors and business errors to the HTTP status and
+// response code the client receives. Anything unknown is logged and answered
+// with a 500 without leaking the cause.
+func ErrorHandler(logger config.Logger) fiber.ErrorHandler {
+	return func(c *fiber.Ctx, err error) error {
+		var businessErr *model.BusinessError
+		if errors.As(err, &businessErr) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(util.BusinessErrorResponseWithoutData(businessErr))
+		}
+
+		status, code := errorStatus(err)
+		message := err.Error()
+		if status >= fiber.StatusInternalServerError {
+			logger.WithContext(c.UserContext()).Error("Error when handling request", zap.String("method", c.Method()),
+				zap.String("path", c.Path()), zap.Error(err))
+			message = util.ERROR_BASE_MSG
+		}
+
+		return c.Status(status).JSON(util.BusinessErrorResponseWithoutData(&model.BusinessError{
+			Code:    code,
+			Message: message,
+		}))
+	}
+}
+
+func errorStatus(err error) (int, int) {
+	var fiberErr *fiber.Error
+	switch {
+	case errors.Is(err, usecase.ErrNotFound):
+		return fiber.StatusNotFound, util.ERROR_NOT_FOUND_CODE
+	case errors.Is(err, usecase.ErrInvalidParam):
+		return fiber.StatusBadRequest, util.ERROR_INVALID_PARAM_CODE
+	case errors.Is(err, usecase.ErrConflict):
+		return fiber.StatusConflict, util.ERROR_CONFLICT_CODE
+	case errors.Is(err, usecase.ErrInsufficientStock):
+		return fiber.StatusConflict, util.ERROR_INSUFFICIENT_STOCK_CODE
+	case errors.Is(err, context.DeadlineExceeded):
+		return fiber.StatusGatewayTimeout, util.ERROR_BASE_CODE
+	case errors.Is(err, context.Canceled):
+		return fiber.StatusServiceUnavailable, util.ERROR_BASE_CODE
+	case errors.As(err, &fiberErr):
+		switch fiberErr.Code {
+		case fiber.StatusNotFound:
+			return fiberErr.Code, util.ERROR_NOT_FOUND_CODE
+		case fiber.StatusBadRequest:
+			return fiberErr.Code, util.ERROR_INVALID_PARAM_CODE
+		case fiber.StatusUnauthorized:
+			return fiberErr.Code, util.ERROR_UNAUTHORIZE_CODE
+		}
+		return fiberErr.Code, util.ERROR_BASE_CODE
+	}
+	return fiber.StatusInternalServerError, util.ERROR_BASE_CODE
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/usecase"
+	mock_config "github.com/SyamSolution/ticket-management-service/mock/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestErrorHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		statusCode int
+		code       int
+	}{
+		{"not found", fmt.Errorf("ticket 1: %w", usecase.ErrNotFound), 404, 4002},
+		{"invalid param", fmt.Errorf("ticket_id: %w", usecase.ErrInvalidParam), 400, 4101},
+		{"conflict", fmt.Errorf("order 1: %w", usecase.ErrConflict), 409, 4105},
+		{"insufficient stock", fmt.Errorf("ticket 1: %w", usecase.ErrInsufficientStock), 409, 4106},
+		{"business error", &model.BusinessError{Code: 4104, Message: "limit"}, 422, 4104},
+		{"fiber error", fiber.ErrNotFound, 404, 4002},
+		{"deadline exceeded", fmt.Errorf("query: %w", context.DeadlineExceeded), 504, 4001},
+		{"unknown error", errors.New("connection refused"), 500, 4001},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockLogger := mock_config.NewMockLogger(ctrl)
+			if tt.statusCode >= 500 {
+				mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger)
+				mockLogger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+			}
+
+			app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(mockLogger)})
+			app.Get("/", func(c *fiber.Ctx) error {
+				return tt.err
+			})
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.statusCode, resp.StatusCode)
+
+			var body model.ResponseWithoutData
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, tt.code, body.Meta.Code)
+			if tt.statusCode >= 500 {
+				assert.Equal(t, "something is wrong, report to support team", body.Meta.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/usecase"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+type eventHandler struct {
+	eventUsecase usecase.EventExecutor
+	logger       config.Logger
+}
+
+type EventHandler interface {
+	GetEvents(c *fiber.Ctx) error
+	GetEvent(c *fiber.Ctx) error
+	UpdateEventStatus(c *fiber.Ctx) error
+	UpdateSaleWindow(c *fiber.Ctx) error
+	UpdateResaleCap(c *fiber.Ctx) error
+	CancelEvent(c *fiber.Ctx) error
+}
+
+func NewEventHandler(eventUsecase usecase.EventExecutor, logger config.Logger) EventHandler {
+	return &eventHandler{eventUsecase: eventUsecase, logger: logger}
+}
+
+func (handler *eventHandler) GetEvents(c *fiber.Ctx) error {
+	events, err := handler.eventUsecase.GetEvents(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: events,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *eventHandler) GetEvent(c *fiber.Ctx) error {
+	eventID, err := intParam(c, "event_id")
+	if err != nil {
+		return err
+	}
+
+	event, err := handler.eventUsecase.GetEvent(c.UserContext(), eventID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: event,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *eventHandler) UpdateEventStatus(c *fiber.Ctx) error {
+	eventID, err := intParam(c, "event_id")
+	if err != nil {
+		return err
+	}
+
+	var request model.EventStatusRequest
+	if err := c.BodyParser(&request); err != nil {
+		return fmt.Errorf("event status request: %w", usecase.ErrInvalidParam)
+	}
+
+	if err := validate.Struct(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(util.ValidatorErrorResponse(util.ValidateStruct(err)))
+	}
+
+	event, err := handler.eventUsecase.UpdateEventStatus(c.UserContext(), eventID, request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: event,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *eventHandler) UpdateSaleWindow(c *fiber.Ctx) error {
+	eventID, err := intParam(c, "event_id")
+	if err != nil {
+		return err
+	}
+
+	var request model.EventSaleWindowRequest
+	if err := c.BodyParser(&request); err != nil {
+		return fmt.Errorf("event sale window request: %w", usecase.ErrInvalidParam)
+	}
+
+	event, err := handler.eventUsecase.UpdateSaleWindow(c.UserContext(), eventID, request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: event,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *eventHandler) UpdateResaleCap(c *fiber.Ctx) error {
+	eventID, err := intParam(c, "event_id")
+	if err != nil {
+		return err
+	}
+
+	var request model.EventResaleCapRequest
+	if err := c.BodyParser(&request); err != nil {
+		return fmt.Errorf("event resale cap request: %w", usecase.ErrInvalidParam)
+	}
+
+	if err := validate.Struct(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(util.ValidatorErrorResponse(util.ValidateStruct(err)))
+	}
+
+	event, err := handler.eventUsecase.UpdateResaleCap(c.UserContext(), eventID, request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: event,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *eventHandler) CancelEvent(c *fiber.Ctx) error {
+	eventID, err := intParam(c, "event_id")
+	if err != nil {
+		return err
+	}
+
+	var request model.EventCancelRequest
+	if err := c.BodyParser(&request); err != nil {
+		return fmt.Errorf("event cancel request: %w", usecase.ErrInvalidParam)
+	}
+
+	if err := validate.Struct(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(util.ValidatorErrorResponse(util.ValidateStruct(err)))
+	}
+
+	cancellation, err := handler.eventUsecase.CancelEvent(c.UserContext(), eventID, request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: cancellation,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
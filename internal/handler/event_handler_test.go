@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/mock"
+	mock_config "github.com/SyamSolution/ticket-management-service/mock/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUpdateEventStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEventUsecase := mock.NewMockEventExecutor(ctrl)
+	mockLogger := mock_config.NewMockLogger(ctrl)
+
+	handler := NewEventHandler(mockEventUsecase, mockLogger)
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(mockLogger)})
+	app.Put("/admin/events/:event_id/status", handler.UpdateEventStatus)
+
+	t.Run("should pause the event", func(t *testing.T) {
+		mockEventUsecase.EXPECT().UpdateEventStatus(gomock.Any(), 3, model.EventStatusRequest{Status: "paused"}).
+			Return(model.Event{EventID: 3, Status: "paused"}, nil)
+
+		req := httptest.NewRequest("PUT", "/admin/events/3/status", strings.NewReader(`{"status":"paused"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("should reject a status set automatically", func(t *testing.T) {
+		req := httptest.NewRequest("PUT", "/admin/events/3/status", strings.NewReader(`{"status":"past"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+}
+
+func TestCancelEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEventUsecase := mock.NewMockEventExecutor(ctrl)
+	mockLogger := mock_config.NewMockLogger(ctrl)
+
+	handler := NewEventHandler(mockEventUsecase, mockLogger)
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(mockLogger)})
+	app.Post("/admin/events/:event_id/cancel", handler.CancelEvent)
+
+	t.Run("should cancel the event", func(t *testing.T) {
+		mockEventUsecase.EXPECT().CancelEvent(gomock.Any(), 3, model.EventCancelRequest{Reason: "Venue closed"}).
+			Return(model.EventCancellation{EventID: 3, Status: "cancelled", Released: 2, Refunded: 1}, nil)
+
+		req := httptest.NewRequest("POST", "/admin/events/3/cancel", strings.NewReader(`{"reason":"Venue closed"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("should require a reason", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/admin/events/3/cancel", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+)
+
+type geographyHandler struct {
+	geographyUsecase usecase.GeographyExecutor
+	logger           config.Logger
+}
+
+type GeographyHandler interface {
+	GetContinents(c *fiber.Ctx) error
+	GetCountriesByContinent(c *fiber.Ctx) error
+	GetCitiesByCountry(c *fiber.Ctx) error
+	GetVenuesByCity(c *fiber.Ctx) error
+}
+
+func NewGeographyHandler(geographyUsecase usecase.GeographyExecutor, logger config.Logger) GeographyHandler {
+	return &geographyHandler{geographyUsecase: geographyUsecase, logger: logger}
+}
+
+func (handler *geographyHandler) GetContinents(c *fiber.Ctx) error {
+	continents, err := handler.geographyUsecase.GetContinents(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: continents,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *geographyHandler) GetCountriesByContinent(c *fiber.Ctx) error {
+	countries, err := handler.geographyUsecase.GetCountriesByContinent(c.UserContext(), c.Params("code"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: countries,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *geographyHandler) GetCitiesByCountry(c *fiber.Ctx) error {
+	cities, err := handler.geographyUsecase.GetCitiesByCountry(c.UserContext(), c.Params("code"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: cities,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *geographyHandler) GetVenuesByCity(c *fiber.Ctx) error {
+	cityID, err := intParam(c, "city_id")
+	if err != nil {
+		return err
+	}
+
+	venues, err := handler.geographyUsecase.GetVenuesByCity(c.UserContext(), cityID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: venues,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
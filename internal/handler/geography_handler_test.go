@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/usecase"
+	"github.com/SyamSolution/ticket-management-service/mock"
+	mock_config "github.com/SyamSolution/ticket-management-service/mock/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetCountriesByContinent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGeographyUsecase := mock.NewMockGeographyExecutor(ctrl)
+	mockLogger := mock_config.NewMockLogger(ctrl)
+
+	handler := NewGeographyHandler(mockGeographyUsecase, mockLogger)
+
+	mockGeographyUsecase.EXPECT().GetCountriesByContinent(gomock.Any(), "AS").
+		Return([]model.Country{{CountryID: 1, Code: "ID", Name: "Indonesia"}}, nil)
+	mockGeographyUsecase.EXPECT().GetCountriesByContinent(gomock.Any(), "XX").
+		Return(nil, fmt.Errorf("continent XX: %w", usecase.ErrNotFound))
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(mockLogger)})
+	app.Get("/continents/:code/countries", handler.GetCountriesByContinent)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/continents/AS/countries", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/continents/XX/countries", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+}
+
+func TestGetVenuesByCity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGeographyUsecase := mock.NewMockGeographyExecutor(ctrl)
+	mockLogger := mock_config.NewMockLogger(ctrl)
+
+	handler := NewGeographyHandler(mockGeographyUsecase, mockLogger)
+
+	mockGeographyUsecase.EXPECT().GetVenuesByCity(gomock.Any(), 7).Return([]model.Venue{{VenueID: 2, CityID: 7}}, nil)
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(mockLogger)})
+	app.Get("/cities/:city_id/venues", handler.GetVenuesByCity)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/cities/7/venues", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/cities/jakarta/venues", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	healthStatusUp   = "up"
+	healthStatusDown = "down"
+)
+
+// HealthCheck probes a single dependency; Check must honour ctx so a hung
+// dependency can't hold the probe past its timeout.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type healthHandler struct {
+	liveness     []HealthCheck
+	readiness    []HealthCheck
+	timeout      time.Duration
+	shuttingDown atomic.Bool
+}
+
+type HealthHandler interface {
+	Liveness(c *fiber.Ctx) error
+	Readiness(c *fiber.Ctx) error
+	Shutdown()
+}
+
+// NewHealthHandler serves /healthz from the liveness checks and /readyz from
+// the readiness checks, giving every check at most timeout to answer.
+func NewHealthHandler(liveness, readiness []HealthCheck, timeout time.Duration) HealthHandler {
+	return &healthHandler{liveness: liveness, readiness: readiness, timeout: timeout}
+}
+
+func (handler *healthHandler) Liveness(c *fiber.Ctx) error {
+	return handler.respond(c, handler.run(c.UserContext(), handler.liveness))
+}
+
+// Readiness reports unavailable as soon as Shutdown is called so the load
+// balancer stops routing new requests while in-flight ones drain.
+func (handler *healthHandler) Readiness(c *fiber.Ctx) error {
+	checks := handler.run(c.UserContext(), handler.readiness)
+	if handler.shuttingDown.Load() {
+		checks["shutdown"] = model.HealthCheckStatus{Status: healthStatusDown, Error: "server is shutting down"}
+	}
+
+	return handler.respond(c, checks)
+}
+
+func (handler *healthHandler) Shutdown() {
+	handler.shuttingDown.Store(true)
+}
+
+func (handler *healthHandler) run(ctx context.Context, checks []HealthCheck) map[string]model.HealthCheckStatus {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]model.HealthCheckStatus, len(checks))
+	)
+
+	for _, check := range checks {
+		wg.Add(1)
+		go func(check HealthCheck) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, handler.timeout)
+			defer cancel()
+
+			start := time.Now()
+			status := model.HealthCheckStatus{Status: healthStatusUp}
+			if err := check.Check(checkCtx); err != nil {
+				status = model.HealthCheckStatus{Status: healthStatusDown, Error: err.Error()}
+			}
+			status.Latency = time.Since(start).String()
+
+			mu.Lock()
+			results[check.Name] = status
+			mu.Unlock()
+		}(check)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func (handler *healthHandler) respond(c *fiber.Ctx, checks map[string]model.HealthCheckStatus) error {
+	response := model.HealthResponse{Status: healthStatusUp, Checks: checks}
+	for _, check := range checks {
+		if check.Status != healthStatusUp {
+			response.Status = healthStatusDown
+			return c.Status(fiber.StatusServiceUnavailable).JSON(response)
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthHandler(t *testing.T) {
+	up := HealthCheck{Name: "database", Check: func(ctx context.Context) error { return nil }}
+	down := HealthCheck{Name: "cache", Check: func(ctx context.Context) error { return errors.New("connection refused") }}
+	hung := HealthCheck{Name: "consumer", Check: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+
+	call := func(h HealthHandler, handle func(h HealthHandler) fiber.Handler) (int, model.HealthResponse) {
+		app := fiber.New()
+		app.Get("/", handle(h))
+		resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+		assert.NoError(t, err)
+
+		var body model.HealthResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return resp.StatusCode, body
+	}
+	liveness := func(h HealthHandler) fiber.Handler { return h.Liveness }
+	readiness := func(h HealthHandler) fiber.Handler { return h.Readiness }
+
+	t.Run("all checks up", func(t *testing.T) {
+		status, body := call(NewHealthHandler(nil, []HealthCheck{up}, time.Second), readiness)
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, "up", body.Status)
+		assert.Equal(t, "up", body.Checks["database"].Status)
+	})
+
+	t.Run("failing dependency", func(t *testing.T) {
+		status, body := call(NewHealthHandler(nil, []HealthCheck{up, down}, time.Second), readiness)
+		assert.Equal(t, fiber.StatusServiceUnavailable, status)
+		assert.Equal(t, "down", body.Status)
+		assert.Equal(t, "connection refused", body.Checks["cache"].Error)
+		assert.Equal(t, "up", body.Checks["database"].Status)
+	})
+
+	t.Run("check times out", func(t *testing.T) {
+		status, body := call(NewHealthHandler([]HealthCheck{hung}, nil, 10*time.Millisecond), liveness)
+		assert.Equal(t, fiber.StatusServiceUnavailable, status)
+		assert.Equal(t, context.DeadlineExceeded.Error(), body.Checks["consumer"].Error)
+	})
+
+	t.Run("not ready while shutting down", func(t *testing.T) {
+		h := NewHealthHandler([]HealthCheck{up}, []HealthCheck{up}, time.Second)
+		h.Shutdown()
+
+		status, body := call(h, readiness)
+		assert.Equal(t, fiber.StatusServiceUnavailable, status)
+		assert.Equal(t, "down", body.Checks["shutdown"].Status)
+
+		status, _ = call(h, liveness)
+		assert.Equal(t, fiber.StatusOK, status)
+	})
+}
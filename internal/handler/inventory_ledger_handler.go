@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/usecase"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+type inventoryLedgerHandler struct {
+	ledgerUsecase usecase.InventoryLedgerExecutor
+	logger        config.Logger
+}
+
+type InventoryLedgerHandler interface {
+	GetTicketLedger(c *fiber.Ctx) error
+}
+
+func NewInventoryLedgerHandler(ledgerUsecase usecase.InventoryLedgerExecutor, logger config.Logger) InventoryLedgerHandler {
+	return &inventoryLedgerHandler{ledgerUsecase: ledgerUsecase, logger: logger}
+}
+
+// GetTicketLedger pages through the ledger of a ticket with the optional
+// "limit" and "offset" query parameters. The total is the number of entries
+// in the whole ledger.
+func (handler *inventoryLedgerHandler) GetTicketLedger(c *fiber.Ctx) error {
+	ticketID, err := intParam(c, "ticket_id")
+	if err != nil {
+		return err
+	}
+	limit := c.QueryInt("limit", util.DEFAULT_LIMIT_PAGINATION)
+	offset := c.QueryInt("offset", 0)
+
+	ledger, err := handler.ledgerUsecase.GetTicketLedger(c.UserContext(), ticketID, limit, offset)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.PaginationResponse{
+		Data: ledger,
+		Meta: model.PaginationMeta{
+			Meta: model.Meta{
+				Code:    fiber.StatusOK,
+				Message: "Success",
+			},
+			Limit:  limit,
+			Offset: offset,
+			Total:  ledger.EntryCount,
+		},
+	})
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/usecase"
+	"github.com/SyamSolution/ticket-management-service/mock"
+	mock_config "github.com/SyamSolution/ticket-management-service/mock/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetTicketLedger(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLedgerUsecase := mock.NewMockInventoryLedgerExecutor(ctrl)
+	mockLogger := mock_config.NewMockLogger(ctrl)
+
+	handler := NewInventoryLedgerHandler(mockLedgerUsecase, mockLogger)
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(mockLogger)})
+	app.Get("/admin/tickets/:ticket_id/ledger", handler.GetTicketLedger)
+
+	t.Run("should page through the ledger", func(t *testing.T) {
+		counters := model.StockCounters{Total: 100, Available: 100}
+		mockLedgerUsecase.EXPECT().GetTicketLedger(gomock.Any(), 2, 5, 10).Return(model.InventoryLedger{
+			LedgerBalance: model.LedgerBalance{TicketID: 2, Current: counters, Recomputed: counters, EntryCount: 12},
+			Consistent:    true,
+		}, nil)
+
+		req := httptest.NewRequest("GET", "/admin/tickets/2/ledger?limit=5&offset=10", nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var body struct {
+			Data struct {
+				Consistent bool `json:"consistent"`
+			} `json:"data"`
+			Meta model.PaginationMeta `json:"meta"`
+		}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.True(t, body.Data.Consistent)
+		assert.Equal(t, 12, body.Meta.Total)
+		assert.Equal(t, 5, body.Meta.Limit)
+	})
+
+	t.Run("should use the default page size", func(t *testing.T) {
+		mockLedgerUsecase.EXPECT().GetTicketLedger(gomock.Any(), 2, 10, 0).Return(model.InventoryLedger{}, nil)
+
+		req := httptest.NewRequest("GET", "/admin/tickets/2/ledger", nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("should report an unknown ticket", func(t *testing.T) {
+		mockLedgerUsecase.EXPECT().GetTicketLedger(gomock.Any(), 9, 10, 0).
+			Return(model.InventoryLedger{}, fmt.Errorf("ticket 9: %w", usecase.ErrNotFound))
+
+		req := httptest.NewRequest("GET", "/admin/tickets/9/ledger", nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 404, resp.StatusCode)
+	})
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/usecase"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+type inventoryReconciliationHandler struct {
+	reconciliationUsecase usecase.InventoryReconciliationExecutor
+	logger                config.Logger
+}
+
+type InventoryReconciliationHandler interface {
+	CheckInventory(c *fiber.Ctx) error
+	RepairInventory(c *fiber.Ctx) error
+}
+
+func NewInventoryReconciliationHandler(reconciliationUsecase usecase.InventoryReconciliationExecutor,
+	logger config.Logger) InventoryReconciliationHandler {
+	return &inventoryReconciliationHandler{reconciliationUsecase: reconciliationUsecase, logger: logger}
+}
+
+func (handler *inventoryReconciliationHandler) CheckInventory(c *fiber.Ctx) error {
+	report, err := handler.reconciliationUsecase.CheckInventory(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: report,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *inventoryReconciliationHandler) RepairInventory(c *fiber.Ctx) error {
+	var request model.InventoryRepairRequest
+	if err := c.BodyParser(&request); err != nil {
+		return fmt.Errorf("inventory repair request: %w", usecase.ErrInvalidParam)
+	}
+
+	if err := validate.Struct(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(util.ValidatorErrorResponse(util.ValidateStruct(err)))
+	}
+
+	report, err := handler.reconciliationUsecase.RepairInventory(c.UserContext(), request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: report,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/usecase"
+	"github.com/SyamSolution/ticket-management-service/mock"
+	mock_config "github.com/SyamSolution/ticket-management-service/mock/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCheckInventory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockReconciliationUsecase := mock.NewMockInventoryReconciliationExecutor(ctrl)
+	mockLogger := mock_config.NewMockLogger(ctrl)
+
+	handler := NewInventoryReconciliationHandler(mockReconciliationUsecase, mockLogger)
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(mockLogger)})
+	app.Get("/admin/inventory/reconciliation", handler.CheckInventory)
+
+	mockReconciliationUsecase.EXPECT().CheckInventory(gomock.Any()).
+		Return(model.InventoryReport{DryRun: true, Checked: 4, Drifted: 1}, nil)
+
+	req := httptest.NewRequest("GET", "/admin/inventory/reconciliation", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
+func TestRepairInventory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockReconciliationUsecase := mock.NewMockInventoryReconciliationExecutor(ctrl)
+	mockLogger := mock_config.NewMockLogger(ctrl)
+
+	handler := NewInventoryReconciliationHandler(mockReconciliationUsecase, mockLogger)
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(mockLogger)})
+	app.Post("/admin/inventory/reconciliation/repair", handler.RepairInventory)
+
+	t.Run("should repair the named tickets", func(t *testing.T) {
+		mockReconciliationUsecase.EXPECT().RepairInventory(gomock.Any(), model.InventoryRepairRequest{
+			Tickets: []model.InventoryRepairTicket{{
+				TicketID: 2,
+				Current:  model.StockCounters{Total: 10, Available: 8, Held: 2},
+				Expected: model.StockCounters{Total: 10, Available: 10},
+			}},
+		}).Return(model.InventoryReport{Checked: 1, Drifted: 1, Repaired: 1}, nil)
+
+		body := `{"tickets":[{"ticket_id":2,"status":"drifted",` +
+			`"current":{"total":10,"available":8,"held":2,"sold":0},"expected":{"total":10,"available":10,"held":0,"sold":0}}]}`
+		req := httptest.NewRequest("POST", "/admin/inventory/reconciliation/repair", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("should require the tickets to repair", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/admin/inventory/reconciliation/repair", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+
+	t.Run("should report unknown tickets", func(t *testing.T) {
+		mockReconciliationUsecase.EXPECT().RepairInventory(gomock.Any(), gomock.Any()).
+			Return(model.InventoryReport{}, fmt.Errorf("tickets [9]: %w", usecase.ErrNotFound))
+
+		req := httptest.NewRequest("POST", "/admin/inventory/reconciliation/repair", strings.NewReader(`{"tickets":[{"ticket_id":9}]}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 404, resp.StatusCode)
+	})
+}
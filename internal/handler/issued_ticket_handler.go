@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/usecase"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+type issuedTicketHandler struct {
+	issuedTicketUsecase usecase.IssuedTicketExecutor
+	logger              config.Logger
+}
+
+type IssuedTicketHandler interface {
+	GetMyTickets(c *fiber.Ctx) error
+	GetVerificationKey(c *fiber.Ctx) error
+	CheckIn(c *fiber.Ctx) error
+	SyncCheckIns(c *fiber.Ctx) error
+}
+
+func NewIssuedTicketHandler(issuedTicketUsecase usecase.IssuedTicketExecutor, logger config.Logger) IssuedTicketHandler {
+	return &issuedTicketHandler{issuedTicketUsecase: issuedTicketUsecase, logger: logger}
+}
+
+func (handler *issuedTicketHandler) GetMyTickets(c *fiber.Ctx) error {
+	email, ok := c.Locals("email").(string)
+	if !ok || email == "" {
+		return util.AbortUnauthorized(c)
+	}
+
+	tickets, err := handler.issuedTicketUsecase.GetMyTickets(c.UserContext(), email)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: tickets,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *issuedTicketHandler) GetVerificationKey(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: handler.issuedTicketUsecase.GetVerificationKey(c.UserContext()),
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *issuedTicketHandler) CheckIn(c *fiber.Ctx) error {
+	eventID, err := intParam(c, "event_id")
+	if err != nil {
+		return err
+	}
+
+	var request model.CheckInRequest
+	if err := c.BodyParser(&request); err != nil {
+		return fmt.Errorf("check in request: %w", usecase.ErrInvalidParam)
+	}
+
+	if err := validate.Struct(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(util.ValidatorErrorResponse(util.ValidateStruct(err)))
+	}
+
+	scan, err := handler.issuedTicketUsecase.CheckIn(c.UserContext(), eventID, request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: scan,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *issuedTicketHandler) SyncCheckIns(c *fiber.Ctx) error {
+	eventID, err := intParam(c, "event_id")
+	if err != nil {
+		return err
+	}
+
+	var request model.CheckInSyncRequest
+	if err := c.BodyParser(&request); err != nil {
+		return fmt.Errorf("check in sync request: %w", usecase.ErrInvalidParam)
+	}
+
+	if err := validate.Struct(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(util.ValidatorErrorResponse(util.ValidateStruct(err)))
+	}
+
+	scans, err := handler.issuedTicketUsecase.SyncCheckIns(c.UserContext(), eventID, request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: scans,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/mock"
+	mock_config "github.com/SyamSolution/ticket-management-service/mock/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetMyTickets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockIssuedTicketUsecase := mock.NewMockIssuedTicketExecutor(ctrl)
+	mockLogger := mock_config.NewMockLogger(ctrl)
+
+	handler := NewIssuedTicketHandler(mockIssuedTicketUsecase, mockLogger)
+
+	t.Run("should list the tickets of the user", func(t *testing.T) {
+		app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(mockLogger)})
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals("email", "user@mail.com")
+			return c.Next()
+		})
+		app.Get("/me/tickets", handler.GetMyTickets)
+
+		mockIssuedTicketUsecase.EXPECT().GetMyTickets(gomock.Any(), "user@mail.com").
+			Return([]model.IssuedTicket{{IssuedTicketID: 1, Code: "CODEONE", Status: "valid", Payload: "TK1.e30.sig"}}, nil)
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/me/tickets", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("should reject a request without a user", func(t *testing.T) {
+		app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(mockLogger)})
+		app.Get("/me/tickets", handler.GetMyTickets)
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/me/tickets", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 401, resp.StatusCode)
+	})
+}
+
+func TestCheckIn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockIssuedTicketUsecase := mock.NewMockIssuedTicketExecutor(ctrl)
+	mockLogger := mock_config.NewMockLogger(ctrl)
+
+	handler := NewIssuedTicketHandler(mockIssuedTicketUsecase, mockLogger)
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(mockLogger)})
+	app.Post("/staff/events/:event_id/check-in", handler.CheckIn)
+	app.Post("/staff/events/:event_id/check-in/sync", handler.SyncCheckIns)
+
+	t.Run("should check the ticket in", func(t *testing.T) {
+		mockIssuedTicketUsecase.EXPECT().CheckIn(gomock.Any(), 3, model.CheckInRequest{Gate: "A", Payload: "TK1.e30.sig"}).
+			Return(model.TicketScan{Code: "CODEONE", EventID: 3, Gate: "A", Result: "accepted"}, nil)
+
+		req := httptest.NewRequest("POST", "/staff/events/3/check-in", strings.NewReader(`{"gate":"A","payload":"TK1.e30.sig"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("should require a gate", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/staff/events/3/check-in", strings.NewReader(`{"payload":"TK1.e30.sig"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+
+	t.Run("should sync offline scans", func(t *testing.T) {
+		mockIssuedTicketUsecase.EXPECT().SyncCheckIns(gomock.Any(), 3, gomock.Any()).
+			Return([]model.TicketScan{{ScanRef: "ref-1", Result: "accepted", Offline: true}}, nil)
+
+		body := `{"gate":"A","scans":[{"scan_ref":"ref-1","payload":"TK1.e30.sig","scanned_at":"2026-12-01T18:00:00Z"}]}`
+		req := httptest.NewRequest("POST", "/staff/events/3/check-in/sync", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("should reject a scan without a reference", func(t *testing.T) {
+		body := `{"gate":"A","scans":[{"payload":"TK1.e30.sig","scanned_at":"2026-12-01T18:00:00Z"}]}`
+		req := httptest.NewRequest("POST", "/staff/events/3/check-in/sync", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/usecase"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+type promoHandler struct {
+	promoUsecase usecase.PromoExecutor
+	logger       config.Logger
+}
+
+type PromoHandler interface {
+	CreatePromo(c *fiber.Ctx) error
+	GetPromos(c *fiber.Ctx) error
+	GetPromo(c *fiber.Ctx) error
+	UpdatePromo(c *fiber.Ctx) error
+	DeletePromo(c *fiber.Ctx) error
+	Quote(c *fiber.Ctx) error
+}
+
+func NewPromoHandler(promoUsecase usecase.PromoExecutor, logger config.Logger) PromoHandler {
+	return &promoHandler{promoUsecase: promoUsecase, logger: logger}
+}
+
+func (handler *promoHandler) CreatePromo(c *fiber.Ctx) error {
+	var request model.PromoRequest
+	if err := c.BodyParser(&request); err != nil {
+		return fmt.Errorf("promo request: %w", usecase.ErrInvalidParam)
+	}
+
+	if err := validate.Struct(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(util.ValidatorErrorResponse(util.ValidateStruct(err)))
+	}
+
+	promo, err := handler.promoUsecase.CreatePromo(c.UserContext(), request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: promo,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *promoHandler) GetPromos(c *fiber.Ctx) error {
+	promos, err := handler.promoUsecase.GetPromos(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: promos,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *promoHandler) GetPromo(c *fiber.Ctx) error {
+	promoID, err := intParam(c, "promo_id")
+	if err != nil {
+		return err
+	}
+
+	promo, err := handler.promoUsecase.GetPromo(c.UserContext(), promoID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: promo,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *promoHandler) UpdatePromo(c *fiber.Ctx) error {
+	promoID, err := intParam(c, "promo_id")
+	if err != nil {
+		return err
+	}
+
+	var request model.PromoRequest
+	if err := c.BodyParser(&request); err != nil {
+		return fmt.Errorf("promo request: %w", usecase.ErrInvalidParam)
+	}
+
+	if err := validate.Struct(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(util.ValidatorErrorResponse(util.ValidateStruct(err)))
+	}
+
+	promo, err := handler.promoUsecase.UpdatePromo(c.UserContext(), promoID, request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: promo,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *promoHandler) DeletePromo(c *fiber.Ctx) error {
+	promoID, err := intParam(c, "promo_id")
+	if err != nil {
+		return err
+	}
+
+	if err := handler.promoUsecase.DeletePromo(c.UserContext(), promoID); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *promoHandler) Quote(c *fiber.Ctx) error {
+	email, ok := c.Locals("email").(string)
+	if !ok || email == "" {
+		return util.AbortUnauthorized(c)
+	}
+
+	var request model.QuoteRequest
+	if err := c.BodyParser(&request); err != nil {
+		return fmt.Errorf("quote request: %w", usecase.ErrInvalidParam)
+	}
+
+	if err := validate.Struct(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(util.ValidatorErrorResponse(util.ValidateStruct(err)))
+	}
+
+	quote, err := handler.promoUsecase.Quote(c.UserContext(), email, request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: quote,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
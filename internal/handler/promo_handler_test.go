@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/usecase"
+	"github.com/SyamSolution/ticket-management-service/mock"
+	mock_config "github.com/SyamSolution/ticket-management-service/mock/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestQuote(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPromoUsecase := mock.NewMockPromoExecutor(ctrl)
+	mockLogger := mock_config.NewMockLogger(ctrl)
+
+	handler := NewPromoHandler(mockPromoUsecase, mockLogger)
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(mockLogger)})
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("email", "user@mail.com")
+		return c.Next()
+	})
+	app.Post("/quote", handler.Quote)
+
+	t.Run("should quote the basket", func(t *testing.T) {
+		request := model.QuoteRequest{Items: []model.QuoteItem{{TicketID: 1, Quantity: 2}}, PromoCode: "SAVE"}
+		mockPromoUsecase.EXPECT().Quote(gomock.Any(), "user@mail.com", request).
+			Return(model.Quote{PromoCode: "SAVE"}, nil)
+
+		req := httptest.NewRequest("POST", "/quote", strings.NewReader(`{"items":[{"ticket_id":1,"quantity":2}],"promo_code":"SAVE"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("should reject an empty basket", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/quote", strings.NewReader(`{"items":[]}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+}
+
+func TestCreatePromo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPromoUsecase := mock.NewMockPromoExecutor(ctrl)
+	mockLogger := mock_config.NewMockLogger(ctrl)
+
+	handler := NewPromoHandler(mockPromoUsecase, mockLogger)
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(mockLogger)})
+	app.Post("/admin/promos", handler.CreatePromo)
+
+	t.Run("should reject an unknown discount type", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/admin/promos", strings.NewReader(`{"code":"SAVE","discount_type":"bogo","discount_value":1}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+
+	t.Run("should map a duplicate code to conflict", func(t *testing.T) {
+		mockPromoUsecase.EXPECT().CreatePromo(gomock.Any(), gomock.Any()).Return(model.Promo{}, usecase.ErrConflict)
+
+		req := httptest.NewRequest("POST", "/admin/promos", strings.NewReader(`{"code":"SAVE","discount_type":"percent","discount_value":10}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 409, resp.StatusCode)
+	})
+}
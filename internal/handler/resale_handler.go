@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/usecase"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+type resaleHandler struct {
+	resaleUsecase usecase.ResaleExecutor
+	logger        config.Logger
+}
+
+type ResaleHandler interface {
+	CreateListing(c *fiber.Ctx) error
+	WithdrawListing(c *fiber.Ctx) error
+	GetEventListings(c *fiber.Ctx) error
+	GetMyListings(c *fiber.Ctx) error
+	PurchaseListing(c *fiber.Ctx) error
+}
+
+func NewResaleHandler(resaleUsecase usecase.ResaleExecutor, logger config.Logger) ResaleHandler {
+	return &resaleHandler{resaleUsecase: resaleUsecase, logger: logger}
+}
+
+func (handler *resaleHandler) CreateListing(c *fiber.Ctx) error {
+	email, ok := c.Locals("email").(string)
+	if !ok || email == "" {
+		return util.AbortUnauthorized(c)
+	}
+
+	issuedTicketID, err := intParam(c, "issued_ticket_id")
+	if err != nil {
+		return err
+	}
+
+	var request model.ResaleListingRequest
+	if err := c.BodyParser(&request); err != nil {
+		return fmt.Errorf("resale listing request: %w", usecase.ErrInvalidParam)
+	}
+
+	if err := validate.Struct(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(util.ValidatorErrorResponse(util.ValidateStruct(err)))
+	}
+
+	listing, err := handler.resaleUsecase.CreateListing(c.UserContext(), email, issuedTicketID, request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: listing,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *resaleHandler) WithdrawListing(c *fiber.Ctx) error {
+	email, ok := c.Locals("email").(string)
+	if !ok || email == "" {
+		return util.AbortUnauthorized(c)
+	}
+
+	listingID, err := intParam(c, "listing_id")
+	if err != nil {
+		return err
+	}
+
+	listing, err := handler.resaleUsecase.WithdrawListing(c.UserContext(), email, listingID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: listing,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *resaleHandler) GetEventListings(c *fiber.Ctx) error {
+	eventID, err := intParam(c, "event_id")
+	if err != nil {
+		return err
+	}
+
+	listings, err := handler.resaleUsecase.GetEventListings(c.UserContext(), eventID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: listings,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *resaleHandler) GetMyListings(c *fiber.Ctx) error {
+	email, ok := c.Locals("email").(string)
+	if !ok || email == "" {
+		return util.AbortUnauthorized(c)
+	}
+
+	listings, err := handler.resaleUsecase.GetMyListings(c.UserContext(), email)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: listings,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *resaleHandler) PurchaseListing(c *fiber.Ctx) error {
+	email, ok := c.Locals("email").(string)
+	if !ok || email == "" {
+		return util.AbortUnauthorized(c)
+	}
+
+	listingID, err := intParam(c, "listing_id")
+	if err != nil {
+		return err
+	}
+
+	var request model.ResalePurchaseRequest
+	if err := c.BodyParser(&request); err != nil {
+		return fmt.Errorf("resale purchase request: %w", usecase.ErrInvalidParam)
+	}
+
+	if err := validate.Struct(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(util.ValidatorErrorResponse(util.ValidateStruct(err)))
+	}
+
+	reservation, err := handler.resaleUsecase.PurchaseListing(c.UserContext(), email, listingID, request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: reservation,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
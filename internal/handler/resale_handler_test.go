@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"github.com/SyamSolution/ticket-management-service/mock"
+	mock_config "github.com/SyamSolution/ticket-management-service/mock/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateListing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockResaleUsecase := mock.NewMockResaleExecutor(ctrl)
+	mockLogger := mock_config.NewMockLogger(ctrl)
+
+	handler := NewResaleHandler(mockResaleUsecase, mockLogger)
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(mockLogger)})
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("email", "seller@mail.com")
+		return c.Next()
+	})
+	app.Post("/me/tickets/:issued_ticket_id/resale", handler.CreateListing)
+
+	t.Run("should list the ticket", func(t *testing.T) {
+		mockResaleUsecase.EXPECT().CreateListing(gomock.Any(), "seller@mail.com", 1, model.ResaleListingRequest{Price: 220000}).
+			Return(model.ResaleListing{ListingID: 5, Status: "active"}, nil)
+
+		req := httptest.NewRequest("POST", "/me/tickets/1/resale", strings.NewReader(`{"price":220000}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("should require a price", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/me/tickets/1/resale", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+
+	t.Run("should reject a price above the cap", func(t *testing.T) {
+		mockResaleUsecase.EXPECT().CreateListing(gomock.Any(), "seller@mail.com", 1, gomock.Any()).
+			Return(model.ResaleListing{}, &model.BusinessError{Code: util.ERROR_RESALE_PRICE_CAP_CODE, Message: "resale price can not exceed 220000 IDR"})
+
+		req := httptest.NewRequest("POST", "/me/tickets/1/resale", strings.NewReader(`{"price":300000}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 422, resp.StatusCode)
+	})
+}
+
+func TestPurchaseListing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockResaleUsecase := mock.NewMockResaleExecutor(ctrl)
+	mockLogger := mock_config.NewMockLogger(ctrl)
+
+	handler := NewResaleHandler(mockResaleUsecase, mockLogger)
+
+	t.Run("should reserve the listing for the buyer", func(t *testing.T) {
+		app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(mockLogger)})
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals("email", "buyer@mail.com")
+			return c.Next()
+		})
+		app.Post("/resale-listings/:listing_id/purchase", handler.PurchaseListing)
+
+		mockResaleUsecase.EXPECT().PurchaseListing(gomock.Any(), "buyer@mail.com", 5, model.ResalePurchaseRequest{OrderID: "order-9"}).
+			Return(model.Reservation{ReservationID: 11, Status: "pending"}, nil)
+
+		req := httptest.NewRequest("POST", "/resale-listings/5/purchase", strings.NewReader(`{"order_id":"order-9"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("should reject a request without a user", func(t *testing.T) {
+		app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(mockLogger)})
+		app.Post("/resale-listings/:listing_id/purchase", handler.PurchaseListing)
+
+		req := httptest.NewRequest("POST", "/resale-listings/5/purchase", strings.NewReader(`{"order_id":"order-9"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 401, resp.StatusCode)
+	})
+}
+
+func TestGetEventListings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockResaleUsecase := mock.NewMockResaleExecutor(ctrl)
+	mockLogger := mock_config.NewMockLogger(ctrl)
+
+	handler := NewResaleHandler(mockResaleUsecase, mockLogger)
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(mockLogger)})
+	app.Get("/events/:event_id/resale-listings", handler.GetEventListings)
+
+	mockResaleUsecase.EXPECT().GetEventListings(gomock.Any(), 3).
+		Return([]model.ResaleListing{{ListingID: 5, SellerEmail: "seller@mail.com", Status: "active"}}, nil)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/events/3/resale-listings", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/usecase"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+type seatHandler struct {
+	seatUsecase usecase.SeatExecutor
+	logger      config.Logger
+}
+
+type SeatHandler interface {
+	GetSeatMap(c *fiber.Ctx) error
+	ReserveSeats(c *fiber.Ctx) error
+}
+
+func NewSeatHandler(seatUsecase usecase.SeatExecutor, logger config.Logger) SeatHandler {
+	return &seatHandler{seatUsecase: seatUsecase, logger: logger}
+}
+
+func (handler *seatHandler) GetSeatMap(c *fiber.Ctx) error {
+	eventID, err := intParam(c, "event_id")
+	if err != nil {
+		return err
+	}
+
+	seatMap, err := handler.seatUsecase.GetSeatMap(c.UserContext(), eventID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: seatMap,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *seatHandler) ReserveSeats(c *fiber.Ctx) error {
+	email, ok := c.Locals("email").(string)
+	if !ok || email == "" {
+		return util.AbortUnauthorized(c)
+	}
+
+	eventID, err := intParam(c, "event_id")
+	if err != nil {
+		return err
+	}
+
+	var request model.ReserveSeatsRequest
+	if err := c.BodyParser(&request); err != nil {
+		return fmt.Errorf("reserve seats request: %w", usecase.ErrInvalidParam)
+	}
+
+	if err := validate.Struct(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(util.ValidatorErrorResponse(util.ValidateStruct(err)))
+	}
+
+	reservation, err := handler.seatUsecase.ReserveSeats(c.UserContext(), eventID, email, request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: reservation,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
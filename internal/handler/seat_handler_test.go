@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/usecase"
+	"github.com/SyamSolution/ticket-management-service/mock"
+	mock_config "github.com/SyamSolution/ticket-management-service/mock/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetSeatMap(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSeatUsecase := mock.NewMockSeatExecutor(ctrl)
+	mockLogger := mock_config.NewMockLogger(ctrl)
+
+	handler := NewSeatHandler(mockSeatUsecase, mockLogger)
+
+	mockSeatUsecase.EXPECT().GetSeatMap(gomock.Any(), 9).Return(model.SeatMap{EventID: 9}, nil)
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(mockLogger)})
+	app.Get("/events/:event_id/seats", handler.GetSeatMap)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/events/9/seats", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/events/abc/seats", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func TestReserveSeats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSeatUsecase := mock.NewMockSeatExecutor(ctrl)
+	mockLogger := mock_config.NewMockLogger(ctrl)
+
+	handler := NewSeatHandler(mockSeatUsecase, mockLogger)
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(mockLogger)})
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("email", "user@mail.com")
+		return c.Next()
+	})
+	app.Post("/events/:event_id/seats/reserve", handler.ReserveSeats)
+
+	t.Run("should reserve seats", func(t *testing.T) {
+		request := model.ReserveSeatsRequest{SeatIDs: []int{1, 2}, OrderID: "order-1"}
+		mockSeatUsecase.EXPECT().ReserveSeats(gomock.Any(), 9, "user@mail.com", request).
+			Return(model.SeatReservation{SeatIDs: []int{1, 2}}, nil)
+
+		req := httptest.NewRequest("POST", "/events/9/seats/reserve", strings.NewReader(`{"seat_ids":[1,2],"order_id":"order-1"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("should return conflict when seats are taken", func(t *testing.T) {
+		request := model.ReserveSeatsRequest{SeatIDs: []int{3}, OrderID: "order-2"}
+		mockSeatUsecase.EXPECT().ReserveSeats(gomock.Any(), 9, "user@mail.com", request).
+			Return(model.SeatReservation{}, fmt.Errorf("seats taken: %w", usecase.ErrConflict))
+
+		req := httptest.NewRequest("POST", "/events/9/seats/reserve", strings.NewReader(`{"seat_ids":[3],"order_id":"order-2"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 409, resp.StatusCode)
+	})
+
+	t.Run("should return bad request without seats", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/events/9/seats/reserve", strings.NewReader(`{"seat_ids":[],"order_id":"order-3"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+}
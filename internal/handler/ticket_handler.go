@@ -1,16 +1,20 @@
 package handler
 
 import (
+	"fmt"
+	"net/url"
+	"strconv"
+
 	"github.com/SyamSolution/ticket-management-service/config"
 	"github.com/SyamSolution/ticket-management-service/internal/model"
 	"github.com/SyamSolution/ticket-management-service/internal/usecase"
 	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
-	"go.uber.org/zap"
-	"net/url"
-	"strconv"
 )
 
+var validate = validator.New()
+
 type ticketHandler struct {
 	ticketUsecase usecase.TicketExecutor
 	logger        config.Logger
@@ -22,6 +26,7 @@ type TicketHandler interface {
 	GetTicketByContinent(c *fiber.Ctx) error
 	GetStockTicketGroupByContinent(c *fiber.Ctx) error
 	GetTicketEventByTicketID(c *fiber.Ctx) error
+	ReserveTicket(c *fiber.Ctx) error
 }
 
 func NewTicketHandler(ticketUsecase usecase.TicketExecutor, logger config.Logger) TicketHandler {
@@ -29,27 +34,14 @@ func NewTicketHandler(ticketUsecase usecase.TicketExecutor, logger config.Logger
 }
 
 func (handler *ticketHandler) GetAvailableTicketByContinent(c *fiber.Ctx) error {
-	continent := c.Params("continent")
-	decodedContinent, err := url.QueryUnescape(continent)
+	continent, err := unescapeParam(c, "continent")
 	if err != nil {
-		handler.logger.Error("Error when unescaping continent", zap.Error(err))
-		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
-			Meta: model.Meta{
-				Code:    fiber.StatusInternalServerError,
-				Message: util.ERROR_BASE_MSG,
-			},
-		})
+		return err
 	}
 
-	tickets, err := handler.ticketUsecase.GetAvailableTicketByContinent(decodedContinent)
+	tickets, err := handler.ticketUsecase.GetAvailableTicketByContinent(c.UserContext(), continent, priceOptions(c))
 	if err != nil {
-		handler.logger.Error("Error when getting available ticket by continent", zap.Error(err))
-		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
-			Meta: model.Meta{
-				Code:    fiber.StatusInternalServerError,
-				Message: util.ERROR_BASE_MSG,
-			},
-		})
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(model.Response{
@@ -62,27 +54,14 @@ func (handler *ticketHandler) GetAvailableTicketByContinent(c *fiber.Ctx) error
 }
 
 func (handler *ticketHandler) GetAvailableTicketByType(c *fiber.Ctx) error {
-	ticketType := c.Params("type")
-	decodedTicketType, err := url.QueryUnescape(ticketType)
+	ticketType, err := unescapeParam(c, "type")
 	if err != nil {
-		handler.logger.Error("Error when unescaping ticket type", zap.Error(err))
-		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
-			Meta: model.Meta{
-				Code:    fiber.StatusInternalServerError,
-				Message: util.ERROR_BASE_MSG,
-			},
-		})
+		return err
 	}
 
-	tickets, err := handler.ticketUsecase.GetAvailableTicketByType(decodedTicketType)
+	tickets, err := handler.ticketUsecase.GetAvailableTicketByType(c.UserContext(), ticketType, priceOptions(c))
 	if err != nil {
-		handler.logger.Error("Error when getting available ticket by type", zap.Error(err))
-		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
-			Meta: model.Meta{
-				Code:    fiber.StatusInternalServerError,
-				Message: util.ERROR_BASE_MSG,
-			},
-		})
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(model.Response{
@@ -95,27 +74,14 @@ func (handler *ticketHandler) GetAvailableTicketByType(c *fiber.Ctx) error {
 }
 
 func (handler *ticketHandler) GetTicketByContinent(c *fiber.Ctx) error {
-	continent := c.Params("continent")
-	decodedContinent, err := url.QueryUnescape(continent)
+	continent, err := unescapeParam(c, "continent")
 	if err != nil {
-		handler.logger.Error("Error when unescaping continent", zap.Error(err))
-		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
-			Meta: model.Meta{
-				Code:    fiber.StatusInternalServerError,
-				Message: util.ERROR_BASE_MSG,
-			},
-		})
+		return err
 	}
 
-	tickets, err := handler.ticketUsecase.GetTicketByContinent(decodedContinent)
+	tickets, err := handler.ticketUsecase.GetTicketByContinent(c.UserContext(), continent, priceOptions(c))
 	if err != nil {
-		handler.logger.Error("Error when getting ticket by continent", zap.Error(err))
-		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
-			Meta: model.Meta{
-				Code:    fiber.StatusInternalServerError,
-				Message: util.ERROR_BASE_MSG,
-			},
-		})
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(model.Response{
@@ -128,15 +94,9 @@ func (handler *ticketHandler) GetTicketByContinent(c *fiber.Ctx) error {
 }
 
 func (handler *ticketHandler) GetStockTicketGroupByContinent(c *fiber.Ctx) error {
-	tickets, err := handler.ticketUsecase.GetStockTicketGroupByContinent()
+	tickets, err := handler.ticketUsecase.GetStockTicketGroupByContinent(c.UserContext())
 	if err != nil {
-		handler.logger.Error("Error when getting stock ticket group by continent", zap.Error(err))
-		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
-			Meta: model.Meta{
-				Code:    fiber.StatusInternalServerError,
-				Message: util.ERROR_BASE_MSG,
-			},
-		})
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(model.Response{
@@ -149,17 +109,14 @@ func (handler *ticketHandler) GetStockTicketGroupByContinent(c *fiber.Ctx) error
 }
 
 func (handler *ticketHandler) GetTicketEventByTicketID(c *fiber.Ctx) error {
-	ticketID := c.Params("ticket_id")
-	ticketIDInt, _ := strconv.Atoi(ticketID)
-	ticketEvent, err := handler.ticketUsecase.GetTicketEventByTicketID(ticketIDInt)
+	ticketID, err := intParam(c, "ticket_id")
 	if err != nil {
-		handler.logger.Error("Error when getting ticket event by continent", zap.Error(err))
-		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
-			Meta: model.Meta{
-				Code:    fiber.StatusInternalServerError,
-				Message: util.ERROR_BASE_MSG,
-			},
-		})
+		return err
+	}
+
+	ticketEvent, err := handler.ticketUsecase.GetTicketEventByTicketID(c.UserContext(), ticketID, priceOptions(c))
+	if err != nil {
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(model.Response{
@@ -170,3 +127,61 @@ func (handler *ticketHandler) GetTicketEventByTicketID(c *fiber.Ctx) error {
 		},
 	})
 }
+
+func (handler *ticketHandler) ReserveTicket(c *fiber.Ctx) error {
+	email, ok := c.Locals("email").(string)
+	if !ok || email == "" {
+		return util.AbortUnauthorized(c)
+	}
+
+	var request model.ReserveTicketRequest
+	if err := c.BodyParser(&request); err != nil {
+		return fmt.Errorf("reserve ticket request: %w", usecase.ErrInvalidParam)
+	}
+
+	if err := validate.Struct(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(util.ValidatorErrorResponse(util.ValidateStruct(err)))
+	}
+
+	reservation, err := handler.ticketUsecase.ReserveTicket(c.UserContext(), model.MessageOrderTicket{
+		TicketID:  request.TicketID,
+		Order:     request.Order,
+		OrderID:   request.OrderID,
+		Email:     email,
+		Region:    request.Region,
+		PromoCode: request.PromoCode,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: reservation,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+// priceOptions reads the optional "currency" and "region" query parameters
+// that select how ticket prices are shown.
+func priceOptions(c *fiber.Ctx) model.PriceOptions {
+	return model.PriceOptions{Currency: c.Query("currency"), Region: c.Query("region")}
+}
+
+func unescapeParam(c *fiber.Ctx, key string) (string, error) {
+	value, err := url.QueryUnescape(c.Params(key))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", key, usecase.ErrInvalidParam)
+	}
+	return value, nil
+}
+
+func intParam(c *fiber.Ctx, key string) (int, error) {
+	value, err := strconv.Atoi(c.Params(key))
+	if err != nil {
+		return 0, fmt.Errorf("%s must be numeric: %w", key, usecase.ErrInvalidParam)
+	}
+	return value, nil
+}
@@ -1,13 +1,16 @@
 package handler
 
 import (
+	"fmt"
 	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/usecase"
 	"github.com/SyamSolution/ticket-management-service/mock"
 	mock_config "github.com/SyamSolution/ticket-management-service/mock/config"
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -24,7 +27,7 @@ func TestGetAvailableTicketByContinent(t *testing.T) {
 
 	continent := "Asia"
 
-	mockTicketUsecase.EXPECT().GetAvailableTicketByContinent(continent).Return(expectedTickets, nil)
+	mockTicketUsecase.EXPECT().GetAvailableTicketByContinent(gomock.Any(), continent, model.PriceOptions{}).Return(expectedTickets, nil)
 
 	app := fiber.New()
 	app.Get("/tickets/:continent", handler.GetAvailableTicketByContinent)
@@ -49,12 +52,12 @@ func TestGetAvailableTicketByType(t *testing.T) {
 
 	ticketType := "Type1"
 
-	mockTicketUsecase.EXPECT().GetAvailableTicketByType(ticketType).Return(expectedTickets, nil)
+	mockTicketUsecase.EXPECT().GetAvailableTicketByType(gomock.Any(), ticketType, model.PriceOptions{Currency: "EUR", Region: "SG"}).Return(expectedTickets, nil)
 
 	app := fiber.New()
 	app.Get("/tickets/type/:type", handler.GetAvailableTicketByType)
 
-	req := httptest.NewRequest("GET", "/tickets/type/"+ticketType, nil)
+	req := httptest.NewRequest("GET", "/tickets/type/"+ticketType+"?currency=EUR&region=SG", nil)
 	resp, err := app.Test(req)
 
 	assert.NoError(t, err)
@@ -74,7 +77,7 @@ func TestGetTicketByContinent(t *testing.T) {
 
 	continent := "Asia"
 
-	mockTicketUsecase.EXPECT().GetTicketByContinent(continent).Return(expectedTickets, nil)
+	mockTicketUsecase.EXPECT().GetTicketByContinent(gomock.Any(), continent, model.PriceOptions{}).Return(expectedTickets, nil)
 
 	app := fiber.New()
 	app.Get("/tickets/:continent", handler.GetTicketByContinent)
@@ -97,7 +100,7 @@ func TestGetStockTicketGroupByContinent(t *testing.T) {
 
 	expectedTickets := []model.StockTicket{{}}
 
-	mockTicketUsecase.EXPECT().GetStockTicketGroupByContinent().Return(expectedTickets, nil)
+	mockTicketUsecase.EXPECT().GetStockTicketGroupByContinent(gomock.Any()).Return(expectedTickets, nil)
 
 	app := fiber.New()
 	app.Get("/tickets/stock", handler.GetStockTicketGroupByContinent)
@@ -122,7 +125,7 @@ func TestGetTicketEventByTicketID(t *testing.T) {
 
 	ticketID := "1"
 
-	mockTicketUsecase.EXPECT().GetTicketEventByTicketID(1).Return(expectedTicketEvent, nil)
+	mockTicketUsecase.EXPECT().GetTicketEventByTicketID(gomock.Any(), 1, model.PriceOptions{}).Return(expectedTicketEvent, nil)
 
 	app := fiber.New()
 	app.Get("/tickets/event/:ticket_id", handler.GetTicketEventByTicketID)
@@ -133,3 +136,94 @@ func TestGetTicketEventByTicketID(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 200, resp.StatusCode)
 }
+
+func TestReserveTicket(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTicketUsecase := mock.NewMockTicketExecutor(ctrl)
+	mockLogger := mock_config.NewMockLogger(ctrl)
+
+	handler := NewTicketHandler(mockTicketUsecase, mockLogger)
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(mockLogger)})
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("email", "user@mail.com")
+		return c.Next()
+	})
+	app.Post("/tickets/reserve", handler.ReserveTicket)
+
+	t.Run("should reserve ticket", func(t *testing.T) {
+		mockTicketUsecase.EXPECT().ReserveTicket(gomock.Any(), model.MessageOrderTicket{TicketID: 1, Order: 2, OrderID: "order-1", Email: "user@mail.com"}).
+			Return(model.Reservation{ReservationID: 1}, nil)
+
+		req := httptest.NewRequest("POST", "/tickets/reserve", strings.NewReader(`{"ticket_id":1,"order":2,"order_id":"order-1"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("should return business error when purchase limit exceeded", func(t *testing.T) {
+		mockTicketUsecase.EXPECT().ReserveTicket(gomock.Any(), model.MessageOrderTicket{TicketID: 1, Order: 5, OrderID: "order-2", Email: "user@mail.com"}).
+			Return(model.Reservation{}, &model.BusinessError{Code: 4104, Message: "limit exceeded"})
+
+		req := httptest.NewRequest("POST", "/tickets/reserve", strings.NewReader(`{"ticket_id":1,"order":5,"order_id":"order-2"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 422, resp.StatusCode)
+	})
+
+	t.Run("should return bad request when order id is missing", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/tickets/reserve", strings.NewReader(`{"ticket_id":1,"order":2}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+
+	t.Run("should return bad request when order is missing", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/tickets/reserve", strings.NewReader(`{"ticket_id":1}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+}
+
+func TestGetTicketEventByTicketIDError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTicketUsecase := mock.NewMockTicketExecutor(ctrl)
+	mockLogger := mock_config.NewMockLogger(ctrl)
+
+	handler := NewTicketHandler(mockTicketUsecase, mockLogger)
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(mockLogger)})
+	app.Get("/tickets/event/:ticket_id", handler.GetTicketEventByTicketID)
+
+	t.Run("should return bad request for non numeric ticket id", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/tickets/event/abc", nil)
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+
+	t.Run("should return not found for unknown ticket", func(t *testing.T) {
+		mockTicketUsecase.EXPECT().GetTicketEventByTicketID(gomock.Any(), 99, model.PriceOptions{}).
+			Return(model.TicketEvent{}, fmt.Errorf("ticket 99: %w", usecase.ErrNotFound))
+
+		req := httptest.NewRequest("GET", "/tickets/event/99", nil)
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 404, resp.StatusCode)
+	})
+}
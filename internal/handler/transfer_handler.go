@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/usecase"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+type transferHandler struct {
+	transferUsecase usecase.TransferExecutor
+	logger          config.Logger
+}
+
+type TransferHandler interface {
+	InitiateTransfer(c *fiber.Ctx) error
+	AcceptTransfer(c *fiber.Ctx) error
+	DeclineTransfer(c *fiber.Ctx) error
+	CancelTransfer(c *fiber.Ctx) error
+	GetMyTransfers(c *fiber.Ctx) error
+	GetTicketTransfers(c *fiber.Ctx) error
+}
+
+func NewTransferHandler(transferUsecase usecase.TransferExecutor, logger config.Logger) TransferHandler {
+	return &transferHandler{transferUsecase: transferUsecase, logger: logger}
+}
+
+func (handler *transferHandler) InitiateTransfer(c *fiber.Ctx) error {
+	email, ok := c.Locals("email").(string)
+	if !ok || email == "" {
+		return util.AbortUnauthorized(c)
+	}
+
+	issuedTicketID, err := intParam(c, "issued_ticket_id")
+	if err != nil {
+		return err
+	}
+
+	var request model.TransferRequest
+	if err := c.BodyParser(&request); err != nil {
+		return fmt.Errorf("transfer request: %w", usecase.ErrInvalidParam)
+	}
+
+	if err := validate.Struct(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(util.ValidatorErrorResponse(util.ValidateStruct(err)))
+	}
+
+	transfer, err := handler.transferUsecase.InitiateTransfer(c.UserContext(), email, issuedTicketID, request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: transfer,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *transferHandler) AcceptTransfer(c *fiber.Ctx) error {
+	return handler.respond(c, handler.transferUsecase.AcceptTransfer)
+}
+
+func (handler *transferHandler) DeclineTransfer(c *fiber.Ctx) error {
+	return handler.respond(c, handler.transferUsecase.DeclineTransfer)
+}
+
+func (handler *transferHandler) CancelTransfer(c *fiber.Ctx) error {
+	return handler.respond(c, handler.transferUsecase.CancelTransfer)
+}
+
+// respond applies the answer of the current user to the transfer in the path.
+func (handler *transferHandler) respond(c *fiber.Ctx,
+	answer func(ctx context.Context, email string, transferID int) (model.TicketTransfer, error)) error {
+	email, ok := c.Locals("email").(string)
+	if !ok || email == "" {
+		return util.AbortUnauthorized(c)
+	}
+
+	transferID, err := intParam(c, "transfer_id")
+	if err != nil {
+		return err
+	}
+
+	transfer, err := answer(c.UserContext(), email, transferID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: transfer,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *transferHandler) GetMyTransfers(c *fiber.Ctx) error {
+	email, ok := c.Locals("email").(string)
+	if !ok || email == "" {
+		return util.AbortUnauthorized(c)
+	}
+
+	transfers, err := handler.transferUsecase.GetMyTransfers(c.UserContext(), email)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: transfers,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *transferHandler) GetTicketTransfers(c *fiber.Ctx) error {
+	issuedTicketID, err := intParam(c, "issued_ticket_id")
+	if err != nil {
+		return err
+	}
+
+	transfers, err := handler.transferUsecase.GetTicketTransfers(c.UserContext(), issuedTicketID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: transfers,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/usecase"
+	"github.com/SyamSolution/ticket-management-service/mock"
+	mock_config "github.com/SyamSolution/ticket-management-service/mock/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestInitiateTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransferUsecase := mock.NewMockTransferExecutor(ctrl)
+	mockLogger := mock_config.NewMockLogger(ctrl)
+
+	handler := NewTransferHandler(mockTransferUsecase, mockLogger)
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(mockLogger)})
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("email", "owner@mail.com")
+		return c.Next()
+	})
+	app.Post("/me/tickets/:issued_ticket_id/transfer", handler.InitiateTransfer)
+
+	t.Run("should offer the ticket", func(t *testing.T) {
+		mockTransferUsecase.EXPECT().InitiateTransfer(gomock.Any(), "owner@mail.com", 1, model.TransferRequest{ToEmail: "friend@mail.com"}).
+			Return(model.TicketTransfer{TransferID: 7, Status: "pending"}, nil)
+
+		req := httptest.NewRequest("POST", "/me/tickets/1/transfer", strings.NewReader(`{"to_email":"friend@mail.com"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("should require a valid recipient email", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/me/tickets/1/transfer", strings.NewReader(`{"to_email":"friend"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+
+	t.Run("should report a pending transfer as conflict", func(t *testing.T) {
+		mockTransferUsecase.EXPECT().InitiateTransfer(gomock.Any(), "owner@mail.com", 1, gomock.Any()).
+			Return(model.TicketTransfer{}, fmt.Errorf("transfer: %w", usecase.ErrConflict))
+
+		req := httptest.NewRequest("POST", "/me/tickets/1/transfer", strings.NewReader(`{"to_email":"friend@mail.com"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 409, resp.StatusCode)
+	})
+}
+
+func TestAnswerTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransferUsecase := mock.NewMockTransferExecutor(ctrl)
+	mockLogger := mock_config.NewMockLogger(ctrl)
+
+	handler := NewTransferHandler(mockTransferUsecase, mockLogger)
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(mockLogger)})
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("email", "friend@mail.com")
+		return c.Next()
+	})
+	app.Post("/me/transfers/:transfer_id/accept", handler.AcceptTransfer)
+	app.Post("/me/transfers/:transfer_id/decline", handler.DeclineTransfer)
+
+	t.Run("should accept the transfer", func(t *testing.T) {
+		mockTransferUsecase.EXPECT().AcceptTransfer(gomock.Any(), "friend@mail.com", 7).
+			Return(model.TicketTransfer{TransferID: 7, Status: "accepted"}, nil)
+
+		resp, err := app.Test(httptest.NewRequest("POST", "/me/transfers/7/accept", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("should hide transfers of other users", func(t *testing.T) {
+		mockTransferUsecase.EXPECT().DeclineTransfer(gomock.Any(), "friend@mail.com", 8).
+			Return(model.TicketTransfer{}, fmt.Errorf("transfer: %w", usecase.ErrNotFound))
+
+		resp, err := app.Test(httptest.NewRequest("POST", "/me/transfers/8/decline", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 404, resp.StatusCode)
+	})
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/usecase"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+type waitlistHandler struct {
+	waitlistUsecase usecase.WaitlistExecutor
+	logger          config.Logger
+}
+
+type WaitlistHandler interface {
+	JoinWaitlist(c *fiber.Ctx) error
+	LeaveWaitlist(c *fiber.Ctx) error
+	GetMyWaitlist(c *fiber.Ctx) error
+	ClaimOffer(c *fiber.Ctx) error
+}
+
+func NewWaitlistHandler(waitlistUsecase usecase.WaitlistExecutor, logger config.Logger) WaitlistHandler {
+	return &waitlistHandler{waitlistUsecase: waitlistUsecase, logger: logger}
+}
+
+func (handler *waitlistHandler) JoinWaitlist(c *fiber.Ctx) error {
+	email, ok := c.Locals("email").(string)
+	if !ok || email == "" {
+		return util.AbortUnauthorized(c)
+	}
+
+	ticketID, err := intParam(c, "ticket_id")
+	if err != nil {
+		return err
+	}
+
+	var request model.WaitlistRequest
+	if err := c.BodyParser(&request); err != nil {
+		return fmt.Errorf("waitlist request: %w", usecase.ErrInvalidParam)
+	}
+
+	if err := validate.Struct(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(util.ValidatorErrorResponse(util.ValidateStruct(err)))
+	}
+
+	entry, err := handler.waitlistUsecase.JoinWaitlist(c.UserContext(), email, ticketID, request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: entry,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *waitlistHandler) LeaveWaitlist(c *fiber.Ctx) error {
+	email, ok := c.Locals("email").(string)
+	if !ok || email == "" {
+		return util.AbortUnauthorized(c)
+	}
+
+	waitlistID, err := intParam(c, "waitlist_id")
+	if err != nil {
+		return err
+	}
+
+	entry, err := handler.waitlistUsecase.LeaveWaitlist(c.UserContext(), email, waitlistID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: entry,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *waitlistHandler) GetMyWaitlist(c *fiber.Ctx) error {
+	email, ok := c.Locals("email").(string)
+	if !ok || email == "" {
+		return util.AbortUnauthorized(c)
+	}
+
+	entries, err := handler.waitlistUsecase.GetMyWaitlist(c.UserContext(), email)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: entries,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
+
+func (handler *waitlistHandler) ClaimOffer(c *fiber.Ctx) error {
+	email, ok := c.Locals("email").(string)
+	if !ok || email == "" {
+		return util.AbortUnauthorized(c)
+	}
+
+	waitlistID, err := intParam(c, "waitlist_id")
+	if err != nil {
+		return err
+	}
+
+	var request model.WaitlistClaimRequest
+	if err := c.BodyParser(&request); err != nil {
+		return fmt.Errorf("waitlist claim request: %w", usecase.ErrInvalidParam)
+	}
+
+	if err := validate.Struct(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(util.ValidatorErrorResponse(util.ValidateStruct(err)))
+	}
+
+	reservation, err := handler.waitlistUsecase.ClaimOffer(c.UserContext(), email, waitlistID, request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: reservation,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Success",
+		},
+	})
+}
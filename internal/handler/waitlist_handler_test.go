@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/usecase"
+	"github.com/SyamSolution/ticket-management-service/mock"
+	mock_config "github.com/SyamSolution/ticket-management-service/mock/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestJoinWaitlist(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWaitlistUsecase := mock.NewMockWaitlistExecutor(ctrl)
+	mockLogger := mock_config.NewMockLogger(ctrl)
+
+	handler := NewWaitlistHandler(mockWaitlistUsecase, mockLogger)
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(mockLogger)})
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("email", "user@mail.com")
+		return c.Next()
+	})
+	app.Post("/tickets/:ticket_id/waitlist", handler.JoinWaitlist)
+
+	t.Run("should queue the user", func(t *testing.T) {
+		mockWaitlistUsecase.EXPECT().JoinWaitlist(gomock.Any(), "user@mail.com", 2, model.WaitlistRequest{Quantity: 2}).
+			Return(model.WaitlistEntry{WaitlistID: 7, Status: "waiting"}, nil)
+
+		req := httptest.NewRequest("POST", "/tickets/2/waitlist", strings.NewReader(`{"quantity":2}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("should require a quantity", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/tickets/2/waitlist", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+
+	t.Run("should reject a ticket that is not sold out", func(t *testing.T) {
+		mockWaitlistUsecase.EXPECT().JoinWaitlist(gomock.Any(), "user@mail.com", 2, gomock.Any()).
+			Return(model.WaitlistEntry{}, fmt.Errorf("ticket 2 is not sold out: %w", usecase.ErrConflict))
+
+		req := httptest.NewRequest("POST", "/tickets/2/waitlist", strings.NewReader(`{"quantity":2}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 409, resp.StatusCode)
+	})
+}
+
+func TestClaimOffer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWaitlistUsecase := mock.NewMockWaitlistExecutor(ctrl)
+	mockLogger := mock_config.NewMockLogger(ctrl)
+
+	handler := NewWaitlistHandler(mockWaitlistUsecase, mockLogger)
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(mockLogger)})
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("email", "user@mail.com")
+		return c.Next()
+	})
+	app.Post("/me/waitlist/:waitlist_id/claim", handler.ClaimOffer)
+
+	t.Run("should reserve the offered tickets", func(t *testing.T) {
+		mockWaitlistUsecase.EXPECT().ClaimOffer(gomock.Any(), "user@mail.com", 7, model.WaitlistClaimRequest{OrderID: "order-9"}).
+			Return(model.Reservation{ReservationID: 11, Status: "pending"}, nil)
+
+		req := httptest.NewRequest("POST", "/me/waitlist/7/claim", strings.NewReader(`{"order_id":"order-9"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("should require an order id", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/me/waitlist/7/claim", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+
+	t.Run("should report an expired offer as conflict", func(t *testing.T) {
+		mockWaitlistUsecase.EXPECT().ClaimOffer(gomock.Any(), "user@mail.com", 7, gomock.Any()).
+			Return(model.Reservation{}, fmt.Errorf("waitlist offer 7 is not claimable: %w", usecase.ErrConflict))
+
+		req := httptest.NewRequest("POST", "/me/waitlist/7/claim", strings.NewReader(`{"order_id":"order-9"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 409, resp.StatusCode)
+	})
+}
@@ -0,0 +1,58 @@
+package model
+
+import "time"
+
+type Event struct {
+	EventID          int        `json:"event_id"`
+	Name             string     `json:"name"`
+	Date             time.Time  `json:"date"`
+	Description      string     `json:"description"`
+	Status           string     `json:"status"`
+	SaleStartsAt     *time.Time `json:"sale_starts_at,omitempty"`
+	SaleEndsAt       *time.Time `json:"sale_ends_at,omitempty"`
+	CancelReason     string     `json:"cancel_reason,omitempty"`
+	CancelledAt      *time.Time `json:"cancelled_at,omitempty"`
+	MaxTicketPerUser int        `json:"max_ticket_per_user"`
+	ResaleCapPercent *int       `json:"resale_cap_percent,omitempty"`
+}
+
+// EventStatusRequest is an operator's change of an event's status. The other
+// statuses are only reached automatically or through a dedicated operation.
+type EventStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=on_sale paused"`
+}
+
+// EventSaleWindowRequest sets when tickets go on and off sale; either end may
+// be left open.
+type EventSaleWindowRequest struct {
+	SaleStartsAt *time.Time `json:"sale_starts_at"`
+	SaleEndsAt   *time.Time `json:"sale_ends_at"`
+}
+
+// EventResaleCapRequest opens resale of an event's tickets at prices up to
+// CapPercent of what their holders paid, or closes it when CapPercent is
+// left out.
+type EventResaleCapRequest struct {
+	CapPercent *int `json:"cap_percent" validate:"omitempty,min=1,max=1000"`
+}
+
+// EventTransitions counts the events moved by one run of the automatic status
+// transitions.
+type EventTransitions struct {
+	Past     int64 `json:"past"`
+	SoldOut  int64 `json:"sold_out"`
+	Reopened int64 `json:"reopened"`
+}
+
+type EventCancelRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+// EventCancellation reports what a cancellation did to the reservations of
+// the event: unpaid ones are released, paid ones are marked for refund.
+type EventCancellation struct {
+	EventID  int    `json:"event_id"`
+	Status   string `json:"status"`
+	Released int    `json:"released"`
+	Refunded int    `json:"refunded"`
+}
@@ -0,0 +1,27 @@
+package model
+
+type Continent struct {
+	ContinentID int    `json:"continent_id"`
+	Code        string `json:"code" example:"AS"`
+	Name        string `json:"name" example:"Asia"`
+}
+
+type Country struct {
+	CountryID   int    `json:"country_id"`
+	ContinentID int    `json:"continent_id"`
+	Code        string `json:"code" example:"ID"`
+	CodeAlpha3  string `json:"code_alpha3" example:"IDN"`
+	Name        string `json:"name" example:"Indonesia"`
+}
+
+type City struct {
+	CityID    int    `json:"city_id"`
+	CountryID int    `json:"country_id"`
+	Name      string `json:"name" example:"Jakarta"`
+}
+
+type Venue struct {
+	VenueID int    `json:"venue_id"`
+	CityID  int    `json:"city_id"`
+	Name    string `json:"name"`
+}
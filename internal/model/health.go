@@ -0,0 +1,12 @@
+package model
+
+type HealthResponse struct {
+	Status string                       `json:"status" example:"up"`
+	Checks map[string]HealthCheckStatus `json:"checks"`
+}
+
+type HealthCheckStatus struct {
+	Status  string `json:"status" example:"up"`
+	Latency string `json:"latency,omitempty" example:"1.2ms"`
+	Error   string `json:"error,omitempty"`
+}
@@ -0,0 +1,64 @@
+package model
+
+import "time"
+
+// IssuedTicket is one admission issued for a confirmed reservation. Payload is
+// the signed QR content built from it and is only filled in for its owner.
+type IssuedTicket struct {
+	IssuedTicketID int        `json:"issued_ticket_id"`
+	Code           string     `json:"code"`
+	ReservationID  int        `json:"reservation_id"`
+	UnitNo         int        `json:"unit_no"`
+	OrderID        string     `json:"order_id"`
+	TicketID       int        `json:"ticket_id"`
+	EventID        int        `json:"event_id"`
+	Email          string     `json:"-"`
+	Status         string     `json:"status"`
+	CheckedInAt    *time.Time `json:"checked_in_at,omitempty"`
+	CheckedInGate  string     `json:"checked_in_gate,omitempty"`
+	Type           string     `json:"type"`
+	EventName      string     `json:"event_name"`
+	EventDate      time.Time  `json:"event_date"`
+	IssuedAt       time.Time  `json:"issued_at"`
+	Payload        string     `json:"qr_payload,omitempty"`
+}
+
+// VerificationKey is what gate scanners need to check QR payloads offline.
+type VerificationKey struct {
+	Algorithm string `json:"algorithm"`
+	PublicKey string `json:"public_key"`
+}
+
+// TicketScan is one scan of a ticket at a gate and what came of it. Scans
+// taken offline carry the ScanRef the scanner gave them. CheckedInAt and
+// CheckedInGate tell when and where the ticket was let in, for accepted and
+// duplicate scans.
+type TicketScan struct {
+	ScanID        int        `json:"scan_id"`
+	ScanRef       string     `json:"scan_ref,omitempty"`
+	Code          string     `json:"code,omitempty"`
+	EventID       int        `json:"event_id"`
+	Gate          string     `json:"gate"`
+	Result        string     `json:"result"`
+	Offline       bool       `json:"offline"`
+	ScannedAt     time.Time  `json:"scanned_at"`
+	CheckedInAt   *time.Time `json:"checked_in_at,omitempty"`
+	CheckedInGate string     `json:"checked_in_gate,omitempty"`
+}
+
+type CheckInRequest struct {
+	Gate    string `json:"gate" validate:"required,max=50"`
+	Payload string `json:"payload" validate:"required"`
+}
+
+// CheckInSyncRequest uploads the scans a scanner took while offline at Gate.
+type CheckInSyncRequest struct {
+	Gate  string        `json:"gate" validate:"required,max=50"`
+	Scans []OfflineScan `json:"scans" validate:"required,min=1,max=500,dive"`
+}
+
+type OfflineScan struct {
+	ScanRef   string    `json:"scan_ref" validate:"required,max=64"`
+	Payload   string    `json:"payload" validate:"required"`
+	ScannedAt time.Time `json:"scanned_at" validate:"required"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// InventoryLedgerEntry is one change of the stock counts of a ticket. Actor
+// is who made the change and RequestID the request or message it was made
+// for.
+type InventoryLedgerEntry struct {
+	EntryID    int64         `json:"entry_id"`
+	TicketID   int           `json:"ticket_id"`
+	Change     StockCounters `json:"change"`
+	Reason     string        `json:"reason"`
+	OrderID    string        `json:"order_id,omitempty"`
+	WaitlistID int           `json:"waitlist_id,omitempty"`
+	RequestID  string        `json:"request_id,omitempty"`
+	Actor      string        `json:"actor"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+// LedgerBalance compares the counters of a ticket with the ones recomputed
+// from its ledger.
+type LedgerBalance struct {
+	TicketID   int           `json:"ticket_id"`
+	Current    StockCounters `json:"current"`
+	Recomputed StockCounters `json:"recomputed"`
+	EntryCount int           `json:"entry_count"`
+}
+
+// Consistent reports whether the ledger adds up to the current counters.
+func (b LedgerBalance) Consistent() bool {
+	return b.Current == b.Recomputed
+}
+
+// InventoryLedger is a page of the ledger of a ticket together with its
+// balance.
+type InventoryLedger struct {
+	LedgerBalance
+	Consistent bool                   `json:"consistent"`
+	Entries    []InventoryLedgerEntry `json:"entries"`
+}
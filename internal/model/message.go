@@ -1,13 +1,15 @@
 package model
 
+import "time"
+
 type Message struct {
-	OrderID      string  `json:"order_id"`
-	Email        string  `json:"email"`
-	URL          string  `json:"url"`
-	Name         string  `json:"name"`
-	Date         string  `json:"date"`
-	DeadlineDate string  `json:"deadline_date"`
-	Total        float32 `json:"total"`
+	OrderID      string `json:"order_id"`
+	Email        string `json:"email"`
+	URL          string `json:"url"`
+	Name         string `json:"name"`
+	Date         string `json:"date"`
+	DeadlineDate string `json:"deadline_date"`
+	Total        Money  `json:"total"`
 }
 
 type CompleteTransactionMessage struct {
@@ -15,6 +17,40 @@ type CompleteTransactionMessage struct {
 }
 
 type MessageOrderTicket struct {
-	TicketID int `json:"ticket_id"`
-	Order    int `json:"order"`
+	TicketID   int    `json:"ticket_id"`
+	Order      int    `json:"order"`
+	OrderID    string `json:"order_id,omitempty"`
+	Email      string `json:"email,omitempty"`
+	Region     string `json:"region,omitempty"`
+	PromoCode  string `json:"promo_code,omitempty"`
+	ListingID  int    `json:"listing_id,omitempty"`
+	WaitlistID int    `json:"waitlist_id,omitempty"`
+}
+
+// MessageWaitlistOffer tells the notification service that tickets were set
+// aside for a waitlisted user, who has until OfferExpiresAt to claim them.
+type MessageWaitlistOffer struct {
+	WaitlistID     int       `json:"waitlist_id"`
+	TicketID       int       `json:"ticket_id"`
+	Email          string    `json:"email"`
+	Quantity       int       `json:"quantity"`
+	OfferExpiresAt time.Time `json:"offer_expires_at"`
+}
+
+// MessageEventCancelled tells the notification and payment services that a
+// reservation was voided because its event was cancelled. Action is released
+// for unpaid reservations and refund for paid ones, which carry the amount
+// paid in Refund.
+type MessageEventCancelled struct {
+	EventID       int       `json:"event_id"`
+	EventName     string    `json:"event_name"`
+	ReservationID int       `json:"reservation_id"`
+	OrderID       string    `json:"order_id,omitempty"`
+	Email         string    `json:"email"`
+	TicketID      int       `json:"ticket_id"`
+	Quantity      int       `json:"quantity"`
+	Action        string    `json:"action"`
+	Refund        Money     `json:"refund"`
+	Reason        string    `json:"reason"`
+	CancelledAt   time.Time `json:"cancelled_at"`
 }
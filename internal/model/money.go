@@ -0,0 +1,54 @@
+package model
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+)
+
+// Money is an amount in the minor unit of its ISO 4217 currency, e.g. 1050
+// USD is $10.50 and 1050 JPY is ¥1050. Amounts are never floats so sums and
+// conversions stay exact.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// currencyExponents lists the ISO 4217 currencies whose minor unit is not a
+// hundredth.
+var currencyExponents = map[string]int{
+	"BHD": 3, "BIF": 0, "CLF": 4, "CLP": 0, "DJF": 0, "GNF": 0, "IQD": 3, "ISK": 0,
+	"JOD": 3, "JPY": 0, "KMF": 0, "KRW": 0, "KWD": 3, "LYD": 3, "OMR": 3, "PYG": 0,
+	"RWF": 0, "TND": 3, "UGX": 0, "UYI": 0, "UYW": 4, "VND": 0, "VUV": 0, "XAF": 0,
+	"XOF": 0, "XPF": 0,
+}
+
+// CurrencyExponent is the number of decimal places of the minor unit of the
+// currency.
+func CurrencyExponent(code string) int {
+	if exponent, ok := currencyExponents[code]; ok {
+		return exponent
+	}
+	return 2
+}
+
+// MajorAmount is the amount of m in the major unit of its currency, the way
+// prices were shown before they carried one: 150000 for Rp150.000,00 and
+// 10.5 for $10.50.
+func (m Money) MajorAmount() json.Number {
+	exponent := CurrencyExponent(m.Currency)
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)
+	amount := new(big.Rat).SetFrac(big.NewInt(m.Amount), scale).FloatString(exponent)
+	if strings.Contains(amount, ".") {
+		amount = strings.TrimRight(strings.TrimRight(amount, "0"), ".")
+	}
+	return json.Number(amount)
+}
+
+// PriceOptions selects how prices are shown to a buyer: the price list of
+// Region (a country code) when the ticket has one, converted to Currency.
+// Empty fields keep the ticket's own price and currency.
+type PriceOptions struct {
+	Currency string `json:"currency"`
+	Region   string `json:"region"`
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMajorAmount(t *testing.T) {
+	tests := []struct {
+		money    Money
+		expected json.Number
+	}{
+		{Money{Amount: 15000000, Currency: "IDR"}, "150000"},
+		{Money{Amount: 1050, Currency: "USD"}, "10.5"},
+		{Money{Amount: 1999, Currency: "EUR"}, "19.99"},
+		{Money{Amount: 1050, Currency: "JPY"}, "1050"},
+		{Money{Amount: 1234, Currency: "KWD"}, "1.234"},
+		{Money{Amount: -250, Currency: "USD"}, "-2.5"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, tt.money.MajorAmount(), tt.money)
+	}
+}
+
+func TestTicketPriceJSON(t *testing.T) {
+	body, err := json.Marshal(TicketResponse{TicketID: 1, Price: Money{Amount: 15000000, Currency: "IDR"}, PriceTier: "early-bird"})
+	assert.NoError(t, err)
+
+	var fields map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(body, &fields))
+	assert.JSONEq(t, `150000`, string(fields["price"]))
+	assert.JSONEq(t, `{"amount":15000000,"currency":"IDR"}`, string(fields["price_money"]))
+	assert.JSONEq(t, `"early-bird"`, string(fields["price_tier"]))
+
+	body, err = json.Marshal(TicketEvent{TicketID: 1, Price: Money{Amount: 1050, Currency: "USD"}})
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(body, &fields))
+	assert.JSONEq(t, `10.5`, string(fields["price"]))
+}
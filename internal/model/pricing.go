@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// PriceSchedule is a price tier of a ticket type. It applies while the time
+// is inside [EffectiveFrom, EffectiveTo) and the remaining stock is inside
+// [MinStock, MaxStock]; unset bounds are open. Price is in the ticket's
+// currency.
+type PriceSchedule struct {
+	ScheduleID    int        `json:"schedule_id"`
+	TicketID      int        `json:"ticket_id"`
+	Name          string     `json:"name"`
+	Price         int64      `json:"price"`
+	EffectiveFrom *time.Time `json:"effective_from,omitempty"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"`
+	MinStock      *int       `json:"min_stock,omitempty"`
+	MaxStock      *int       `json:"max_stock,omitempty"`
+	Priority      int        `json:"priority"`
+}
@@ -0,0 +1,73 @@
+package model
+
+import "time"
+
+type Promo struct {
+	PromoID       int        `json:"promo_id"`
+	Code          string     `json:"code"`
+	DiscountType  string     `json:"discount_type"`
+	DiscountValue int64      `json:"discount_value"`
+	Currency      string     `json:"currency,omitempty"`
+	EventID       *int       `json:"event_id,omitempty"`
+	MaxUses       *int       `json:"max_uses,omitempty"`
+	UsedCount     int        `json:"used_count"`
+	OncePerUser   bool       `json:"once_per_user"`
+	StartsAt      *time.Time `json:"starts_at,omitempty"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	Active        bool       `json:"active"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// PromoRequest creates or replaces a promo code. DiscountValue is a
+// percentage for percent codes and an amount in the minor unit of Currency
+// for fixed ones.
+type PromoRequest struct {
+	Code          string     `json:"code" validate:"required,alphanum,max=50"`
+	DiscountType  string     `json:"discount_type" validate:"required,oneof=percent fixed"`
+	DiscountValue int64      `json:"discount_value" validate:"required,gt=0"`
+	Currency      string     `json:"currency" validate:"required_if=DiscountType fixed,omitempty,len=3"`
+	EventID       *int       `json:"event_id" validate:"omitempty,gt=0"`
+	MaxUses       *int       `json:"max_uses" validate:"omitempty,gt=0"`
+	OncePerUser   bool       `json:"once_per_user"`
+	StartsAt      *time.Time `json:"starts_at"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+	Active        bool       `json:"active"`
+}
+
+type PromoRedemption struct {
+	PromoID  int    `json:"promo_id"`
+	OrderID  string `json:"order_id"`
+	Email    string `json:"email"`
+	Discount Money  `json:"discount"`
+	Status   string `json:"status"`
+}
+
+type QuoteItem struct {
+	TicketID int `json:"ticket_id" validate:"required"`
+	Quantity int `json:"quantity" validate:"required,min=1"`
+}
+
+type QuoteRequest struct {
+	Items     []QuoteItem `json:"items" validate:"required,min=1,dive"`
+	PromoCode string      `json:"promo_code"`
+	Currency  string      `json:"currency"`
+	Region    string      `json:"region"`
+}
+
+type QuoteLine struct {
+	TicketID  int    `json:"ticket_id"`
+	EventID   int    `json:"event_id"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice Money  `json:"unit_price"`
+	PriceTier string `json:"price_tier,omitempty"`
+	Amount    Money  `json:"amount"`
+}
+
+type Quote struct {
+	Lines     []QuoteLine `json:"lines"`
+	PromoCode string      `json:"promo_code,omitempty"`
+	Subtotal  Money       `json:"subtotal"`
+	Discount  Money       `json:"discount"`
+	Total     Money       `json:"total"`
+}
@@ -0,0 +1,67 @@
+package model
+
+// StockSnapshot is everything the stock counters of a ticket are checked
+// against, read at one point in time: the counters, the sums of its inventory
+// ledger and the quantities its orders and waitlist offers account for.
+type StockSnapshot struct {
+	TicketID int           `json:"ticket_id"`
+	Type     string        `json:"type"`
+	Current  StockCounters `json:"current"`
+	Ledger   StockCounters `json:"ledger"`
+	// OrdersHeld is the quantity of unpaid reservations and open waitlist
+	// offers.
+	OrdersHeld int `json:"orders_held"`
+	// OrdersSold is the quantity of paid reservations, refunded or not.
+	OrdersSold int `json:"orders_sold"`
+	// LegacyHeld and LegacySold are what the ledger holds and sold for orders
+	// placed without a reservation, which the reservations do not account for.
+	LegacyHeld int `json:"legacy_held"`
+	LegacySold int `json:"legacy_sold"`
+	// OpeningHeld and OpeningSold are the held and sold stock the ledger
+	// opened with, taken by orders from before it that may have no
+	// reservation.
+	OpeningHeld int `json:"opening_held"`
+	OpeningSold int `json:"opening_sold"`
+}
+
+// InventoryViolation is one stock invariant a ticket breaks.
+type InventoryViolation struct {
+	Invariant string `json:"invariant"`
+	Detail    string `json:"detail"`
+}
+
+// InventoryDrift is the result of checking one ticket. Expected holds the
+// counters a repair sets; Status says whether the ticket drifted and what a
+// repair did about it.
+type InventoryDrift struct {
+	StockSnapshot
+	Status     string               `json:"status"`
+	Expected   StockCounters        `json:"expected"`
+	Violations []InventoryViolation `json:"violations,omitempty"`
+}
+
+// InventoryReport lists the tickets that break a stock invariant. A dry run
+// only reports them; a repair also lists the tickets it was asked to repair
+// and how each went.
+type InventoryReport struct {
+	DryRun   bool             `json:"dry_run"`
+	Checked  int              `json:"checked"`
+	Drifted  int              `json:"drifted"`
+	Repaired int              `json:"repaired"`
+	Tickets  []InventoryDrift `json:"tickets"`
+}
+
+// InventoryRepairRequest names the tickets of a dry run report to repair,
+// each with the counters the dry run reported for it.
+type InventoryRepairRequest struct {
+	Tickets []InventoryRepairTicket `json:"tickets" validate:"required,min=1,dive"`
+}
+
+// InventoryRepairTicket is a ticket of a dry run report as the operator saw
+// it: its counters then and the ones the repair is to set. The tickets of a
+// dry run report can be sent back as they are.
+type InventoryRepairTicket struct {
+	TicketID int           `json:"ticket_id" validate:"gt=0"`
+	Current  StockCounters `json:"current"`
+	Expected StockCounters `json:"expected"`
+}
@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// ResaleListing offers an issued ticket for resale. Price goes to the seller
+// and Fee to the platform; Total, their sum, is what the buyer pays. The
+// parties are kept out of the JSON so browsing listings reveals no one.
+type ResaleListing struct {
+	ListingID      int        `json:"listing_id"`
+	IssuedTicketID int        `json:"issued_ticket_id"`
+	EventID        int        `json:"event_id"`
+	TicketID       int        `json:"ticket_id"`
+	SellerEmail    string     `json:"-"`
+	Price          Money      `json:"price"`
+	Fee            Money      `json:"fee"`
+	Total          Money      `json:"total"`
+	Status         string     `json:"status"`
+	OrderID        string     `json:"-"`
+	BuyerEmail     string     `json:"-"`
+	SoldAt         *time.Time `json:"sold_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// ResaleListingRequest lists a ticket for Price, in the minor unit of the
+// currency the seller paid in.
+type ResaleListingRequest struct {
+	Price int64 `json:"price" validate:"required,gt=0"`
+}
+
+type ResalePurchaseRequest struct {
+	OrderID string `json:"order_id" validate:"required,max=100"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+type Reservation struct {
+	ReservationID int        `json:"reservation_id"`
+	OrderID       string     `json:"order_id"`
+	TicketID      int        `json:"ticket_id"`
+	EventID       int        `json:"event_id"`
+	Email         string     `json:"email"`
+	Quantity      int        `json:"quantity"`
+	UnitPrice     Money      `json:"unit_price"`
+	PriceTier     string     `json:"price_tier,omitempty"`
+	PromoCode     string     `json:"promo_code,omitempty"`
+	Discount      Money      `json:"discount"`
+	Status        string     `json:"status"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+type ReserveTicketRequest struct {
+	TicketID  int    `json:"ticket_id" validate:"required"`
+	Order     int    `json:"order" validate:"required,min=1"`
+	OrderID   string `json:"order_id" validate:"required,max=100"`
+	Region    string `json:"region" validate:"omitempty,alpha,min=2,max=3"`
+	PromoCode string `json:"promo_code" validate:"omitempty,alphanum,max=50"`
+}
@@ -44,6 +44,10 @@ type BusinessError struct {
 	Message string
 }
 
+func (e *BusinessError) Error() string {
+	return e.Message
+}
+
 type UserIdentity struct {
 	UserNik      string
 	RequestID    string
@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// EventSeat is one seat of a seated event together with its sale state.
+type EventSeat struct {
+	SeatID        int        `json:"seat_id"`
+	SectionID     int        `json:"section_id"`
+	SectionName   string     `json:"section_name"`
+	Row           string     `json:"row"`
+	Number        int        `json:"number"`
+	TicketID      int        `json:"ticket_id"`
+	Status        string     `json:"status"`
+	OrderID       string     `json:"-"`
+	HoldExpiresAt *time.Time `json:"hold_expires_at,omitempty"`
+}
+
+type SeatMap struct {
+	EventID  int              `json:"event_id"`
+	Sections []SeatMapSection `json:"sections"`
+}
+
+type SeatMapSection struct {
+	SectionID int          `json:"section_id"`
+	Name      string       `json:"name"`
+	Rows      []SeatMapRow `json:"rows"`
+}
+
+type SeatMapRow struct {
+	Row   string      `json:"row"`
+	Seats []EventSeat `json:"seats"`
+}
+
+// SeatHold is the seats an order holds on a single ticket tier.
+type SeatHold struct {
+	OrderID  string `json:"order_id"`
+	TicketID int    `json:"ticket_id"`
+	Quantity int    `json:"quantity"`
+}
+
+type ReserveSeatsRequest struct {
+	SeatIDs []int  `json:"seat_ids" validate:"required,min=1,dive,gt=0"`
+	OrderID string `json:"order_id" validate:"required"`
+}
+
+type SeatReservation struct {
+	Reservation   Reservation `json:"reservation"`
+	SeatIDs       []int       `json:"seat_ids"`
+	HoldExpiresAt time.Time   `json:"hold_expires_at"`
+}
@@ -0,0 +1,66 @@
+package model
+
+// StockCounters are the stock counts of a ticket, or a change of them. The
+// counts of a ticket always keep to
+//
+//	total = available + held + sold
+//
+// with none of them negative. Available stock can be ordered, held stock is
+// taken by unpaid orders and open waitlist offers, and sold stock by paid
+// orders. Only new stock changes the total.
+type StockCounters struct {
+	Total     int `json:"total"`
+	Available int `json:"available"`
+	Held      int `json:"held"`
+	Sold      int `json:"sold"`
+}
+
+// ReserveStock is the change of an order or waitlist offer taking quantity.
+func ReserveStock(quantity int) StockCounters {
+	return StockCounters{Available: -quantity, Held: quantity}
+}
+
+// ConfirmStock is the change of paying for quantity held.
+func ConfirmStock(quantity int) StockCounters {
+	return StockCounters{Held: -quantity, Sold: quantity}
+}
+
+// ReleaseStock is the change of giving quantity held back.
+func ReleaseStock(quantity int) StockCounters {
+	return StockCounters{Available: quantity, Held: -quantity}
+}
+
+// Balanced reports whether the total of c is the sum of its other counts,
+// which holds for the counts of a ticket and for every change of them.
+func (c StockCounters) Balanced() bool {
+	return c.Total == c.Available+c.Held+c.Sold
+}
+
+// Valid reports whether c are counts a ticket can have.
+func (c StockCounters) Valid() bool {
+	return c.Balanced() && c.Available >= 0 && c.Held >= 0 && c.Sold >= 0
+}
+
+// Add returns c changed by change, whether or not the result is valid.
+func (c StockCounters) Add(change StockCounters) StockCounters {
+	return StockCounters{
+		Total:     c.Total + change.Total,
+		Available: c.Available + change.Available,
+		Held:      c.Held + change.Held,
+		Sold:      c.Sold + change.Sold,
+	}
+}
+
+// Apply returns c changed by change, and false with c unchanged when change
+// is unbalanced or the result would be negative anywhere. The repository
+// applies every change of the stock of a ticket by this rule.
+func (c StockCounters) Apply(change StockCounters) (StockCounters, bool) {
+	if !change.Balanced() {
+		return c, false
+	}
+	next := c.Add(change)
+	if !next.Valid() {
+		return c, false
+	}
+	return next, true
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stockStep is one random change of stock: a transition of Quantity, an
+// arbitrary balanced change such as new stock, or an unbalanced one.
+type stockStep struct {
+	Kind                  uint8
+	Quantity              int8
+	Available, Held, Sold int8
+}
+
+func (s stockStep) transition() bool {
+	return s.Kind%5 < 3
+}
+
+func (s stockStep) change() StockCounters {
+	quantity := int(s.Quantity)
+	switch s.Kind % 5 {
+	case 0:
+		return ReserveStock(quantity)
+	case 1:
+		return ConfirmStock(quantity)
+	case 2:
+		return ReleaseStock(quantity)
+	}
+	change := StockCounters{Available: int(s.Available), Held: int(s.Held), Sold: int(s.Sold)}
+	change.Total = change.Available + change.Held + change.Sold
+	if s.Kind%5 == 4 {
+		change.Total++
+	}
+	return change
+}
+
+func TestStockCountersApply(t *testing.T) {
+	start := StockCounters{Total: 10, Available: 7, Held: 2, Sold: 1}
+
+	t.Run("should move stock between the counts", func(t *testing.T) {
+		next, ok := start.Apply(ReserveStock(3))
+		assert.True(t, ok)
+		assert.Equal(t, StockCounters{Total: 10, Available: 4, Held: 5, Sold: 1}, next)
+
+		next, ok = next.Apply(ConfirmStock(4))
+		assert.True(t, ok)
+		assert.Equal(t, StockCounters{Total: 10, Available: 4, Held: 1, Sold: 5}, next)
+	})
+
+	t.Run("should refuse to confirm more than is held", func(t *testing.T) {
+		next, ok := start.Apply(ConfirmStock(3))
+		assert.False(t, ok)
+		assert.Equal(t, start, next)
+	})
+
+	t.Run("should refuse an unbalanced change", func(t *testing.T) {
+		next, ok := start.Apply(StockCounters{Available: 1})
+		assert.False(t, ok)
+		assert.Equal(t, start, next)
+	})
+}
+
+func TestStockCountersInvariants(t *testing.T) {
+	property := func(total uint8, steps []stockStep) bool {
+		counts := StockCounters{Total: int(total), Available: int(total)}
+		ledger := counts
+		for _, step := range steps {
+			change := step.change()
+			next, ok := counts.Apply(change)
+			if !ok {
+				// a refused change leaves the counts as they were.
+				if next != counts {
+					return false
+				}
+				continue
+			}
+			if !next.Valid() || next != counts.Add(change) {
+				return false
+			}
+			if step.transition() && next.Total != counts.Total {
+				return false
+			}
+			counts = next
+			ledger = ledger.Add(change)
+		}
+		return counts.Valid() && counts == ledger
+	}
+
+	assert.NoError(t, quick.Check(property, &quick.Config{MaxCount: 2000}))
+}
@@ -1,15 +1,20 @@
 package model
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type Ticket struct {
 	TicketID      int       `json:"ticket_id"`
 	Type          string    `json:"type"`
-	Price         int       `json:"price"`
+	Price         Money     `json:"price_money"`
+	PriceTier     string    `json:"price_tier,omitempty"`
 	ContinentName string    `json:"continent_name"`
-	StockTicket   int       `json:"stock_ticket"`
-	Stock         int       `json:"stock"`
-	StockOrdered  int       `json:"stock_ordered"`
+	Total         int       `json:"total"`
+	Available     int       `json:"available"`
+	Held          int       `json:"held"`
+	Sold          int       `json:"sold"`
 	CountryName   string    `json:"country_name"`
 	CountryCity   string    `json:"country_city"`
 	CountryPlace  string    `json:"country_place"`
@@ -17,10 +22,21 @@ type Ticket struct {
 	UpdatedAt     time.Time `json:"updated_at"`
 }
 
+// MarshalJSON writes Price as price_money and, for clients from before
+// prices carried a currency, its amount in the major unit as price.
+func (t Ticket) MarshalJSON() ([]byte, error) {
+	type ticket Ticket
+	return json.Marshal(struct {
+		ticket
+		LegacyPrice json.Number `json:"price"`
+	}{ticket(t), t.Price.MajorAmount()})
+}
+
 type TicketResponse struct {
 	TicketID      int    `json:"ticket_id"`
 	Type          string `json:"type"`
-	Price         int    `json:"price"`
+	Price         Money  `json:"price_money"`
+	PriceTier     string `json:"price_tier,omitempty"`
 	ContinentName string `json:"continent_name"`
 	Stock         int    `json:"stock"`
 	CountryName   string `json:"country_name"`
@@ -28,20 +44,54 @@ type TicketResponse struct {
 	CountryPlace  string `json:"country_place"`
 }
 
+// MarshalJSON writes the price the way Ticket.MarshalJSON does.
+func (t TicketResponse) MarshalJSON() ([]byte, error) {
+	type ticketResponse TicketResponse
+	return json.Marshal(struct {
+		ticketResponse
+		LegacyPrice json.Number `json:"price"`
+	}{ticketResponse(t), t.Price.MajorAmount()})
+}
+
 type StockTicket struct {
 	Continent string `json:"continent"`
 	Stock     int    `json:"stock"`
 }
 
 type TicketEvent struct {
-	TicketID     int       `json:"ticket_id"`
-	Type         string    `json:"type"`
-	Price        int       `json:"price"`
-	Stock        int       `json:"stock"`
-	Continent    string    `json:"continent"`
-	CountryCity  string    `json:"country_city"`
-	CountryPlace string    `json:"country_place"`
-	EventName    string    `json:"event_name"`
-	Date         time.Time `json:"date"`
-	Description  string    `json:"description"`
+	TicketID     int        `json:"ticket_id"`
+	Type         string     `json:"type"`
+	Price        Money      `json:"price_money"`
+	PriceTier    string     `json:"price_tier,omitempty"`
+	Stock        int        `json:"stock"`
+	Continent    string     `json:"continent"`
+	CountryCity  string     `json:"country_city"`
+	CountryPlace string     `json:"country_place"`
+	EventName    string     `json:"event_name"`
+	Date         time.Time  `json:"date"`
+	Description  string     `json:"description"`
+	EventStatus  string     `json:"event_status"`
+	SaleStartsAt *time.Time `json:"sale_starts_at,omitempty"`
+	SaleEndsAt   *time.Time `json:"sale_ends_at,omitempty"`
+}
+
+// MarshalJSON writes the price the way Ticket.MarshalJSON does.
+func (t TicketEvent) MarshalJSON() ([]byte, error) {
+	type ticketEvent TicketEvent
+	return json.Marshal(struct {
+		ticketEvent
+		LegacyPrice json.Number `json:"price"`
+	}{ticketEvent(t), t.Price.MajorAmount()})
+}
+
+// TicketPurchaseLimit holds what decides whether a ticket can be bought: the
+// per-user limit and the sale status and window of its event. Tickets without
+// an event are always on sale.
+type TicketPurchaseLimit struct {
+	TicketID         int        `json:"ticket_id"`
+	EventID          int        `json:"event_id"`
+	MaxTicketPerUser int        `json:"max_ticket_per_user"`
+	EventStatus      string     `json:"event_status"`
+	SaleStartsAt     *time.Time `json:"sale_starts_at,omitempty"`
+	SaleEndsAt       *time.Time `json:"sale_ends_at,omitempty"`
 }
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// TicketTransfer is an offer from the owner of an issued ticket to hand it to
+// ToEmail. Transfers are never deleted, so together they are the ownership
+// history of the ticket. A resale is recorded as an accepted transfer of
+// ListingID.
+type TicketTransfer struct {
+	TransferID     int        `json:"transfer_id"`
+	IssuedTicketID int        `json:"issued_ticket_id"`
+	ListingID      int        `json:"listing_id,omitempty"`
+	FromEmail      string     `json:"from_email"`
+	ToEmail        string     `json:"to_email"`
+	Status         string     `json:"status"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	RespondedAt    *time.Time `json:"responded_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type TransferRequest struct {
+	ToEmail string `json:"to_email" validate:"required,email,max=255"`
+}
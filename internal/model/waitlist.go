@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// WaitlistEntry is a user waiting for Quantity tickets of a sold out ticket
+// type. Once stock frees up it is set aside for the entry, which may then be
+// claimed until OfferExpiresAt.
+type WaitlistEntry struct {
+	WaitlistID     int        `json:"waitlist_id"`
+	TicketID       int        `json:"ticket_id"`
+	Email          string     `json:"-"`
+	Quantity       int        `json:"quantity"`
+	Status         string     `json:"status"`
+	OfferedAt      *time.Time `json:"offered_at,omitempty"`
+	OfferExpiresAt *time.Time `json:"offer_expires_at,omitempty"`
+	OrderID        string     `json:"order_id,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type WaitlistRequest struct {
+	Quantity int `json:"quantity" validate:"required,min=1"`
+}
+
+// WaitlistClaimRequest takes up a waitlist offer under OrderID, like a
+// reservation of the offered tickets.
+type WaitlistClaimRequest struct {
+	OrderID   string `json:"order_id" validate:"required,max=100"`
+	Region    string `json:"region" validate:"omitempty,alpha,min=2,max=3"`
+	PromoCode string `json:"promo_code" validate:"omitempty,alphanum,max=50"`
+}
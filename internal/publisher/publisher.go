@@ -0,0 +1,95 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"go.opentelemetry.io/otel"
+)
+
+// ErrUnknownTopic is returned when a message is published to a topic that has
+// no queue configured.
+var ErrUnknownTopic = errors.New("unknown topic")
+
+// correlationIDAttribute matches the attribute the consumers read, so the
+// request id follows the message to the services downstream.
+const correlationIDAttribute = "correlation_id"
+
+// Publisher sends messages about things that happened in this service to the
+// other services.
+type Publisher interface {
+	Publish(ctx context.Context, topic string, message interface{}) error
+}
+
+// sender is the part of *sqs.Client the publisher needs.
+type sender interface {
+	SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+}
+
+type sqsPublisher struct {
+	client sender
+	queues map[string]string
+}
+
+// NewSQSPublisher publishes each topic as JSON to the SQS queue URL mapped to
+// it in queues.
+func NewSQSPublisher(client sender, queues map[string]string) Publisher {
+	return &sqsPublisher{client: client, queues: queues}
+}
+
+func (p *sqsPublisher) Publish(ctx context.Context, topic string, message interface{}) error {
+	queueURL := p.queues[topic]
+	if queueURL == "" {
+		return fmt.Errorf("%s: %w", topic, ErrUnknownTopic)
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	attributes := attributeCarrier{}
+	if requestID := config.RequestIDFromContext(ctx); requestID != "" {
+		attributes.Set(correlationIDAttribute, requestID)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, attributes)
+
+	_, err = p.client.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:          aws.String(queueURL),
+		MessageBody:       aws.String(string(body)),
+		MessageAttributes: attributes,
+	})
+	return err
+}
+
+// attributeCarrier adapts SQS message attributes to a
+// propagation.TextMapCarrier so the trace context travels with the message.
+type attributeCarrier map[string]types.MessageAttributeValue
+
+func (c attributeCarrier) Get(key string) string {
+	if value, ok := c[key]; ok {
+		return aws.ToString(value.StringValue)
+	}
+	return ""
+}
+
+func (c attributeCarrier) Set(key, value string) {
+	c[key] = types.MessageAttributeValue{
+		DataType:    aws.String("String"),
+		StringValue: aws.String(value),
+	}
+}
+
+func (c attributeCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,44 @@
+package publisher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSender struct {
+	inputs []*sqs.SendMessageInput
+}
+
+func (f *fakeSender) SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	f.inputs = append(f.inputs, params)
+	return &sqs.SendMessageOutput{}, nil
+}
+
+func TestPublish(t *testing.T) {
+	client := &fakeSender{}
+	publisher := NewSQSPublisher(client, map[string]string{"event-cancelled": "https://sqs.local/event-cancelled"})
+
+	ctx := config.WithRequestID(context.Background(), "request-1")
+	err := publisher.Publish(ctx, "event-cancelled", map[string]int{"event_id": 3})
+	assert.NoError(t, err)
+	if assert.Len(t, client.inputs, 1) {
+		input := client.inputs[0]
+		assert.Equal(t, "https://sqs.local/event-cancelled", aws.ToString(input.QueueUrl))
+		assert.JSONEq(t, `{"event_id":3}`, aws.ToString(input.MessageBody))
+		assert.Equal(t, "request-1", aws.ToString(input.MessageAttributes["correlation_id"].StringValue))
+	}
+}
+
+func TestPublishUnknownTopic(t *testing.T) {
+	client := &fakeSender{}
+	publisher := NewSQSPublisher(client, map[string]string{"event-cancelled": ""})
+
+	err := publisher.Publish(context.Background(), "event-cancelled", struct{}{})
+	assert.ErrorIs(t, err, ErrUnknownTopic)
+	assert.Empty(t, client.inputs)
+}
@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"go.uber.org/zap"
+)
+
+type eventRepository struct {
+	DB           *sql.DB
+	logger       config.Logger
+	queryTimeout time.Duration
+}
+
+type EventPersister interface {
+	GetEvents(ctx context.Context) ([]model.Event, error)
+	GetEventByID(ctx context.Context, eventID int) (model.Event, error)
+	UpdateEventStatus(ctx context.Context, eventID int, from []string, to string) error
+	UpdateEventSaleWindow(ctx context.Context, eventID int, startsAt, endsAt *time.Time) error
+	UpdateEventResaleCap(ctx context.Context, eventID int, capPercent *int) error
+	TransitionEvents(ctx context.Context, now time.Time) (model.EventTransitions, error)
+	CancelEvent(ctx context.Context, eventID int, reason string, at time.Time) error
+}
+
+func NewEventRepository(DB *sql.DB, logger config.Logger, queryTimeout time.Duration) EventPersister {
+	return &eventRepository{DB: DB, logger: logger, queryTimeout: queryTimeout}
+}
+
+const eventColumns = `event_id, COALESCE(event_name, ''), date, COALESCE(description, ''), status, sale_starts_at, sale_ends_at,
+	COALESCE(cancel_reason, ''), cancelled_at, max_ticket_per_user, resale_cap_percent`
+
+func scanEvent(row rowScanner) (model.Event, error) {
+	var (
+		event                               model.Event
+		date, saleStart, saleEnd, cancelled sql.NullTime
+		resaleCap                           sql.NullInt64
+	)
+	err := row.Scan(&event.EventID, &event.Name, &date, &event.Description, &event.Status, &saleStart, &saleEnd,
+		&event.CancelReason, &cancelled, &event.MaxTicketPerUser, &resaleCap)
+	if err != nil {
+		return event, err
+	}
+	event.Date = date.Time
+	event.SaleStartsAt = nullTime(saleStart)
+	event.SaleEndsAt = nullTime(saleEnd)
+	event.CancelledAt = nullTime(cancelled)
+	if resaleCap.Valid {
+		value := int(resaleCap.Int64)
+		event.ResaleCapPercent = &value
+	}
+	return event, nil
+}
+
+func (r *eventRepository) GetEvents(ctx context.Context) ([]model.Event, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var events []model.Event
+	query := `SELECT ` + eventColumns + ` FROM event ORDER BY date, event_id`
+
+	rows, err := r.DB.QueryContext(ctx, query)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when querying event table", zap.Error(err))
+		return events, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			r.logger.WithContext(ctx).Error("Error when scanning event table", zap.Error(err))
+			return events, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func (r *eventRepository) GetEventByID(ctx context.Context, eventID int) (model.Event, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + eventColumns + ` FROM event WHERE event_id = ?`
+
+	event, err := scanEvent(r.DB.QueryRowContext(ctx, query, eventID))
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when scanning event table", zap.Error(err))
+		return event, err
+	}
+	return event, nil
+}
+
+// UpdateEventStatus moves the event to status to when it is currently in one
+// of from, and returns sql.ErrNoRows otherwise, so a transition never
+// overwrites a concurrent one.
+func (r *eventRepository) UpdateEventStatus(ctx context.Context, eventID int, from []string, to string) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	args := []interface{}{to, eventID}
+	for _, status := range from {
+		args = append(args, status)
+	}
+	query := `UPDATE event SET status = ? WHERE event_id = ? AND status IN (` +
+		strings.TrimSuffix(strings.Repeat("?, ", len(from)), ", ") + `)`
+
+	result, err := r.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when updating event table", zap.Error(err))
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when getting affected rows of event table", zap.Error(err))
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *eventRepository) UpdateEventSaleWindow(ctx context.Context, eventID int, startsAt, endsAt *time.Time) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `UPDATE event SET sale_starts_at = ?, sale_ends_at = ? WHERE event_id = ?`
+	_, err := r.DB.ExecContext(ctx, query, startsAt, endsAt, eventID)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when updating event table", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// UpdateEventResaleCap sets the resale price cap of the event; a nil
+// capPercent closes resale.
+func (r *eventRepository) UpdateEventResaleCap(ctx context.Context, eventID int, capPercent *int) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `UPDATE event SET resale_cap_percent = ? WHERE event_id = ?`
+	_, err := r.DB.ExecContext(ctx, query, capPercent, eventID)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when updating event table", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// TransitionEvents applies the automatic status changes as of now: events
+// whose date has passed become past, on sale events without any stock left
+// become sold out, and sold out events whose stock came back are on sale
+// again.
+func (r *eventRepository) TransitionEvents(ctx context.Context, now time.Time) (model.EventTransitions, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var transitions model.EventTransitions
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when starting event transaction", zap.Error(err))
+		return transitions, err
+	}
+	defer tx.Rollback()
+
+	statements := []struct {
+		count *int64
+		query string
+		args  []interface{}
+	}{
+		{
+			count: &transitions.Past,
+			query: `UPDATE event SET status = ? WHERE status IN (?, ?, ?) AND date IS NOT NULL AND date <= ?`,
+			args: []interface{}{util.EVENT_STATUS_PAST, util.EVENT_STATUS_ON_SALE, util.EVENT_STATUS_PAUSED,
+				util.EVENT_STATUS_SOLD_OUT, now},
+		},
+		{
+			count: &transitions.SoldOut,
+			query: `UPDATE event e SET e.status = ? WHERE e.status = ?
+				AND EXISTS (SELECT 1 FROM ticket_detail td WHERE td.event_id = e.event_id)
+				AND NOT EXISTS (SELECT 1 FROM ticket_detail td WHERE td.event_id = e.event_id AND td.available > 0)`,
+			args: []interface{}{util.EVENT_STATUS_SOLD_OUT, util.EVENT_STATUS_ON_SALE},
+		},
+		{
+			count: &transitions.Reopened,
+			query: `UPDATE event e SET e.status = ? WHERE e.status = ?
+				AND EXISTS (SELECT 1 FROM ticket_detail td WHERE td.event_id = e.event_id AND td.available > 0)`,
+			args: []interface{}{util.EVENT_STATUS_ON_SALE, util.EVENT_STATUS_SOLD_OUT},
+		},
+	}
+
+	for _, statement := range statements {
+		result, err := tx.ExecContext(ctx, statement.query, statement.args...)
+		if err != nil {
+			r.logger.WithContext(ctx).Error("Error when updating event table", zap.Error(err))
+			return transitions, err
+		}
+		if *statement.count, err = result.RowsAffected(); err != nil {
+			r.logger.WithContext(ctx).Error("Error when getting affected rows of event table", zap.Error(err))
+			return transitions, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.WithContext(ctx).Error("Error when committing event transaction", zap.Error(err))
+		return transitions, err
+	}
+	return transitions, nil
+}
+
+// CancelEvent marks the event cancelled with reason at the given time. Events
+// that already took place or were cancelled before return sql.ErrNoRows.
+func (r *eventRepository) CancelEvent(ctx context.Context, eventID int, reason string, at time.Time) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `UPDATE event SET status = ?, cancel_reason = ?, cancelled_at = ? WHERE event_id = ? AND status IN (?, ?, ?, ?)`
+	result, err := r.DB.ExecContext(ctx, query, util.EVENT_STATUS_CANCELLED, reason, at, eventID, util.EVENT_STATUS_DRAFT,
+		util.EVENT_STATUS_ON_SALE, util.EVENT_STATUS_PAUSED, util.EVENT_STATUS_SOLD_OUT)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when updating event table", zap.Error(err))
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when getting affected rows of event table", zap.Error(err))
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
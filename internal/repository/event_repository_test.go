@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	mock_config "github.com/SyamSolution/ticket-management-service/mock/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetEventByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	date := time.Date(2026, 12, 1, 19, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"event_id", "event_name", "date", "description", "status", "sale_starts_at", "sale_ends_at", "cancel_reason", "cancelled_at", "max_ticket_per_user", "resale_cap_percent"}).
+		AddRow(3, "Concert", date, "", "on_sale", nil, date, "", nil, 4, 120)
+
+	mock.ExpectQuery("^SELECT event_id, (.+) FROM event WHERE event_id = \\?$").WithArgs(3).WillReturnRows(rows)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewEventRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+	event, err := repo.GetEventByID(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "on_sale", event.Status)
+	assert.Nil(t, event.SaleStartsAt)
+	assert.Equal(t, date, *event.SaleEndsAt)
+	assert.Equal(t, 4, event.MaxTicketPerUser)
+	assert.Equal(t, 120, *event.ResaleCapPercent)
+}
+
+func TestUpdateEventStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("^UPDATE event SET status = \\? WHERE event_id = \\? AND status IN \\(\\?, \\?\\)$").
+		WithArgs("on_sale", 3, "draft", "paused").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewEventRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+	err = repo.UpdateEventStatus(context.Background(), 3, []string{"draft", "paused"}, "on_sale")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestTransitionEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE event SET status = \\? WHERE status IN \\(\\?, \\?, \\?\\) AND date IS NOT NULL AND date <= \\?").
+		WithArgs("past", "on_sale", "paused", "sold_out", now).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("UPDATE event e SET e.status = \\? WHERE e.status = \\? AND EXISTS (.+) AND NOT EXISTS (.+)").
+		WithArgs("sold_out", "on_sale").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE event e SET e.status = \\? WHERE e.status = \\? AND EXISTS (.+)").
+		WithArgs("on_sale", "sold_out").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewEventRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+	transitions, err := repo.TransitionEvents(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), transitions.Past)
+	assert.Equal(t, int64(1), transitions.SoldOut)
+	assert.Equal(t, int64(0), transitions.Reopened)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCancelEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	at := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	mock.ExpectExec("^UPDATE event SET status = \\?, cancel_reason = \\?, cancelled_at = \\? WHERE event_id = \\? AND status IN \\(\\?, \\?, \\?, \\?\\)$").
+		WithArgs("cancelled", "Venue closed", at, 3, "draft", "on_sale", "paused", "sold_out").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewEventRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+	err = repo.CancelEvent(context.Background(), 3, "Venue closed", at)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
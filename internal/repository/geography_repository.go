@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"go.uber.org/zap"
+)
+
+type geographyRepository struct {
+	DB           *sql.DB
+	logger       config.Logger
+	queryTimeout time.Duration
+}
+
+type GeographyPersister interface {
+	GetContinents(ctx context.Context) ([]model.Continent, error)
+	GetContinentByCode(ctx context.Context, code string) (model.Continent, error)
+	GetCountriesByContinentID(ctx context.Context, continentID int) ([]model.Country, error)
+	GetCountryByCode(ctx context.Context, code string) (model.Country, error)
+	GetCitiesByCountryID(ctx context.Context, countryID int) ([]model.City, error)
+	GetCityByID(ctx context.Context, cityID int) (model.City, error)
+	GetVenuesByCityID(ctx context.Context, cityID int) ([]model.Venue, error)
+}
+
+func NewGeographyRepository(DB *sql.DB, logger config.Logger, queryTimeout time.Duration) GeographyPersister {
+	return &geographyRepository{DB: DB, logger: logger, queryTimeout: queryTimeout}
+}
+
+func (r *geographyRepository) GetContinents(ctx context.Context) ([]model.Continent, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var continents []model.Continent
+	query := `SELECT continent_id, code, name FROM continent ORDER BY name`
+
+	rows, err := r.DB.QueryContext(ctx, query)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when querying continent table", zap.Error(err))
+		return continents, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var continent model.Continent
+		if err := rows.Scan(&continent.ContinentID, &continent.Code, &continent.Name); err != nil {
+			r.logger.WithContext(ctx).Error("Error when scanning continent table", zap.Error(err))
+			return continents, err
+		}
+		continents = append(continents, continent)
+	}
+	return continents, nil
+}
+
+func (r *geographyRepository) GetContinentByCode(ctx context.Context, code string) (model.Continent, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var continent model.Continent
+	query := `SELECT continent_id, code, name FROM continent WHERE code = ?`
+
+	err := r.DB.QueryRowContext(ctx, query, code).Scan(&continent.ContinentID, &continent.Code, &continent.Name)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when scanning continent table", zap.Error(err))
+		return continent, err
+	}
+	return continent, nil
+}
+
+func (r *geographyRepository) GetCountriesByContinentID(ctx context.Context, continentID int) ([]model.Country, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var countries []model.Country
+	query := `SELECT country_id, continent_id, code, code_alpha3, name FROM country WHERE continent_id = ? ORDER BY name`
+
+	rows, err := r.DB.QueryContext(ctx, query, continentID)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when querying country table", zap.Error(err))
+		return countries, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var country model.Country
+		if err := rows.Scan(&country.CountryID, &country.ContinentID, &country.Code, &country.CodeAlpha3, &country.Name); err != nil {
+			r.logger.WithContext(ctx).Error("Error when scanning country table", zap.Error(err))
+			return countries, err
+		}
+		countries = append(countries, country)
+	}
+	return countries, nil
+}
+
+func (r *geographyRepository) GetCountryByCode(ctx context.Context, code string) (model.Country, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var country model.Country
+	query := `SELECT country_id, continent_id, code, code_alpha3, name FROM country WHERE code = ? OR code_alpha3 = ?`
+
+	err := r.DB.QueryRowContext(ctx, query, code, code).Scan(&country.CountryID, &country.ContinentID, &country.Code,
+		&country.CodeAlpha3, &country.Name)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when scanning country table", zap.Error(err))
+		return country, err
+	}
+	return country, nil
+}
+
+func (r *geographyRepository) GetCitiesByCountryID(ctx context.Context, countryID int) ([]model.City, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var cities []model.City
+	query := `SELECT city_id, country_id, name FROM city WHERE country_id = ? ORDER BY name`
+
+	rows, err := r.DB.QueryContext(ctx, query, countryID)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when querying city table", zap.Error(err))
+		return cities, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var city model.City
+		if err := rows.Scan(&city.CityID, &city.CountryID, &city.Name); err != nil {
+			r.logger.WithContext(ctx).Error("Error when scanning city table", zap.Error(err))
+			return cities, err
+		}
+		cities = append(cities, city)
+	}
+	return cities, nil
+}
+
+func (r *geographyRepository) GetCityByID(ctx context.Context, cityID int) (model.City, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var city model.City
+	query := `SELECT city_id, country_id, name FROM city WHERE city_id = ?`
+
+	err := r.DB.QueryRowContext(ctx, query, cityID).Scan(&city.CityID, &city.CountryID, &city.Name)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when scanning city table", zap.Error(err))
+		return city, err
+	}
+	return city, nil
+}
+
+func (r *geographyRepository) GetVenuesByCityID(ctx context.Context, cityID int) ([]model.Venue, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var venues []model.Venue
+	query := `SELECT venue_id, city_id, name FROM venue WHERE city_id = ? ORDER BY name`
+
+	rows, err := r.DB.QueryContext(ctx, query, cityID)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when querying venue table", zap.Error(err))
+		return venues, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var venue model.Venue
+		if err := rows.Scan(&venue.VenueID, &venue.CityID, &venue.Name); err != nil {
+			r.logger.WithContext(ctx).Error("Error when scanning venue table", zap.Error(err))
+			return venues, err
+		}
+		venues = append(venues, venue)
+	}
+	return venues, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	mock_config "github.com/SyamSolution/ticket-management-service/mock/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetContinents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"continent_id", "code", "name"}).
+		AddRow(1, "AF", "Africa").
+		AddRow(3, "AS", "Asia")
+
+	mock.ExpectQuery("^SELECT continent_id, code, name FROM continent ORDER BY name$").WillReturnRows(rows)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+	repo := NewGeographyRepository(db, logger, 0)
+
+	continents, err := repo.GetContinents(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, continents, 2)
+	assert.Equal(t, "AS", continents[1].Code)
+}
+
+func TestGetCountryByCode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"country_id", "continent_id", "code", "code_alpha3", "name"}).
+		AddRow(1, 3, "ID", "IDN", "Indonesia")
+
+	mock.ExpectQuery("^SELECT country_id, continent_id, code, code_alpha3, name FROM country WHERE code = \\? OR code_alpha3 = \\?$").
+		WithArgs("IDN", "IDN").
+		WillReturnRows(rows)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+	repo := NewGeographyRepository(db, logger, 0)
+
+	country, err := repo.GetCountryByCode(context.Background(), "IDN")
+	assert.NoError(t, err)
+	assert.Equal(t, "ID", country.Code)
+	assert.Equal(t, 3, country.ContinentID)
+}
+
+func TestGetVenuesByCityID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("^SELECT venue_id, city_id, name FROM venue WHERE city_id = \\? ORDER BY name$").
+		WithArgs(7).
+		WillReturnError(sql.ErrConnDone)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+	logger.EXPECT().WithContext(gomock.Any()).Return(logger)
+	logger.EXPECT().Error(gomock.Any(), gomock.Any())
+	repo := NewGeographyRepository(db, logger, 0)
+
+	_, err = repo.GetVenuesByCityID(context.Background(), 7)
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+}
@@ -0,0 +1,298 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrStockChanged is returned by RepairStock when the stock of the ticket
+	// is no longer the one it was checked with.
+	ErrStockChanged = errors.New("stock changed since it was checked")
+	// ErrUnbalancedStock is returned for a change of stock that would break
+	// total = available + held + sold. No such change is ever applied.
+	ErrUnbalancedStock = errors.New("stock change is unbalanced")
+)
+
+type inventoryLedgerRepository struct {
+	DB           *sql.DB
+	logger       config.Logger
+	queryTimeout time.Duration
+}
+
+// InventoryLedgerPersister reads the inventory ledger and the stock it
+// accounts for. The counts of a ticket are only ever changed by moveStock,
+// which keeps them to the invariants of model.StockCounters and records every
+// change in the ledger, which is never changed afterwards.
+type InventoryLedgerPersister interface {
+	GetLedgerBalance(ctx context.Context, ticketID int) (model.LedgerBalance, error)
+	GetLedgerEntries(ctx context.Context, ticketID, limit, offset int) ([]model.InventoryLedgerEntry, error)
+	GetStockSnapshots(ctx context.Context, ticketIDs []int) ([]model.StockSnapshot, error)
+	RepairStock(ctx context.Context, checked model.StockSnapshot, counters model.StockCounters) error
+}
+
+func NewInventoryLedgerRepository(DB *sql.DB, logger config.Logger, queryTimeout time.Duration) InventoryLedgerPersister {
+	return &inventoryLedgerRepository{DB: DB, logger: logger, queryTimeout: queryTimeout}
+}
+
+// moveStockQuery changes the counts of a ticket by a balanced change unless
+// that would take any of them below zero.
+const moveStockQuery = `UPDATE ticket_detail SET total = total + ?, available = available + ?, held = held + ?, sold = sold + ?
+	WHERE ticket_detail_id = ? AND available + ? >= 0 AND held + ? >= 0 AND sold + ? >= 0`
+
+// moveStock changes the counts of the ticket of entry by entry.Change inside
+// tx and records entry in the inventory ledger, the same rule as
+// model.StockCounters.Apply. It returns ErrUnbalancedStock for an unbalanced
+// change and sql.ErrNoRows when the ticket does not exist or the change would
+// take a count below zero, leaving the counts and the ledger untouched.
+func moveStock(ctx context.Context, tx *sql.Tx, entry model.InventoryLedgerEntry) error {
+	change := entry.Change
+	if !change.Balanced() {
+		return ErrUnbalancedStock
+	}
+
+	result, err := tx.ExecContext(ctx, moveStockQuery, change.Total, change.Available, change.Held, change.Sold,
+		entry.TicketID, change.Available, change.Held, change.Sold)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return recordStockMovement(ctx, tx, entry)
+}
+
+// recordStockMovement appends entry to the inventory ledger inside tx. The
+// actor and request id come from ctx.
+func recordStockMovement(ctx context.Context, tx *sql.Tx, entry model.InventoryLedgerEntry) error {
+	actor := config.ActorFromContext(ctx)
+	if actor == "" {
+		actor = util.LEDGER_ACTOR_SYSTEM
+	}
+
+	query := `INSERT INTO inventory_ledger (ticket_detail_id, total_delta, available_delta, held_delta, sold_delta, reason,
+		order_id, waitlist_id, request_id, actor) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := tx.ExecContext(ctx, query, entry.TicketID, entry.Change.Total, entry.Change.Available, entry.Change.Held,
+		entry.Change.Sold, entry.Reason, nullString(entry.OrderID),
+		sql.NullInt64{Int64: int64(entry.WaitlistID), Valid: entry.WaitlistID != 0},
+		nullString(config.RequestIDFromContext(ctx)), actor)
+	return err
+}
+
+// GetLedgerBalance reads the counters of the ticket and sums its ledger in one
+// statement, so both come from the same snapshot. It returns sql.ErrNoRows
+// when the ticket does not exist.
+func (r *inventoryLedgerRepository) GetLedgerBalance(ctx context.Context, ticketID int) (model.LedgerBalance, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	balance := model.LedgerBalance{TicketID: ticketID}
+
+	query := `SELECT td.total, td.available, td.held, td.sold,
+		COALESCE(SUM(il.total_delta), 0), COALESCE(SUM(il.available_delta), 0), COALESCE(SUM(il.held_delta), 0),
+		COALESCE(SUM(il.sold_delta), 0), COUNT(il.entry_id)
+		FROM ticket_detail td LEFT JOIN inventory_ledger il ON il.ticket_detail_id = td.ticket_detail_id
+		WHERE td.ticket_detail_id = ? GROUP BY td.ticket_detail_id`
+	err := r.DB.QueryRowContext(ctx, query, ticketID).Scan(&balance.Current.Total, &balance.Current.Available,
+		&balance.Current.Held, &balance.Current.Sold, &balance.Recomputed.Total, &balance.Recomputed.Available,
+		&balance.Recomputed.Held, &balance.Recomputed.Sold, &balance.EntryCount)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when scanning inventory_ledger table", zap.Error(err))
+		return balance, err
+	}
+	return balance, nil
+}
+
+// GetLedgerEntries returns a page of the ledger of the ticket, oldest first.
+func (r *inventoryLedgerRepository) GetLedgerEntries(ctx context.Context, ticketID, limit, offset int) ([]model.InventoryLedgerEntry, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var entries []model.InventoryLedgerEntry
+
+	query := `SELECT entry_id, ticket_detail_id, total_delta, available_delta, held_delta, sold_delta, reason,
+		COALESCE(order_id, ''), COALESCE(waitlist_id, 0), COALESCE(request_id, ''), actor, created_at
+		FROM inventory_ledger WHERE ticket_detail_id = ? ORDER BY entry_id LIMIT ? OFFSET ?`
+	rows, err := r.DB.QueryContext(ctx, query, ticketID, limit, offset)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when querying inventory_ledger table", zap.Error(err))
+		return entries, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry model.InventoryLedgerEntry
+		err := rows.Scan(&entry.EntryID, &entry.TicketID, &entry.Change.Total, &entry.Change.Available, &entry.Change.Held,
+			&entry.Change.Sold, &entry.Reason, &entry.OrderID, &entry.WaitlistID, &entry.RequestID, &entry.Actor, &entry.CreatedAt)
+		if err != nil {
+			r.logger.WithContext(ctx).Error("Error when scanning inventory_ledger table", zap.Error(err))
+			return entries, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// stockSnapshotQuery reads the snapshots of the tickets matched by the WHERE
+// clause appended to it, with stockSnapshotArgs before the ones of the
+// clause. Resale reservations sell tickets that were sold already, so they
+// take no stock and are left out. Orders without a reservation are accounted
+// for by what the ledger recorded for them.
+const stockSnapshotQuery = `SELECT td.ticket_detail_id, COALESCE(td.type, ''), td.total, td.available, td.held, td.sold,
+	COALESCE(l.total, 0), COALESCE(l.available, 0), COALESCE(l.held, 0), COALESCE(l.sold, 0),
+	COALESCE(r.held, 0) + COALESCE(w.held, 0), COALESCE(r.sold, 0), COALESCE(l.legacy_held, 0),
+	COALESCE(l.legacy_sold, 0), COALESCE(l.opening_held, 0), COALESCE(l.opening_sold, 0)
+	FROM ticket_detail td
+	LEFT JOIN (SELECT il.ticket_detail_id, SUM(il.total_delta) AS total, SUM(il.available_delta) AS available,
+		SUM(il.held_delta) AS held, SUM(il.sold_delta) AS sold,
+		SUM(CASE WHEN rs.order_id IS NULL AND il.order_id IS NOT NULL THEN il.held_delta ELSE 0 END) AS legacy_held,
+		SUM(CASE WHEN rs.order_id IS NULL AND il.order_id IS NOT NULL THEN il.sold_delta ELSE 0 END) AS legacy_sold,
+		SUM(CASE WHEN il.reason = ? THEN il.held_delta ELSE 0 END) AS opening_held,
+		SUM(CASE WHEN il.reason = ? THEN il.sold_delta ELSE 0 END) AS opening_sold
+		FROM inventory_ledger il LEFT JOIN (SELECT DISTINCT order_id FROM reservation WHERE order_id IS NOT NULL) rs
+			ON rs.order_id = il.order_id
+		GROUP BY il.ticket_detail_id) l
+		ON l.ticket_detail_id = td.ticket_detail_id
+	LEFT JOIN (SELECT ticket_detail_id, SUM(CASE WHEN status = ? THEN quantity ELSE 0 END) AS held,
+		SUM(CASE WHEN status IN (?, ?) THEN quantity ELSE 0 END) AS sold
+		FROM reservation WHERE COALESCE(price_tier, '') <> ? GROUP BY ticket_detail_id) r
+		ON r.ticket_detail_id = td.ticket_detail_id
+	LEFT JOIN (SELECT ticket_detail_id, SUM(quantity) AS held FROM ticket_waitlist WHERE status = ?
+		GROUP BY ticket_detail_id) w ON w.ticket_detail_id = td.ticket_detail_id`
+
+func stockSnapshotArgs() []interface{} {
+	return []interface{}{util.LEDGER_REASON_OPENING, util.LEDGER_REASON_OPENING, util.RESERVATION_STATUS_PENDING, util.RESERVATION_STATUS_CONFIRMED,
+		util.RESERVATION_STATUS_REFUND_PENDING, util.RESALE_PRICE_TIER, util.WAITLIST_STATUS_OFFERED}
+}
+
+func scanStockSnapshot(row rowScanner) (model.StockSnapshot, error) {
+	var snapshot model.StockSnapshot
+	err := row.Scan(&snapshot.TicketID, &snapshot.Type, &snapshot.Current.Total, &snapshot.Current.Available,
+		&snapshot.Current.Held, &snapshot.Current.Sold, &snapshot.Ledger.Total, &snapshot.Ledger.Available,
+		&snapshot.Ledger.Held, &snapshot.Ledger.Sold, &snapshot.OrdersHeld, &snapshot.OrdersSold, &snapshot.LegacyHeld,
+		&snapshot.LegacySold, &snapshot.OpeningHeld, &snapshot.OpeningSold)
+	return snapshot, err
+}
+
+// GetStockSnapshots returns the stock snapshots of the tickets, or of every
+// ticket when ticketIDs is empty, ordered by ticket id.
+func (r *inventoryLedgerRepository) GetStockSnapshots(ctx context.Context, ticketIDs []int) ([]model.StockSnapshot, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var snapshots []model.StockSnapshot
+
+	query := stockSnapshotQuery
+	args := stockSnapshotArgs()
+	if len(ticketIDs) > 0 {
+		placeholders, idArgs := inClause(ticketIDs)
+		query += ` WHERE td.ticket_detail_id IN (` + placeholders + `)`
+		args = append(args, idArgs...)
+	}
+	query += ` ORDER BY td.ticket_detail_id`
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when querying ticket_detail stock", zap.Error(err))
+		return snapshots, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		snapshot, err := scanStockSnapshot(rows)
+		if err != nil {
+			r.logger.WithContext(ctx).Error("Error when scanning ticket_detail stock", zap.Error(err))
+			return snapshots, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+// RepairStock sets the counters of the ticket of checked to counters. The
+// ticket is locked and read again first, and left alone with ErrStockChanged
+// unless it is still exactly as checked. A change of the counters made
+// without a ledger entry is recorded as untracked before the repair itself
+// moves the stock, so the ledger adds up to the repaired counters. Counters
+// that break the invariants fail like any other change of stock.
+func (r *inventoryLedgerRepository) RepairStock(ctx context.Context, checked model.StockSnapshot, counters model.StockCounters) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when starting stock repair transaction", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	var locked int
+	query := `SELECT ticket_detail_id FROM ticket_detail WHERE ticket_detail_id = ? FOR UPDATE`
+	if err := tx.QueryRowContext(ctx, query, checked.TicketID).Scan(&locked); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrStockChanged
+		}
+		r.logger.WithContext(ctx).Error("Error when locking ticket_detail table", zap.Error(err))
+		return err
+	}
+
+	snapshot, err := scanStockSnapshot(tx.QueryRowContext(ctx, stockSnapshotQuery+` WHERE td.ticket_detail_id = ?`,
+		append(stockSnapshotArgs(), checked.TicketID)...))
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when scanning ticket_detail stock", zap.Error(err))
+		return err
+	}
+	if snapshot != checked {
+		return ErrStockChanged
+	}
+
+	if checked.Current != checked.Ledger {
+		untracked := model.InventoryLedgerEntry{
+			TicketID: checked.TicketID,
+			Change: model.StockCounters{
+				Total:     checked.Current.Total - checked.Ledger.Total,
+				Available: checked.Current.Available - checked.Ledger.Available,
+				Held:      checked.Current.Held - checked.Ledger.Held,
+				Sold:      checked.Current.Sold - checked.Ledger.Sold,
+			},
+			Reason: util.LEDGER_REASON_UNTRACKED,
+		}
+		if err := recordStockMovement(ctx, tx, untracked); err != nil {
+			r.logger.WithContext(ctx).Error("Error when inserting inventory_ledger table", zap.Error(err))
+			return err
+		}
+	}
+	if counters != checked.Current {
+		repair := model.InventoryLedgerEntry{
+			TicketID: checked.TicketID,
+			Change: model.StockCounters{
+				Total:     counters.Total - checked.Current.Total,
+				Available: counters.Available - checked.Current.Available,
+				Held:      counters.Held - checked.Current.Held,
+				Sold:      counters.Sold - checked.Current.Sold,
+			},
+			Reason: util.LEDGER_REASON_RECONCILE,
+		}
+		if err := moveStock(ctx, tx, repair); err != nil {
+			r.logger.WithContext(ctx).Error("Error when updating ticket_detail stock", zap.Error(err))
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.WithContext(ctx).Error("Error when committing stock repair transaction", zap.Error(err))
+		return err
+	}
+	return nil
+}
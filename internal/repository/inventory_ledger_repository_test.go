@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	mock_config "github.com/SyamSolution/ticket-management-service/mock/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetLedgerBalance(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT td.total, td.available, td.held, td.sold,")).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"total", "available", "held", "sold", "total_sum", "available_sum", "held_sum",
+			"sold_sum", "entries"}).AddRow(100, 95, 3, 2, 100, 96, 2, 2, 4))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewInventoryLedgerRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+	balance, err := repo.GetLedgerBalance(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, model.LedgerBalance{
+		TicketID:   2,
+		Current:    model.StockCounters{Total: 100, Available: 95, Held: 3, Sold: 2},
+		Recomputed: model.StockCounters{Total: 100, Available: 96, Held: 2, Sold: 2},
+		EntryCount: 4,
+	}, balance)
+	assert.False(t, balance.Consistent())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetLedgerEntries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	createdAt := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM inventory_ledger WHERE ticket_detail_id = ? ORDER BY entry_id LIMIT ? OFFSET ?")).
+		WithArgs(2, 10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"entry_id", "ticket_detail_id", "total_delta", "available_delta", "held_delta",
+			"sold_delta", "reason", "order_id", "waitlist_id", "request_id", "actor", "created_at"}).
+			AddRow(1, 2, 100, 100, 0, 0, "opening", "", 0, "", "migration", createdAt).
+			AddRow(2, 2, 0, -2, 2, 0, "reserve", "order-1", 0, "req-1", "user:user@mail.com", createdAt))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewInventoryLedgerRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+	entries, err := repo.GetLedgerEntries(context.Background(), 2, 10, 0)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, model.InventoryLedgerEntry{EntryID: 2, TicketID: 2, Change: model.ReserveStock(2), Reason: "reserve",
+		OrderID: "order-1", RequestID: "req-1", Actor: "user:user@mail.com", CreatedAt: createdAt}, entries[1])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMoveStock(t *testing.T) {
+	t.Run("should apply the change and record it", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(stockQuery).
+			WithArgs(0, 3, -3, 0, 2, 3, -3, 0).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(ledgerQuery).
+			WithArgs(2, 0, 3, -3, 0, "waitlist_expire", nil, 5, nil, "system").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectRollback()
+
+		tx, err := db.BeginTx(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when starting a transaction", err)
+		}
+
+		err = moveStock(context.Background(), tx, model.InventoryLedgerEntry{TicketID: 2, Change: model.ReleaseStock(3),
+			Reason: "waitlist_expire", WaitlistID: 5})
+		assert.NoError(t, err)
+		assert.NoError(t, tx.Rollback())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should not record a change the counts do not allow", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(stockQuery).
+			WithArgs(0, 3, -3, 0, 2, 3, -3, 0).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		tx, err := db.BeginTx(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when starting a transaction", err)
+		}
+
+		err = moveStock(context.Background(), tx, model.InventoryLedgerEntry{TicketID: 2, Change: model.ReleaseStock(3),
+			Reason: "waitlist_expire", WaitlistID: 5})
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, tx.Rollback())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should reject an unbalanced change", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		tx, err := db.BeginTx(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when starting a transaction", err)
+		}
+
+		err = moveStock(context.Background(), tx, model.InventoryLedgerEntry{TicketID: 2,
+			Change: model.StockCounters{Available: -3}, Reason: "reserve"})
+		assert.ErrorIs(t, err, ErrUnbalancedStock)
+		assert.NoError(t, tx.Rollback())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+var snapshotColumns = []string{"ticket_detail_id", "type", "total", "available", "held", "sold", "ledger_total",
+	"ledger_available", "ledger_held", "ledger_sold", "orders_held", "orders_sold", "legacy_held", "legacy_sold",
+	"opening_held", "opening_sold"}
+
+func TestGetStockSnapshots(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE td.ticket_detail_id IN (?, ?) ORDER BY td.ticket_detail_id")).
+		WithArgs("opening", "opening", "pending", "confirmed", "refund_pending", "resale", "offered", 2, 3).
+		WillReturnRows(sqlmock.NewRows(snapshotColumns).
+			AddRow(2, "VIP", 100, 93, 2, 5, 100, 93, 2, 5, 2, 2, 0, 3, 0, 0).
+			AddRow(3, "Regular", 100, 100, 0, 0, 100, 100, 0, 0, 0, 0, 0, 0, 0, 0))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewInventoryLedgerRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+	snapshots, err := repo.GetStockSnapshots(context.Background(), []int{2, 3})
+	assert.NoError(t, err)
+	assert.Len(t, snapshots, 2)
+	assert.Equal(t, model.StockSnapshot{
+		TicketID:   2,
+		Type:       "VIP",
+		Current:    model.StockCounters{Total: 100, Available: 93, Held: 2, Sold: 5},
+		Ledger:     model.StockCounters{Total: 100, Available: 93, Held: 2, Sold: 5},
+		OrdersHeld: 2,
+		OrdersSold: 2,
+		LegacySold: 3,
+	}, snapshots[0])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepairStock(t *testing.T) {
+	lockQuery := regexp.QuoteMeta("SELECT ticket_detail_id FROM ticket_detail WHERE ticket_detail_id = ? FOR UPDATE")
+	snapshotQuery := regexp.QuoteMeta("WHERE td.ticket_detail_id = ?")
+	checked := model.StockSnapshot{
+		TicketID: 2,
+		Type:     "VIP",
+		Current:  model.StockCounters{Total: 100, Available: 98, Sold: 2},
+		Ledger:   model.StockCounters{Total: 100, Available: 100},
+	}
+
+	t.Run("should record the untracked change and the repair", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"ticket_detail_id"}).AddRow(2))
+		mock.ExpectQuery(snapshotQuery).
+			WithArgs("opening", "opening", "pending", "confirmed", "refund_pending", "resale", "offered", 2).
+			WillReturnRows(sqlmock.NewRows(snapshotColumns).AddRow(2, "VIP", 100, 98, 0, 2, 100, 100, 0, 0, 0, 0, 0, 0, 0, 0))
+		mock.ExpectExec(ledgerQuery).
+			WithArgs(2, 0, -2, 0, 2, "untracked", nil, nil, nil, "cli:reconcile").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(stockQuery).
+			WithArgs(0, 2, 0, -2, 2, 2, 0, -2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(ledgerQuery).
+			WithArgs(2, 0, 2, 0, -2, "reconcile", nil, nil, nil, "cli:reconcile").
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectCommit()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewInventoryLedgerRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+		err = repo.RepairStock(config.WithActor(context.Background(), "cli:reconcile"), checked,
+			model.StockCounters{Total: 100, Available: 100})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should leave a ticket that changed since the check", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"ticket_detail_id"}).AddRow(2))
+		mock.ExpectQuery(snapshotQuery).
+			WithArgs("opening", "opening", "pending", "confirmed", "refund_pending", "resale", "offered", 2).
+			WillReturnRows(sqlmock.NewRows(snapshotColumns).AddRow(2, "VIP", 100, 96, 2, 2, 100, 98, 2, 0, 2, 0, 0, 0, 0, 0))
+		mock.ExpectRollback()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewInventoryLedgerRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+		err = repo.RepairStock(context.Background(), checked, model.StockCounters{Total: 100, Available: 100})
+		assert.ErrorIs(t, err, ErrStockChanged)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"go.uber.org/zap"
+)
+
+type issuedTicketRepository struct {
+	DB           *sql.DB
+	logger       config.Logger
+	queryTimeout time.Duration
+}
+
+type IssuedTicketPersister interface {
+	CreateIssuedTickets(ctx context.Context, tickets []model.IssuedTicket) error
+	GetIssuedTicketsByReservationID(ctx context.Context, reservationID int) ([]model.IssuedTicket, error)
+	GetIssuedTicketsByEmail(ctx context.Context, email string) ([]model.IssuedTicket, error)
+	GetIssuedTicketByID(ctx context.Context, issuedTicketID int) (model.IssuedTicket, error)
+	GetIssuedTicketByCode(ctx context.Context, code string) (model.IssuedTicket, error)
+	VoidIssuedTicketsByReservationID(ctx context.Context, reservationID int) error
+	VoidIssuedTicket(ctx context.Context, issuedTicketID int) error
+	RecordScan(ctx context.Context, scan model.TicketScan) (model.TicketScan, error)
+}
+
+func NewIssuedTicketRepository(DB *sql.DB, logger config.Logger, queryTimeout time.Duration) IssuedTicketPersister {
+	return &issuedTicketRepository{DB: DB, logger: logger, queryTimeout: queryTimeout}
+}
+
+const issuedTicketQuery = `SELECT it.issued_ticket_id, it.code, it.reservation_id, it.unit_no, it.order_id, it.ticket_detail_id,
+		it.event_id, it.email, it.status, it.checked_in_at, COALESCE(it.checked_in_gate, ''), COALESCE(td.type, ''), COALESCE(e.event_name, ''), e.date, it.issued_at
+		FROM issued_ticket it
+		LEFT JOIN ticket_detail td ON td.ticket_detail_id = it.ticket_detail_id
+		LEFT JOIN event e ON e.event_id = it.event_id`
+
+func scanIssuedTicket(row rowScanner) (model.IssuedTicket, error) {
+	var (
+		ticket                 model.IssuedTicket
+		checkedInAt, eventDate sql.NullTime
+	)
+	err := row.Scan(&ticket.IssuedTicketID, &ticket.Code, &ticket.ReservationID, &ticket.UnitNo, &ticket.OrderID, &ticket.TicketID,
+		&ticket.EventID, &ticket.Email, &ticket.Status, &checkedInAt, &ticket.CheckedInGate, &ticket.Type, &ticket.EventName,
+		&eventDate, &ticket.IssuedAt)
+	ticket.CheckedInAt = nullTime(checkedInAt)
+	ticket.EventDate = eventDate.Time
+	return ticket, err
+}
+
+func (r *issuedTicketRepository) queryIssuedTickets(ctx context.Context, query string, args ...interface{}) ([]model.IssuedTicket, error) {
+	var tickets []model.IssuedTicket
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when querying issued_ticket table", zap.Error(err))
+		return tickets, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		ticket, err := scanIssuedTicket(rows)
+		if err != nil {
+			r.logger.WithContext(ctx).Error("Error when scanning issued_ticket table", zap.Error(err))
+			return tickets, err
+		}
+		tickets = append(tickets, ticket)
+	}
+	return tickets, nil
+}
+
+// CreateIssuedTickets inserts all tickets in one statement, so either every
+// unit of a reservation is issued or none is.
+func (r *issuedTicketRepository) CreateIssuedTickets(ctx context.Context, tickets []model.IssuedTicket) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	if len(tickets) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(tickets)*8)
+	for _, ticket := range tickets {
+		args = append(args, ticket.Code, ticket.ReservationID, ticket.UnitNo, ticket.OrderID, ticket.TicketID, ticket.EventID,
+			ticket.Email, ticket.Status)
+	}
+	query := `INSERT INTO issued_ticket (code, reservation_id, unit_no, order_id, ticket_detail_id, event_id, email, status) VALUES ` +
+		strings.TrimSuffix(strings.Repeat("(?, ?, ?, ?, ?, ?, ?, ?), ", len(tickets)), ", ")
+
+	if _, err := r.DB.ExecContext(ctx, query, args...); err != nil {
+		r.logger.WithContext(ctx).Error("Error when inserting issued_ticket table", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *issuedTicketRepository) GetIssuedTicketsByReservationID(ctx context.Context, reservationID int) ([]model.IssuedTicket, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	return r.queryIssuedTickets(ctx, issuedTicketQuery+` WHERE it.reservation_id = ? ORDER BY it.unit_no`, reservationID)
+}
+
+func (r *issuedTicketRepository) GetIssuedTicketsByEmail(ctx context.Context, email string) ([]model.IssuedTicket, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	return r.queryIssuedTickets(ctx, issuedTicketQuery+` WHERE it.email = ? ORDER BY e.date, it.issued_ticket_id`, email)
+}
+
+func (r *issuedTicketRepository) GetIssuedTicketByID(ctx context.Context, issuedTicketID int) (model.IssuedTicket, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	ticket, err := scanIssuedTicket(r.DB.QueryRowContext(ctx, issuedTicketQuery+` WHERE it.issued_ticket_id = ?`, issuedTicketID))
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when scanning issued_ticket table", zap.Error(err))
+		return ticket, err
+	}
+	return ticket, nil
+}
+
+func (r *issuedTicketRepository) GetIssuedTicketByCode(ctx context.Context, code string) (model.IssuedTicket, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	ticket, err := scanIssuedTicket(r.DB.QueryRowContext(ctx, issuedTicketQuery+` WHERE it.code = ?`, code))
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when scanning issued_ticket table", zap.Error(err))
+		return ticket, err
+	}
+	return ticket, nil
+}
+
+// VoidIssuedTicketsByReservationID voids the tickets of a reservation so they
+// no longer get anyone in.
+func (r *issuedTicketRepository) VoidIssuedTicketsByReservationID(ctx context.Context, reservationID int) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `UPDATE issued_ticket SET status = ? WHERE reservation_id = ?`
+	if _, err := r.DB.ExecContext(ctx, query, util.ISSUED_TICKET_STATUS_VOID, reservationID); err != nil {
+		r.logger.WithContext(ctx).Error("Error when updating issued_ticket table", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// VoidIssuedTicket voids a single ticket, e.g. one that changed hands and is
+// no longer covered by the reservation it was issued for.
+func (r *issuedTicketRepository) VoidIssuedTicket(ctx context.Context, issuedTicketID int) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `UPDATE issued_ticket SET status = ? WHERE issued_ticket_id = ?`
+	if _, err := r.DB.ExecContext(ctx, query, util.ISSUED_TICKET_STATUS_VOID, issuedTicketID); err != nil {
+		r.logger.WithContext(ctx).Error("Error when updating issued_ticket table", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+const ticketScanQuery = `SELECT ts.scan_id, COALESCE(ts.scan_ref, ''), COALESCE(ts.code, ''), ts.event_id, ts.gate, ts.result, ts.offline,
+		ts.scanned_at, it.checked_in_at, COALESCE(it.checked_in_gate, '')
+		FROM ticket_scan ts
+		LEFT JOIN issued_ticket it ON it.code = ts.code`
+
+func scanTicketScan(row rowScanner) (model.TicketScan, error) {
+	var (
+		scan        model.TicketScan
+		checkedInAt sql.NullTime
+	)
+	err := row.Scan(&scan.ScanID, &scan.ScanRef, &scan.Code, &scan.EventID, &scan.Gate, &scan.Result, &scan.Offline,
+		&scan.ScannedAt, &checkedInAt, &scan.CheckedInGate)
+	scan.CheckedInAt = nullTime(checkedInAt)
+	return scan, err
+}
+
+// RecordScan stores a scan in one transaction with its effect. A scan with
+// result accepted checks the ticket in, unless it was checked in before, in
+// which case the scan is recorded as a duplicate instead. A scan whose ScanRef
+// was recorded already is not applied again; the earlier scan is returned.
+func (r *issuedTicketRepository) RecordScan(ctx context.Context, scan model.TicketScan) (model.TicketScan, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when starting ticket scan transaction", zap.Error(err))
+		return scan, err
+	}
+	defer tx.Rollback()
+
+	if scan.ScanRef != "" {
+		recorded, err := scanTicketScan(tx.QueryRowContext(ctx, ticketScanQuery+` WHERE ts.scan_ref = ?`, scan.ScanRef))
+		if err == nil {
+			return recorded, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			r.logger.WithContext(ctx).Error("Error when scanning ticket_scan table", zap.Error(err))
+			return scan, err
+		}
+	}
+
+	if scan.Result == util.SCAN_RESULT_ACCEPTED {
+		query := `UPDATE issued_ticket SET checked_in_at = ?, checked_in_gate = ?
+			WHERE code = ? AND status = ? AND checked_in_at IS NULL`
+		result, err := tx.ExecContext(ctx, query, scan.ScannedAt, scan.Gate, scan.Code, util.ISSUED_TICKET_STATUS_VALID)
+		if err != nil {
+			r.logger.WithContext(ctx).Error("Error when updating issued_ticket table", zap.Error(err))
+			return scan, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			r.logger.WithContext(ctx).Error("Error when getting affected rows of issued_ticket table", zap.Error(err))
+			return scan, err
+		}
+		if affected == 0 {
+			scan.Result = util.SCAN_RESULT_DUPLICATE
+		}
+	}
+
+	query := `INSERT INTO ticket_scan (scan_ref, code, event_id, gate, result, offline, scanned_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	result, err := tx.ExecContext(ctx, query, nullString(scan.ScanRef), nullString(scan.Code), scan.EventID, scan.Gate, scan.Result,
+		scan.Offline, scan.ScannedAt)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when inserting ticket_scan table", zap.Error(err))
+		return scan, err
+	}
+	scanID, err := result.LastInsertId()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when getting last insert id of ticket_scan table", zap.Error(err))
+		return scan, err
+	}
+
+	recorded, err := scanTicketScan(tx.QueryRowContext(ctx, ticketScanQuery+` WHERE ts.scan_id = ?`, scanID))
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when scanning ticket_scan table", zap.Error(err))
+		return scan, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.WithContext(ctx).Error("Error when committing ticket scan transaction", zap.Error(err))
+		return scan, err
+	}
+	return recorded, nil
+}
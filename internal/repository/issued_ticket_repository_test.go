@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	mock_config "github.com/SyamSolution/ticket-management-service/mock/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateIssuedTickets(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO issued_ticket (code, reservation_id, unit_no, order_id, ticket_detail_id, event_id, email, status) VALUES (?, ?, ?, ?, ?, ?, ?, ?), (?, ?, ?, ?, ?, ?, ?, ?)")).
+		WithArgs("CODEONE", 9, 1, "order-1", 2, 3, "user@mail.com", "valid", "CODETWO", 9, 2, "order-1", 2, 3, "user@mail.com", "valid").
+		WillReturnResult(sqlmock.NewResult(1, 2))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewIssuedTicketRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+	err = repo.CreateIssuedTickets(context.Background(), []model.IssuedTicket{
+		{Code: "CODEONE", ReservationID: 9, UnitNo: 1, OrderID: "order-1", TicketID: 2, EventID: 3, Email: "user@mail.com", Status: "valid"},
+		{Code: "CODETWO", ReservationID: 9, UnitNo: 2, OrderID: "order-1", TicketID: 2, EventID: 3, Email: "user@mail.com", Status: "valid"},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetIssuedTicketsByEmail(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	date := time.Date(2026, 12, 1, 19, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"issued_ticket_id", "code", "reservation_id", "unit_no", "order_id", "ticket_detail_id", "event_id", "email", "status", "checked_in_at", "checked_in_gate", "type", "event_name", "date", "issued_at"}).
+		AddRow(1, "CODEONE", 9, 1, "order-1", 2, 3, "user@mail.com", "valid", nil, "", "VIP", "Concert", date, time.Now()).
+		AddRow(2, "CODETWO", 9, 2, "order-1", 2, 3, "user@mail.com", "valid", nil, "", "VIP", "Concert", date, time.Now())
+
+	mock.ExpectQuery("^SELECT it.issued_ticket_id, (.+) FROM issued_ticket it (.+) WHERE it.email = \\? ORDER BY e.date, it.issued_ticket_id$").
+		WithArgs("user@mail.com").
+		WillReturnRows(rows)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewIssuedTicketRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+	tickets, err := repo.GetIssuedTicketsByEmail(context.Background(), "user@mail.com")
+	assert.NoError(t, err)
+	assert.Len(t, tickets, 2)
+	assert.Equal(t, "Concert", tickets[0].EventName)
+	assert.Equal(t, date, tickets[1].EventDate)
+}
+
+func TestRecordScan(t *testing.T) {
+	scannedAt := time.Date(2026, 12, 1, 18, 30, 0, 0, time.UTC)
+	scanColumns := []string{"scan_id", "scan_ref", "code", "event_id", "gate", "result", "offline", "scanned_at", "checked_in_at", "checked_in_gate"}
+
+	t.Run("should check the ticket in", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE issued_ticket SET checked_in_at = \\?, checked_in_gate = \\? WHERE code = \\? AND status = \\? AND checked_in_at IS NULL").
+			WithArgs(scannedAt, "A", "CODEONE", "valid").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO ticket_scan (scan_ref, code, event_id, gate, result, offline, scanned_at) VALUES (?, ?, ?, ?, ?, ?, ?)")).
+			WithArgs(nil, "CODEONE", 3, "A", "accepted", false, scannedAt).
+			WillReturnResult(sqlmock.NewResult(5, 1))
+		mock.ExpectQuery("^SELECT ts.scan_id, (.+) FROM ticket_scan ts (.+) WHERE ts.scan_id = \\?$").WithArgs(int64(5)).
+			WillReturnRows(sqlmock.NewRows(scanColumns).AddRow(5, "", "CODEONE", 3, "A", "accepted", false, scannedAt, scannedAt, "A"))
+		mock.ExpectCommit()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewIssuedTicketRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+		scan, err := repo.RecordScan(context.Background(), model.TicketScan{Code: "CODEONE", EventID: 3, Gate: "A", Result: "accepted", ScannedAt: scannedAt})
+		assert.NoError(t, err)
+		assert.Equal(t, 5, scan.ScanID)
+		assert.Equal(t, "accepted", scan.Result)
+		assert.Equal(t, scannedAt, *scan.CheckedInAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should record a second scan as duplicate", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("^SELECT ts.scan_id, (.+) WHERE ts.scan_ref = \\?$").WithArgs("ref-1").WillReturnError(sql.ErrNoRows)
+		mock.ExpectExec("UPDATE issued_ticket SET checked_in_at").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("INSERT INTO ticket_scan").
+			WithArgs("ref-1", "CODEONE", 3, "B", "duplicate", true, scannedAt).
+			WillReturnResult(sqlmock.NewResult(6, 1))
+		mock.ExpectQuery("^SELECT ts.scan_id, (.+) WHERE ts.scan_id = \\?$").WithArgs(int64(6)).
+			WillReturnRows(sqlmock.NewRows(scanColumns).AddRow(6, "ref-1", "CODEONE", 3, "B", "duplicate", true, scannedAt, scannedAt.Add(-time.Minute), "A"))
+		mock.ExpectCommit()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewIssuedTicketRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+		scan, err := repo.RecordScan(context.Background(), model.TicketScan{ScanRef: "ref-1", Code: "CODEONE", EventID: 3, Gate: "B",
+			Result: "accepted", Offline: true, ScannedAt: scannedAt})
+		assert.NoError(t, err)
+		assert.Equal(t, "duplicate", scan.Result)
+		assert.Equal(t, "A", scan.CheckedInGate)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return a scan uploaded before", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("^SELECT ts.scan_id, (.+) WHERE ts.scan_ref = \\?$").WithArgs("ref-1").
+			WillReturnRows(sqlmock.NewRows(scanColumns).AddRow(6, "ref-1", "CODEONE", 3, "B", "accepted", true, scannedAt, scannedAt, "B"))
+		mock.ExpectRollback()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewIssuedTicketRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+		scan, err := repo.RecordScan(context.Background(), model.TicketScan{ScanRef: "ref-1", Code: "CODEONE", EventID: 3, Gate: "B",
+			Result: "accepted", Offline: true, ScannedAt: scannedAt})
+		assert.NoError(t, err)
+		assert.Equal(t, 6, scan.ScanID)
+		assert.Equal(t, "accepted", scan.Result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,331 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"go.uber.org/zap"
+)
+
+// Errors returned by HoldPromo when the code can not be used for the order.
+var (
+	ErrPromoExhausted   = errors.New("promo code usage limit reached")
+	ErrPromoAlreadyUsed = errors.New("promo code already used by this user")
+)
+
+type promoRepository struct {
+	DB           *sql.DB
+	logger       config.Logger
+	queryTimeout time.Duration
+}
+
+type PromoPersister interface {
+	CreatePromo(ctx context.Context, promo model.Promo) (int, error)
+	GetPromos(ctx context.Context) ([]model.Promo, error)
+	GetPromoByID(ctx context.Context, promoID int) (model.Promo, error)
+	GetPromoByCode(ctx context.Context, code string) (model.Promo, error)
+	UpdatePromo(ctx context.Context, promo model.Promo) error
+	DeletePromo(ctx context.Context, promoID int) error
+	CountActiveRedemptions(ctx context.Context, promoID int, email string) (int, error)
+	HoldPromo(ctx context.Context, redemption model.PromoRedemption) error
+	RedeemPromoByOrderID(ctx context.Context, orderID string) error
+	ReleasePromoByOrderID(ctx context.Context, orderID string) error
+}
+
+func NewPromoRepository(DB *sql.DB, logger config.Logger, queryTimeout time.Duration) PromoPersister {
+	return &promoRepository{DB: DB, logger: logger, queryTimeout: queryTimeout}
+}
+
+const promoColumns = `promo_id, code, discount_type, discount_value, COALESCE(currency, ''), event_id, max_uses, used_count,
+	once_per_user, starts_at, expires_at, active, created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPromo(row rowScanner) (model.Promo, error) {
+	var (
+		promo              model.Promo
+		eventID, maxUses   sql.NullInt64
+		startsAt, expireAt sql.NullTime
+	)
+	err := row.Scan(&promo.PromoID, &promo.Code, &promo.DiscountType, &promo.DiscountValue, &promo.Currency, &eventID, &maxUses,
+		&promo.UsedCount, &promo.OncePerUser, &startsAt, &expireAt, &promo.Active, &promo.CreatedAt, &promo.UpdatedAt)
+	if err != nil {
+		return promo, err
+	}
+	if eventID.Valid {
+		value := int(eventID.Int64)
+		promo.EventID = &value
+	}
+	if maxUses.Valid {
+		value := int(maxUses.Int64)
+		promo.MaxUses = &value
+	}
+	if startsAt.Valid {
+		promo.StartsAt = &startsAt.Time
+	}
+	if expireAt.Valid {
+		promo.ExpiresAt = &expireAt.Time
+	}
+	return promo, nil
+}
+
+func (r *promoRepository) CreatePromo(ctx context.Context, promo model.Promo) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `INSERT INTO promo_code (code, discount_type, discount_value, currency, event_id, max_uses, once_per_user, starts_at,
+		expires_at, active) VALUES (?, ?, ?, NULLIF(?, ''), ?, ?, ?, ?, ?, ?)`
+
+	result, err := r.DB.ExecContext(ctx, query, promo.Code, promo.DiscountType, promo.DiscountValue, promo.Currency, promo.EventID,
+		promo.MaxUses, promo.OncePerUser, promo.StartsAt, promo.ExpiresAt, promo.Active)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when inserting promo_code table", zap.Error(err))
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when getting last insert id of promo_code table", zap.Error(err))
+		return 0, err
+	}
+	return int(id), nil
+}
+
+func (r *promoRepository) GetPromos(ctx context.Context) ([]model.Promo, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var promos []model.Promo
+	query := `SELECT ` + promoColumns + ` FROM promo_code ORDER BY promo_id`
+
+	rows, err := r.DB.QueryContext(ctx, query)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when querying promo_code table", zap.Error(err))
+		return promos, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		promo, err := scanPromo(rows)
+		if err != nil {
+			r.logger.WithContext(ctx).Error("Error when scanning promo_code table", zap.Error(err))
+			return promos, err
+		}
+		promos = append(promos, promo)
+	}
+	return promos, nil
+}
+
+func (r *promoRepository) GetPromoByID(ctx context.Context, promoID int) (model.Promo, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + promoColumns + ` FROM promo_code WHERE promo_id = ?`
+
+	promo, err := scanPromo(r.DB.QueryRowContext(ctx, query, promoID))
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when scanning promo_code table", zap.Error(err))
+		return promo, err
+	}
+	return promo, nil
+}
+
+func (r *promoRepository) GetPromoByCode(ctx context.Context, code string) (model.Promo, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + promoColumns + ` FROM promo_code WHERE code = ?`
+
+	promo, err := scanPromo(r.DB.QueryRowContext(ctx, query, code))
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when scanning promo_code table", zap.Error(err))
+		return promo, err
+	}
+	return promo, nil
+}
+
+func (r *promoRepository) UpdatePromo(ctx context.Context, promo model.Promo) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `UPDATE promo_code SET code = ?, discount_type = ?, discount_value = ?, currency = NULLIF(?, ''), event_id = ?,
+		max_uses = ?, once_per_user = ?, starts_at = ?, expires_at = ?, active = ? WHERE promo_id = ?`
+
+	_, err := r.DB.ExecContext(ctx, query, promo.Code, promo.DiscountType, promo.DiscountValue, promo.Currency, promo.EventID,
+		promo.MaxUses, promo.OncePerUser, promo.StartsAt, promo.ExpiresAt, promo.Active, promo.PromoID)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when updating promo_code table", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// DeletePromo removes a promo code that has never been used. It returns
+// sql.ErrNoRows when the code does not exist or has redemptions, which keep
+// it for the record; deactivate those instead.
+func (r *promoRepository) DeletePromo(ctx context.Context, promoID int) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `DELETE FROM promo_code WHERE promo_id = ?
+		AND NOT EXISTS (SELECT 1 FROM promo_redemption WHERE promo_id = ?)`
+
+	result, err := r.DB.ExecContext(ctx, query, promoID, promoID)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when deleting promo_code table", zap.Error(err))
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when getting affected rows of promo_code table", zap.Error(err))
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// CountActiveRedemptions counts the held and redeemed uses of a code by email.
+func (r *promoRepository) CountActiveRedemptions(ctx context.Context, promoID int, email string) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var count int
+	query := `SELECT COUNT(*) FROM promo_redemption WHERE promo_id = ? AND email = ? AND status <> ?`
+
+	err := r.DB.QueryRowContext(ctx, query, promoID, email, util.PROMO_REDEMPTION_RELEASED).Scan(&count)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when scanning promo_redemption table", zap.Error(err))
+		return count, err
+	}
+	return count, nil
+}
+
+// HoldPromo takes one use of the code for redemption.OrderID. The promo row is
+// locked for the duration of the transaction, so concurrent orders see each
+// other's holds and the usage limit and the once-per-user rule can not be
+// exceeded; those violations return ErrPromoExhausted and ErrPromoAlreadyUsed.
+func (r *promoRepository) HoldPromo(ctx context.Context, redemption model.PromoRedemption) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when starting promo_code transaction", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	var (
+		usedCount   int
+		maxUses     sql.NullInt64
+		oncePerUser bool
+	)
+	query := `SELECT used_count, max_uses, once_per_user FROM promo_code WHERE promo_id = ? FOR UPDATE`
+	err = tx.QueryRowContext(ctx, query, redemption.PromoID).Scan(&usedCount, &maxUses, &oncePerUser)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when locking promo_code table", zap.Error(err))
+		return err
+	}
+	if maxUses.Valid && int64(usedCount) >= maxUses.Int64 {
+		return ErrPromoExhausted
+	}
+
+	if oncePerUser {
+		var used int
+		query = `SELECT COUNT(*) FROM promo_redemption WHERE promo_id = ? AND email = ? AND status <> ?`
+		err = tx.QueryRowContext(ctx, query, redemption.PromoID, redemption.Email, util.PROMO_REDEMPTION_RELEASED).Scan(&used)
+		if err != nil {
+			r.logger.WithContext(ctx).Error("Error when scanning promo_redemption table", zap.Error(err))
+			return err
+		}
+		if used > 0 {
+			return ErrPromoAlreadyUsed
+		}
+	}
+
+	query = `UPDATE promo_code SET used_count = used_count + 1 WHERE promo_id = ?`
+	if _, err = tx.ExecContext(ctx, query, redemption.PromoID); err != nil {
+		r.logger.WithContext(ctx).Error("Error when updating promo_code table", zap.Error(err))
+		return err
+	}
+
+	query = `INSERT INTO promo_redemption (promo_id, order_id, email, discount, currency, status) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err = tx.ExecContext(ctx, query, redemption.PromoID, redemption.OrderID, redemption.Email, redemption.Discount.Amount,
+		redemption.Discount.Currency, util.PROMO_REDEMPTION_HELD)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when inserting promo_redemption table", zap.Error(err))
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.WithContext(ctx).Error("Error when committing promo_code transaction", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *promoRepository) RedeemPromoByOrderID(ctx context.Context, orderID string) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `UPDATE promo_redemption SET status = ? WHERE order_id = ? AND status = ?`
+	_, err := r.DB.ExecContext(ctx, query, util.PROMO_REDEMPTION_REDEEMED, orderID, util.PROMO_REDEMPTION_HELD)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when updating promo_redemption table", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// ReleasePromoByOrderID gives the use held by orderID back to its code. Orders
+// without a held use are unaffected.
+func (r *promoRepository) ReleasePromoByOrderID(ctx context.Context, orderID string) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when starting promo_redemption transaction", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	var promoID int
+	query := `SELECT promo_id FROM promo_redemption WHERE order_id = ? AND status = ? FOR UPDATE`
+	err = tx.QueryRowContext(ctx, query, orderID, util.PROMO_REDEMPTION_HELD).Scan(&promoID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when locking promo_redemption table", zap.Error(err))
+		return err
+	}
+
+	query = `UPDATE promo_redemption SET status = ? WHERE order_id = ?`
+	if _, err = tx.ExecContext(ctx, query, util.PROMO_REDEMPTION_RELEASED, orderID); err != nil {
+		r.logger.WithContext(ctx).Error("Error when updating promo_redemption table", zap.Error(err))
+		return err
+	}
+
+	query = `UPDATE promo_code SET used_count = used_count - 1 WHERE promo_id = ? AND used_count > 0`
+	if _, err = tx.ExecContext(ctx, query, promoID); err != nil {
+		r.logger.WithContext(ctx).Error("Error when updating promo_code table", zap.Error(err))
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.WithContext(ctx).Error("Error when committing promo_redemption transaction", zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	mock_config "github.com/SyamSolution/ticket-management-service/mock/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHoldPromo(t *testing.T) {
+	redemption := model.PromoRedemption{PromoID: 7, OrderID: "order-1", Email: "user@mail.com", Discount: model.Money{Amount: 40000, Currency: "IDR"}}
+	lockQuery := "SELECT used_count, max_uses, once_per_user FROM promo_code WHERE promo_id = \\? FOR UPDATE"
+
+	t.Run("holds one use of the code", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"used_count", "max_uses", "once_per_user"}).AddRow(4, 5, true))
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM promo_redemption WHERE promo_id = \\? AND email = \\? AND status <> \\?").
+			WithArgs(7, "user@mail.com", "released").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec("UPDATE promo_code SET used_count = used_count \\+ 1 WHERE promo_id = \\?").
+			WithArgs(7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO promo_redemption").
+			WithArgs(7, "order-1", "user@mail.com", int64(40000), "IDR", "held").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewPromoRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+		assert.NoError(t, repo.HoldPromo(context.Background(), redemption))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rolls back when the usage limit is reached", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"used_count", "max_uses", "once_per_user"}).AddRow(5, 5, false))
+		mock.ExpectRollback()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewPromoRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+		err = repo.HoldPromo(context.Background(), redemption)
+		assert.ErrorIs(t, err, ErrPromoExhausted)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReleasePromoByOrderID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT promo_id FROM promo_redemption WHERE order_id = \\? AND status = \\? FOR UPDATE").
+		WithArgs("order-1", "held").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewPromoRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+	assert.NoError(t, repo.ReleasePromoByOrderID(context.Background(), "order-1"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeletePromo(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("DELETE FROM promo_code WHERE promo_id = \\?").
+		WithArgs(4, 4).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewPromoRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+	err = repo.DeletePromo(context.Background(), 4)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -0,0 +1,295 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"go.uber.org/zap"
+)
+
+// ErrTicketUnavailable is returned by CreateListing when the ticket is
+// already listed or has a pending transfer.
+var ErrTicketUnavailable = errors.New("ticket is already listed for resale or being transferred")
+
+type resaleRepository struct {
+	DB           *sql.DB
+	logger       config.Logger
+	queryTimeout time.Duration
+}
+
+type ResalePersister interface {
+	CreateListing(ctx context.Context, listing model.ResaleListing) (int, error)
+	GetListingByID(ctx context.Context, listingID int) (model.ResaleListing, error)
+	GetListingByOrderID(ctx context.Context, orderID string) (model.ResaleListing, error)
+	GetSoldListingsByReservationID(ctx context.Context, reservationID int) ([]model.ResaleListing, error)
+	GetActiveListingsByEventID(ctx context.Context, eventID int) ([]model.ResaleListing, error)
+	GetListingsBySellerEmail(ctx context.Context, email string) ([]model.ResaleListing, error)
+	WithdrawListing(ctx context.Context, listingID int) error
+	ReserveListing(ctx context.Context, listingID int, orderID, buyerEmail string) error
+	ReleaseListing(ctx context.Context, listingID int, orderID string) error
+	CancelListing(ctx context.Context, listingID int, orderID string) error
+	SellListing(ctx context.Context, listing model.ResaleListing, code string, at time.Time) error
+}
+
+func NewResaleRepository(DB *sql.DB, logger config.Logger, queryTimeout time.Duration) ResalePersister {
+	return &resaleRepository{DB: DB, logger: logger, queryTimeout: queryTimeout}
+}
+
+const resaleListingColumns = `listing_id, issued_ticket_id, event_id, ticket_detail_id, seller_email, price, fee, currency, status,
+	COALESCE(order_id, ''), COALESCE(buyer_email, ''), sold_at, created_at, updated_at`
+
+func scanListing(row rowScanner) (model.ResaleListing, error) {
+	var (
+		listing model.ResaleListing
+		soldAt  sql.NullTime
+	)
+	err := row.Scan(&listing.ListingID, &listing.IssuedTicketID, &listing.EventID, &listing.TicketID, &listing.SellerEmail,
+		&listing.Price.Amount, &listing.Fee.Amount, &listing.Price.Currency, &listing.Status, &listing.OrderID,
+		&listing.BuyerEmail, &soldAt, &listing.CreatedAt, &listing.UpdatedAt)
+	listing.Fee.Currency = listing.Price.Currency
+	listing.Total = model.Money{Amount: listing.Price.Amount + listing.Fee.Amount, Currency: listing.Price.Currency}
+	listing.SoldAt = nullTime(soldAt)
+	return listing, err
+}
+
+func (r *resaleRepository) queryListings(ctx context.Context, query string, args ...interface{}) ([]model.ResaleListing, error) {
+	var listings []model.ResaleListing
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when querying resale_listing table", zap.Error(err))
+		return listings, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		listing, err := scanListing(rows)
+		if err != nil {
+			r.logger.WithContext(ctx).Error("Error when scanning resale_listing table", zap.Error(err))
+			return listings, err
+		}
+		listings = append(listings, listing)
+	}
+	return listings, nil
+}
+
+// CreateListing puts a ticket up for resale unless it is already listed or
+// has a pending transfer, in which case ErrTicketUnavailable is returned.
+func (r *resaleRepository) CreateListing(ctx context.Context, listing model.ResaleListing) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `INSERT INTO resale_listing (issued_ticket_id, event_id, ticket_detail_id, seller_email, price, fee, currency, status)
+		SELECT ?, ?, ?, ?, ?, ?, ?, ? FROM DUAL
+		WHERE NOT EXISTS (SELECT 1 FROM resale_listing WHERE issued_ticket_id = ? AND status IN (?, ?))
+		AND NOT EXISTS (SELECT 1 FROM ticket_transfer WHERE issued_ticket_id = ? AND status = ?)`
+	result, err := r.DB.ExecContext(ctx, query, listing.IssuedTicketID, listing.EventID, listing.TicketID, listing.SellerEmail,
+		listing.Price.Amount, listing.Fee.Amount, listing.Price.Currency, util.RESALE_LISTING_STATUS_ACTIVE,
+		listing.IssuedTicketID, util.RESALE_LISTING_STATUS_ACTIVE, util.RESALE_LISTING_STATUS_RESERVED,
+		listing.IssuedTicketID, util.TRANSFER_STATUS_PENDING)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when inserting resale_listing table", zap.Error(err))
+		return 0, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when getting affected rows of resale_listing table", zap.Error(err))
+		return 0, err
+	}
+	if affected == 0 {
+		return 0, ErrTicketUnavailable
+	}
+
+	listingID, err := result.LastInsertId()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when getting last insert id of resale_listing table", zap.Error(err))
+		return 0, err
+	}
+	return int(listingID), nil
+}
+
+func (r *resaleRepository) GetListingByID(ctx context.Context, listingID int) (model.ResaleListing, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + resaleListingColumns + ` FROM resale_listing WHERE listing_id = ?`
+	listing, err := scanListing(r.DB.QueryRowContext(ctx, query, listingID))
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when scanning resale_listing table", zap.Error(err))
+		return listing, err
+	}
+	return listing, nil
+}
+
+// GetListingByOrderID returns the listing reserved or bought by orderID.
+func (r *resaleRepository) GetListingByOrderID(ctx context.Context, orderID string) (model.ResaleListing, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + resaleListingColumns + ` FROM resale_listing WHERE order_id = ? ORDER BY listing_id DESC LIMIT 1`
+	listing, err := scanListing(r.DB.QueryRowContext(ctx, query, orderID))
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when scanning resale_listing table", zap.Error(err))
+		return listing, err
+	}
+	return listing, nil
+}
+
+// GetSoldListingsByReservationID returns every sale of the tickets issued for
+// the reservation, a ticket resold twice showing up twice, oldest first.
+func (r *resaleRepository) GetSoldListingsByReservationID(ctx context.Context, reservationID int) ([]model.ResaleListing, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + resaleListingColumns + ` FROM resale_listing WHERE status = ?
+		AND issued_ticket_id IN (SELECT issued_ticket_id FROM issued_ticket WHERE reservation_id = ?) ORDER BY listing_id`
+	return r.queryListings(ctx, query, util.RESALE_LISTING_STATUS_SOLD, reservationID)
+}
+
+// GetActiveListingsByEventID returns the listings of the event that can be
+// bought, cheapest first.
+func (r *resaleRepository) GetActiveListingsByEventID(ctx context.Context, eventID int) ([]model.ResaleListing, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + resaleListingColumns + ` FROM resale_listing WHERE event_id = ? AND status = ? ORDER BY price + fee, listing_id`
+	return r.queryListings(ctx, query, eventID, util.RESALE_LISTING_STATUS_ACTIVE)
+}
+
+func (r *resaleRepository) GetListingsBySellerEmail(ctx context.Context, email string) ([]model.ResaleListing, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + resaleListingColumns + ` FROM resale_listing WHERE seller_email = ? ORDER BY listing_id DESC`
+	return r.queryListings(ctx, query, email)
+}
+
+// WithdrawListing takes an active listing off sale. It returns sql.ErrNoRows
+// when the listing is not active, e.g. because a buyer reserved it.
+func (r *resaleRepository) WithdrawListing(ctx context.Context, listingID int) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `UPDATE resale_listing SET status = ? WHERE listing_id = ? AND status = ?`
+	return r.exec(ctx, query, util.RESALE_LISTING_STATUS_CANCELLED, listingID, util.RESALE_LISTING_STATUS_ACTIVE)
+}
+
+// ReserveListing holds an active listing for the order of buyerEmail. It
+// returns sql.ErrNoRows when the listing is no longer active.
+func (r *resaleRepository) ReserveListing(ctx context.Context, listingID int, orderID, buyerEmail string) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `UPDATE resale_listing SET status = ?, order_id = ?, buyer_email = ? WHERE listing_id = ? AND status = ?`
+	return r.exec(ctx, query, util.RESALE_LISTING_STATUS_RESERVED, orderID, buyerEmail, listingID, util.RESALE_LISTING_STATUS_ACTIVE)
+}
+
+// ReleaseListing puts a listing reserved by orderID back on sale. It returns
+// sql.ErrNoRows when orderID no longer holds the listing.
+func (r *resaleRepository) ReleaseListing(ctx context.Context, listingID int, orderID string) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `UPDATE resale_listing SET status = ?, order_id = NULL, buyer_email = NULL
+		WHERE listing_id = ? AND status = ? AND order_id = ?`
+	return r.exec(ctx, query, util.RESALE_LISTING_STATUS_ACTIVE, listingID, util.RESALE_LISTING_STATUS_RESERVED, orderID)
+}
+
+// CancelListing calls off the sale of a listing reserved by orderID, for
+// when its ticket can no longer change hands. It returns sql.ErrNoRows when
+// orderID no longer holds the listing.
+func (r *resaleRepository) CancelListing(ctx context.Context, listingID int, orderID string) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `UPDATE resale_listing SET status = ? WHERE listing_id = ? AND status = ? AND order_id = ?`
+	return r.exec(ctx, query, util.RESALE_LISTING_STATUS_CANCELLED, listingID, util.RESALE_LISTING_STATUS_RESERVED, orderID)
+}
+
+// SellListing completes the sale of a listing reserved by listing.OrderID in
+// one transaction: the ticket moves to the buyer under code, which voids
+// every QR payload signed for the old one, and the hand-over is recorded as
+// an accepted transfer. It returns sql.ErrNoRows when the
+// order no longer holds the listing or the ticket is no longer the seller's
+// to give, e.g. because it was checked in meanwhile.
+func (r *resaleRepository) SellListing(ctx context.Context, listing model.ResaleListing, code string, at time.Time) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when starting resale transaction", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	statements := []struct {
+		query string
+		args  []interface{}
+	}{
+		{
+			query: `UPDATE resale_listing SET status = ?, sold_at = ? WHERE listing_id = ? AND status = ? AND order_id = ?`,
+			args: []interface{}{util.RESALE_LISTING_STATUS_SOLD, at, listing.ListingID, util.RESALE_LISTING_STATUS_RESERVED,
+				listing.OrderID},
+		},
+		{
+			query: `UPDATE issued_ticket SET email = ?, code = ?
+				WHERE issued_ticket_id = ? AND email = ? AND status = ? AND checked_in_at IS NULL`,
+			args: []interface{}{listing.BuyerEmail, code, listing.IssuedTicketID, listing.SellerEmail, util.ISSUED_TICKET_STATUS_VALID},
+		},
+		{
+			query: `INSERT INTO ticket_transfer (issued_ticket_id, listing_id, from_email, to_email, status, expires_at,
+				responded_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			args: []interface{}{listing.IssuedTicketID, listing.ListingID, listing.SellerEmail, listing.BuyerEmail,
+				util.TRANSFER_STATUS_ACCEPTED, at, at, at},
+		},
+	}
+
+	for _, statement := range statements {
+		result, err := tx.ExecContext(ctx, statement.query, statement.args...)
+		if err != nil {
+			r.logger.WithContext(ctx).Error("Error when updating resale sale", zap.Error(err))
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			r.logger.WithContext(ctx).Error("Error when getting affected rows of resale sale", zap.Error(err))
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.WithContext(ctx).Error("Error when committing resale transaction", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// exec runs a guarded update of resale_listing and returns sql.ErrNoRows
+// when it changed nothing.
+func (r *resaleRepository) exec(ctx context.Context, query string, args ...interface{}) error {
+	result, err := r.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when updating resale_listing table", zap.Error(err))
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when getting affected rows of resale_listing table", zap.Error(err))
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
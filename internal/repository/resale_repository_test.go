@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	mock_config "github.com/SyamSolution/ticket-management-service/mock/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateListing(t *testing.T) {
+	listing := model.ResaleListing{IssuedTicketID: 1, EventID: 3, TicketID: 2, SellerEmail: "seller@mail.com",
+		Price: model.Money{Amount: 220000, Currency: "IDR"}, Fee: model.Money{Amount: 11000, Currency: "IDR"}}
+	query := regexp.QuoteMeta("INSERT INTO resale_listing (issued_ticket_id, event_id, ticket_detail_id, seller_email, price, fee, currency, status)")
+
+	t.Run("should insert an active listing", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectExec(query).
+			WithArgs(1, 3, 2, "seller@mail.com", int64(220000), int64(11000), "IDR", "active", 1, "active", "reserved", 1, "pending").
+			WillReturnResult(sqlmock.NewResult(5, 1))
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewResaleRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+		listingID, err := repo.CreateListing(context.Background(), listing)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, listingID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should refuse a ticket already listed or being transferred", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewResaleRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+		_, err = repo.CreateListing(context.Background(), listing)
+		assert.ErrorIs(t, err, ErrTicketUnavailable)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetActiveListingsByEventID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"listing_id", "issued_ticket_id", "event_id", "ticket_detail_id", "seller_email", "price", "fee",
+		"currency", "status", "order_id", "buyer_email", "sold_at", "created_at", "updated_at"}).
+		AddRow(5, 1, 3, 2, "seller@mail.com", 220000, 11000, "IDR", "active", "", "", nil, time.Now(), time.Now())
+
+	mock.ExpectQuery("^SELECT listing_id, (.+) FROM resale_listing WHERE event_id = \\? AND status = \\? ORDER BY price \\+ fee, listing_id$").
+		WithArgs(3, "active").
+		WillReturnRows(rows)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewResaleRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+	listings, err := repo.GetActiveListingsByEventID(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Len(t, listings, 1)
+	assert.Equal(t, model.Money{Amount: 11000, Currency: "IDR"}, listings[0].Fee)
+	assert.Equal(t, model.Money{Amount: 231000, Currency: "IDR"}, listings[0].Total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSoldListingsByReservationID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	soldAt := time.Now()
+	rows := sqlmock.NewRows([]string{"listing_id", "issued_ticket_id", "event_id", "ticket_detail_id", "seller_email", "price", "fee",
+		"currency", "status", "order_id", "buyer_email", "sold_at", "created_at", "updated_at"}).
+		AddRow(5, 1, 3, 2, "seller@mail.com", 220000, 11000, "IDR", "sold", "order-5", "buyer@mail.com", soldAt, time.Now(), time.Now())
+
+	mock.ExpectQuery("^SELECT listing_id, (.+) FROM resale_listing WHERE status = \\? "+
+		"AND issued_ticket_id IN \\(SELECT issued_ticket_id FROM issued_ticket WHERE reservation_id = \\?\\) ORDER BY listing_id$").
+		WithArgs("sold", 4).
+		WillReturnRows(rows)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewResaleRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+	listings, err := repo.GetSoldListingsByReservationID(context.Background(), 4)
+	assert.NoError(t, err)
+	assert.Len(t, listings, 1)
+	assert.Equal(t, "order-5", listings[0].OrderID)
+	assert.Equal(t, &soldAt, listings[0].SoldAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSellListing(t *testing.T) {
+	at := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+	listing := model.ResaleListing{ListingID: 5, IssuedTicketID: 1, SellerEmail: "seller@mail.com", BuyerEmail: "buyer@mail.com",
+		OrderID: "order-9"}
+
+	t.Run("should move the ticket to the buyer under the new code", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE resale_listing SET status = ?, sold_at = ?")).
+			WithArgs("sold", at, 5, "reserved", "order-9").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE issued_ticket SET email = ?, code = ?")).
+			WithArgs("buyer@mail.com", "NEWCODE", 1, "seller@mail.com", "valid").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO ticket_transfer (issued_ticket_id, listing_id, from_email, to_email, status,")).
+			WithArgs(1, 5, "seller@mail.com", "buyer@mail.com", "accepted", at, at, at).
+			WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectCommit()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewResaleRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+		err = repo.SellListing(context.Background(), listing, "NEWCODE", at)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should roll back when the ticket is no longer the seller's", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE resale_listing SET status = ?, sold_at = ?")).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE issued_ticket SET email = ?, code = ?")).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewResaleRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+		err = repo.SellListing(context.Background(), listing, "NEWCODE", at)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReleaseListing(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE resale_listing SET status = ?, order_id = NULL, buyer_email = NULL")).
+		WithArgs("active", 5, "reserved", "order-9").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewResaleRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+	err = repo.ReleaseListing(context.Background(), 5, "order-9")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"github.com/go-sql-driver/mysql"
+	"go.uber.org/zap"
+)
+
+// ErrPurchaseLimit is returned by CreateReservationWithinLimit when the
+// reservation would take its buyer past the per-user limit of the event.
+var ErrPurchaseLimit = errors.New("purchase limit of event reached")
+
+// ErrDuplicateOrder is returned when a reservation is recorded for an order
+// that already has one.
+var ErrDuplicateOrder = errors.New("order already has a reservation")
+
+// mysqlDuplicateEntry is the MySQL error number of a duplicate unique key.
+const mysqlDuplicateEntry = 1062
+
+// reservationInsertError maps the duplicate order id of a failed insert to
+// ErrDuplicateOrder.
+func reservationInsertError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+		return ErrDuplicateOrder
+	}
+	return err
+}
+
+type reservationRepository struct {
+	DB           *sql.DB
+	logger       config.Logger
+	queryTimeout time.Duration
+}
+
+type ReservationPersister interface {
+	CreateReservation(ctx context.Context, reservation model.Reservation) (int, error)
+	CreateReservationWithinLimit(ctx context.Context, reservation model.Reservation, maxQuantity int) (int, error)
+	GetReservationByOrderID(ctx context.Context, orderID string) (model.Reservation, error)
+	GetReservedQuantityByEventAndEmail(ctx context.Context, eventID int, email string) (int, error)
+	UpdateReservationStatusByOrderID(ctx context.Context, orderID, status string) error
+	GetReservationsByEventAndStatus(ctx context.Context, eventID int, status string) ([]model.Reservation, error)
+	UpdateReservationStatus(ctx context.Context, reservationID int, from, to string) error
+	GetExpiredReservations(ctx context.Context, now time.Time) ([]model.Reservation, error)
+}
+
+func NewReservationRepository(DB *sql.DB, logger config.Logger, queryTimeout time.Duration) ReservationPersister {
+	return &reservationRepository{DB: DB, logger: logger, queryTimeout: queryTimeout}
+}
+
+const reservationColumns = `reservation_id, COALESCE(order_id, ''), ticket_detail_id, event_id, email, quantity, unit_price, currency,
+	COALESCE(price_tier, ''), COALESCE(promo_code, ''), discount, status, expires_at, created_at, updated_at`
+
+func scanReservation(row rowScanner) (model.Reservation, error) {
+	var (
+		reservation model.Reservation
+		expiresAt   sql.NullTime
+	)
+	err := row.Scan(&reservation.ReservationID, &reservation.OrderID, &reservation.TicketID, &reservation.EventID,
+		&reservation.Email, &reservation.Quantity, &reservation.UnitPrice.Amount, &reservation.UnitPrice.Currency,
+		&reservation.PriceTier, &reservation.PromoCode, &reservation.Discount.Amount, &reservation.Status, &expiresAt,
+		&reservation.CreatedAt, &reservation.UpdatedAt)
+	reservation.Discount.Currency = reservation.UnitPrice.Currency
+	reservation.ExpiresAt = nullTime(expiresAt)
+	return reservation, err
+}
+
+const insertReservationQuery = `INSERT INTO reservation (order_id, ticket_detail_id, event_id, email, quantity, unit_price, currency,
+	price_tier, promo_code, discount, status, expires_at)
+	VALUES (NULLIF(?, ''), ?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?, ?)`
+
+func reservationArgs(reservation model.Reservation) []interface{} {
+	return []interface{}{reservation.OrderID, reservation.TicketID, reservation.EventID, reservation.Email,
+		reservation.Quantity, reservation.UnitPrice.Amount, reservation.UnitPrice.Currency, reservation.PriceTier,
+		reservation.PromoCode, reservation.Discount.Amount, reservation.Status, reservation.ExpiresAt}
+}
+
+// CreateReservation records reservation. It returns ErrDuplicateOrder when its
+// order already has a reservation.
+func (r *reservationRepository) CreateReservation(ctx context.Context, reservation model.Reservation) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	result, err := r.DB.ExecContext(ctx, insertReservationQuery, reservationArgs(reservation)...)
+	if err = reservationInsertError(err); errors.Is(err, ErrDuplicateOrder) {
+		return 0, err
+	}
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when inserting reservation table", zap.Error(err))
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when getting last insert id of reservation table", zap.Error(err))
+		return 0, err
+	}
+	return int(id), nil
+}
+
+// CreateReservationWithinLimit records reservation unless it would take its
+// buyer past maxQuantity tickets of the event, counting their pending and
+// confirmed reservations, in which case ErrPurchaseLimit is returned. The
+// buyer's reservation_buyer row is locked while the tickets are counted and
+// the reservation is inserted, so concurrent orders of one buyer are counted
+// one after the other. Like CreateReservation it returns ErrDuplicateOrder
+// when the order already has a reservation.
+func (r *reservationRepository) CreateReservationWithinLimit(ctx context.Context, reservation model.Reservation,
+	maxQuantity int) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when starting reservation transaction", zap.Error(err))
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// the update of an existing row takes its exclusive lock right away, where
+	// a plain read would share it and have two orders deadlock on upgrading.
+	query := `INSERT INTO reservation_buyer (event_id, email) VALUES (?, ?) ON DUPLICATE KEY UPDATE email = email`
+	if _, err := tx.ExecContext(ctx, query, reservation.EventID, reservation.Email); err != nil {
+		r.logger.WithContext(ctx).Error("Error when locking reservation_buyer table", zap.Error(err))
+		return 0, err
+	}
+
+	var reserved int
+	query = `SELECT COALESCE(SUM(quantity), 0) FROM reservation WHERE event_id = ? AND email = ? AND status IN (?, ?)`
+	err = tx.QueryRowContext(ctx, query, reservation.EventID, reservation.Email, util.RESERVATION_STATUS_PENDING,
+		util.RESERVATION_STATUS_CONFIRMED).Scan(&reserved)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when scanning reservation table", zap.Error(err))
+		return 0, err
+	}
+	if reserved+reservation.Quantity > maxQuantity {
+		return 0, ErrPurchaseLimit
+	}
+
+	result, err := tx.ExecContext(ctx, insertReservationQuery, reservationArgs(reservation)...)
+	if err = reservationInsertError(err); errors.Is(err, ErrDuplicateOrder) {
+		return 0, err
+	}
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when inserting reservation table", zap.Error(err))
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when getting last insert id of reservation table", zap.Error(err))
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.WithContext(ctx).Error("Error when committing reservation transaction", zap.Error(err))
+		return 0, err
+	}
+	return int(id), nil
+}
+
+func (r *reservationRepository) GetReservationByOrderID(ctx context.Context, orderID string) (model.Reservation, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + reservationColumns + ` FROM reservation WHERE order_id = ?`
+
+	reservation, err := scanReservation(r.DB.QueryRowContext(ctx, query, orderID))
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when scanning reservation table", zap.Error(err))
+		return reservation, err
+	}
+	return reservation, nil
+}
+
+func (r *reservationRepository) GetReservedQuantityByEventAndEmail(ctx context.Context, eventID int, email string) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var quantity int
+	query := `SELECT COALESCE(SUM(quantity), 0) FROM reservation WHERE event_id = ? AND email = ? AND status IN (?, ?)`
+
+	err := r.DB.QueryRowContext(ctx, query, eventID, email, util.RESERVATION_STATUS_PENDING, util.RESERVATION_STATUS_CONFIRMED).Scan(&quantity)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when scanning reservation table", zap.Error(err))
+		return quantity, err
+	}
+	return quantity, nil
+}
+
+func (r *reservationRepository) UpdateReservationStatusByOrderID(ctx context.Context, orderID, status string) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `UPDATE reservation SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE order_id = ?`
+	_, err := r.DB.ExecContext(ctx, query, status, orderID)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when updating reservation table", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *reservationRepository) GetReservationsByEventAndStatus(ctx context.Context, eventID int, status string) ([]model.Reservation, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + reservationColumns + ` FROM reservation WHERE event_id = ? AND status = ? ORDER BY reservation_id`
+	return r.queryReservations(ctx, query, eventID, status)
+}
+
+// GetExpiredReservations returns the pending reservations whose hold ran out
+// before now, oldest first.
+func (r *reservationRepository) GetExpiredReservations(ctx context.Context, now time.Time) ([]model.Reservation, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + reservationColumns + ` FROM reservation WHERE status = ? AND expires_at < ? ORDER BY expires_at, reservation_id`
+	return r.queryReservations(ctx, query, util.RESERVATION_STATUS_PENDING, now)
+}
+
+func (r *reservationRepository) queryReservations(ctx context.Context, query string, args ...interface{}) ([]model.Reservation, error) {
+	var reservations []model.Reservation
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when querying reservation table", zap.Error(err))
+		return reservations, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		reservation, err := scanReservation(rows)
+		if err != nil {
+			r.logger.WithContext(ctx).Error("Error when scanning reservation table", zap.Error(err))
+			return reservations, err
+		}
+		reservations = append(reservations, reservation)
+	}
+	return reservations, nil
+}
+
+// UpdateReservationStatus moves a reservation from status from to to. It
+// returns sql.ErrNoRows when the reservation is no longer in from, e.g. because
+// its order was settled in the meantime.
+func (r *reservationRepository) UpdateReservationStatus(ctx context.Context, reservationID int, from, to string) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `UPDATE reservation SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE reservation_id = ? AND status = ?`
+	result, err := r.DB.ExecContext(ctx, query, to, reservationID, from)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when updating reservation table", zap.Error(err))
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when getting affected rows of reservation table", zap.Error(err))
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	mock_config "github.com/SyamSolution/ticket-management-service/mock/config"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateReservation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO reservation \\(order_id, ticket_detail_id, event_id, email, quantity, unit_price, currency, price_tier,\\s+promo_code, discount, status, expires_at\\)").
+		WithArgs("order-1", 1, 2, "user@mail.com", 3, int64(150000), "IDR", "early-bird", "", int64(0), "pending", nil).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+	repo := NewReservationRepository(db, logger, 0)
+
+	id, err := repo.CreateReservation(context.Background(), model.Reservation{
+		OrderID:   "order-1",
+		TicketID:  1,
+		EventID:   2,
+		Email:     "user@mail.com",
+		Quantity:  3,
+		UnitPrice: model.Money{Amount: 150000, Currency: "IDR"},
+		PriceTier: "early-bird",
+		Status:    "pending",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 7, id)
+}
+
+func TestCreateReservationWithinLimit(t *testing.T) {
+	reservation := model.Reservation{OrderID: "order-1", TicketID: 1, EventID: 2, Email: "user@mail.com", Quantity: 3,
+		UnitPrice: model.Money{Amount: 150000, Currency: "IDR"}, Status: "pending"}
+	lockQuery := regexp.QuoteMeta("INSERT INTO reservation_buyer (event_id, email) VALUES (?, ?) ON DUPLICATE KEY UPDATE email = email")
+	sumQuery := regexp.QuoteMeta("SELECT COALESCE(SUM(quantity), 0) FROM reservation WHERE event_id = ? AND email = ? AND status IN (?, ?)")
+
+	setup := func(t *testing.T) (*sql.DB, sqlmock.Sqlmock, ReservationPersister) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		ctrl := gomock.NewController(t)
+		return db, mock, NewReservationRepository(db, mock_config.NewMockLogger(ctrl), 0)
+	}
+
+	t.Run("inserts the reservation while the buyer is locked", func(t *testing.T) {
+		db, mock, repo := setup(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(lockQuery).WithArgs(2, "user@mail.com").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(sumQuery).
+			WithArgs(2, "user@mail.com", "pending", "confirmed").
+			WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(1))
+		mock.ExpectExec("INSERT INTO reservation \\(order_id").
+			WithArgs("order-1", 1, 2, "user@mail.com", 3, int64(150000), "IDR", "", "", int64(0), "pending", nil).
+			WillReturnResult(sqlmock.NewResult(7, 1))
+		mock.ExpectCommit()
+
+		id, err := repo.CreateReservationWithinLimit(context.Background(), reservation, 4)
+		assert.NoError(t, err)
+		assert.Equal(t, 7, id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("reports an order that already has a reservation", func(t *testing.T) {
+		db, mock, repo := setup(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(lockQuery).WithArgs(2, "user@mail.com").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(sumQuery).
+			WithArgs(2, "user@mail.com", "pending", "confirmed").
+			WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(1))
+		mock.ExpectExec("INSERT INTO reservation \\(order_id").
+			WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'order-1' for key 'uq_reservation_order_id'"})
+		mock.ExpectRollback()
+
+		_, err := repo.CreateReservationWithinLimit(context.Background(), reservation, 4)
+		assert.ErrorIs(t, err, ErrDuplicateOrder)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rejects a reservation past the limit", func(t *testing.T) {
+		db, mock, repo := setup(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(lockQuery).WithArgs(2, "user@mail.com").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(sumQuery).
+			WithArgs(2, "user@mail.com", "pending", "confirmed").
+			WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(2))
+		mock.ExpectRollback()
+
+		_, err := repo.CreateReservationWithinLimit(context.Background(), reservation, 4)
+		assert.ErrorIs(t, err, ErrPurchaseLimit)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetReservedQuantityByEventAndEmail(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"quantity"}).AddRow(5)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(SUM(quantity), 0) FROM reservation WHERE event_id = ? AND email = ? AND status IN (?, ?)")).
+		WithArgs(2, "user@mail.com", "pending", "confirmed").
+		WillReturnRows(rows)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+	repo := NewReservationRepository(db, logger, 0)
+
+	quantity, err := repo.GetReservedQuantityByEventAndEmail(context.Background(), 2, "user@mail.com")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, quantity)
+}
+
+func TestUpdateReservationStatusByOrderID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE reservation SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE order_id = ?")).
+		WithArgs("confirmed", "order-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+	repo := NewReservationRepository(db, logger, 0)
+
+	err = repo.UpdateReservationStatusByOrderID(context.Background(), "order-1", "confirmed")
+	assert.NoError(t, err)
+}
+
+func TestGetReservationByOrderID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"reservation_id", "order_id", "ticket_detail_id", "event_id", "email", "quantity", "unit_price", "currency", "price_tier", "promo_code", "discount", "status", "expires_at", "created_at", "updated_at"}).
+		AddRow(1, "order-1", 2, 3, "user@mail.com", 4, 150000, "IDR", "", "EARLY10", 60000, "pending", nil, time.Now(), time.Now())
+
+	mock.ExpectQuery("^SELECT (.+) FROM reservation WHERE order_id = \\?$").
+		WithArgs("order-1").
+		WillReturnRows(rows)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+	repo := NewReservationRepository(db, logger, 0)
+
+	reservation, err := repo.GetReservationByOrderID(context.Background(), "order-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, reservation.TicketID)
+	assert.Equal(t, "pending", reservation.Status)
+	assert.Equal(t, model.Money{Amount: 150000, Currency: "IDR"}, reservation.UnitPrice)
+	assert.Equal(t, model.Money{Amount: 60000, Currency: "IDR"}, reservation.Discount)
+}
+
+func TestGetReservationsByEventAndStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"reservation_id", "order_id", "ticket_detail_id", "event_id", "email", "quantity", "unit_price", "currency", "price_tier", "promo_code", "discount", "status", "expires_at", "created_at", "updated_at"}).
+		AddRow(1, "order-1", 2, 3, "user@mail.com", 4, 150000, "IDR", "", "", 0, "confirmed", nil, time.Now(), time.Now()).
+		AddRow(5, "", 2, 3, "other@mail.com", 1, 150000, "IDR", "", "", 0, "confirmed", nil, time.Now(), time.Now())
+
+	mock.ExpectQuery("^SELECT (.+) FROM reservation WHERE event_id = \\? AND status = \\? ORDER BY reservation_id$").
+		WithArgs(3, "confirmed").
+		WillReturnRows(rows)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewReservationRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+	reservations, err := repo.GetReservationsByEventAndStatus(context.Background(), 3, "confirmed")
+	assert.NoError(t, err)
+	assert.Len(t, reservations, 2)
+	assert.Equal(t, "order-1", reservations[0].OrderID)
+	assert.Equal(t, 5, reservations[1].ReservationID)
+}
+
+func TestGetExpiredReservations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(-time.Minute)
+	rows := sqlmock.NewRows([]string{"reservation_id", "order_id", "ticket_detail_id", "event_id", "email", "quantity", "unit_price", "currency", "price_tier", "promo_code", "discount", "status", "expires_at", "created_at", "updated_at"}).
+		AddRow(1, "order-1", 2, 3, "user@mail.com", 4, 150000, "IDR", "", "", 0, "pending", expiresAt, time.Now(), time.Now())
+
+	mock.ExpectQuery("^SELECT (.+) FROM reservation WHERE status = \\? AND expires_at < \\? ORDER BY expires_at, reservation_id$").
+		WithArgs("pending", now).
+		WillReturnRows(rows)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewReservationRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+	reservations, err := repo.GetExpiredReservations(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Len(t, reservations, 1)
+	assert.Equal(t, "order-1", reservations[0].OrderID)
+	assert.Equal(t, expiresAt, *reservations[0].ExpiresAt)
+}
+
+func TestUpdateReservationStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE reservation SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE reservation_id = ? AND status = ?")).
+		WithArgs("refund_pending", 1, "confirmed").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewReservationRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+	err = repo.UpdateReservationStatus(context.Background(), 1, "confirmed", "refund_pending")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"go.uber.org/zap"
+)
+
+type seatRepository struct {
+	DB           *sql.DB
+	logger       config.Logger
+	queryTimeout time.Duration
+}
+
+type SeatPersister interface {
+	GetEventSeats(ctx context.Context, eventID int) ([]model.EventSeat, error)
+	GetEventSeatsByIDs(ctx context.Context, eventID int, seatIDs []int) ([]model.EventSeat, error)
+	HoldSeats(ctx context.Context, eventID int, seatIDs []int, orderID, email string, expiresAt time.Time) error
+	SellSeatsByOrderID(ctx context.Context, orderID string) error
+	ReleaseSeatsByOrderID(ctx context.Context, orderID string) error
+	ReleaseSeats(ctx context.Context, eventID int, seatIDs []int, orderID string) error
+	GetExpiredSeatHolds(ctx context.Context, now time.Time) ([]model.SeatHold, error)
+	ClaimExpiredSeatHold(ctx context.Context, hold model.SeatHold, now time.Time) (bool, error)
+}
+
+func NewSeatRepository(DB *sql.DB, logger config.Logger, queryTimeout time.Duration) SeatPersister {
+	return &seatRepository{DB: DB, logger: logger, queryTimeout: queryTimeout}
+}
+
+const eventSeatQuery = `SELECT es.seat_id, vs.section_id, sec.name, vs.row_label, vs.seat_number, es.ticket_detail_id, es.status,
+		COALESCE(es.order_id, ''), es.hold_expires_at
+		FROM event_seat es
+		JOIN venue_seat vs ON es.seat_id = vs.seat_id
+		JOIN venue_section sec ON vs.section_id = sec.section_id`
+
+func (r *seatRepository) GetEventSeats(ctx context.Context, eventID int) ([]model.EventSeat, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := eventSeatQuery + ` WHERE es.event_id = ? ORDER BY sec.section_id, vs.row_label, vs.seat_number`
+
+	return r.queryEventSeats(ctx, query, eventID)
+}
+
+func (r *seatRepository) GetEventSeatsByIDs(ctx context.Context, eventID int, seatIDs []int) ([]model.EventSeat, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	placeholders, args := inClause(seatIDs)
+	query := eventSeatQuery + ` WHERE es.event_id = ? AND es.seat_id IN (` + placeholders + `) ORDER BY es.seat_id`
+
+	return r.queryEventSeats(ctx, query, append([]interface{}{eventID}, args...)...)
+}
+
+func (r *seatRepository) queryEventSeats(ctx context.Context, query string, args ...interface{}) ([]model.EventSeat, error) {
+	var seats []model.EventSeat
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when querying event_seat table", zap.Error(err))
+		return seats, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			seat      model.EventSeat
+			expiresAt sql.NullTime
+		)
+		err := rows.Scan(&seat.SeatID, &seat.SectionID, &seat.SectionName, &seat.Row, &seat.Number, &seat.TicketID, &seat.Status,
+			&seat.OrderID, &expiresAt)
+		if err != nil {
+			r.logger.WithContext(ctx).Error("Error when scanning event_seat table", zap.Error(err))
+			return seats, err
+		}
+		if expiresAt.Valid {
+			seat.HoldExpiresAt = &expiresAt.Time
+		}
+		seats = append(seats, seat)
+	}
+	return seats, nil
+}
+
+// HoldSeats marks every seat in seatIDs as held by orderID until expiresAt, or
+// none of them. The seats are locked in seat_id order for the duration of the
+// transaction so two orders racing for the same seat serialize; the loser gets
+// sql.ErrNoRows, as it does when any seat is missing or no longer available.
+func (r *seatRepository) HoldSeats(ctx context.Context, eventID int, seatIDs []int, orderID, email string, expiresAt time.Time) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when starting event_seat transaction", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	placeholders, args := inClause(seatIDs)
+	query := `SELECT seat_id, status FROM event_seat WHERE event_id = ? AND seat_id IN (` + placeholders + `) ORDER BY seat_id FOR UPDATE`
+
+	rows, err := tx.QueryContext(ctx, query, append([]interface{}{eventID}, args...)...)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when locking event_seat table", zap.Error(err))
+		return err
+	}
+
+	available := 0
+	for rows.Next() {
+		var (
+			seatID int
+			status string
+		)
+		if err := rows.Scan(&seatID, &status); err != nil {
+			rows.Close()
+			r.logger.WithContext(ctx).Error("Error when scanning event_seat table", zap.Error(err))
+			return err
+		}
+		if status == util.SEAT_STATUS_AVAILABLE {
+			available++
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		r.logger.WithContext(ctx).Error("Error when locking event_seat table", zap.Error(err))
+		return err
+	}
+	if available != len(seatIDs) {
+		return sql.ErrNoRows
+	}
+
+	query = `UPDATE event_seat SET status = ?, order_id = ?, email = ?, hold_expires_at = ?
+		WHERE event_id = ? AND seat_id IN (` + placeholders + `)`
+	_, err = tx.ExecContext(ctx, query, append([]interface{}{util.SEAT_STATUS_HELD, orderID, email, expiresAt, eventID}, args...)...)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when updating event_seat table", zap.Error(err))
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.WithContext(ctx).Error("Error when committing event_seat transaction", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *seatRepository) SellSeatsByOrderID(ctx context.Context, orderID string) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `UPDATE event_seat SET status = ?, hold_expires_at = NULL WHERE order_id = ? AND status = ?`
+	_, err := r.DB.ExecContext(ctx, query, util.SEAT_STATUS_SOLD, orderID, util.SEAT_STATUS_HELD)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when updating event_seat table", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *seatRepository) ReleaseSeatsByOrderID(ctx context.Context, orderID string) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `UPDATE event_seat SET status = ?, order_id = NULL, email = NULL, hold_expires_at = NULL WHERE order_id = ? AND status = ?`
+	_, err := r.DB.ExecContext(ctx, query, util.SEAT_STATUS_AVAILABLE, orderID, util.SEAT_STATUS_HELD)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when updating event_seat table", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// ReleaseSeats returns the given seats to sale if they are still held by orderID.
+func (r *seatRepository) ReleaseSeats(ctx context.Context, eventID int, seatIDs []int, orderID string) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	placeholders, args := inClause(seatIDs)
+	query := `UPDATE event_seat SET status = ?, order_id = NULL, email = NULL, hold_expires_at = NULL
+		WHERE event_id = ? AND seat_id IN (` + placeholders + `) AND order_id = ? AND status = ?`
+	args = append([]interface{}{util.SEAT_STATUS_AVAILABLE, eventID}, args...)
+	_, err := r.DB.ExecContext(ctx, query, append(args, orderID, util.SEAT_STATUS_HELD)...)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when updating event_seat table", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// GetExpiredSeatHolds lists the orders whose seats are held past now. The
+// list is read without locks; each hold is claimed by ClaimExpiredSeatHold.
+func (r *seatRepository) GetExpiredSeatHolds(ctx context.Context, now time.Time) ([]model.SeatHold, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var holds []model.SeatHold
+	query := `SELECT order_id, ticket_detail_id, COUNT(*) FROM event_seat
+		WHERE status = ? AND hold_expires_at < ? GROUP BY order_id, ticket_detail_id`
+
+	rows, err := r.DB.QueryContext(ctx, query, util.SEAT_STATUS_HELD, now)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when querying event_seat table", zap.Error(err))
+		return holds, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hold model.SeatHold
+		if err := rows.Scan(&hold.OrderID, &hold.TicketID, &hold.Quantity); err != nil {
+			r.logger.WithContext(ctx).Error("Error when scanning event_seat table", zap.Error(err))
+			return holds, err
+		}
+		holds = append(holds, hold)
+	}
+	return holds, nil
+}
+
+// ClaimExpiredSeatHold returns the seats of hold that are still held past now
+// to sale and releases the stock and reservation of their order, in one
+// transaction. Only the seats the update claims are released, so of two
+// sweeps racing for the same hold the loser claims nothing and gets false. It
+// returns ErrOrderSettled and leaves the seats alone when the order no longer
+// holds the stock, e.g. because it was paid meanwhile.
+func (r *seatRepository) ClaimExpiredSeatHold(ctx context.Context, hold model.SeatHold, now time.Time) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when starting event_seat transaction", zap.Error(err))
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE event_seat SET status = ?, order_id = NULL, email = NULL, hold_expires_at = NULL
+		WHERE order_id = ? AND ticket_detail_id = ? AND status = ? AND hold_expires_at < ?`
+	result, err := tx.ExecContext(ctx, query, util.SEAT_STATUS_AVAILABLE, hold.OrderID, hold.TicketID, util.SEAT_STATUS_HELD, now)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when updating event_seat table", zap.Error(err))
+		return false, err
+	}
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when getting affected rows of event_seat table", zap.Error(err))
+		return false, err
+	}
+	if claimed == 0 {
+		return false, nil
+	}
+
+	quantity := int(claimed)
+	if err := claimOrder(ctx, tx, hold.TicketID, quantity, hold.OrderID, util.RESERVATION_STATUS_RELEASED); err != nil {
+		if !errors.Is(err, ErrOrderSettled) {
+			r.logger.WithContext(ctx).Error("Error when settling reservation of order", zap.Error(err))
+		}
+		return false, err
+	}
+
+	err = moveStock(ctx, tx, model.InventoryLedgerEntry{TicketID: hold.TicketID, Change: model.ReleaseStock(quantity),
+		Reason: util.LEDGER_REASON_RELEASE, OrderID: hold.OrderID})
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			r.logger.WithContext(ctx).Error("Error when updating ticket_detail stock", zap.Error(err))
+		}
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.WithContext(ctx).Error("Error when committing event_seat transaction", zap.Error(err))
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	mock_config "github.com/SyamSolution/ticket-management-service/mock/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var eventSeatColumns = []string{"seat_id", "section_id", "name", "row_label", "seat_number", "ticket_detail_id", "status", "order_id", "hold_expires_at"}
+
+func TestGetEventSeats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	expiresAt := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows(eventSeatColumns).
+		AddRow(1, 1, "Floor", "A", 1, 3, "available", "", nil).
+		AddRow(2, 1, "Floor", "A", 2, 3, "held", "order-1", expiresAt)
+
+	mock.ExpectQuery("FROM event_seat es .* WHERE es.event_id = \\? ORDER BY sec.section_id, vs.row_label, vs.seat_number").
+		WithArgs(9).
+		WillReturnRows(rows)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+	repo := NewSeatRepository(db, logger, 0)
+
+	seats, err := repo.GetEventSeats(context.Background(), 9)
+	assert.NoError(t, err)
+	assert.Len(t, seats, 2)
+	assert.Nil(t, seats[0].HoldExpiresAt)
+	assert.Equal(t, "order-1", seats[1].OrderID)
+	assert.Equal(t, expiresAt, *seats[1].HoldExpiresAt)
+}
+
+func TestHoldSeats(t *testing.T) {
+	expiresAt := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	lockQuery := "^SELECT seat_id, status FROM event_seat WHERE event_id = \\? AND seat_id IN \\(\\?, \\?\\) ORDER BY seat_id FOR UPDATE$"
+
+	t.Run("holds every seat", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).
+			WithArgs(9, 1, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"seat_id", "status"}).AddRow(1, "available").AddRow(2, "available"))
+		mock.ExpectExec("UPDATE event_seat SET status = \\?, order_id = \\?, email = \\?, hold_expires_at = \\?").
+			WithArgs("held", "order-1", "user@mail.com", expiresAt, 9, 1, 2).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		logger := mock_config.NewMockLogger(ctrl)
+		repo := NewSeatRepository(db, logger, 0)
+
+		err = repo.HoldSeats(context.Background(), 9, []int{1, 2}, "order-1", "user@mail.com", expiresAt)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rolls back when a seat is taken", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).
+			WithArgs(9, 1, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"seat_id", "status"}).AddRow(1, "available").AddRow(2, "sold"))
+		mock.ExpectRollback()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		logger := mock_config.NewMockLogger(ctrl)
+		repo := NewSeatRepository(db, logger, 0)
+
+		err = repo.HoldSeats(context.Background(), 9, []int{1, 2}, "order-1", "user@mail.com", expiresAt)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReleaseSeats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE event_seat SET status = \\?, order_id = NULL, email = NULL, hold_expires_at = NULL\\s+WHERE event_id = \\? AND seat_id IN \\(\\?\\) AND order_id = \\? AND status = \\?").
+		WithArgs("available", 9, 4, "order-1", "held").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+	repo := NewSeatRepository(db, logger, 0)
+
+	err = repo.ReleaseSeats(context.Background(), 9, []int{4}, "order-1")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetExpiredSeatHolds(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT order_id, ticket_detail_id, COUNT\\(\\*\\) FROM event_seat").
+		WithArgs("held", now).
+		WillReturnError(sql.ErrConnDone)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+	logger.EXPECT().WithContext(gomock.Any()).Return(logger)
+	logger.EXPECT().Error(gomock.Any(), gomock.Any())
+	repo := NewSeatRepository(db, logger, 0)
+
+	_, err = repo.GetExpiredSeatHolds(context.Background(), now)
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+}
+
+func TestClaimExpiredSeatHold(t *testing.T) {
+	now := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	hold := model.SeatHold{OrderID: "order-1", TicketID: 3, Quantity: 2}
+	seatQuery := regexp.QuoteMeta("UPDATE event_seat SET status = ?, order_id = NULL, email = NULL, hold_expires_at = NULL")
+
+	setup := func(t *testing.T) (*sql.DB, sqlmock.Sqlmock, SeatPersister) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		ctrl := gomock.NewController(t)
+		return db, mock, NewSeatRepository(db, mock_config.NewMockLogger(ctrl), 0)
+	}
+
+	t.Run("releases the stock of the seats it claims", func(t *testing.T) {
+		db, mock, repo := setup(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(seatQuery).
+			WithArgs("available", "order-1", 3, "held", now).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(claimQuery).
+			WithArgs("released", "order-1", 3, 2, "pending", "resale").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(stockQuery).
+			WithArgs(0, 2, -2, 0, 3, 2, -2, 0).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(ledgerQuery).
+			WithArgs(3, 0, 2, -2, 0, "release", "order-1", nil, nil, "system").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		claimed, err := repo.ClaimExpiredSeatHold(context.Background(), hold, now)
+		assert.NoError(t, err)
+		assert.True(t, claimed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("releases nothing when another sweep claimed the hold", func(t *testing.T) {
+		db, mock, repo := setup(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(seatQuery).
+			WithArgs("available", "order-1", 3, "held", now).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		claimed, err := repo.ClaimExpiredSeatHold(context.Background(), hold, now)
+		assert.NoError(t, err)
+		assert.False(t, claimed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("leaves the seats of an order settled meanwhile", func(t *testing.T) {
+		db, mock, repo := setup(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(seatQuery).
+			WithArgs("available", "order-1", 3, "held", now).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(claimQuery).
+			WithArgs("released", "order-1", 3, 2, "pending", "resale").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM reservation WHERE order_id = ?")).
+			WithArgs("order-1").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectRollback()
+
+		_, err := repo.ClaimExpiredSeatHold(context.Background(), hold, now)
+		assert.ErrorIs(t, err, ErrOrderSettled)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -1,52 +1,95 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"time"
+
 	"github.com/SyamSolution/ticket-management-service/config"
 	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
 	"go.uber.org/zap"
 )
 
+// ErrOrderSettled is returned when an order holds no stock to confirm or
+// release, e.g. because a message about it was delivered twice or the order
+// was settled another way meanwhile.
+var ErrOrderSettled = errors.New("order holds no stock to settle")
+
 type ticketRepository struct {
-	DB     *sql.DB
-	logger config.Logger
+	DB           *sql.DB
+	logger       config.Logger
+	queryTimeout time.Duration
 }
 
 type TicketPersister interface {
-	GetAvailableTicketByContinent(continent string) ([]model.Ticket, error)
-	GetAvailableTicketByType(ticketType string) ([]model.Ticket, error)
-	GetTicketByID(ticketID int) (model.Ticket, error)
-	GetTicketByContinent(continent string) ([]model.Ticket, error)
-	UpdateStockCreateOrderTicket(ticketID, order int) error
-	UpdateStockSuccessOrderTicket(ticketID, order int) error
-	UpdateStockFailOrderTicket(ticketID, order int) error
-	GetStockTicketGroupByContinent() ([]model.StockTicket, error)
-	GetTicketEventByTicketID(ticketID int) (model.TicketEvent, error)
+	GetAvailableTicketByContinent(ctx context.Context, continent string) ([]model.Ticket, error)
+	GetAvailableTicketByType(ctx context.Context, ticketType string) ([]model.Ticket, error)
+	GetTicketByID(ctx context.Context, ticketID int) (model.Ticket, error)
+	GetTicketByContinent(ctx context.Context, continent string) ([]model.Ticket, error)
+	UpdateStockCreateOrderTicket(ctx context.Context, ticketID, order int, orderID string) error
+	UpdateStockSuccessOrderTicket(ctx context.Context, ticketID, order int, orderID string) error
+	UpdateStockFailOrderTicket(ctx context.Context, ticketID, order int, orderID string) error
+	UpdateStockFailReservation(ctx context.Context, reservationID, ticketID, quantity int) error
+	GetStockTicketGroupByContinent(ctx context.Context) ([]model.StockTicket, error)
+	GetTicketEventByTicketID(ctx context.Context, ticketID int) (model.TicketEvent, error)
+	GetTicketPurchaseLimit(ctx context.Context, ticketID int) (model.TicketPurchaseLimit, error)
+	GetTicketInventory(ctx context.Context) ([]model.Ticket, error)
+	GetTicketPricesByRegion(ctx context.Context, ticketIDs []int, region string) (map[int]model.Money, error)
+	GetTicketPriceSchedules(ctx context.Context, ticketIDs []int) (map[int][]model.PriceSchedule, error)
 }
 
-func NewTicketRepository(DB *sql.DB, logger config.Logger) TicketPersister {
-	return &ticketRepository{DB: DB, logger: logger}
+// ticketColumns selects a full model.Ticket from ticketTables. The continent,
+// country, city and place are read from the geography tables; a ticket the
+// geography migration could not link falls back to the text it was stored
+// with, and reads it as empty when unset, as it does its other unset columns.
+const ticketColumns = `td.ticket_detail_id, COALESCE(td.type, ''), td.price, td.currency, COALESCE(c.name, td.continent_name, ''),
+	td.total, td.available, td.held, td.sold, COALESCE(co.name, td.country_name, ''), COALESCE(ci.name, td.country_city, ''),
+	COALESCE(v.name, td.country_place, ''), td.created_at, td.updated_at`
+
+// ticketTables joins ticket_detail, as td, to its continent and venue.
+const ticketTables = `ticket_detail td
+	LEFT JOIN continent c ON c.continent_id = td.continent_id
+	LEFT JOIN venue v ON v.venue_id = td.venue_id
+	LEFT JOIN city ci ON ci.city_id = v.city_id
+	LEFT JOIN country co ON co.country_id = ci.country_id`
+
+// continentByCodeOrName resolves a continent given either its code or its name,
+// in any letter case, so "asia", "Asia" and "AS" select the same tickets.
+const continentByCodeOrName = `SELECT continent_id FROM continent WHERE UPPER(code) = UPPER(?) OR UPPER(name) = UPPER(?)`
+
+// ticketOnSale keeps the tickets that can be bought right now: those of events
+// that are on sale and inside their sale window, and those without an event.
+const ticketOnSale = `(td.event_id IS NULL OR td.event_id IN (SELECT event_id FROM event WHERE status = '` + util.EVENT_STATUS_ON_SALE + `'
+	AND (sale_starts_at IS NULL OR sale_starts_at <= CURRENT_TIMESTAMP)
+	AND (sale_ends_at IS NULL OR sale_ends_at > CURRENT_TIMESTAMP)))`
+
+func NewTicketRepository(DB *sql.DB, logger config.Logger, queryTimeout time.Duration) TicketPersister {
+	return &ticketRepository{DB: DB, logger: logger, queryTimeout: queryTimeout}
 }
 
-func (r *ticketRepository) GetAvailableTicketByContinent(continent string) ([]model.Ticket, error) {
+func (r *ticketRepository) GetAvailableTicketByContinent(ctx context.Context, continent string) ([]model.Ticket, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	var tickets []model.Ticket
-	query := `SELECT ticket_detail_id, type, price, continent_name, stock_ticket, stock, stock_ordered, country_name, 
-       	country_city, country_place, created_at, updated_at 
-		FROM ticket_detail WHERE continent_name = ? AND stock > 0`
+	query := `SELECT ` + ticketColumns + ` FROM ` + ticketTables + ` WHERE td.continent_id IN (` + continentByCodeOrName + `) AND td.available > 0
+		AND ` + ticketOnSale
 
-	rows, err := r.DB.Query(query, continent)
+	rows, err := r.DB.QueryContext(ctx, query, continent, continent)
 	if err != nil {
-		r.logger.Error("Error when querying ticket_detail table", zap.Error(err))
+		r.logger.WithContext(ctx).Error("Error when querying ticket_detail table", zap.Error(err))
 		return tickets, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
 		var ticket model.Ticket
-		err := rows.Scan(&ticket.TicketID, &ticket.Type, &ticket.Price, &ticket.ContinentName, &ticket.StockTicket, &ticket.Stock,
-			&ticket.StockOrdered, &ticket.CountryName, &ticket.CountryCity, &ticket.CountryPlace, &ticket.CreatedAt, &ticket.UpdatedAt)
+		err := rows.Scan(&ticket.TicketID, &ticket.Type, &ticket.Price.Amount, &ticket.Price.Currency, &ticket.ContinentName, &ticket.Total, &ticket.Available,
+			&ticket.Held, &ticket.Sold, &ticket.CountryName, &ticket.CountryCity, &ticket.CountryPlace, &ticket.CreatedAt, &ticket.UpdatedAt)
 		if err != nil {
-			r.logger.Error("Error when scanning ticket_detail table", zap.Error(err))
+			r.logger.WithContext(ctx).Error("Error when scanning ticket_detail table", zap.Error(err))
 			return tickets, err
 		}
 		tickets = append(tickets, ticket)
@@ -54,25 +97,26 @@ func (r *ticketRepository) GetAvailableTicketByContinent(continent string) ([]mo
 	return tickets, nil
 }
 
-func (r *ticketRepository) GetAvailableTicketByType(ticketType string) ([]model.Ticket, error) {
+func (r *ticketRepository) GetAvailableTicketByType(ctx context.Context, ticketType string) ([]model.Ticket, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	var tickets []model.Ticket
-	query := `SELECT ticket_detail_id, type, price, continent_name, stock_ticket, stock, stock_ordered, country_name, 
-	   	country_city, country_place, created_at, updated_at 
-		FROM ticket_detail WHERE type = ? AND stock > 0`
+	query := `SELECT ` + ticketColumns + ` FROM ` + ticketTables + ` WHERE td.type = ? AND td.available > 0 AND ` + ticketOnSale
 
-	rows, err := r.DB.Query(query, ticketType)
+	rows, err := r.DB.QueryContext(ctx, query, ticketType)
 	if err != nil {
-		r.logger.Error("Error when querying ticket_detail table", zap.Error(err))
+		r.logger.WithContext(ctx).Error("Error when querying ticket_detail table", zap.Error(err))
 		return tickets, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
 		var ticket model.Ticket
-		err := rows.Scan(&ticket.TicketID, &ticket.Type, &ticket.Price, &ticket.ContinentName, &ticket.StockTicket, &ticket.Stock,
-			&ticket.StockOrdered, &ticket.CountryName, &ticket.CountryCity, &ticket.CountryPlace, &ticket.CreatedAt, &ticket.UpdatedAt)
+		err := rows.Scan(&ticket.TicketID, &ticket.Type, &ticket.Price.Amount, &ticket.Price.Currency, &ticket.ContinentName, &ticket.Total, &ticket.Available,
+			&ticket.Held, &ticket.Sold, &ticket.CountryName, &ticket.CountryCity, &ticket.CountryPlace, &ticket.CreatedAt, &ticket.UpdatedAt)
 		if err != nil {
-			r.logger.Error("Error when scanning ticket_detail table", zap.Error(err))
+			r.logger.WithContext(ctx).Error("Error when scanning ticket_detail table", zap.Error(err))
 			return tickets, err
 		}
 		tickets = append(tickets, ticket)
@@ -80,40 +124,42 @@ func (r *ticketRepository) GetAvailableTicketByType(ticketType string) ([]model.
 	return tickets, nil
 }
 
-func (r *ticketRepository) GetTicketByID(ticketID int) (model.Ticket, error) {
+func (r *ticketRepository) GetTicketByID(ctx context.Context, ticketID int) (model.Ticket, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	var ticket model.Ticket
-	query := `SELECT ticket_detail_id, type, price, continent_name, stock_ticket, stock, stock_ordered, country_name, 
-		country_city, country_place, created_at, updated_at 
-		FROM ticket_detail WHERE ticket_detail_id = ?`
+	query := `SELECT ` + ticketColumns + ` FROM ` + ticketTables + ` WHERE td.ticket_detail_id = ?`
 
-	err := r.DB.QueryRow(query, ticketID).Scan(&ticket.TicketID, &ticket.Type, &ticket.Price, &ticket.ContinentName, &ticket.StockTicket, &ticket.Stock,
-		&ticket.StockOrdered, &ticket.CountryName, &ticket.CountryCity, &ticket.CountryPlace, &ticket.CreatedAt, &ticket.UpdatedAt)
+	err := r.DB.QueryRowContext(ctx, query, ticketID).Scan(&ticket.TicketID, &ticket.Type, &ticket.Price.Amount, &ticket.Price.Currency, &ticket.ContinentName, &ticket.Total, &ticket.Available,
+		&ticket.Held, &ticket.Sold, &ticket.CountryName, &ticket.CountryCity, &ticket.CountryPlace, &ticket.CreatedAt, &ticket.UpdatedAt)
 	if err != nil {
-		r.logger.Error("Error when scanning ticket_detail table", zap.Error(err))
+		r.logger.WithContext(ctx).Error("Error when scanning ticket_detail table", zap.Error(err))
 		return ticket, err
 	}
 	return ticket, nil
 }
 
-func (r *ticketRepository) GetTicketByContinent(continent string) ([]model.Ticket, error) {
+func (r *ticketRepository) GetTicketByContinent(ctx context.Context, continent string) ([]model.Ticket, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	var tickets []model.Ticket
-	query := `SELECT ticket_detail_id, type, price, continent_name, stock_ticket, stock, stock_ordered, country_name, 
-		country_city, country_place, created_at, updated_at 
-		FROM ticket_detail WHERE continent_name = ?`
+	query := `SELECT ` + ticketColumns + ` FROM ` + ticketTables + ` WHERE td.continent_id IN (` + continentByCodeOrName + `)`
 
-	rows, err := r.DB.Query(query, continent)
+	rows, err := r.DB.QueryContext(ctx, query, continent, continent)
 	if err != nil {
-		r.logger.Error("Error when querying ticket_detail table", zap.Error(err))
+		r.logger.WithContext(ctx).Error("Error when querying ticket_detail table", zap.Error(err))
 		return tickets, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
 		var ticket model.Ticket
-		err := rows.Scan(&ticket.TicketID, &ticket.Type, &ticket.Price, &ticket.ContinentName, &ticket.StockTicket, &ticket.Stock,
-			&ticket.StockOrdered, &ticket.CountryName, &ticket.CountryCity, &ticket.CountryPlace, &ticket.CreatedAt, &ticket.UpdatedAt)
+		err := rows.Scan(&ticket.TicketID, &ticket.Type, &ticket.Price.Amount, &ticket.Price.Currency, &ticket.ContinentName, &ticket.Total, &ticket.Available,
+			&ticket.Held, &ticket.Sold, &ticket.CountryName, &ticket.CountryCity, &ticket.CountryPlace, &ticket.CreatedAt, &ticket.UpdatedAt)
 		if err != nil {
-			r.logger.Error("Error when scanning ticket_detail table", zap.Error(err))
+			r.logger.WithContext(ctx).Error("Error when scanning ticket_detail table", zap.Error(err))
 			return tickets, err
 		}
 		tickets = append(tickets, ticket)
@@ -121,43 +167,206 @@ func (r *ticketRepository) GetTicketByContinent(continent string) ([]model.Ticke
 	return tickets, nil
 }
 
-func (r *ticketRepository) UpdateStockCreateOrderTicket(ticketID, order int) error {
-	query := `UPDATE ticket_detail SET stock = stock - ?, stock_ordered = stock_ordered + ? WHERE ticket_detail_id = ?`
-	_, err := r.DB.Exec(query, order, order, ticketID)
+// UpdateStockCreateOrderTicket holds order of the available stock of the
+// ticket for orderID. It returns sql.ErrNoRows when the ticket does not exist
+// or has less than order available.
+func (r *ticketRepository) UpdateStockCreateOrderTicket(ctx context.Context, ticketID, order int, orderID string) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	return r.moveStock(ctx, model.InventoryLedgerEntry{TicketID: ticketID, Change: model.ReserveStock(order),
+		Reason: util.LEDGER_REASON_RESERVE, OrderID: orderID})
+}
+
+// UpdateStockSuccessOrderTicket sells order of the stock held for orderID and
+// confirms its reservation in one transaction. It returns ErrOrderSettled when
+// the order holds no such stock, and sql.ErrNoRows when the ticket does not
+// exist or holds less than order.
+func (r *ticketRepository) UpdateStockSuccessOrderTicket(ctx context.Context, ticketID, order int, orderID string) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	return r.settleOrder(ctx, model.InventoryLedgerEntry{TicketID: ticketID, Change: model.ConfirmStock(order),
+		Reason: util.LEDGER_REASON_CONFIRM, OrderID: orderID}, util.RESERVATION_STATUS_CONFIRMED)
+}
+
+// UpdateStockFailOrderTicket makes order of the stock held for orderID
+// available again and releases its reservation in one transaction. It returns
+// ErrOrderSettled when the order holds no such stock, and sql.ErrNoRows when
+// the ticket does not exist or holds less than order.
+func (r *ticketRepository) UpdateStockFailOrderTicket(ctx context.Context, ticketID, order int, orderID string) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	return r.settleOrder(ctx, model.InventoryLedgerEntry{TicketID: ticketID, Change: model.ReleaseStock(order),
+		Reason: util.LEDGER_REASON_RELEASE, OrderID: orderID}, util.RESERVATION_STATUS_RELEASED)
+}
+
+// UpdateStockFailReservation makes the quantity of the stock held by a
+// pending reservation placed without an order available again and releases
+// the reservation in one transaction. It returns ErrOrderSettled when the
+// reservation is no longer pending.
+func (r *ticketRepository) UpdateStockFailReservation(ctx context.Context, reservationID, ticketID, quantity int) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
-		r.logger.Error("Error when updating ticket_detail table", zap.Error(err))
+		r.logger.WithContext(ctx).Error("Error when starting ticket_detail transaction", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE reservation SET status = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE reservation_id = ? AND ticket_detail_id = ? AND quantity = ? AND status = ? AND COALESCE(price_tier, '') <> ?`
+	result, err := tx.ExecContext(ctx, query, util.RESERVATION_STATUS_RELEASED, reservationID, ticketID, quantity,
+		util.RESERVATION_STATUS_PENDING, util.RESALE_PRICE_TIER)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when updating reservation table", zap.Error(err))
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when getting affected rows of reservation table", zap.Error(err))
+		return err
+	}
+	if affected == 0 {
+		return ErrOrderSettled
+	}
+
+	err = moveStock(ctx, tx, model.InventoryLedgerEntry{TicketID: ticketID, Change: model.ReleaseStock(quantity),
+		Reason: util.LEDGER_REASON_RELEASE})
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			r.logger.WithContext(ctx).Error("Error when updating ticket_detail stock", zap.Error(err))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.WithContext(ctx).Error("Error when committing ticket_detail transaction", zap.Error(err))
 		return err
 	}
 	return nil
 }
 
-func (r *ticketRepository) UpdateStockSuccessOrderTicket(ticketID, order int) error {
-	query := `UPDATE ticket_detail SET stock_ticket = stock_ticket - ? WHERE ticket_detail_id = ?`
-	_, err := r.DB.Exec(query, order, ticketID)
+// settleOrder applies the change of entry to the stock held by its order and
+// moves the order's pending reservation to status, in one transaction, so of
+// two settlements of the same order the second changes nothing. Orders
+// without an id can not be told apart and only move stock.
+func (r *ticketRepository) settleOrder(ctx context.Context, entry model.InventoryLedgerEntry, status string) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
-		r.logger.Error("Error when updating ticket_detail table", zap.Error(err))
+		r.logger.WithContext(ctx).Error("Error when starting ticket_detail transaction", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	if entry.OrderID != "" {
+		if err := claimOrder(ctx, tx, entry.TicketID, -entry.Change.Held, entry.OrderID, status); err != nil {
+			if !errors.Is(err, ErrOrderSettled) {
+				r.logger.WithContext(ctx).Error("Error when settling reservation of order", zap.Error(err))
+			}
+			return err
+		}
+	}
+
+	if err := moveStock(ctx, tx, entry); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			r.logger.WithContext(ctx).Error("Error when updating ticket_detail stock", zap.Error(err))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.WithContext(ctx).Error("Error when committing ticket_detail transaction", zap.Error(err))
 		return err
 	}
 	return nil
 }
 
-func (r *ticketRepository) UpdateStockFailOrderTicket(ticketID, order int) error {
-	query := `UPDATE ticket_detail SET stock = stock + ?, stock_ordered = stock_ordered - ? WHERE ticket_detail_id = ?`
-	_, err := r.DB.Exec(query, order, order, ticketID)
+// claimOrder moves the pending reservation of orderID for quantity of the
+// ticket to status. Resale reservations hold a listing, not stock, and are
+// never claimed. An order reserved without a reservation is claimed when the
+// inventory ledger shows it still holding quantity of the ticket. It returns
+// ErrOrderSettled when there is nothing to claim.
+func claimOrder(ctx context.Context, tx *sql.Tx, ticketID, quantity int, orderID, status string) error {
+	query := `UPDATE reservation SET status = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE order_id = ? AND ticket_detail_id = ? AND quantity = ? AND status = ? AND COALESCE(price_tier, '') <> ?`
+	result, err := tx.ExecContext(ctx, query, status, orderID, ticketID, quantity, util.RESERVATION_STATUS_PENDING,
+		util.RESALE_PRICE_TIER)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
 	if err != nil {
-		r.logger.Error("Error when updating ticket_detail table", zap.Error(err))
 		return err
 	}
+	if affected > 0 {
+		return nil
+	}
+
+	var reservations int
+	query = `SELECT COUNT(*) FROM reservation WHERE order_id = ?`
+	if err := tx.QueryRowContext(ctx, query, orderID).Scan(&reservations); err != nil {
+		return err
+	}
+	if reservations > 0 {
+		return ErrOrderSettled
+	}
+
+	var held int
+	query = `SELECT ticket_detail_id FROM ticket_detail WHERE ticket_detail_id = ? FOR UPDATE`
+	if err := tx.QueryRowContext(ctx, query, ticketID).Scan(&ticketID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrOrderSettled
+		}
+		return err
+	}
+	query = `SELECT COALESCE(SUM(held_delta), 0) FROM inventory_ledger WHERE ticket_detail_id = ? AND order_id = ?`
+	if err := tx.QueryRowContext(ctx, query, ticketID, orderID).Scan(&held); err != nil {
+		return err
+	}
+	if held < quantity {
+		return ErrOrderSettled
+	}
 	return nil
 }
 
-func (r *ticketRepository) GetStockTicketGroupByContinent() ([]model.StockTicket, error) {
+// moveStock applies the change of entry in a transaction of its own.
+func (r *ticketRepository) moveStock(ctx context.Context, entry model.InventoryLedgerEntry) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when starting ticket_detail transaction", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := moveStock(ctx, tx, entry); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			r.logger.WithContext(ctx).Error("Error when updating ticket_detail stock", zap.Error(err))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.WithContext(ctx).Error("Error when committing ticket_detail transaction", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *ticketRepository) GetStockTicketGroupByContinent(ctx context.Context) ([]model.StockTicket, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	var tickets []model.StockTicket
-	query := `SELECT continent_name, SUM(stock) as stock FROM ticket_detail GROUP BY continent_name`
+	query := `SELECT COALESCE(c.name, '` + util.UNKNOWN_CONTINENT + `') as continent_name, SUM(td.available) as stock FROM ticket_detail td
+		LEFT JOIN continent c ON td.continent_id = c.continent_id GROUP BY c.continent_id, c.name`
 
-	rows, err := r.DB.Query(query)
+	rows, err := r.DB.QueryContext(ctx, query)
 	if err != nil {
-		r.logger.Error("Error when querying ticket_detail table", zap.Error(err))
+		r.logger.WithContext(ctx).Error("Error when querying ticket_detail table", zap.Error(err))
 		return tickets, err
 	}
 	defer rows.Close()
@@ -166,7 +375,7 @@ func (r *ticketRepository) GetStockTicketGroupByContinent() ([]model.StockTicket
 		var ticket model.StockTicket
 		err := rows.Scan(&ticket.Continent, &ticket.Stock)
 		if err != nil {
-			r.logger.Error("Error when scanning ticket_detail table", zap.Error(err))
+			r.logger.WithContext(ctx).Error("Error when scanning ticket_detail table", zap.Error(err))
 			return tickets, err
 		}
 		tickets = append(tickets, ticket)
@@ -174,18 +383,176 @@ func (r *ticketRepository) GetStockTicketGroupByContinent() ([]model.StockTicket
 	return tickets, nil
 }
 
-func (r *ticketRepository) GetTicketEventByTicketID(ticketID int) (model.TicketEvent, error) {
-	var ticketEvent model.TicketEvent
-	query := `SELECT td.ticket_detail_id, td.type, td.price, td.stock, td.continent_name, td.country_city, td.country_place, e.event_name, e.date, e.description
-		from ticket_detail td
+func (r *ticketRepository) GetTicketEventByTicketID(ctx context.Context, ticketID int) (model.TicketEvent, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var (
+		ticketEvent              model.TicketEvent
+		date, saleStart, saleEnd sql.NullTime
+	)
+	query := `SELECT td.ticket_detail_id, COALESCE(td.type, ''), td.price, td.currency, td.available, COALESCE(c.name, td.continent_name, ''),
+		COALESCE(ci.name, td.country_city, ''), COALESCE(v.name, td.country_place, ''), COALESCE(e.event_name, ''), e.date, COALESCE(e.description, ''),
+		COALESCE(e.status, '` + util.EVENT_STATUS_ON_SALE + `'), e.sale_starts_at, e.sale_ends_at
+		from ` + ticketTables + `
 		left join event e on td.event_id = e.event_id
 		WHERE td.ticket_detail_id = ?`
 
-	err := r.DB.QueryRow(query, ticketID).Scan(&ticketEvent.TicketID, &ticketEvent.Type, &ticketEvent.Price, &ticketEvent.Stock,
-		&ticketEvent.Continent, &ticketEvent.CountryCity, &ticketEvent.CountryPlace, &ticketEvent.EventName, &ticketEvent.Date, &ticketEvent.Description)
+	err := r.DB.QueryRowContext(ctx, query, ticketID).Scan(&ticketEvent.TicketID, &ticketEvent.Type, &ticketEvent.Price.Amount, &ticketEvent.Price.Currency, &ticketEvent.Stock,
+		&ticketEvent.Continent, &ticketEvent.CountryCity, &ticketEvent.CountryPlace, &ticketEvent.EventName, &date, &ticketEvent.Description,
+		&ticketEvent.EventStatus, &saleStart, &saleEnd)
 	if err != nil {
-		r.logger.Error("Error when scanning ticket_event table", zap.Error(err))
+		r.logger.WithContext(ctx).Error("Error when scanning ticket_event table", zap.Error(err))
 		return ticketEvent, err
 	}
+	ticketEvent.Date = date.Time
+	ticketEvent.SaleStartsAt = nullTime(saleStart)
+	ticketEvent.SaleEndsAt = nullTime(saleEnd)
 	return ticketEvent, nil
 }
+
+func (r *ticketRepository) GetTicketPurchaseLimit(ctx context.Context, ticketID int) (model.TicketPurchaseLimit, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var (
+		limit              model.TicketPurchaseLimit
+		saleStart, saleEnd sql.NullTime
+	)
+	query := `SELECT td.ticket_detail_id, COALESCE(td.event_id, 0), COALESCE(e.max_ticket_per_user, 0),
+		COALESCE(e.status, '` + util.EVENT_STATUS_ON_SALE + `'), e.sale_starts_at, e.sale_ends_at
+		from ticket_detail td
+		left join event e on td.event_id = e.event_id
+		WHERE td.ticket_detail_id = ?`
+
+	err := r.DB.QueryRowContext(ctx, query, ticketID).Scan(&limit.TicketID, &limit.EventID, &limit.MaxTicketPerUser,
+		&limit.EventStatus, &saleStart, &saleEnd)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when scanning ticket purchase limit", zap.Error(err))
+		return limit, err
+	}
+	limit.SaleStartsAt = nullTime(saleStart)
+	limit.SaleEndsAt = nullTime(saleEnd)
+	return limit, nil
+}
+
+func (r *ticketRepository) GetTicketInventory(ctx context.Context) ([]model.Ticket, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var tickets []model.Ticket
+	query := `SELECT td.ticket_detail_id, COALESCE(td.type, ''), COALESCE(c.name, '` + util.UNKNOWN_CONTINENT + `'), td.total, td.available,
+		td.held, td.sold FROM ticket_detail td LEFT JOIN continent c ON c.continent_id = td.continent_id`
+
+	rows, err := r.DB.QueryContext(ctx, query)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when querying ticket_detail table", zap.Error(err))
+		return tickets, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ticket model.Ticket
+		err := rows.Scan(&ticket.TicketID, &ticket.Type, &ticket.ContinentName, &ticket.Total, &ticket.Available, &ticket.Held,
+			&ticket.Sold)
+		if err != nil {
+			r.logger.WithContext(ctx).Error("Error when scanning ticket_detail table", zap.Error(err))
+			return tickets, err
+		}
+		tickets = append(tickets, ticket)
+	}
+	return tickets, nil
+}
+
+// GetTicketPricesByRegion returns the price list entries of region, a country
+// code, for the given tickets. Tickets without an entry are left out.
+func (r *ticketRepository) GetTicketPricesByRegion(ctx context.Context, ticketIDs []int, region string) (map[int]model.Money, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	prices := make(map[int]model.Money)
+	if len(ticketIDs) == 0 {
+		return prices, nil
+	}
+
+	placeholders, args := inClause(ticketIDs)
+	query := `SELECT tp.ticket_detail_id, tp.amount, tp.currency
+		FROM ticket_price tp
+		JOIN country c ON tp.country_id = c.country_id
+		WHERE (c.code = ? OR c.code_alpha3 = ?) AND tp.ticket_detail_id IN (` + placeholders + `)`
+
+	rows, err := r.DB.QueryContext(ctx, query, append([]interface{}{region, region}, args...)...)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when querying ticket_price table", zap.Error(err))
+		return prices, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			ticketID int
+			price    model.Money
+		)
+		if err := rows.Scan(&ticketID, &price.Amount, &price.Currency); err != nil {
+			r.logger.WithContext(ctx).Error("Error when scanning ticket_price table", zap.Error(err))
+			return prices, err
+		}
+		prices[ticketID] = price
+	}
+	return prices, nil
+}
+
+// GetTicketPriceSchedules returns the price tiers of the given tickets, each
+// ticket's tiers ordered from the highest priority down.
+func (r *ticketRepository) GetTicketPriceSchedules(ctx context.Context, ticketIDs []int) (map[int][]model.PriceSchedule, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	schedules := make(map[int][]model.PriceSchedule)
+	if len(ticketIDs) == 0 {
+		return schedules, nil
+	}
+
+	placeholders, args := inClause(ticketIDs)
+	query := `SELECT schedule_id, ticket_detail_id, name, price, effective_from, effective_to, min_stock, max_stock, priority
+		FROM ticket_price_schedule
+		WHERE ticket_detail_id IN (` + placeholders + `)
+		ORDER BY ticket_detail_id, priority DESC, schedule_id`
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when querying ticket_price_schedule table", zap.Error(err))
+		return schedules, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			schedule           model.PriceSchedule
+			from, to           sql.NullTime
+			minStock, maxStock sql.NullInt64
+		)
+		err := rows.Scan(&schedule.ScheduleID, &schedule.TicketID, &schedule.Name, &schedule.Price, &from, &to,
+			&minStock, &maxStock, &schedule.Priority)
+		if err != nil {
+			r.logger.WithContext(ctx).Error("Error when scanning ticket_price_schedule table", zap.Error(err))
+			return schedules, err
+		}
+		if from.Valid {
+			schedule.EffectiveFrom = &from.Time
+		}
+		if to.Valid {
+			schedule.EffectiveTo = &to.Time
+		}
+		if minStock.Valid {
+			value := int(minStock.Int64)
+			schedule.MinStock = &value
+		}
+		if maxStock.Valid {
+			value := int(maxStock.Int64)
+			schedule.MaxStock = &value
+		}
+		schedules[schedule.TicketID] = append(schedules[schedule.TicketID], schedule)
+	}
+	return schedules, nil
+}
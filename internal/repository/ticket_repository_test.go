@@ -1,7 +1,11 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
 	mock_config "github.com/SyamSolution/ticket-management-service/mock/config"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -17,18 +21,18 @@ func TestGetAvailableTicketByContinent(t *testing.T) {
 	}
 	defer db.Close()
 
-	rows := sqlmock.NewRows([]string{"ticket_detail_id", "type", "price", "continent_name", "stock_ticket", "stock", "stock_ordered", "country_name", "country_city", "country_place", "created_at", "updated_at"}).
-		AddRow(1, "Type1", 100, "Continent1", 10, 10, 0, "Country1", "City1", "Place1", time.Now(), time.Now())
+	rows := sqlmock.NewRows([]string{"ticket_detail_id", "type", "price", "currency", "continent_name", "total", "available", "held", "sold", "country_name", "country_city", "country_place", "created_at", "updated_at"}).
+		AddRow(1, "Type1", 10000, "IDR", "Continent1", 10, 10, 0, 0, "Country1", "City1", "Place1", time.Now(), time.Now())
 
-	mock.ExpectQuery("^SELECT (.+) FROM ticket_detail WHERE continent_name = \\? AND stock > 0$").WithArgs("Continent1").WillReturnRows(rows)
+	mock.ExpectQuery("^SELECT (.+) FROM ticket_detail td LEFT JOIN continent c ON c.continent_id = td.continent_id LEFT JOIN venue v ON v.venue_id = td.venue_id LEFT JOIN city ci ON ci.city_id = v.city_id LEFT JOIN country co ON co.country_id = ci.country_id WHERE td.continent_id IN \\(SELECT continent_id FROM continent WHERE (.+)\\) AND td.available > 0 AND \\(td.event_id IS NULL OR td.event_id IN \\(SELECT event_id FROM event WHERE status = 'on_sale' (.+)\\)\\)$").WithArgs("Continent1", "Continent1").WillReturnRows(rows)
 
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	logger := mock_config.NewMockLogger(ctrl)
-	repo := NewTicketRepository(db, logger)
+	repo := NewTicketRepository(db, logger, 0)
 
-	tickets, err := repo.GetAvailableTicketByContinent("Continent1")
+	tickets, err := repo.GetAvailableTicketByContinent(context.Background(), "Continent1")
 	if err != nil {
 		t.Errorf("error was not expected while getting available tickets: %s", err)
 	}
@@ -45,18 +49,18 @@ func TestGetAvailableTicketByType(t *testing.T) {
 	}
 	defer db.Close()
 
-	rows := sqlmock.NewRows([]string{"ticket_detail_id", "type", "price", "continent_name", "stock_ticket", "stock", "stock_ordered", "country_name", "country_city", "country_place", "created_at", "updated_at"}).
-		AddRow(1, "Type1", 100, "Continent1", 10, 10, 0, "Country1", "City1", "Place1", time.Now(), time.Now())
+	rows := sqlmock.NewRows([]string{"ticket_detail_id", "type", "price", "currency", "continent_name", "total", "available", "held", "sold", "country_name", "country_city", "country_place", "created_at", "updated_at"}).
+		AddRow(1, "Type1", 10000, "IDR", "Continent1", 10, 10, 0, 0, "Country1", "City1", "Place1", time.Now(), time.Now())
 
-	mock.ExpectQuery("^SELECT (.+) FROM ticket_detail WHERE type = \\? AND stock > 0$").WithArgs("Type1").WillReturnRows(rows)
+	mock.ExpectQuery("^SELECT (.+) FROM ticket_detail td LEFT JOIN continent c ON c.continent_id = td.continent_id LEFT JOIN venue v ON v.venue_id = td.venue_id LEFT JOIN city ci ON ci.city_id = v.city_id LEFT JOIN country co ON co.country_id = ci.country_id WHERE td.type = \\? AND td.available > 0 AND \\(td.event_id IS NULL OR td.event_id IN \\(SELECT event_id FROM event WHERE status = 'on_sale' (.+)\\)\\)$").WithArgs("Type1").WillReturnRows(rows)
 
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	logger := mock_config.NewMockLogger(ctrl)
-	repo := NewTicketRepository(db, logger)
+	repo := NewTicketRepository(db, logger, 0)
 
-	tickets, err := repo.GetAvailableTicketByType("Type1")
+	tickets, err := repo.GetAvailableTicketByType(context.Background(), "Type1")
 	if err != nil {
 		t.Errorf("error was not expected while getting available tickets: %s", err)
 	}
@@ -73,18 +77,18 @@ func TestGetTicketByID(t *testing.T) {
 	}
 	defer db.Close()
 
-	rows := sqlmock.NewRows([]string{"ticket_detail_id", "type", "price", "continent_name", "stock_ticket", "stock", "stock_ordered", "country_name", "country_city", "country_place", "created_at", "updated_at"}).
-		AddRow(1, "Type1", 100, "Continent1", 10, 10, 0, "Country1", "City1", "Place1", time.Now(), time.Now())
+	rows := sqlmock.NewRows([]string{"ticket_detail_id", "type", "price", "currency", "continent_name", "total", "available", "held", "sold", "country_name", "country_city", "country_place", "created_at", "updated_at"}).
+		AddRow(1, "Type1", 10000, "IDR", "Continent1", 10, 10, 0, 0, "Country1", "City1", "Place1", time.Now(), time.Now())
 
-	mock.ExpectQuery("^SELECT (.+) FROM ticket_detail WHERE ticket_detail_id = \\?$").WithArgs(1).WillReturnRows(rows)
+	mock.ExpectQuery("^SELECT (.+) FROM ticket_detail td LEFT JOIN continent c ON c.continent_id = td.continent_id LEFT JOIN venue v ON v.venue_id = td.venue_id LEFT JOIN city ci ON ci.city_id = v.city_id LEFT JOIN country co ON co.country_id = ci.country_id WHERE td.ticket_detail_id = \\?$").WithArgs(1).WillReturnRows(rows)
 
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	logger := mock_config.NewMockLogger(ctrl)
-	repo := NewTicketRepository(db, logger)
+	repo := NewTicketRepository(db, logger, 0)
 
-	ticket, err := repo.GetTicketByID(1)
+	ticket, err := repo.GetTicketByID(context.Background(), 1)
 	if err != nil {
 		t.Errorf("error was not expected while getting ticket by ID: %s", err)
 	}
@@ -93,6 +97,36 @@ func TestGetTicketByID(t *testing.T) {
 	assert.Equal(t, "Continent1", ticket.ContinentName)
 }
 
+func TestGetTicketByIDWithoutGeographyLink(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"ticket_detail_id", "type", "price", "currency", "continent_name", "total", "available", "held", "sold", "country_name", "country_city", "country_place", "created_at", "updated_at"}).
+		AddRow(2, "Type1", 10000, "IDR", "Atlantis", 10, 10, 0, 0, "Lost Kingdom", "Old Town", "Harbour", time.Now(), time.Now())
+
+	mock.ExpectQuery("^SELECT td.ticket_detail_id, (.+), COALESCE\\(c.name, td.continent_name, ''\\), (.+), COALESCE\\(co.name, td.country_name, ''\\), COALESCE\\(ci.name, td.country_city, ''\\), COALESCE\\(v.name, td.country_place, ''\\), (.+) FROM ticket_detail td (.+) WHERE td.ticket_detail_id = \\?$").WithArgs(2).WillReturnRows(rows)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+	repo := NewTicketRepository(db, logger, 0)
+
+	ticket, err := repo.GetTicketByID(context.Background(), 2)
+	if err != nil {
+		t.Errorf("error was not expected while getting ticket by ID: %s", err)
+	}
+
+	assert.Equal(t, "Atlantis", ticket.ContinentName)
+	assert.Equal(t, "Lost Kingdom", ticket.CountryName)
+	assert.Equal(t, "Old Town", ticket.CountryCity)
+	assert.Equal(t, "Harbour", ticket.CountryPlace)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetTicketByContinent(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -100,18 +134,18 @@ func TestGetTicketByContinent(t *testing.T) {
 	}
 	defer db.Close()
 
-	rows := sqlmock.NewRows([]string{"ticket_detail_id", "type", "price", "continent_name", "stock_ticket", "stock", "stock_ordered", "country_name", "country_city", "country_place", "created_at", "updated_at"}).
-		AddRow(1, "Type1", 100, "Continent1", 10, 10, 0, "Country1", "City1", "Place1", time.Now(), time.Now())
+	rows := sqlmock.NewRows([]string{"ticket_detail_id", "type", "price", "currency", "continent_name", "total", "available", "held", "sold", "country_name", "country_city", "country_place", "created_at", "updated_at"}).
+		AddRow(1, "Type1", 10000, "IDR", "Continent1", 10, 10, 0, 0, "Country1", "City1", "Place1", time.Now(), time.Now())
 
-	mock.ExpectQuery("^SELECT (.+) FROM ticket_detail WHERE continent_name = \\?$").WithArgs("Continent1").WillReturnRows(rows)
+	mock.ExpectQuery("^SELECT (.+) FROM ticket_detail td LEFT JOIN continent c ON c.continent_id = td.continent_id LEFT JOIN venue v ON v.venue_id = td.venue_id LEFT JOIN city ci ON ci.city_id = v.city_id LEFT JOIN country co ON co.country_id = ci.country_id WHERE td.continent_id IN \\(SELECT continent_id FROM continent WHERE (.+)\\)$").WithArgs("Continent1", "Continent1").WillReturnRows(rows)
 
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	logger := mock_config.NewMockLogger(ctrl)
-	repo := NewTicketRepository(db, logger)
+	repo := NewTicketRepository(db, logger, 0)
 
-	tickets, err := repo.GetTicketByContinent("Continent1")
+	tickets, err := repo.GetTicketByContinent(context.Background(), "Continent1")
 	if err != nil {
 		t.Errorf("error was not expected while getting tickets by continent: %s", err)
 	}
@@ -121,6 +155,12 @@ func TestGetTicketByContinent(t *testing.T) {
 	assert.Equal(t, "Continent1", tickets[0].ContinentName)
 }
 
+var (
+	stockQuery  = regexp.QuoteMeta("UPDATE ticket_detail SET total = total + ?, available = available + ?, held = held + ?, sold = sold + ?")
+	ledgerQuery = regexp.QuoteMeta("INSERT INTO inventory_ledger (ticket_detail_id, total_delta, available_delta, held_delta, sold_delta, reason,")
+	claimQuery  = regexp.QuoteMeta("UPDATE reservation SET status = ?, updated_at = CURRENT_TIMESTAMP")
+)
+
 func TestUpdateStockCreateOrderTicket(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -128,18 +168,49 @@ func TestUpdateStockCreateOrderTicket(t *testing.T) {
 	}
 	defer db.Close()
 
-	mock.ExpectExec(regexp.QuoteMeta("UPDATE ticket_detail SET stock = stock - ?, stock_ordered = stock_ordered + ? WHERE ticket_detail_id = ?")).
-		WithArgs(10, 10, 1).
+	mock.ExpectBegin()
+	mock.ExpectExec(stockQuery).
+		WithArgs(0, -10, 10, 0, 1, -10, 10, 0).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(ledgerQuery).
+		WithArgs(1, 0, -10, 10, 0, "reserve", "order-1", nil, "req-1", "user:user@mail.com").
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	logger := mock_config.NewMockLogger(ctrl)
-	repo := NewTicketRepository(db, logger)
+	repo := NewTicketRepository(db, logger, 0)
 
-	err = repo.UpdateStockCreateOrderTicket(1, 10)
+	ctx := config.WithActor(config.WithRequestID(context.Background(), "req-1"), "user:user@mail.com")
+	err = repo.UpdateStockCreateOrderTicket(ctx, 1, 10, "order-1")
 	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateStockCreateOrderTicketInsufficientStock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(stockQuery).
+		WithArgs(0, -10, 10, 0, 1, -10, 10, 0).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+	repo := NewTicketRepository(db, logger, 0)
+
+	err = repo.UpdateStockCreateOrderTicket(context.Background(), 1, 10, "order-1")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestUpdateStockSuccessOrderTicket(t *testing.T) {
@@ -149,18 +220,54 @@ func TestUpdateStockSuccessOrderTicket(t *testing.T) {
 	}
 	defer db.Close()
 
-	mock.ExpectExec(regexp.QuoteMeta("UPDATE ticket_detail SET stock_ticket = stock_ticket - ? WHERE ticket_detail_id = ?")).
-		WithArgs(10, 1).
+	mock.ExpectBegin()
+	mock.ExpectExec(claimQuery).
+		WithArgs("confirmed", "order-1", 1, 10, "pending", "resale").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(stockQuery).
+		WithArgs(0, 0, -10, 10, 1, 0, -10, 10).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(ledgerQuery).
+		WithArgs(1, 0, 0, -10, 10, "confirm", "order-1", nil, nil, "system").
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
 
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	logger := mock_config.NewMockLogger(ctrl)
-	repo := NewTicketRepository(db, logger)
+	repo := NewTicketRepository(db, logger, 0)
 
-	err = repo.UpdateStockSuccessOrderTicket(1, 10)
+	err = repo.UpdateStockSuccessOrderTicket(context.Background(), 1, 10, "order-1")
 	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateStockSuccessOrderTicketNotHeld(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(claimQuery).
+		WithArgs("confirmed", "order-1", 1, 10, "pending", "resale").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(stockQuery).
+		WithArgs(0, 0, -10, 10, 1, 0, -10, 10).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+	repo := NewTicketRepository(db, logger, 0)
+
+	err = repo.UpdateStockSuccessOrderTicket(context.Background(), 1, 10, "order-1")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestUpdateStockFailOrderTicket(t *testing.T) {
@@ -170,18 +277,169 @@ func TestUpdateStockFailOrderTicket(t *testing.T) {
 	}
 	defer db.Close()
 
-	mock.ExpectExec(regexp.QuoteMeta("UPDATE ticket_detail SET stock = stock + ?, stock_ordered = stock_ordered - ? WHERE ticket_detail_id = ?")).
-		WithArgs(10, 10, 1).
+	mock.ExpectBegin()
+	mock.ExpectExec(claimQuery).
+		WithArgs("released", "order-1", 1, 10, "pending", "resale").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(stockQuery).
+		WithArgs(0, 10, -10, 0, 1, 10, -10, 0).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(ledgerQuery).
+		WithArgs(1, 0, 10, -10, 0, "release", "order-1", nil, nil, "system").
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectCommit()
 
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	logger := mock_config.NewMockLogger(ctrl)
-	repo := NewTicketRepository(db, logger)
+	repo := NewTicketRepository(db, logger, 0)
 
-	err = repo.UpdateStockFailOrderTicket(1, 10)
+	err = repo.UpdateStockFailOrderTicket(context.Background(), 1, 10, "order-1")
 	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateStockOrderTicketSettled(t *testing.T) {
+	countQuery := regexp.QuoteMeta("SELECT COUNT(*) FROM reservation WHERE order_id = ?")
+	lockQuery := regexp.QuoteMeta("SELECT ticket_detail_id FROM ticket_detail WHERE ticket_detail_id = ? FOR UPDATE")
+	heldQuery := regexp.QuoteMeta("SELECT COALESCE(SUM(held_delta), 0) FROM inventory_ledger WHERE ticket_detail_id = ? AND order_id = ?")
+
+	t.Run("should leave the stock of an order whose reservation is settled", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(claimQuery).
+			WithArgs("released", "order-1", 1, 10, "pending", "resale").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(countQuery).WithArgs("order-1").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectRollback()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewTicketRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+		err = repo.UpdateStockFailOrderTicket(context.Background(), 1, 10, "order-1")
+		assert.ErrorIs(t, err, ErrOrderSettled)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should settle an order without reservation that the ledger shows holding", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(claimQuery).
+			WithArgs("confirmed", "order-1", 1, 10, "pending", "resale").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(countQuery).WithArgs("order-1").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectQuery(lockQuery).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"ticket_detail_id"}).AddRow(1))
+		mock.ExpectQuery(heldQuery).WithArgs(1, "order-1").WillReturnRows(sqlmock.NewRows([]string{"held"}).AddRow(10))
+		mock.ExpectExec(stockQuery).
+			WithArgs(0, 0, -10, 10, 1, 0, -10, 10).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(ledgerQuery).
+			WithArgs(1, 0, 0, -10, 10, "confirm", "order-1", nil, nil, "system").
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectCommit()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewTicketRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+		err = repo.UpdateStockSuccessOrderTicket(context.Background(), 1, 10, "order-1")
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should leave the stock of an order without reservation that holds none", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(claimQuery).
+			WithArgs("confirmed", "order-1", 1, 10, "pending", "resale").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(countQuery).WithArgs("order-1").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectQuery(lockQuery).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"ticket_detail_id"}).AddRow(1))
+		mock.ExpectQuery(heldQuery).WithArgs(1, "order-1").WillReturnRows(sqlmock.NewRows([]string{"held"}).AddRow(0))
+		mock.ExpectRollback()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewTicketRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+		err = repo.UpdateStockSuccessOrderTicket(context.Background(), 1, 10, "order-1")
+		assert.ErrorIs(t, err, ErrOrderSettled)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUpdateStockFailReservation(t *testing.T) {
+	t.Run("should release the reservation and its stock", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(claimQuery).
+			WithArgs("released", 4, 1, 2, "pending", "resale").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(stockQuery).
+			WithArgs(0, 2, -2, 0, 1, 2, -2, 0).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(ledgerQuery).
+			WithArgs(1, 0, 2, -2, 0, "release", nil, nil, nil, "system").
+			WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectCommit()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewTicketRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+		err = repo.UpdateStockFailReservation(context.Background(), 4, 1, 2)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should leave a reservation that is no longer pending", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(claimQuery).
+			WithArgs("released", 4, 1, 2, "pending", "resale").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewTicketRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+		err = repo.UpdateStockFailReservation(context.Background(), 4, 1, 2)
+		assert.ErrorIs(t, err, ErrOrderSettled)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 }
 
 func TestGetStockTicketGroupByContinent(t *testing.T) {
@@ -192,24 +450,26 @@ func TestGetStockTicketGroupByContinent(t *testing.T) {
 	defer db.Close()
 
 	rows := sqlmock.NewRows([]string{"continent_name", "stock"}).
-		AddRow("Continent1", 10)
+		AddRow("Continent1", 10).
+		AddRow("Unknown", 4)
 
-	mock.ExpectQuery("^SELECT continent_name, SUM\\(stock\\) as stock FROM ticket_detail GROUP BY continent_name$").WillReturnRows(rows)
+	mock.ExpectQuery("^SELECT COALESCE\\(c.name, 'Unknown'\\) as continent_name, SUM\\(td.available\\) as stock FROM ticket_detail td LEFT JOIN continent c ON td.continent_id = c.continent_id GROUP BY c.continent_id, c.name$").WillReturnRows(rows)
 
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	logger := mock_config.NewMockLogger(ctrl)
-	repo := NewTicketRepository(db, logger)
+	repo := NewTicketRepository(db, logger, 0)
 
-	tickets, err := repo.GetStockTicketGroupByContinent()
+	tickets, err := repo.GetStockTicketGroupByContinent(context.Background())
 	if err != nil {
 		t.Errorf("error was not expected while getting stock tickets by continent: %s", err)
 	}
 
-	assert.Len(t, tickets, 1)
+	assert.Len(t, tickets, 2)
 	assert.Equal(t, "Continent1", tickets[0].Continent)
 	assert.Equal(t, 10, tickets[0].Stock)
+	assert.Equal(t, model.StockTicket{Continent: "Unknown", Stock: 4}, tickets[1])
 }
 
 func TestGetTicketEventByTicketID(t *testing.T) {
@@ -219,10 +479,11 @@ func TestGetTicketEventByTicketID(t *testing.T) {
 	}
 	defer db.Close()
 
-	rows := sqlmock.NewRows([]string{"ticket_detail_id", "type", "price", "stock", "continent_name", "country_city", "country_place", "event_name", "date", "description"}).
-		AddRow(1, "Type1", 100, 10, "Continent1", "City1", "Place1", "Event1", time.Now(), "Description1")
+	saleEndsAt := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"ticket_detail_id", "type", "price", "currency", "stock", "continent_name", "country_city", "country_place", "event_name", "date", "description", "status", "sale_starts_at", "sale_ends_at"}).
+		AddRow(1, "Type1", 10000, "IDR", 10, "Continent1", "City1", "Place1", "Event1", time.Now(), "Description1", "paused", nil, saleEndsAt)
 
-	mock.ExpectQuery("^SELECT td.ticket_detail_id, td.type, td.price, td.stock, td.continent_name, td.country_city, td.country_place, e.event_name, e.date, e.description from ticket_detail td left join event e on td.event_id = e.event_id WHERE td.ticket_detail_id = \\?$").
+	mock.ExpectQuery("^SELECT td.ticket_detail_id, (.+), e.date, (.+) from ticket_detail td LEFT JOIN continent c ON c.continent_id = td.continent_id LEFT JOIN venue v ON v.venue_id = td.venue_id LEFT JOIN city ci ON ci.city_id = v.city_id LEFT JOIN country co ON co.country_id = ci.country_id left join event e on td.event_id = e.event_id WHERE td.ticket_detail_id = \\?$").
 		WithArgs(1).
 		WillReturnRows(rows)
 
@@ -230,9 +491,9 @@ func TestGetTicketEventByTicketID(t *testing.T) {
 	defer ctrl.Finish()
 
 	logger := mock_config.NewMockLogger(ctrl)
-	repo := NewTicketRepository(db, logger)
+	repo := NewTicketRepository(db, logger, 0)
 
-	ticketEvent, err := repo.GetTicketEventByTicketID(1)
+	ticketEvent, err := repo.GetTicketEventByTicketID(context.Background(), 1)
 	if err != nil {
 		t.Errorf("error was not expected while getting ticket event by ticket id: %s", err)
 	}
@@ -243,4 +504,150 @@ func TestGetTicketEventByTicketID(t *testing.T) {
 	assert.Equal(t, "Place1", ticketEvent.CountryPlace)
 	assert.Equal(t, "Event1", ticketEvent.EventName)
 	assert.Equal(t, "Description1", ticketEvent.Description)
+	assert.Equal(t, "paused", ticketEvent.EventStatus)
+	assert.Nil(t, ticketEvent.SaleStartsAt)
+	assert.Equal(t, saleEndsAt, *ticketEvent.SaleEndsAt)
+}
+
+func TestGetTicketEventByTicketIDWithoutEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"ticket_detail_id", "type", "price", "currency", "stock", "continent_name", "country_city", "country_place", "event_name", "date", "description", "status", "sale_starts_at", "sale_ends_at"}).
+		AddRow(1, "Type1", 10000, "IDR", 10, "Continent1", "City1", "Place1", "", nil, "", "on_sale", nil, nil)
+
+	mock.ExpectQuery("^SELECT td.ticket_detail_id, (.+) from ticket_detail td LEFT JOIN continent c ON c.continent_id = td.continent_id LEFT JOIN venue v ON v.venue_id = td.venue_id LEFT JOIN city ci ON ci.city_id = v.city_id LEFT JOIN country co ON co.country_id = ci.country_id left join event e on td.event_id = e.event_id WHERE td.ticket_detail_id = \\?$").
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+	repo := NewTicketRepository(db, logger, 0)
+
+	ticketEvent, err := repo.GetTicketEventByTicketID(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Type1", ticketEvent.Type)
+	assert.Empty(t, ticketEvent.EventName)
+	assert.True(t, ticketEvent.Date.IsZero())
+	assert.Equal(t, "on_sale", ticketEvent.EventStatus)
+}
+
+func TestGetTicketPurchaseLimit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	saleStartsAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"ticket_detail_id", "event_id", "max_ticket_per_user", "status", "sale_starts_at", "sale_ends_at"}).
+		AddRow(1, 2, 4, "on_sale", saleStartsAt, nil)
+
+	mock.ExpectQuery("^SELECT td.ticket_detail_id, COALESCE\\(td.event_id, 0\\), COALESCE\\(e.max_ticket_per_user, 0\\), COALESCE\\(e.status, 'on_sale'\\), e.sale_starts_at, e.sale_ends_at from ticket_detail td left join event e on td.event_id = e.event_id WHERE td.ticket_detail_id = \\?$").
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+	repo := NewTicketRepository(db, logger, 0)
+
+	limit, err := repo.GetTicketPurchaseLimit(context.Background(), 1)
+	if err != nil {
+		t.Errorf("error was not expected while getting ticket purchase limit: %s", err)
+	}
+
+	assert.Equal(t, 1, limit.TicketID)
+	assert.Equal(t, 2, limit.EventID)
+	assert.Equal(t, 4, limit.MaxTicketPerUser)
+	assert.Equal(t, "on_sale", limit.EventStatus)
+	assert.Equal(t, saleStartsAt, *limit.SaleStartsAt)
+	assert.Nil(t, limit.SaleEndsAt)
+}
+
+func TestGetTicketInventory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"ticket_detail_id", "type", "continent_name", "total", "available", "held", "sold"}).
+		AddRow(1, "Type1", "Continent1", 10, 7, 2, 1)
+
+	mock.ExpectQuery("^SELECT td.ticket_detail_id, COALESCE\\(td.type, ''\\), COALESCE\\(c.name, 'Unknown'\\), td.total, td.available, td.held, td.sold FROM ticket_detail td LEFT JOIN continent c ON c.continent_id = td.continent_id$").WillReturnRows(rows)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+	repo := NewTicketRepository(db, logger, 0)
+
+	tickets, err := repo.GetTicketInventory(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, tickets, 1)
+	assert.Equal(t, model.StockCounters{Total: 10, Available: 7, Held: 2, Sold: 1},
+		model.StockCounters{Total: tickets[0].Total, Available: tickets[0].Available, Held: tickets[0].Held, Sold: tickets[0].Sold})
+}
+
+func TestGetTicketPricesByRegion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"ticket_detail_id", "amount", "currency"}).
+		AddRow(1, 15000, "SGD")
+
+	mock.ExpectQuery("FROM ticket_price tp\\s+JOIN country c ON tp.country_id = c.country_id\\s+WHERE \\(c.code = \\? OR c.code_alpha3 = \\?\\) AND tp.ticket_detail_id IN \\(\\?, \\?\\)").
+		WithArgs("SG", "SG", 1, 2).
+		WillReturnRows(rows)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+	repo := NewTicketRepository(db, logger, 0)
+
+	prices, err := repo.GetTicketPricesByRegion(context.Background(), []int{1, 2}, "SG")
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]model.Money{1: {Amount: 15000, Currency: "SGD"}}, prices)
+}
+
+func TestGetTicketPriceSchedules(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	until := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"schedule_id", "ticket_detail_id", "name", "price", "effective_from", "effective_to", "min_stock", "max_stock", "priority"}).
+		AddRow(2, 1, "last-minute", 250000, nil, nil, nil, 50, 10).
+		AddRow(1, 1, "early-bird", 150000, nil, until, 900, nil, 5)
+
+	mock.ExpectQuery("FROM ticket_price_schedule\\s+WHERE ticket_detail_id IN \\(\\?\\)\\s+ORDER BY ticket_detail_id, priority DESC, schedule_id").
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+	repo := NewTicketRepository(db, logger, 0)
+
+	schedules, err := repo.GetTicketPriceSchedules(context.Background(), []int{1})
+	assert.NoError(t, err)
+	assert.Len(t, schedules[1], 2)
+	assert.Nil(t, schedules[1][0].MinStock)
+	assert.Equal(t, 50, *schedules[1][0].MaxStock)
+	assert.Equal(t, until, *schedules[1][1].EffectiveTo)
+	assert.Equal(t, 900, *schedules[1][1].MinStock)
 }
@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"go.uber.org/zap"
+)
+
+// ErrTransferPending is returned by CreateTransfer when the ticket already has
+// a transfer waiting for an answer or is listed for resale.
+var ErrTransferPending = errors.New("ticket already has a pending transfer or resale listing")
+
+type transferRepository struct {
+	DB           *sql.DB
+	logger       config.Logger
+	queryTimeout time.Duration
+}
+
+type TransferPersister interface {
+	CreateTransfer(ctx context.Context, transfer model.TicketTransfer) (int, error)
+	GetTransferByID(ctx context.Context, transferID int) (model.TicketTransfer, error)
+	GetTransfersByEmail(ctx context.Context, email string) ([]model.TicketTransfer, error)
+	GetTransfersByIssuedTicketID(ctx context.Context, issuedTicketID int) ([]model.TicketTransfer, error)
+	AcceptTransfer(ctx context.Context, transfer model.TicketTransfer, code string, at time.Time) error
+	UpdateTransferStatus(ctx context.Context, transferID int, status string, at time.Time) error
+	ExpireTransfers(ctx context.Context, now time.Time) (int64, error)
+}
+
+func NewTransferRepository(DB *sql.DB, logger config.Logger, queryTimeout time.Duration) TransferPersister {
+	return &transferRepository{DB: DB, logger: logger, queryTimeout: queryTimeout}
+}
+
+const transferColumns = `transfer_id, issued_ticket_id, COALESCE(listing_id, 0), from_email, to_email, status, expires_at, responded_at, created_at`
+
+func scanTransfer(row rowScanner) (model.TicketTransfer, error) {
+	var (
+		transfer    model.TicketTransfer
+		respondedAt sql.NullTime
+	)
+	err := row.Scan(&transfer.TransferID, &transfer.IssuedTicketID, &transfer.ListingID, &transfer.FromEmail, &transfer.ToEmail, &transfer.Status,
+		&transfer.ExpiresAt, &respondedAt, &transfer.CreatedAt)
+	transfer.RespondedAt = nullTime(respondedAt)
+	return transfer, err
+}
+
+func (r *transferRepository) queryTransfers(ctx context.Context, query string, args ...interface{}) ([]model.TicketTransfer, error) {
+	var transfers []model.TicketTransfer
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when querying ticket_transfer table", zap.Error(err))
+		return transfers, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		transfer, err := scanTransfer(rows)
+		if err != nil {
+			r.logger.WithContext(ctx).Error("Error when scanning ticket_transfer table", zap.Error(err))
+			return transfers, err
+		}
+		transfers = append(transfers, transfer)
+	}
+	return transfers, nil
+}
+
+// CreateTransfer records a pending transfer unless the ticket already has one
+// or is listed for resale, in which case ErrTransferPending is returned.
+func (r *transferRepository) CreateTransfer(ctx context.Context, transfer model.TicketTransfer) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `INSERT INTO ticket_transfer (issued_ticket_id, from_email, to_email, status, expires_at)
+		SELECT ?, ?, ?, ?, ? FROM DUAL
+		WHERE NOT EXISTS (SELECT 1 FROM ticket_transfer WHERE issued_ticket_id = ? AND status = ?)
+		AND NOT EXISTS (SELECT 1 FROM resale_listing WHERE issued_ticket_id = ? AND status IN (?, ?))`
+	result, err := r.DB.ExecContext(ctx, query, transfer.IssuedTicketID, transfer.FromEmail, transfer.ToEmail,
+		util.TRANSFER_STATUS_PENDING, transfer.ExpiresAt, transfer.IssuedTicketID, util.TRANSFER_STATUS_PENDING,
+		transfer.IssuedTicketID, util.RESALE_LISTING_STATUS_ACTIVE, util.RESALE_LISTING_STATUS_RESERVED)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when inserting ticket_transfer table", zap.Error(err))
+		return 0, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when getting affected rows of ticket_transfer table", zap.Error(err))
+		return 0, err
+	}
+	if affected == 0 {
+		return 0, ErrTransferPending
+	}
+
+	transferID, err := result.LastInsertId()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when getting last insert id of ticket_transfer table", zap.Error(err))
+		return 0, err
+	}
+	return int(transferID), nil
+}
+
+func (r *transferRepository) GetTransferByID(ctx context.Context, transferID int) (model.TicketTransfer, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + transferColumns + ` FROM ticket_transfer WHERE transfer_id = ?`
+	transfer, err := scanTransfer(r.DB.QueryRowContext(ctx, query, transferID))
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when scanning ticket_transfer table", zap.Error(err))
+		return transfer, err
+	}
+	return transfer, nil
+}
+
+// GetTransfersByEmail returns the transfers email sent or received, newest
+// first.
+func (r *transferRepository) GetTransfersByEmail(ctx context.Context, email string) ([]model.TicketTransfer, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + transferColumns + ` FROM ticket_transfer WHERE from_email = ? OR to_email = ? ORDER BY transfer_id DESC`
+	return r.queryTransfers(ctx, query, email, email)
+}
+
+// GetTransfersByIssuedTicketID returns the transfers of a ticket, resales
+// included, oldest first.
+func (r *transferRepository) GetTransfersByIssuedTicketID(ctx context.Context, issuedTicketID int) ([]model.TicketTransfer, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + transferColumns + ` FROM ticket_transfer WHERE issued_ticket_id = ? ORDER BY created_at, transfer_id`
+	return r.queryTransfers(ctx, query, issuedTicketID)
+}
+
+// AcceptTransfer completes a pending, unexpired transfer in one transaction:
+// the ticket moves to the recipient under code, which voids every QR payload
+// signed for the old one. It returns sql.ErrNoRows when the transfer is no
+// longer pending or the ticket is no longer the sender's to give, e.g.
+// because it was checked in meanwhile.
+func (r *transferRepository) AcceptTransfer(ctx context.Context, transfer model.TicketTransfer, code string, at time.Time) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when starting ticket transfer transaction", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	statements := []struct {
+		query string
+		args  []interface{}
+	}{
+		{
+			query: `UPDATE ticket_transfer SET status = ?, responded_at = ? WHERE transfer_id = ? AND status = ? AND expires_at > ?`,
+			args:  []interface{}{util.TRANSFER_STATUS_ACCEPTED, at, transfer.TransferID, util.TRANSFER_STATUS_PENDING, at},
+		},
+		{
+			query: `UPDATE issued_ticket SET email = ?, code = ?
+				WHERE issued_ticket_id = ? AND email = ? AND status = ? AND checked_in_at IS NULL`,
+			args: []interface{}{transfer.ToEmail, code, transfer.IssuedTicketID, transfer.FromEmail, util.ISSUED_TICKET_STATUS_VALID},
+		},
+	}
+
+	for _, statement := range statements {
+		result, err := tx.ExecContext(ctx, statement.query, statement.args...)
+		if err != nil {
+			r.logger.WithContext(ctx).Error("Error when updating ticket transfer", zap.Error(err))
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			r.logger.WithContext(ctx).Error("Error when getting affected rows of ticket transfer", zap.Error(err))
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.WithContext(ctx).Error("Error when committing ticket transfer transaction", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// UpdateTransferStatus ends a pending transfer with status. It returns
+// sql.ErrNoRows when the transfer is no longer pending.
+func (r *transferRepository) UpdateTransferStatus(ctx context.Context, transferID int, status string, at time.Time) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `UPDATE ticket_transfer SET status = ?, responded_at = ? WHERE transfer_id = ? AND status = ?`
+	result, err := r.DB.ExecContext(ctx, query, status, at, transferID, util.TRANSFER_STATUS_PENDING)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when updating ticket_transfer table", zap.Error(err))
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when getting affected rows of ticket_transfer table", zap.Error(err))
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ExpireTransfers marks the pending transfers that were not answered in time
+// as expired.
+func (r *transferRepository) ExpireTransfers(ctx context.Context, now time.Time) (int64, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `UPDATE ticket_transfer SET status = ? WHERE status = ? AND expires_at <= ?`
+	result, err := r.DB.ExecContext(ctx, query, util.TRANSFER_STATUS_EXPIRED, util.TRANSFER_STATUS_PENDING, now)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when updating ticket_transfer table", zap.Error(err))
+		return 0, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when getting affected rows of ticket_transfer table", zap.Error(err))
+		return 0, err
+	}
+	return affected, nil
+}
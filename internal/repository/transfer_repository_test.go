@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	mock_config "github.com/SyamSolution/ticket-management-service/mock/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateTransfer(t *testing.T) {
+	expiresAt := time.Date(2026, 11, 3, 10, 0, 0, 0, time.UTC)
+	transfer := model.TicketTransfer{IssuedTicketID: 1, FromEmail: "owner@mail.com", ToEmail: "friend@mail.com", ExpiresAt: expiresAt}
+	query := regexp.QuoteMeta("INSERT INTO ticket_transfer (issued_ticket_id, from_email, to_email, status, expires_at)")
+
+	t.Run("should insert a pending transfer", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectExec(query).
+			WithArgs(1, "owner@mail.com", "friend@mail.com", "pending", expiresAt, 1, "pending", 1, "active", "reserved").
+			WillReturnResult(sqlmock.NewResult(7, 1))
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewTransferRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+		transferID, err := repo.CreateTransfer(context.Background(), transfer)
+		assert.NoError(t, err)
+		assert.Equal(t, 7, transferID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should refuse a ticket with a pending transfer or listing", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewTransferRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+		_, err = repo.CreateTransfer(context.Background(), transfer)
+		assert.ErrorIs(t, err, ErrTransferPending)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetTransfersByIssuedTicketID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	at := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM ticket_transfer WHERE issued_ticket_id = ? ORDER BY created_at, transfer_id")).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"transfer_id", "issued_ticket_id", "listing_id", "from_email", "to_email", "status",
+			"expires_at", "responded_at", "created_at"}).
+			AddRow(2, 1, 0, "owner@mail.com", "friend@mail.com", "accepted", at, at, at).
+			AddRow(3, 1, 5, "friend@mail.com", "buyer@mail.com", "accepted", at, at, at))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewTransferRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+	transfers, err := repo.GetTransfersByIssuedTicketID(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, transfers, 2)
+	assert.Equal(t, model.TicketTransfer{TransferID: 3, IssuedTicketID: 1, ListingID: 5, FromEmail: "friend@mail.com",
+		ToEmail: "buyer@mail.com", Status: "accepted", ExpiresAt: at, RespondedAt: &at, CreatedAt: at}, transfers[1])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAcceptTransfer(t *testing.T) {
+	at := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+	transfer := model.TicketTransfer{TransferID: 7, IssuedTicketID: 1, FromEmail: "owner@mail.com", ToEmail: "friend@mail.com"}
+
+	t.Run("should move the ticket under the new code", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE ticket_transfer SET status = ?, responded_at = ?")).
+			WithArgs("accepted", at, 7, "pending", at).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE issued_ticket SET email = ?, code = ?")).
+			WithArgs("friend@mail.com", "NEWCODE", 1, "owner@mail.com", "valid").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewTransferRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+		err = repo.AcceptTransfer(context.Background(), transfer, "NEWCODE", at)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should roll back when the ticket is no longer the sender's", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE ticket_transfer SET status = ?, responded_at = ?")).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE issued_ticket SET email = ?, code = ?")).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewTransferRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+		err = repo.AcceptTransfer(context.Background(), transfer, "NEWCODE", at)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestExpireTransfers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE ticket_transfer SET status = ? WHERE status = ? AND expires_at <= ?")).
+		WithArgs("expired", "pending", now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewTransferRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+	expired, err := repo.ExpireTransfers(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), expired)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,369 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"go.uber.org/zap"
+)
+
+// ErrAlreadyWaitlisted is returned by JoinWaitlist when the user is already
+// waiting for, or holds an offer of, the ticket.
+var ErrAlreadyWaitlisted = errors.New("already on the waitlist of this ticket")
+
+type waitlistRepository struct {
+	DB           *sql.DB
+	logger       config.Logger
+	queryTimeout time.Duration
+}
+
+type WaitlistPersister interface {
+	JoinWaitlist(ctx context.Context, entry model.WaitlistEntry) (int, error)
+	GetWaitlistEntryByID(ctx context.Context, waitlistID int) (model.WaitlistEntry, error)
+	GetWaitlistEntriesByEmail(ctx context.Context, email string) ([]model.WaitlistEntry, error)
+	LeaveWaitlist(ctx context.Context, entry model.WaitlistEntry) error
+	OfferStock(ctx context.Context, ticketID int, offeredAt, expiresAt time.Time) ([]model.WaitlistEntry, error)
+	ClaimOffer(ctx context.Context, entry model.WaitlistEntry, orderID string, now time.Time) error
+	ExpireOffers(ctx context.Context, now time.Time) (int64, error)
+	GetTicketIDsToOffer(ctx context.Context) ([]int, error)
+}
+
+func NewWaitlistRepository(DB *sql.DB, logger config.Logger, queryTimeout time.Duration) WaitlistPersister {
+	return &waitlistRepository{DB: DB, logger: logger, queryTimeout: queryTimeout}
+}
+
+const waitlistColumns = `waitlist_id, ticket_detail_id, email, quantity, status, offered_at, offer_expires_at,
+	COALESCE(order_id, ''), created_at, updated_at`
+
+// ticketOfferable keeps the tickets whose freed stock goes to the waitlist:
+// those of events still selling, or sold out, and those without an event.
+const ticketOfferable = `(event_id IS NULL OR event_id IN (SELECT event_id FROM event WHERE status IN ('` +
+	util.EVENT_STATUS_ON_SALE + `', '` + util.EVENT_STATUS_SOLD_OUT + `')))`
+
+func scanWaitlistEntry(row rowScanner) (model.WaitlistEntry, error) {
+	var (
+		entry          model.WaitlistEntry
+		offeredAt      sql.NullTime
+		offerExpiresAt sql.NullTime
+	)
+	err := row.Scan(&entry.WaitlistID, &entry.TicketID, &entry.Email, &entry.Quantity, &entry.Status, &offeredAt,
+		&offerExpiresAt, &entry.OrderID, &entry.CreatedAt, &entry.UpdatedAt)
+	entry.OfferedAt = nullTime(offeredAt)
+	entry.OfferExpiresAt = nullTime(offerExpiresAt)
+	return entry, err
+}
+
+// JoinWaitlist queues the user for the ticket unless they already wait for it
+// or hold an offer of it, in which case ErrAlreadyWaitlisted is returned.
+func (r *waitlistRepository) JoinWaitlist(ctx context.Context, entry model.WaitlistEntry) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `INSERT INTO ticket_waitlist (ticket_detail_id, email, quantity, status)
+		SELECT ?, ?, ?, ? FROM DUAL
+		WHERE NOT EXISTS (SELECT 1 FROM ticket_waitlist WHERE ticket_detail_id = ? AND email = ? AND status IN (?, ?))`
+	result, err := r.DB.ExecContext(ctx, query, entry.TicketID, entry.Email, entry.Quantity, util.WAITLIST_STATUS_WAITING,
+		entry.TicketID, entry.Email, util.WAITLIST_STATUS_WAITING, util.WAITLIST_STATUS_OFFERED)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when inserting ticket_waitlist table", zap.Error(err))
+		return 0, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when getting affected rows of ticket_waitlist table", zap.Error(err))
+		return 0, err
+	}
+	if affected == 0 {
+		return 0, ErrAlreadyWaitlisted
+	}
+
+	waitlistID, err := result.LastInsertId()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when getting last insert id of ticket_waitlist table", zap.Error(err))
+		return 0, err
+	}
+	return int(waitlistID), nil
+}
+
+func (r *waitlistRepository) GetWaitlistEntryByID(ctx context.Context, waitlistID int) (model.WaitlistEntry, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + waitlistColumns + ` FROM ticket_waitlist WHERE waitlist_id = ?`
+	entry, err := scanWaitlistEntry(r.DB.QueryRowContext(ctx, query, waitlistID))
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when scanning ticket_waitlist table", zap.Error(err))
+		return entry, err
+	}
+	return entry, nil
+}
+
+// GetWaitlistEntriesByEmail returns the waitlist entries of email, newest
+// first.
+func (r *waitlistRepository) GetWaitlistEntriesByEmail(ctx context.Context, email string) ([]model.WaitlistEntry, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var entries []model.WaitlistEntry
+
+	query := `SELECT ` + waitlistColumns + ` FROM ticket_waitlist WHERE email = ? ORDER BY waitlist_id DESC`
+	rows, err := r.DB.QueryContext(ctx, query, email)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when querying ticket_waitlist table", zap.Error(err))
+		return entries, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry, err := scanWaitlistEntry(rows)
+		if err != nil {
+			r.logger.WithContext(ctx).Error("Error when scanning ticket_waitlist table", zap.Error(err))
+			return entries, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// LeaveWaitlist takes entry off the waitlist. The stock of an offer is
+// returned to the ticket in the same transaction. It returns sql.ErrNoRows
+// when the entry is no longer in entry.Status.
+func (r *waitlistRepository) LeaveWaitlist(ctx context.Context, entry model.WaitlistEntry) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when starting ticket waitlist transaction", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE ticket_waitlist SET status = ? WHERE waitlist_id = ? AND status = ?`
+	result, err := tx.ExecContext(ctx, query, util.WAITLIST_STATUS_LEFT, entry.WaitlistID, entry.Status)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when updating ticket waitlist", zap.Error(err))
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when getting affected rows of ticket waitlist", zap.Error(err))
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if entry.Status == util.WAITLIST_STATUS_OFFERED {
+		movement := model.InventoryLedgerEntry{TicketID: entry.TicketID, Change: model.ReleaseStock(entry.Quantity),
+			Reason: util.LEDGER_REASON_WAITLIST_LEAVE, WaitlistID: entry.WaitlistID}
+		if err := moveStock(ctx, tx, movement); err != nil {
+			r.logger.WithContext(ctx).Error("Error when updating ticket_detail stock", zap.Error(err))
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.WithContext(ctx).Error("Error when committing ticket waitlist transaction", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// OfferStock sets the stock of the ticket aside for the entries at the head
+// of its waitlist, in the order they joined, as long as their quantity fits.
+// An entry that does not fit stops the offers, so nobody is skipped for a
+// smaller request behind them. The offered stock is held like a reservation,
+// which a claim then takes over. Tickets of events that no
+// longer sell offer nothing.
+func (r *waitlistRepository) OfferStock(ctx context.Context, ticketID int, offeredAt, expiresAt time.Time) ([]model.WaitlistEntry, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var offers []model.WaitlistEntry
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when starting ticket waitlist transaction", zap.Error(err))
+		return offers, err
+	}
+	defer tx.Rollback()
+
+	var available int
+	query := `SELECT available FROM ticket_detail WHERE ticket_detail_id = ? AND ` + ticketOfferable + ` FOR UPDATE`
+	err = tx.QueryRowContext(ctx, query, ticketID).Scan(&available)
+	if errors.Is(err, sql.ErrNoRows) {
+		return offers, nil
+	}
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when locking ticket_detail table", zap.Error(err))
+		return offers, err
+	}
+
+	query = `SELECT waitlist_id, email, quantity FROM ticket_waitlist WHERE ticket_detail_id = ? AND status = ?
+		ORDER BY waitlist_id FOR UPDATE`
+	rows, err := tx.QueryContext(ctx, query, ticketID, util.WAITLIST_STATUS_WAITING)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when querying ticket_waitlist table", zap.Error(err))
+		return offers, err
+	}
+	offered := 0
+	for rows.Next() {
+		entry := model.WaitlistEntry{TicketID: ticketID, Status: util.WAITLIST_STATUS_OFFERED}
+		if err := rows.Scan(&entry.WaitlistID, &entry.Email, &entry.Quantity); err != nil {
+			rows.Close()
+			r.logger.WithContext(ctx).Error("Error when scanning ticket_waitlist table", zap.Error(err))
+			return nil, err
+		}
+		if offered+entry.Quantity > available {
+			break
+		}
+		offered += entry.Quantity
+		entry.OfferedAt, entry.OfferExpiresAt = &offeredAt, &expiresAt
+		offers = append(offers, entry)
+	}
+	rows.Close()
+	if len(offers) == 0 {
+		return offers, nil
+	}
+
+	for _, entry := range offers {
+		query = `UPDATE ticket_waitlist SET status = ?, offered_at = ?, offer_expires_at = ? WHERE waitlist_id = ?`
+		if _, err := tx.ExecContext(ctx, query, util.WAITLIST_STATUS_OFFERED, offeredAt, expiresAt, entry.WaitlistID); err != nil {
+			r.logger.WithContext(ctx).Error("Error when updating ticket_waitlist table", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	for _, entry := range offers {
+		movement := model.InventoryLedgerEntry{TicketID: ticketID, Change: model.ReserveStock(entry.Quantity),
+			Reason: util.LEDGER_REASON_WAITLIST_OFFER, WaitlistID: entry.WaitlistID}
+		if err := moveStock(ctx, tx, movement); err != nil {
+			r.logger.WithContext(ctx).Error("Error when updating ticket_detail stock", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.WithContext(ctx).Error("Error when committing ticket waitlist transaction", zap.Error(err))
+		return nil, err
+	}
+	return offers, nil
+}
+
+// ClaimOffer takes up the unexpired offer of entry for orderID. The offered
+// stock is already held, so the order continues from there like
+// any reservation. It returns sql.ErrNoRows when the offer is not entry's to
+// claim anymore.
+func (r *waitlistRepository) ClaimOffer(ctx context.Context, entry model.WaitlistEntry, orderID string, now time.Time) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `UPDATE ticket_waitlist SET status = ?, order_id = ?
+		WHERE waitlist_id = ? AND ticket_detail_id = ? AND email = ? AND quantity = ? AND status = ? AND offer_expires_at > ?`
+	result, err := r.DB.ExecContext(ctx, query, util.WAITLIST_STATUS_CLAIMED, orderID, entry.WaitlistID, entry.TicketID,
+		entry.Email, entry.Quantity, util.WAITLIST_STATUS_OFFERED, now)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when updating ticket_waitlist table", zap.Error(err))
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when getting affected rows of ticket_waitlist table", zap.Error(err))
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ExpireOffers marks the offers that were not claimed in time as expired and
+// returns their stock to the tickets, recorded in the inventory ledger, in one
+// transaction.
+func (r *waitlistRepository) ExpireOffers(ctx context.Context, now time.Time) (int64, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when starting ticket waitlist transaction", zap.Error(err))
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT waitlist_id, ticket_detail_id, quantity FROM ticket_waitlist WHERE status = ? AND offer_expires_at <= ?
+		ORDER BY waitlist_id FOR UPDATE`
+	rows, err := tx.QueryContext(ctx, query, util.WAITLIST_STATUS_OFFERED, now)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when querying ticket_waitlist table", zap.Error(err))
+		return 0, err
+	}
+	var expired []model.WaitlistEntry
+	for rows.Next() {
+		var entry model.WaitlistEntry
+		if err := rows.Scan(&entry.WaitlistID, &entry.TicketID, &entry.Quantity); err != nil {
+			rows.Close()
+			r.logger.WithContext(ctx).Error("Error when scanning ticket_waitlist table", zap.Error(err))
+			return 0, err
+		}
+		expired = append(expired, entry)
+	}
+	rows.Close()
+
+	for _, entry := range expired {
+		query = `UPDATE ticket_waitlist SET status = ? WHERE waitlist_id = ?`
+		if _, err := tx.ExecContext(ctx, query, util.WAITLIST_STATUS_EXPIRED, entry.WaitlistID); err != nil {
+			r.logger.WithContext(ctx).Error("Error when updating ticket_waitlist table", zap.Error(err))
+			return 0, err
+		}
+		movement := model.InventoryLedgerEntry{TicketID: entry.TicketID, Change: model.ReleaseStock(entry.Quantity),
+			Reason: util.LEDGER_REASON_WAITLIST_EXPIRE, WaitlistID: entry.WaitlistID}
+		if err := moveStock(ctx, tx, movement); err != nil {
+			r.logger.WithContext(ctx).Error("Error when updating ticket_detail stock", zap.Error(err))
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.WithContext(ctx).Error("Error when committing ticket waitlist transaction", zap.Error(err))
+		return 0, err
+	}
+	return int64(len(expired)), nil
+}
+
+// GetTicketIDsToOffer returns the tickets that have stock available and users
+// waiting for it.
+func (r *waitlistRepository) GetTicketIDsToOffer(ctx context.Context) ([]int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var ticketIDs []int
+
+	query := `SELECT ticket_detail_id FROM ticket_detail td WHERE available > 0 AND ` + ticketOfferable + `
+		AND EXISTS (SELECT 1 FROM ticket_waitlist tw WHERE tw.ticket_detail_id = td.ticket_detail_id AND tw.status = ?)
+		ORDER BY ticket_detail_id`
+	rows, err := r.DB.QueryContext(ctx, query, util.WAITLIST_STATUS_WAITING)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Error when querying ticket_waitlist table", zap.Error(err))
+		return ticketIDs, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ticketID int
+		if err := rows.Scan(&ticketID); err != nil {
+			r.logger.WithContext(ctx).Error("Error when scanning ticket_waitlist table", zap.Error(err))
+			return ticketIDs, err
+		}
+		ticketIDs = append(ticketIDs, ticketID)
+	}
+	return ticketIDs, nil
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	mock_config "github.com/SyamSolution/ticket-management-service/mock/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestJoinWaitlist(t *testing.T) {
+	entry := model.WaitlistEntry{TicketID: 2, Email: "user@mail.com", Quantity: 2}
+	query := regexp.QuoteMeta("INSERT INTO ticket_waitlist (ticket_detail_id, email, quantity, status)")
+
+	t.Run("should queue the user", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectExec(query).
+			WithArgs(2, "user@mail.com", 2, "waiting", 2, "user@mail.com", "waiting", "offered").
+			WillReturnResult(sqlmock.NewResult(7, 1))
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewWaitlistRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+		waitlistID, err := repo.JoinWaitlist(context.Background(), entry)
+		assert.NoError(t, err)
+		assert.Equal(t, 7, waitlistID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should refuse a user already waiting", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewWaitlistRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+		_, err = repo.JoinWaitlist(context.Background(), entry)
+		assert.ErrorIs(t, err, ErrAlreadyWaitlisted)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOfferStock(t *testing.T) {
+	offeredAt := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+	expiresAt := offeredAt.Add(30 * time.Minute)
+	lockQuery := regexp.QuoteMeta("SELECT available FROM ticket_detail WHERE ticket_detail_id = ?")
+	waitingQuery := regexp.QuoteMeta("SELECT waitlist_id, email, quantity FROM ticket_waitlist WHERE ticket_detail_id = ? AND status = ?")
+
+	t.Run("should offer the stock in joining order until an entry does not fit", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"available"}).AddRow(4))
+		mock.ExpectQuery(waitingQuery).WithArgs(2, "waiting").
+			WillReturnRows(sqlmock.NewRows([]string{"waitlist_id", "email", "quantity"}).
+				AddRow(1, "first@mail.com", 2).
+				AddRow(3, "second@mail.com", 3).
+				AddRow(5, "third@mail.com", 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE ticket_waitlist SET status = ?, offered_at = ?, offer_expires_at = ?")).
+			WithArgs("offered", offeredAt, expiresAt, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(stockQuery).
+			WithArgs(0, -2, 2, 0, 2, -2, 2, 0).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(ledgerQuery).
+			WithArgs(2, 0, -2, 2, 0, "waitlist_offer", nil, 1, nil, "system").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewWaitlistRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+		offers, err := repo.OfferStock(context.Background(), 2, offeredAt, expiresAt)
+		assert.NoError(t, err)
+		assert.Len(t, offers, 1)
+		assert.Equal(t, "first@mail.com", offers[0].Email)
+		assert.Equal(t, expiresAt, *offers[0].OfferExpiresAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should offer nothing for tickets of events that no longer sell", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"available"}))
+		mock.ExpectRollback()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewWaitlistRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+		offers, err := repo.OfferStock(context.Background(), 2, offeredAt, expiresAt)
+		assert.NoError(t, err)
+		assert.Empty(t, offers)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestExpireOffers(t *testing.T) {
+	now := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT waitlist_id, ticket_detail_id, quantity FROM ticket_waitlist WHERE status = ?")).
+		WithArgs("offered", now).
+		WillReturnRows(sqlmock.NewRows([]string{"waitlist_id", "ticket_detail_id", "quantity"}).AddRow(1, 2, 3))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE ticket_waitlist SET status = ? WHERE waitlist_id = ?")).
+		WithArgs("expired", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(stockQuery).
+		WithArgs(0, 3, -3, 0, 2, 3, -3, 0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(ledgerQuery).
+		WithArgs(2, 0, 3, -3, 0, "waitlist_expire", nil, 1, nil, "job:process ticket waitlists").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewWaitlistRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+	expired, err := repo.ExpireOffers(config.WithActor(context.Background(), "job:process ticket waitlists"), now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), expired)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLeaveWaitlist(t *testing.T) {
+	t.Run("should return the stock of an offer", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE ticket_waitlist SET status = ? WHERE waitlist_id = ? AND status = ?")).
+			WithArgs("left", 7, "offered").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(stockQuery).
+			WithArgs(0, 2, -2, 0, 4, 2, -2, 0).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(ledgerQuery).
+			WithArgs(4, 0, 2, -2, 0, "waitlist_leave", nil, 7, nil, "system").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewWaitlistRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+		err = repo.LeaveWaitlist(context.Background(), model.WaitlistEntry{WaitlistID: 7, TicketID: 4, Quantity: 2, Status: "offered"})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should leave stock alone for a waiting entry", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE ticket_waitlist SET status = ? WHERE waitlist_id = ? AND status = ?")).
+			WithArgs("left", 7, "waiting").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewWaitlistRepository(db, mock_config.NewMockLogger(ctrl), 0)
+
+		err = repo.LeaveWaitlist(context.Background(), model.WaitlistEntry{WaitlistID: 7, TicketID: 4, Quantity: 2, Status: "waiting"})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
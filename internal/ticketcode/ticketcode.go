@@ -0,0 +1,106 @@
+// Package ticketcode creates the codes of issued tickets and the signed QR
+// payloads gate scanners verify without calling back to the service.
+package ticketcode
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidPayload is returned for payloads that are malformed or whose
+// signature does not match.
+var ErrInvalidPayload = errors.New("invalid ticket payload")
+
+// version prefixes every payload so the format can change without breaking
+// scanners that only know the old one.
+const version = "TK1"
+
+var codeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewCode returns a random 128 bit ticket code, e.g. "MZXW6YTBOI3DCMRTGQ2TMNZYHE".
+// Codes are unique by chance alone; the database still enforces it.
+func NewCode() (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return codeEncoding.EncodeToString(raw), nil
+}
+
+// Claims is what a QR payload asserts about an issued ticket. The owner is
+// deliberately left out so a photo of the code leaks no personal data.
+type Claims struct {
+	Code     string `json:"c"`
+	EventID  int    `json:"e"`
+	TicketID int    `json:"t"`
+	IssuedAt int64  `json:"i"`
+}
+
+// Signer signs and verifies QR payloads with an Ed25519 key, so scanners only
+// need the public key and can not forge tickets themselves.
+type Signer struct {
+	private ed25519.PrivateKey
+	public  ed25519.PublicKey
+}
+
+// NewSigner builds a Signer from a base64 encoded 32 byte Ed25519 seed.
+func NewSigner(seed string) (*Signer, error) {
+	raw, err := base64.StdEncoding.DecodeString(seed)
+	if err != nil {
+		return nil, fmt.Errorf("decode signing key: %w", err)
+	}
+	if len(raw) != ed25519.SeedSize {
+		return nil, fmt.Errorf("signing key must be %d bytes, got %d", ed25519.SeedSize, len(raw))
+	}
+	private := ed25519.NewKeyFromSeed(raw)
+	return &Signer{private: private, public: private.Public().(ed25519.PublicKey)}, nil
+}
+
+// PublicKey returns the base64 encoded key scanners verify payloads with.
+func (s *Signer) PublicKey() string {
+	return base64.StdEncoding.EncodeToString(s.public)
+}
+
+// Sign returns the QR payload for claims: the version, the claims as JSON and
+// the signature over both, each part base64url encoded and joined by dots.
+func (s *Signer) Sign(claims Claims) (string, error) {
+	body, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signed := version + "." + base64.RawURLEncoding.EncodeToString(body)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(ed25519.Sign(s.private, []byte(signed))), nil
+}
+
+// Verify checks the signature of payload and returns its claims.
+func (s *Signer) Verify(payload string) (Claims, error) {
+	return Verify(s.public, payload)
+}
+
+// Verify checks payload against public, the way an offline scanner does.
+func Verify(public ed25519.PublicKey, payload string) (Claims, error) {
+	var claims Claims
+
+	parts := strings.Split(payload, ".")
+	if len(parts) != 3 || parts[0] != version {
+		return claims, ErrInvalidPayload
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !ed25519.Verify(public, []byte(parts[0]+"."+parts[1]), signature) {
+		return claims, ErrInvalidPayload
+	}
+	body, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return claims, ErrInvalidPayload
+	}
+	if err := json.Unmarshal(body, &claims); err != nil || claims.Code == "" {
+		return claims, ErrInvalidPayload
+	}
+	return claims, nil
+}
@@ -0,0 +1,62 @@
+package ticketcode
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testSeed = base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+
+func TestNewCode(t *testing.T) {
+	first, err := NewCode()
+	assert.NoError(t, err)
+	second, err := NewCode()
+	assert.NoError(t, err)
+
+	assert.Len(t, first, 26)
+	assert.NotEqual(t, first, second)
+}
+
+func TestSignAndVerify(t *testing.T) {
+	signer, err := NewSigner(testSeed)
+	assert.NoError(t, err)
+
+	claims := Claims{Code: "MZXW6YTBOI3DCMRTGQ2TMNZYHE", EventID: 3, TicketID: 2, IssuedAt: 1792400000}
+	payload, err := signer.Sign(claims)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(payload, "TK1."))
+
+	verified, err := signer.Verify(payload)
+	assert.NoError(t, err)
+	assert.Equal(t, claims, verified)
+
+	t.Run("should reject a tampered payload", func(t *testing.T) {
+		forged, err := signer.Sign(Claims{Code: claims.Code, EventID: 4, TicketID: 2, IssuedAt: claims.IssuedAt})
+		assert.NoError(t, err)
+		parts, forgedParts := strings.Split(payload, "."), strings.Split(forged, ".")
+
+		_, err = signer.Verify(parts[0] + "." + forgedParts[1] + "." + parts[2])
+		assert.ErrorIs(t, err, ErrInvalidPayload)
+	})
+
+	t.Run("should reject a payload signed with another key", func(t *testing.T) {
+		other, err := NewSigner(base64.StdEncoding.EncodeToString([]byte("fedcba9876543210fedcba9876543210")))
+		assert.NoError(t, err)
+
+		_, err = other.Verify(payload)
+		assert.ErrorIs(t, err, ErrInvalidPayload)
+	})
+
+	t.Run("should reject garbage", func(t *testing.T) {
+		_, err := signer.Verify("not-a-ticket")
+		assert.ErrorIs(t, err, ErrInvalidPayload)
+	})
+}
+
+func TestNewSignerRejectsShortKey(t *testing.T) {
+	_, err := NewSigner(base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.Error(t, err)
+}
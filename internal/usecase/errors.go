@@ -0,0 +1,12 @@
+package usecase
+
+import "errors"
+
+// Domain errors returned by the usecases. They are wrapped with context using
+// fmt.Errorf("...: %w", err) and mapped to HTTP status codes by handler.ErrorHandler.
+var (
+	ErrNotFound          = errors.New("not found")
+	ErrInvalidParam      = errors.New("invalid parameter")
+	ErrConflict          = errors.New("conflict")
+	ErrInsufficientStock = errors.New("insufficient stock")
+)
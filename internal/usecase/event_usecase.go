@@ -0,0 +1,394 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/currency"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/publisher"
+	"github.com/SyamSolution/ticket-management-service/internal/repository"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"go.uber.org/zap"
+)
+
+type eventUsecase struct {
+	eventRepo        repository.EventPersister
+	reservationRepo  repository.ReservationPersister
+	issuedTicketRepo repository.IssuedTicketPersister
+	resaleRepo       repository.ResalePersister
+	ticketUsecase    TicketExecutor
+	publisher        publisher.Publisher
+	logger           config.Logger
+}
+
+// EventExecutor manages the sale lifecycle of events. Operators publish,
+// pause and resume sales, set the sale window and cancel events;
+// TransitionEvents moves events to past, sold out and back on sale as time
+// passes and stock changes.
+type EventExecutor interface {
+	GetEvents(ctx context.Context) ([]model.Event, error)
+	GetEvent(ctx context.Context, eventID int) (model.Event, error)
+	UpdateEventStatus(ctx context.Context, eventID int, request model.EventStatusRequest) (model.Event, error)
+	UpdateSaleWindow(ctx context.Context, eventID int, request model.EventSaleWindowRequest) (model.Event, error)
+	UpdateResaleCap(ctx context.Context, eventID int, request model.EventResaleCapRequest) (model.Event, error)
+	TransitionEvents(ctx context.Context) (model.EventTransitions, error)
+	CancelEvent(ctx context.Context, eventID int, request model.EventCancelRequest) (model.EventCancellation, error)
+}
+
+func NewEventUsecase(eventRepo repository.EventPersister, reservationRepo repository.ReservationPersister,
+	issuedTicketRepo repository.IssuedTicketPersister, resaleRepo repository.ResalePersister, ticketUsecase TicketExecutor,
+	publisher publisher.Publisher, logger config.Logger) EventExecutor {
+	return &eventUsecase{
+		eventRepo:        eventRepo,
+		reservationRepo:  reservationRepo,
+		issuedTicketRepo: issuedTicketRepo,
+		resaleRepo:       resaleRepo,
+		ticketUsecase:    ticketUsecase,
+		publisher:        publisher,
+		logger:           logger,
+	}
+}
+
+// manualTransitions lists, for each status an operator may set, the statuses
+// the event may be moved from.
+var manualTransitions = map[string][]string{
+	util.EVENT_STATUS_ON_SALE: {util.EVENT_STATUS_DRAFT, util.EVENT_STATUS_PAUSED},
+	util.EVENT_STATUS_PAUSED:  {util.EVENT_STATUS_ON_SALE, util.EVENT_STATUS_SOLD_OUT},
+}
+
+func (uc *eventUsecase) GetEvents(ctx context.Context) ([]model.Event, error) {
+	ctx, span := tracer.Start(ctx, "EventUsecase.GetEvents")
+	defer span.End()
+
+	events, err := uc.eventRepo.GetEvents(ctx)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting events", zap.Error(err))
+		return events, err
+	}
+
+	return events, nil
+}
+
+func (uc *eventUsecase) GetEvent(ctx context.Context, eventID int) (model.Event, error) {
+	ctx, span := tracer.Start(ctx, "EventUsecase.GetEvent")
+	defer span.End()
+
+	event, err := uc.eventRepo.GetEventByID(ctx, eventID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return event, fmt.Errorf("event %d: %w", eventID, ErrNotFound)
+		}
+		uc.logger.WithContext(ctx).Error("Error when getting event by id", zap.Error(err))
+		return event, err
+	}
+
+	return event, nil
+}
+
+func (uc *eventUsecase) UpdateEventStatus(ctx context.Context, eventID int, request model.EventStatusRequest) (model.Event, error) {
+	ctx, span := tracer.Start(ctx, "EventUsecase.UpdateEventStatus")
+	defer span.End()
+
+	event, err := uc.GetEvent(ctx, eventID)
+	if err != nil {
+		return event, err
+	}
+	if event.Status == request.Status {
+		return event, nil
+	}
+
+	from, ok := manualTransitions[request.Status]
+	if !ok {
+		return event, fmt.Errorf("status %s can not be set: %w", request.Status, ErrInvalidParam)
+	}
+	if request.Status == util.EVENT_STATUS_ON_SALE && !event.Date.IsZero() && !util.TimeNow().Before(event.Date) {
+		return event, fmt.Errorf("event %d has already taken place: %w", eventID, ErrConflict)
+	}
+
+	if err := uc.eventRepo.UpdateEventStatus(ctx, eventID, from, request.Status); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return event, fmt.Errorf("event %d is %s and can not become %s: %w", eventID, event.Status, request.Status, ErrConflict)
+		}
+		uc.logger.WithContext(ctx).Error("Error when updating event status", zap.Error(err))
+		return event, err
+	}
+
+	return uc.GetEvent(ctx, eventID)
+}
+
+func (uc *eventUsecase) UpdateSaleWindow(ctx context.Context, eventID int, request model.EventSaleWindowRequest) (model.Event, error) {
+	ctx, span := tracer.Start(ctx, "EventUsecase.UpdateSaleWindow")
+	defer span.End()
+
+	if request.SaleStartsAt != nil && request.SaleEndsAt != nil && !request.SaleStartsAt.Before(*request.SaleEndsAt) {
+		return model.Event{}, fmt.Errorf("sale_starts_at must be before sale_ends_at: %w", ErrInvalidParam)
+	}
+
+	event, err := uc.GetEvent(ctx, eventID)
+	if err != nil {
+		return event, err
+	}
+	if event.Status == util.EVENT_STATUS_CANCELLED || event.Status == util.EVENT_STATUS_PAST {
+		return event, fmt.Errorf("event %d is %s: %w", eventID, event.Status, ErrConflict)
+	}
+
+	if err := uc.eventRepo.UpdateEventSaleWindow(ctx, eventID, request.SaleStartsAt, request.SaleEndsAt); err != nil {
+		uc.logger.WithContext(ctx).Error("Error when updating event sale window", zap.Error(err))
+		return event, err
+	}
+
+	return uc.GetEvent(ctx, eventID)
+}
+
+// UpdateResaleCap opens, changes or closes resale of the tickets of an event.
+// Listings made under an earlier cap stay as they are.
+func (uc *eventUsecase) UpdateResaleCap(ctx context.Context, eventID int, request model.EventResaleCapRequest) (model.Event, error) {
+	ctx, span := tracer.Start(ctx, "EventUsecase.UpdateResaleCap")
+	defer span.End()
+
+	event, err := uc.GetEvent(ctx, eventID)
+	if err != nil {
+		return event, err
+	}
+	if event.Status == util.EVENT_STATUS_CANCELLED || event.Status == util.EVENT_STATUS_PAST {
+		return event, fmt.Errorf("event %d is %s: %w", eventID, event.Status, ErrConflict)
+	}
+
+	if err := uc.eventRepo.UpdateEventResaleCap(ctx, eventID, request.CapPercent); err != nil {
+		uc.logger.WithContext(ctx).Error("Error when updating event resale cap", zap.Error(err))
+		return event, err
+	}
+
+	return uc.GetEvent(ctx, eventID)
+}
+
+// TransitionEvents applies the automatic status changes that are due.
+func (uc *eventUsecase) TransitionEvents(ctx context.Context) (model.EventTransitions, error) {
+	ctx, span := tracer.Start(ctx, "EventUsecase.TransitionEvents")
+	defer span.End()
+
+	transitions, err := uc.eventRepo.TransitionEvents(ctx, util.TimeNow())
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when transitioning events", zap.Error(err))
+		return transitions, err
+	}
+
+	if transitions.Past+transitions.SoldOut+transitions.Reopened > 0 {
+		uc.logger.WithContext(ctx).Info("Transitioned events",
+			zap.Int64("past", transitions.Past), zap.Int64("sold_out", transitions.SoldOut), zap.Int64("reopened", transitions.Reopened))
+	}
+	return transitions, nil
+}
+
+// CancelEvent cancels the event and voids its reservations: pending ones are
+// released back to stock and confirmed ones are marked for refund. A message
+// is published for every reservation before it changes, so buyers are
+// notified at least once. The reservations are worked off one by one and the
+// first error stops the cancellation; calling CancelEvent again on the
+// cancelled event picks up the reservations that are left.
+func (uc *eventUsecase) CancelEvent(ctx context.Context, eventID int, request model.EventCancelRequest) (model.EventCancellation, error) {
+	ctx, span := tracer.Start(ctx, "EventUsecase.CancelEvent")
+	defer span.End()
+
+	cancellation := model.EventCancellation{EventID: eventID}
+
+	event, err := uc.GetEvent(ctx, eventID)
+	if err != nil {
+		return cancellation, err
+	}
+	if event.Status != util.EVENT_STATUS_CANCELLED {
+		now := util.TimeNow()
+		if err := uc.eventRepo.CancelEvent(ctx, eventID, request.Reason, now); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return cancellation, fmt.Errorf("event %d is %s and can not be cancelled: %w", eventID, event.Status, ErrConflict)
+			}
+			uc.logger.WithContext(ctx).Error("Error when cancelling event", zap.Error(err))
+			return cancellation, err
+		}
+		event.Status = util.EVENT_STATUS_CANCELLED
+		event.CancelReason = request.Reason
+		event.CancelledAt = &now
+	}
+	cancellation.Status = event.Status
+
+	pending, err := uc.reservationRepo.GetReservationsByEventAndStatus(ctx, eventID, util.RESERVATION_STATUS_PENDING)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting pending reservations of event", zap.Error(err))
+		return cancellation, err
+	}
+	for _, reservation := range pending {
+		if err := uc.releaseReservation(ctx, event, reservation); err != nil {
+			return cancellation, err
+		}
+		cancellation.Released++
+	}
+
+	confirmed, err := uc.reservationRepo.GetReservationsByEventAndStatus(ctx, eventID, util.RESERVATION_STATUS_CONFIRMED)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting confirmed reservations of event", zap.Error(err))
+		return cancellation, err
+	}
+	for _, reservation := range confirmed {
+		if err := uc.refundReservation(ctx, event, reservation); err != nil {
+			return cancellation, err
+		}
+		cancellation.Refunded++
+	}
+
+	uc.logger.WithContext(ctx).Info("Cancelled event", zap.Int("event_id", eventID),
+		zap.Int("released", cancellation.Released), zap.Int("refunded", cancellation.Refunded))
+	return cancellation, nil
+}
+
+// releaseReservation returns the stock of an unpaid reservation, which also
+// frees the seats and promo of its order. A reservation settled meanwhile, by
+// its order's message or an earlier cancellation, is left alone.
+func (uc *eventUsecase) releaseReservation(ctx context.Context, event model.Event, reservation model.Reservation) error {
+	refund := model.Money{Currency: reservation.UnitPrice.Currency}
+	if err := uc.publishCancellation(ctx, event, reservation, util.CANCELLATION_ACTION_RELEASED, refund); err != nil {
+		return err
+	}
+	return uc.ticketUsecase.ReleaseReservation(ctx, reservation)
+}
+
+// refundReservation marks a paid reservation for refund and voids its
+// tickets. The sold stock is not returned as the event will not take place.
+// A resold ticket is refunded once, to the buyer who paid for it last: the
+// resale reservation gets what its buyer paid and the reservation the ticket
+// was issued for is refunded only for the units its buyer kept.
+func (uc *eventUsecase) refundReservation(ctx context.Context, event model.Event, reservation model.Reservation) error {
+	if reservation.PriceTier == util.RESALE_PRICE_TIER {
+		return uc.refundResale(ctx, event, reservation)
+	}
+
+	sold, err := uc.resaleRepo.GetSoldListingsByReservationID(ctx, reservation.ReservationID)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting resold tickets of reservation", zap.Error(err))
+		return err
+	}
+	resold := make(map[int]bool)
+	for _, listing := range sold {
+		resold[listing.IssuedTicketID] = true
+	}
+	refund := currency.Scale(paidFor(reservation), int64(reservation.Quantity-len(resold)), int64(reservation.Quantity))
+	if err := uc.publishCancellation(ctx, event, reservation, util.CANCELLATION_ACTION_REFUND, refund); err != nil {
+		return err
+	}
+
+	if err := uc.issuedTicketRepo.VoidIssuedTicketsByReservationID(ctx, reservation.ReservationID); err != nil {
+		uc.logger.WithContext(ctx).Error("Error when voiding tickets of reservation", zap.Error(err))
+		return err
+	}
+	return uc.markForRefund(ctx, reservation)
+}
+
+// refundResale refunds a paid resale reservation and voids the ticket it
+// bought. When that ticket was resold again, the later buyer is refunded
+// instead and this reservation's refund is zero.
+func (uc *eventUsecase) refundResale(ctx context.Context, event model.Event, reservation model.Reservation) error {
+	listing, err := uc.resaleRepo.GetListingByOrderID(ctx, reservation.OrderID)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting resale listing by order id", zap.Error(err))
+		return err
+	}
+	ticket, err := uc.issuedTicketRepo.GetIssuedTicketByID(ctx, listing.IssuedTicketID)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting issued ticket by id", zap.Error(err))
+		return err
+	}
+	sold, err := uc.resaleRepo.GetSoldListingsByReservationID(ctx, ticket.ReservationID)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting resold tickets of reservation", zap.Error(err))
+		return err
+	}
+
+	refund := paidFor(reservation)
+	for _, later := range sold {
+		if later.IssuedTicketID == listing.IssuedTicketID && later.ListingID > listing.ListingID {
+			refund.Amount = 0
+		}
+	}
+	if err := uc.publishCancellation(ctx, event, reservation, util.CANCELLATION_ACTION_REFUND, refund); err != nil {
+		return err
+	}
+
+	if err := uc.issuedTicketRepo.VoidIssuedTicket(ctx, listing.IssuedTicketID); err != nil {
+		uc.logger.WithContext(ctx).Error("Error when voiding resold ticket", zap.Error(err))
+		return err
+	}
+	return uc.markForRefund(ctx, reservation)
+}
+
+// paidFor returns what the buyer of reservation paid.
+func paidFor(reservation model.Reservation) model.Money {
+	return model.Money{
+		Amount:   reservation.UnitPrice.Amount*int64(reservation.Quantity) - reservation.Discount.Amount,
+		Currency: reservation.UnitPrice.Currency,
+	}
+}
+
+// markForRefund moves a paid reservation to refund pending unless it moved
+// on meanwhile.
+func (uc *eventUsecase) markForRefund(ctx context.Context, reservation model.Reservation) error {
+	err := uc.reservationRepo.UpdateReservationStatus(ctx, reservation.ReservationID, util.RESERVATION_STATUS_CONFIRMED,
+		util.RESERVATION_STATUS_REFUND_PENDING)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		uc.logger.WithContext(ctx).Error("Error when marking reservation for refund", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (uc *eventUsecase) publishCancellation(ctx context.Context, event model.Event, reservation model.Reservation, action string,
+	refund model.Money) error {
+	message := model.MessageEventCancelled{
+		EventID:       event.EventID,
+		EventName:     event.Name,
+		ReservationID: reservation.ReservationID,
+		OrderID:       reservation.OrderID,
+		Email:         reservation.Email,
+		TicketID:      reservation.TicketID,
+		Quantity:      reservation.Quantity,
+		Action:        action,
+		Refund:        refund,
+		Reason:        event.CancelReason,
+	}
+	if event.CancelledAt != nil {
+		message.CancelledAt = *event.CancelledAt
+	}
+
+	if err := uc.publisher.Publish(ctx, util.TOPIC_EVENT_CANCELLED, message); err != nil {
+		uc.logger.WithContext(ctx).Error("Error when publishing event cancellation", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// saleError tells why tickets of an event in status with the given sale
+// window can not be bought at now, or returns nil when they can.
+func saleError(status string, startsAt, endsAt *time.Time, now time.Time) error {
+	if status != util.EVENT_STATUS_ON_SALE {
+		return &model.BusinessError{
+			Code:    util.ERROR_EVENT_NOT_ON_SALE_CODE,
+			Message: fmt.Sprintf(util.ERROR_EVENT_NOT_ON_SALE_MSG, status),
+		}
+	}
+	if startsAt != nil && now.Before(*startsAt) {
+		return &model.BusinessError{
+			Code:    util.ERROR_EVENT_NOT_ON_SALE_CODE,
+			Message: fmt.Sprintf(util.ERROR_SALE_NOT_STARTED_MSG, startsAt.Format(util.TIMESTAMP_DEFAULT_FORMAT)),
+		}
+	}
+	if endsAt != nil && !now.Before(*endsAt) {
+		return &model.BusinessError{
+			Code:    util.ERROR_EVENT_NOT_ON_SALE_CODE,
+			Message: fmt.Sprintf(util.ERROR_SALE_ENDED_MSG, endsAt.Format(util.TIMESTAMP_DEFAULT_FORMAT)),
+		}
+	}
+	return nil
+}
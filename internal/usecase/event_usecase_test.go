@@ -0,0 +1,343 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/currency"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/repository"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEventPersister struct {
+	mock.Mock
+}
+
+func (m *MockEventPersister) GetEvents(ctx context.Context) ([]model.Event, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.Event), args.Error(1)
+}
+
+func (m *MockEventPersister) GetEventByID(ctx context.Context, eventID int) (model.Event, error) {
+	args := m.Called(ctx, eventID)
+	return args.Get(0).(model.Event), args.Error(1)
+}
+
+func (m *MockEventPersister) UpdateEventStatus(ctx context.Context, eventID int, from []string, to string) error {
+	args := m.Called(ctx, eventID, from, to)
+	return args.Error(0)
+}
+
+func (m *MockEventPersister) UpdateEventSaleWindow(ctx context.Context, eventID int, startsAt, endsAt *time.Time) error {
+	args := m.Called(ctx, eventID, startsAt, endsAt)
+	return args.Error(0)
+}
+
+func (m *MockEventPersister) UpdateEventResaleCap(ctx context.Context, eventID int, capPercent *int) error {
+	args := m.Called(ctx, eventID, capPercent)
+	return args.Error(0)
+}
+
+func (m *MockEventPersister) TransitionEvents(ctx context.Context, now time.Time) (model.EventTransitions, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(model.EventTransitions), args.Error(1)
+}
+
+func (m *MockEventPersister) CancelEvent(ctx context.Context, eventID int, reason string, at time.Time) error {
+	args := m.Called(ctx, eventID, reason, at)
+	return args.Error(0)
+}
+
+type MockPublisher struct {
+	mock.Mock
+}
+
+func (m *MockPublisher) Publish(ctx context.Context, topic string, message interface{}) error {
+	args := m.Called(ctx, topic, message)
+	return args.Error(0)
+}
+
+func TestUpdateEventStatus(t *testing.T) {
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	util.TimeNow = func() time.Time { return now }
+	defer func() { util.TimeNow = time.Now }()
+
+	t.Run("should publish a draft event", func(t *testing.T) {
+		eventRepo := new(MockEventPersister)
+		uc := NewEventUsecase(eventRepo, new(MockReservationPersister), new(MockIssuedTicketPersister), new(MockResalePersister), nil, new(MockPublisher), config.NewNopLogger())
+
+		eventRepo.On("GetEventByID", mock.Anything, 3).Return(model.Event{EventID: 3, Status: "draft", Date: now.Add(24 * time.Hour)}, nil).Once()
+		eventRepo.On("UpdateEventStatus", mock.Anything, 3, []string{"draft", "paused"}, "on_sale").Return(nil)
+		eventRepo.On("GetEventByID", mock.Anything, 3).Return(model.Event{EventID: 3, Status: "on_sale"}, nil).Once()
+
+		event, err := uc.UpdateEventStatus(context.Background(), 3, model.EventStatusRequest{Status: "on_sale"})
+		assert.NoError(t, err)
+		assert.Equal(t, "on_sale", event.Status)
+		eventRepo.AssertExpectations(t)
+	})
+
+	t.Run("should not resume a cancelled event", func(t *testing.T) {
+		eventRepo := new(MockEventPersister)
+		uc := NewEventUsecase(eventRepo, new(MockReservationPersister), new(MockIssuedTicketPersister), new(MockResalePersister), nil, new(MockPublisher), config.NewNopLogger())
+
+		eventRepo.On("GetEventByID", mock.Anything, 3).Return(model.Event{EventID: 3, Status: "cancelled", Date: now.Add(24 * time.Hour)}, nil)
+		eventRepo.On("UpdateEventStatus", mock.Anything, 3, []string{"draft", "paused"}, "on_sale").Return(sql.ErrNoRows)
+
+		_, err := uc.UpdateEventStatus(context.Background(), 3, model.EventStatusRequest{Status: "on_sale"})
+		assert.ErrorIs(t, err, ErrConflict)
+	})
+
+	t.Run("should not put a past event on sale", func(t *testing.T) {
+		eventRepo := new(MockEventPersister)
+		uc := NewEventUsecase(eventRepo, new(MockReservationPersister), new(MockIssuedTicketPersister), new(MockResalePersister), nil, new(MockPublisher), config.NewNopLogger())
+
+		eventRepo.On("GetEventByID", mock.Anything, 3).Return(model.Event{EventID: 3, Status: "paused", Date: now.Add(-time.Hour)}, nil)
+
+		_, err := uc.UpdateEventStatus(context.Background(), 3, model.EventStatusRequest{Status: "on_sale"})
+		assert.ErrorIs(t, err, ErrConflict)
+		eventRepo.AssertNotCalled(t, "UpdateEventStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateSaleWindow(t *testing.T) {
+	uc := NewEventUsecase(new(MockEventPersister), new(MockReservationPersister), new(MockIssuedTicketPersister), new(MockResalePersister), nil, new(MockPublisher), config.NewNopLogger())
+	startsAt := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	endsAt := startsAt.Add(-time.Hour)
+
+	_, err := uc.UpdateSaleWindow(context.Background(), 3, model.EventSaleWindowRequest{SaleStartsAt: &startsAt, SaleEndsAt: &endsAt})
+	assert.ErrorIs(t, err, ErrInvalidParam)
+}
+
+func TestSaleError(t *testing.T) {
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	before := now.Add(-time.Hour)
+	after := now.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		status   string
+		startsAt *time.Time
+		endsAt   *time.Time
+		onSale   bool
+	}{
+		{"on sale without window", "on_sale", nil, nil, true},
+		{"inside window", "on_sale", &before, &after, true},
+		{"window not open yet", "on_sale", &after, nil, false},
+		{"window closed at now", "on_sale", nil, &now, false},
+		{"paused", "paused", nil, nil, false},
+		{"draft", "draft", nil, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := saleError(tt.status, tt.startsAt, tt.endsAt, now)
+			if tt.onSale {
+				assert.NoError(t, err)
+				return
+			}
+			var businessErr *model.BusinessError
+			assert.ErrorAs(t, err, &businessErr)
+			assert.Equal(t, 4111, businessErr.Code)
+		})
+	}
+}
+
+func TestReserveTicketNotOnSale(t *testing.T) {
+	ticketRepo := new(MockTicketPersister)
+	reservationRepo := new(MockReservationPersister)
+	uc := NewTicketUsecase(ticketRepo, reservationRepo, new(MockSeatPersister), new(MockPromoPersister), new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), new(MockWaitlistOfferer), currency.NewStaticRates("", nil), config.NewNopLogger(), 0, 0)
+
+	reservationRepo.On("GetReservationByOrderID", mock.Anything, "order-1").Return(model.Reservation{}, sql.ErrNoRows)
+	ticketRepo.On("GetTicketPurchaseLimit", mock.Anything, 1).Return(model.TicketPurchaseLimit{TicketID: 1, EventID: 3, EventStatus: "paused"}, nil)
+
+	_, err := uc.ReserveTicket(context.Background(), model.MessageOrderTicket{TicketID: 1, Order: 1, OrderID: "order-1", Email: "user@mail.com"})
+	var businessErr *model.BusinessError
+	assert.ErrorAs(t, err, &businessErr)
+	assert.Equal(t, 4111, businessErr.Code)
+	ticketRepo.AssertNotCalled(t, "UpdateStockCreateOrderTicket", mock.Anything, 1, 1, mock.Anything)
+}
+
+func TestCancelEvent(t *testing.T) {
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	util.TimeNow = func() time.Time { return now }
+	defer func() { util.TimeNow = time.Now }()
+
+	price := model.Money{Amount: 150000, Currency: "IDR"}
+	pending := []model.Reservation{
+		{ReservationID: 1, OrderID: "order-1", TicketID: 2, EventID: 3, Email: "a@mail.com", Quantity: 2, UnitPrice: price, Status: "pending"},
+		{ReservationID: 2, TicketID: 2, EventID: 3, Email: "b@mail.com", Quantity: 1, UnitPrice: price, Status: "pending"},
+	}
+	confirmed := []model.Reservation{
+		{ReservationID: 3, OrderID: "order-3", TicketID: 2, EventID: 3, Email: "c@mail.com", Quantity: 2, UnitPrice: price,
+			Discount: model.Money{Amount: 30000, Currency: "IDR"}, Status: "confirmed"},
+	}
+
+	setup := func() (*MockEventPersister, *MockReservationPersister, *MockTicketPersister, *MockPublisher, EventExecutor,
+		*MockResalePersister, *MockIssuedTicketPersister) {
+		issuedTicketRepo := new(MockIssuedTicketPersister)
+		resaleRepo := new(MockResalePersister)
+		eventRepo := new(MockEventPersister)
+		reservationRepo := new(MockReservationPersister)
+		ticketRepo := new(MockTicketPersister)
+		seatRepo := new(MockSeatPersister)
+		promoRepo := new(MockPromoPersister)
+		publisher := new(MockPublisher)
+		waitlist := new(MockWaitlistOfferer)
+		ticketUsecase := NewTicketUsecase(ticketRepo, reservationRepo, seatRepo, promoRepo, new(MockIssuedTicketPersister), resaleRepo, new(MockWaitlistPersister), waitlist, currency.NewStaticRates("", nil), config.NewNopLogger(), 0, 0)
+		uc := NewEventUsecase(eventRepo, reservationRepo, issuedTicketRepo, resaleRepo, ticketUsecase, publisher, config.NewNopLogger())
+
+		issuedTicketRepo.On("VoidIssuedTicketsByReservationID", mock.Anything, mock.Anything).Return(nil)
+		seatRepo.On("ReleaseSeatsByOrderID", mock.Anything, mock.Anything).Return(nil)
+		promoRepo.On("ReleasePromoByOrderID", mock.Anything, mock.Anything).Return(nil)
+		waitlist.On("OfferFreedStock", mock.Anything, mock.Anything).Return(0, nil)
+		return eventRepo, reservationRepo, ticketRepo, publisher, uc, resaleRepo, issuedTicketRepo
+	}
+
+	t.Run("should release pending and refund confirmed reservations", func(t *testing.T) {
+		eventRepo, reservationRepo, ticketRepo, publisher, uc, resaleRepo, _ := setup()
+		eventRepo.On("GetEventByID", mock.Anything, 3).Return(model.Event{EventID: 3, Name: "Concert", Status: "on_sale"}, nil)
+		eventRepo.On("CancelEvent", mock.Anything, 3, "Venue closed", now).Return(nil)
+		reservationRepo.On("GetReservationsByEventAndStatus", mock.Anything, 3, "pending").Return(pending, nil)
+		reservationRepo.On("GetReservationsByEventAndStatus", mock.Anything, 3, "confirmed").Return(confirmed, nil)
+		resaleRepo.On("GetSoldListingsByReservationID", mock.Anything, 3).Return([]model.ResaleListing(nil), nil)
+		publisher.On("Publish", mock.Anything, "event-cancelled", mock.AnythingOfType("model.MessageEventCancelled")).Return(nil)
+		ticketRepo.On("UpdateStockFailOrderTicket", mock.Anything, 2, 2, "order-1").Return(nil)
+		ticketRepo.On("UpdateStockFailReservation", mock.Anything, 2, 2, 1).Return(nil)
+		reservationRepo.On("UpdateReservationStatus", mock.Anything, 3, "confirmed", "refund_pending").Return(nil)
+
+		cancellation, err := uc.CancelEvent(context.Background(), 3, model.EventCancelRequest{Reason: "Venue closed"})
+		assert.NoError(t, err)
+		assert.Equal(t, model.EventCancellation{EventID: 3, Status: "cancelled", Released: 2, Refunded: 1}, cancellation)
+		reservationRepo.AssertExpectations(t)
+		ticketRepo.AssertExpectations(t)
+
+		refund := publisher.Calls[2].Arguments.Get(2).(model.MessageEventCancelled)
+		assert.Equal(t, "refund", refund.Action)
+		assert.Equal(t, model.Money{Amount: 270000, Currency: "IDR"}, refund.Refund)
+		assert.Equal(t, "Venue closed", refund.Reason)
+		assert.Equal(t, now, refund.CancelledAt)
+	})
+
+	t.Run("should leave reservations settled meanwhile", func(t *testing.T) {
+		eventRepo, reservationRepo, ticketRepo, publisher, uc, _, _ := setup()
+		eventRepo.On("GetEventByID", mock.Anything, 3).Return(model.Event{EventID: 3, Status: "cancelled", CancelReason: "Venue closed", CancelledAt: &now}, nil)
+		reservationRepo.On("GetReservationsByEventAndStatus", mock.Anything, 3, "pending").Return(pending, nil)
+		reservationRepo.On("GetReservationsByEventAndStatus", mock.Anything, 3, "confirmed").Return([]model.Reservation(nil), nil)
+		publisher.On("Publish", mock.Anything, "event-cancelled", mock.AnythingOfType("model.MessageEventCancelled")).Return(nil)
+		ticketRepo.On("UpdateStockFailOrderTicket", mock.Anything, 2, 2, "order-1").Return(repository.ErrOrderSettled)
+		reservationRepo.On("GetReservationByOrderID", mock.Anything, "order-1").
+			Return(model.Reservation{ReservationID: 1, OrderID: "order-1", Status: "released"}, nil)
+		ticketRepo.On("UpdateStockFailReservation", mock.Anything, 2, 2, 1).Return(repository.ErrOrderSettled)
+
+		_, err := uc.CancelEvent(context.Background(), 3, model.EventCancelRequest{Reason: "Venue closed"})
+		assert.NoError(t, err)
+		ticketRepo.AssertExpectations(t)
+	})
+
+	t.Run("should put the listing of a pending resale back on sale", func(t *testing.T) {
+		eventRepo, reservationRepo, ticketRepo, publisher, uc, resaleRepo, _ := setup()
+		resale := model.Reservation{ReservationID: 6, OrderID: "order-6", TicketID: 2, EventID: 3, Email: "f@mail.com", Quantity: 1,
+			UnitPrice: model.Money{Amount: 180000, Currency: "IDR"}, PriceTier: "resale", Status: "pending"}
+		eventRepo.On("GetEventByID", mock.Anything, 3).Return(model.Event{EventID: 3, Status: "cancelled", CancelReason: "Venue closed", CancelledAt: &now}, nil)
+		reservationRepo.On("GetReservationsByEventAndStatus", mock.Anything, 3, "pending").Return([]model.Reservation{resale}, nil)
+		reservationRepo.On("GetReservationsByEventAndStatus", mock.Anything, 3, "confirmed").Return([]model.Reservation(nil), nil)
+		publisher.On("Publish", mock.Anything, "event-cancelled", mock.AnythingOfType("model.MessageEventCancelled")).Return(nil)
+		resaleRepo.On("GetListingByOrderID", mock.Anything, "order-6").
+			Return(model.ResaleListing{ListingID: 9, IssuedTicketID: 12, OrderID: "order-6", Status: "reserved"}, nil)
+		resaleRepo.On("ReleaseListing", mock.Anything, 9, "order-6").Return(nil)
+		reservationRepo.On("UpdateReservationStatus", mock.Anything, 6, "pending", "released").Return(nil)
+
+		cancellation, err := uc.CancelEvent(context.Background(), 3, model.EventCancelRequest{Reason: "Venue closed"})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, cancellation.Released)
+		resaleRepo.AssertExpectations(t)
+		reservationRepo.AssertExpectations(t)
+		ticketRepo.AssertNotCalled(t, "UpdateStockFailOrderTicket", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		ticketRepo.AssertNotCalled(t, "UpdateStockFailReservation", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should refund a resold ticket once, to its last buyer", func(t *testing.T) {
+		eventRepo, reservationRepo, _, publisher, uc, resaleRepo, issuedTicketRepo := setup()
+		resalePrice := model.Money{Amount: 180000, Currency: "IDR"}
+		resales := []model.Reservation{
+			{ReservationID: 4, OrderID: "order-4", TicketID: 2, EventID: 3, Email: "d@mail.com", Quantity: 1, UnitPrice: resalePrice,
+				PriceTier: "resale", Status: "confirmed"},
+			{ReservationID: 5, OrderID: "order-5", TicketID: 2, EventID: 3, Email: "e@mail.com", Quantity: 1, UnitPrice: resalePrice,
+				PriceTier: "resale", Status: "confirmed"},
+		}
+		sold := []model.ResaleListing{
+			{ListingID: 7, IssuedTicketID: 11, OrderID: "order-4", Status: "sold"},
+			{ListingID: 8, IssuedTicketID: 11, OrderID: "order-5", Status: "sold"},
+		}
+		eventRepo.On("GetEventByID", mock.Anything, 3).Return(model.Event{EventID: 3, Status: "cancelled", CancelReason: "Venue closed", CancelledAt: &now}, nil)
+		reservationRepo.On("GetReservationsByEventAndStatus", mock.Anything, 3, "pending").Return([]model.Reservation(nil), nil)
+		reservationRepo.On("GetReservationsByEventAndStatus", mock.Anything, 3, "confirmed").Return(append(confirmed, resales...), nil)
+		publisher.On("Publish", mock.Anything, "event-cancelled", mock.AnythingOfType("model.MessageEventCancelled")).Return(nil)
+		resaleRepo.On("GetSoldListingsByReservationID", mock.Anything, 3).Return(sold, nil)
+		resaleRepo.On("GetListingByOrderID", mock.Anything, "order-4").Return(sold[0], nil)
+		resaleRepo.On("GetListingByOrderID", mock.Anything, "order-5").Return(sold[1], nil)
+		issuedTicketRepo.On("GetIssuedTicketByID", mock.Anything, 11).Return(model.IssuedTicket{IssuedTicketID: 11, ReservationID: 3}, nil)
+		issuedTicketRepo.On("VoidIssuedTicket", mock.Anything, 11).Return(nil)
+		reservationRepo.On("UpdateReservationStatus", mock.Anything, mock.Anything, "confirmed", "refund_pending").Return(nil)
+
+		cancellation, err := uc.CancelEvent(context.Background(), 3, model.EventCancelRequest{Reason: "Venue closed"})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, cancellation.Refunded)
+		issuedTicketRepo.AssertCalled(t, "VoidIssuedTicketsByReservationID", mock.Anything, 3)
+		issuedTicketRepo.AssertNumberOfCalls(t, "VoidIssuedTicket", 2)
+
+		var refunds []model.Money
+		for _, call := range publisher.Calls {
+			refunds = append(refunds, call.Arguments.Get(2).(model.MessageEventCancelled).Refund)
+		}
+		assert.Equal(t, []model.Money{
+			{Amount: 135000, Currency: "IDR"},
+			{Amount: 0, Currency: "IDR"},
+			{Amount: 180000, Currency: "IDR"},
+		}, refunds)
+	})
+
+	t.Run("should resume an event that is already cancelled", func(t *testing.T) {
+		eventRepo, reservationRepo, _, publisher, uc, resaleRepo, _ := setup()
+		eventRepo.On("GetEventByID", mock.Anything, 3).Return(model.Event{EventID: 3, Status: "cancelled", CancelReason: "Venue closed", CancelledAt: &now}, nil)
+		reservationRepo.On("GetReservationsByEventAndStatus", mock.Anything, 3, "pending").Return([]model.Reservation(nil), nil)
+		reservationRepo.On("GetReservationsByEventAndStatus", mock.Anything, 3, "confirmed").Return(confirmed, nil)
+		resaleRepo.On("GetSoldListingsByReservationID", mock.Anything, 3).Return([]model.ResaleListing(nil), nil)
+		publisher.On("Publish", mock.Anything, "event-cancelled", mock.AnythingOfType("model.MessageEventCancelled")).Return(nil)
+		reservationRepo.On("UpdateReservationStatus", mock.Anything, 3, "confirmed", "refund_pending").Return(nil)
+
+		cancellation, err := uc.CancelEvent(context.Background(), 3, model.EventCancelRequest{Reason: "Other"})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, cancellation.Refunded)
+		eventRepo.AssertNotCalled(t, "CancelEvent", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject an event that already took place", func(t *testing.T) {
+		eventRepo, _, _, _, uc, _, _ := setup()
+		eventRepo.On("GetEventByID", mock.Anything, 3).Return(model.Event{EventID: 3, Status: "past"}, nil)
+		eventRepo.On("CancelEvent", mock.Anything, 3, "Venue closed", now).Return(sql.ErrNoRows)
+
+		_, err := uc.CancelEvent(context.Background(), 3, model.EventCancelRequest{Reason: "Venue closed"})
+		assert.ErrorIs(t, err, ErrConflict)
+	})
+
+	t.Run("should stop before releasing when publishing fails", func(t *testing.T) {
+		eventRepo, reservationRepo, ticketRepo, publisher, uc, _, _ := setup()
+		eventRepo.On("GetEventByID", mock.Anything, 3).Return(model.Event{EventID: 3, Status: "on_sale"}, nil)
+		eventRepo.On("CancelEvent", mock.Anything, 3, "Venue closed", now).Return(nil)
+		reservationRepo.On("GetReservationsByEventAndStatus", mock.Anything, 3, "pending").Return(pending, nil)
+		publisher.On("Publish", mock.Anything, "event-cancelled", mock.Anything).Return(errors.New("queue unavailable"))
+
+		cancellation, err := uc.CancelEvent(context.Background(), 3, model.EventCancelRequest{Reason: "Venue closed"})
+		assert.Error(t, err)
+		assert.Equal(t, 0, cancellation.Released)
+		ticketRepo.AssertNotCalled(t, "UpdateStockFailOrderTicket", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/repository"
+	"go.uber.org/zap"
+)
+
+type geographyUsecase struct {
+	geographyRepo repository.GeographyPersister
+	logger        config.Logger
+}
+
+// GeographyExecutor serves the canonical continent, country, city and venue
+// lists clients use to build location filters. Continents and countries are
+// looked up by their ISO codes, case-insensitively.
+type GeographyExecutor interface {
+	GetContinents(ctx context.Context) ([]model.Continent, error)
+	GetCountriesByContinent(ctx context.Context, continentCode string) ([]model.Country, error)
+	GetCitiesByCountry(ctx context.Context, countryCode string) ([]model.City, error)
+	GetVenuesByCity(ctx context.Context, cityID int) ([]model.Venue, error)
+}
+
+func NewGeographyUsecase(geographyRepo repository.GeographyPersister, logger config.Logger) GeographyExecutor {
+	return &geographyUsecase{geographyRepo: geographyRepo, logger: logger}
+}
+
+func (uc *geographyUsecase) GetContinents(ctx context.Context) ([]model.Continent, error) {
+	ctx, span := tracer.Start(ctx, "GeographyUsecase.GetContinents")
+	defer span.End()
+
+	continents, err := uc.geographyRepo.GetContinents(ctx)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting continents", zap.Error(err))
+		return continents, err
+	}
+
+	return continents, nil
+}
+
+func (uc *geographyUsecase) GetCountriesByContinent(ctx context.Context, continentCode string) ([]model.Country, error) {
+	ctx, span := tracer.Start(ctx, "GeographyUsecase.GetCountriesByContinent")
+	defer span.End()
+
+	continent, err := uc.geographyRepo.GetContinentByCode(ctx, strings.ToUpper(continentCode))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("continent %s: %w", continentCode, ErrNotFound)
+		}
+		uc.logger.WithContext(ctx).Error("Error when getting continent by code", zap.Error(err))
+		return nil, err
+	}
+
+	countries, err := uc.geographyRepo.GetCountriesByContinentID(ctx, continent.ContinentID)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting countries by continent", zap.Error(err))
+		return countries, err
+	}
+
+	return countries, nil
+}
+
+func (uc *geographyUsecase) GetCitiesByCountry(ctx context.Context, countryCode string) ([]model.City, error) {
+	ctx, span := tracer.Start(ctx, "GeographyUsecase.GetCitiesByCountry")
+	defer span.End()
+
+	country, err := uc.geographyRepo.GetCountryByCode(ctx, strings.ToUpper(countryCode))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("country %s: %w", countryCode, ErrNotFound)
+		}
+		uc.logger.WithContext(ctx).Error("Error when getting country by code", zap.Error(err))
+		return nil, err
+	}
+
+	cities, err := uc.geographyRepo.GetCitiesByCountryID(ctx, country.CountryID)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting cities by country", zap.Error(err))
+		return cities, err
+	}
+
+	return cities, nil
+}
+
+func (uc *geographyUsecase) GetVenuesByCity(ctx context.Context, cityID int) ([]model.Venue, error) {
+	ctx, span := tracer.Start(ctx, "GeographyUsecase.GetVenuesByCity")
+	defer span.End()
+
+	if _, err := uc.geographyRepo.GetCityByID(ctx, cityID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("city %d: %w", cityID, ErrNotFound)
+		}
+		uc.logger.WithContext(ctx).Error("Error when getting city by id", zap.Error(err))
+		return nil, err
+	}
+
+	venues, err := uc.geographyRepo.GetVenuesByCityID(ctx, cityID)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting venues by city", zap.Error(err))
+		return venues, err
+	}
+
+	return venues, nil
+}
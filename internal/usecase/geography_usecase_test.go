@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockGeographyPersister struct {
+	mock.Mock
+}
+
+func (m *MockGeographyPersister) GetContinents(ctx context.Context) ([]model.Continent, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.Continent), args.Error(1)
+}
+
+func (m *MockGeographyPersister) GetContinentByCode(ctx context.Context, code string) (model.Continent, error) {
+	args := m.Called(ctx, code)
+	return args.Get(0).(model.Continent), args.Error(1)
+}
+
+func (m *MockGeographyPersister) GetCountriesByContinentID(ctx context.Context, continentID int) ([]model.Country, error) {
+	args := m.Called(ctx, continentID)
+	return args.Get(0).([]model.Country), args.Error(1)
+}
+
+func (m *MockGeographyPersister) GetCountryByCode(ctx context.Context, code string) (model.Country, error) {
+	args := m.Called(ctx, code)
+	return args.Get(0).(model.Country), args.Error(1)
+}
+
+func (m *MockGeographyPersister) GetCitiesByCountryID(ctx context.Context, countryID int) ([]model.City, error) {
+	args := m.Called(ctx, countryID)
+	return args.Get(0).([]model.City), args.Error(1)
+}
+
+func (m *MockGeographyPersister) GetCityByID(ctx context.Context, cityID int) (model.City, error) {
+	args := m.Called(ctx, cityID)
+	return args.Get(0).(model.City), args.Error(1)
+}
+
+func (m *MockGeographyPersister) GetVenuesByCityID(ctx context.Context, cityID int) ([]model.Venue, error) {
+	args := m.Called(ctx, cityID)
+	return args.Get(0).([]model.Venue), args.Error(1)
+}
+
+func TestGeographyUsecase(t *testing.T) {
+	t.Run("countries by continent code in any case", func(t *testing.T) {
+		repo := new(MockGeographyPersister)
+		uc := NewGeographyUsecase(repo, config.NewNopLogger())
+
+		repo.On("GetContinentByCode", mock.Anything, "AS").Return(model.Continent{ContinentID: 3, Code: "AS", Name: "Asia"}, nil)
+		repo.On("GetCountriesByContinentID", mock.Anything, 3).Return([]model.Country{{CountryID: 1, ContinentID: 3, Code: "ID", Name: "Indonesia"}}, nil)
+
+		countries, err := uc.GetCountriesByContinent(context.Background(), "as")
+		assert.NoError(t, err)
+		assert.Len(t, countries, 1)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("unknown continent", func(t *testing.T) {
+		repo := new(MockGeographyPersister)
+		uc := NewGeographyUsecase(repo, config.NewNopLogger())
+
+		repo.On("GetContinentByCode", mock.Anything, "XX").Return(model.Continent{}, sql.ErrNoRows)
+
+		_, err := uc.GetCountriesByContinent(context.Background(), "xx")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("unknown country", func(t *testing.T) {
+		repo := new(MockGeographyPersister)
+		uc := NewGeographyUsecase(repo, config.NewNopLogger())
+
+		repo.On("GetCountryByCode", mock.Anything, "XX").Return(model.Country{}, sql.ErrNoRows)
+
+		_, err := uc.GetCitiesByCountry(context.Background(), "xx")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("venues by city", func(t *testing.T) {
+		repo := new(MockGeographyPersister)
+		uc := NewGeographyUsecase(repo, config.NewNopLogger())
+
+		repo.On("GetCityByID", mock.Anything, 7).Return(model.City{CityID: 7, CountryID: 1, Name: "Jakarta"}, nil)
+		repo.On("GetVenuesByCityID", mock.Anything, 7).Return([]model.Venue{{VenueID: 2, CityID: 7, Name: "Gelora Bung Karno"}}, nil)
+
+		venues, err := uc.GetVenuesByCity(context.Background(), 7)
+		assert.NoError(t, err)
+		assert.Equal(t, "Gelora Bung Karno", venues[0].Name)
+	})
+
+	t.Run("repository failure is not masked", func(t *testing.T) {
+		repo := new(MockGeographyPersister)
+		uc := NewGeographyUsecase(repo, config.NewNopLogger())
+
+		repo.On("GetCityByID", mock.Anything, 7).Return(model.City{}, errors.New("connection refused"))
+
+		_, err := uc.GetVenuesByCity(context.Background(), 7)
+		assert.EqualError(t, err, "connection refused")
+	})
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/repository"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"go.uber.org/zap"
+)
+
+type inventoryLedgerUsecase struct {
+	ledgerRepo repository.InventoryLedgerPersister
+	logger     config.Logger
+}
+
+// InventoryLedgerExecutor shows the stock changes of a ticket and checks
+// that they add up to its current counters.
+type InventoryLedgerExecutor interface {
+	GetTicketLedger(ctx context.Context, ticketID, limit, offset int) (model.InventoryLedger, error)
+}
+
+func NewInventoryLedgerUsecase(ledgerRepo repository.InventoryLedgerPersister, logger config.Logger) InventoryLedgerExecutor {
+	return &inventoryLedgerUsecase{ledgerRepo: ledgerRepo, logger: logger}
+}
+
+// GetTicketLedger returns a page of the ledger of the ticket, oldest first,
+// with the counters recomputed from the whole ledger. A limit of zero or less
+// uses the default page size.
+func (uc *inventoryLedgerUsecase) GetTicketLedger(ctx context.Context, ticketID, limit, offset int) (model.InventoryLedger, error) {
+	ctx, span := tracer.Start(ctx, "InventoryLedgerUsecase.GetTicketLedger")
+	defer span.End()
+
+	var ledger model.InventoryLedger
+	if offset < 0 {
+		return ledger, fmt.Errorf("offset must not be negative: %w", ErrInvalidParam)
+	}
+	if limit <= 0 {
+		limit = util.DEFAULT_LIMIT_PAGINATION
+	}
+
+	balance, err := uc.ledgerRepo.GetLedgerBalance(ctx, ticketID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ledger, fmt.Errorf("ticket %d: %w", ticketID, ErrNotFound)
+		}
+		uc.logger.WithContext(ctx).Error("Error when getting inventory ledger balance", zap.Error(err))
+		return ledger, err
+	}
+
+	entries, err := uc.ledgerRepo.GetLedgerEntries(ctx, ticketID, limit, offset)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting inventory ledger entries", zap.Error(err))
+		return ledger, err
+	}
+
+	ledger = model.InventoryLedger{LedgerBalance: balance, Consistent: balance.Consistent(), Entries: entries}
+	if !ledger.Consistent {
+		uc.logger.WithContext(ctx).Info("Inventory ledger does not match stock counters", zap.Int("ticket_id", ticketID),
+			zap.Any("current", balance.Current), zap.Any("recomputed", balance.Recomputed))
+	}
+	return ledger, nil
+}
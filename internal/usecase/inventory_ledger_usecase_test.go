@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockInventoryLedgerPersister struct {
+	mock.Mock
+}
+
+func (m *MockInventoryLedgerPersister) GetLedgerBalance(ctx context.Context, ticketID int) (model.LedgerBalance, error) {
+	args := m.Called(ctx, ticketID)
+	return args.Get(0).(model.LedgerBalance), args.Error(1)
+}
+
+func (m *MockInventoryLedgerPersister) GetLedgerEntries(ctx context.Context, ticketID, limit, offset int) ([]model.InventoryLedgerEntry, error) {
+	args := m.Called(ctx, ticketID, limit, offset)
+	return args.Get(0).([]model.InventoryLedgerEntry), args.Error(1)
+}
+
+func (m *MockInventoryLedgerPersister) GetStockSnapshots(ctx context.Context, ticketIDs []int) ([]model.StockSnapshot, error) {
+	args := m.Called(ctx, ticketIDs)
+	return args.Get(0).([]model.StockSnapshot), args.Error(1)
+}
+
+func (m *MockInventoryLedgerPersister) RepairStock(ctx context.Context, checked model.StockSnapshot, counters model.StockCounters) error {
+	args := m.Called(ctx, checked, counters)
+	return args.Error(0)
+}
+
+func TestGetTicketLedger(t *testing.T) {
+	counters := model.StockCounters{Total: 100, Available: 98, Held: 2}
+	entries := []model.InventoryLedgerEntry{
+		{EntryID: 1, TicketID: 2, Change: model.StockCounters{Total: 100, Available: 100}, Reason: "opening", Actor: "migration"},
+		{EntryID: 2, TicketID: 2, Change: model.ReserveStock(2), Reason: "reserve", OrderID: "order-1", Actor: "user:user@mail.com"},
+	}
+
+	t.Run("should report a ledger that adds up", func(t *testing.T) {
+		ledgerRepo := new(MockInventoryLedgerPersister)
+		ledgerRepo.On("GetLedgerBalance", mock.Anything, 2).
+			Return(model.LedgerBalance{TicketID: 2, Current: counters, Recomputed: counters, EntryCount: 2}, nil)
+		ledgerRepo.On("GetLedgerEntries", mock.Anything, 2, 10, 0).Return(entries, nil)
+		uc := NewInventoryLedgerUsecase(ledgerRepo, config.NewNopLogger())
+
+		ledger, err := uc.GetTicketLedger(context.Background(), 2, 0, 0)
+		assert.NoError(t, err)
+		assert.True(t, ledger.Consistent)
+		assert.Equal(t, 2, ledger.EntryCount)
+		assert.Equal(t, entries, ledger.Entries)
+		ledgerRepo.AssertExpectations(t)
+	})
+
+	t.Run("should flag counters that drifted from the ledger", func(t *testing.T) {
+		ledgerRepo := new(MockInventoryLedgerPersister)
+		drifted := counters
+		drifted.Available, drifted.Held = 96, 4
+		ledgerRepo.On("GetLedgerBalance", mock.Anything, 2).
+			Return(model.LedgerBalance{TicketID: 2, Current: drifted, Recomputed: counters, EntryCount: 2}, nil)
+		ledgerRepo.On("GetLedgerEntries", mock.Anything, 2, 1, 1).Return(entries[1:], nil)
+		uc := NewInventoryLedgerUsecase(ledgerRepo, config.NewNopLogger())
+
+		ledger, err := uc.GetTicketLedger(context.Background(), 2, 1, 1)
+		assert.NoError(t, err)
+		assert.False(t, ledger.Consistent)
+		assert.Equal(t, drifted, ledger.Current)
+	})
+
+	t.Run("should report an unknown ticket as not found", func(t *testing.T) {
+		ledgerRepo := new(MockInventoryLedgerPersister)
+		ledgerRepo.On("GetLedgerBalance", mock.Anything, 2).Return(model.LedgerBalance{}, sql.ErrNoRows)
+		uc := NewInventoryLedgerUsecase(ledgerRepo, config.NewNopLogger())
+
+		_, err := uc.GetTicketLedger(context.Background(), 2, 10, 0)
+		assert.ErrorIs(t, err, ErrNotFound)
+		ledgerRepo.AssertNotCalled(t, "GetLedgerEntries", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject a negative offset", func(t *testing.T) {
+		uc := NewInventoryLedgerUsecase(new(MockInventoryLedgerPersister), config.NewNopLogger())
+
+		_, err := uc.GetTicketLedger(context.Background(), 2, 10, -1)
+		assert.ErrorIs(t, err, ErrInvalidParam)
+	})
+}
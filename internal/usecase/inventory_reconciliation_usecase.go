@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/repository"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"go.uber.org/zap"
+)
+
+type inventoryReconciliationUsecase struct {
+	ledgerRepo repository.InventoryLedgerPersister
+	logger     config.Logger
+}
+
+// InventoryReconciliationExecutor checks the stock counters of every ticket
+// against the orders, waitlist offers and inventory ledger behind them, and
+// repairs the tickets a check found drifted. Repairs are never made without
+// naming the tickets and the counters a dry run reported for them, so every
+// repair follows a dry run and sets what the operator saw in it.
+type InventoryReconciliationExecutor interface {
+	CheckInventory(ctx context.Context) (model.InventoryReport, error)
+	RepairInventory(ctx context.Context, request model.InventoryRepairRequest) (model.InventoryReport, error)
+}
+
+func NewInventoryReconciliationUsecase(ledgerRepo repository.InventoryLedgerPersister, logger config.Logger) InventoryReconciliationExecutor {
+	return &inventoryReconciliationUsecase{ledgerRepo: ledgerRepo, logger: logger}
+}
+
+// CheckInventory is the dry run: it reports every ticket that breaks a stock
+// invariant and changes nothing.
+func (uc *inventoryReconciliationUsecase) CheckInventory(ctx context.Context) (model.InventoryReport, error) {
+	ctx, span := tracer.Start(ctx, "InventoryReconciliationUsecase.CheckInventory")
+	defer span.End()
+
+	report := model.InventoryReport{DryRun: true, Tickets: []model.InventoryDrift{}}
+
+	snapshots, err := uc.ledgerRepo.GetStockSnapshots(ctx, nil)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting stock snapshots", zap.Error(err))
+		return report, err
+	}
+
+	report.Checked = len(snapshots)
+	for _, snapshot := range snapshots {
+		drift := checkStock(snapshot)
+		if drift.Status == util.INVENTORY_STATUS_CONSISTENT {
+			continue
+		}
+		report.Drifted++
+		report.Tickets = append(report.Tickets, drift)
+		uc.logger.WithContext(ctx).Info("Ticket stock drifted", zap.Int("ticket_id", drift.TicketID),
+			zap.String("status", drift.Status), zap.Any("violations", drift.Violations))
+	}
+	inventoryDriftedTickets.Set(float64(report.Drifted))
+
+	return report, nil
+}
+
+// RepairInventory sets the counters of the requested tickets to the expected
+// ones. A ticket is only repaired while its counters and the expected ones
+// are still those of the dry run the request was taken from. Tickets that
+// turn out consistent, can not be repaired or changed since they were checked
+// are left alone and listed with their status.
+func (uc *inventoryReconciliationUsecase) RepairInventory(ctx context.Context, request model.InventoryRepairRequest) (model.InventoryReport, error) {
+	ctx, span := tracer.Start(ctx, "InventoryReconciliationUsecase.RepairInventory")
+	defer span.End()
+
+	report := model.InventoryReport{Tickets: []model.InventoryDrift{}}
+
+	seen, err := seenTickets(request.Tickets)
+	if err != nil {
+		return report, err
+	}
+	ticketIDs := make([]int, 0, len(seen))
+	for ticketID := range seen {
+		ticketIDs = append(ticketIDs, ticketID)
+	}
+	sort.Ints(ticketIDs)
+
+	snapshots, err := uc.ledgerRepo.GetStockSnapshots(ctx, ticketIDs)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting stock snapshots", zap.Error(err))
+		return report, err
+	}
+	if len(snapshots) != len(ticketIDs) {
+		return report, fmt.Errorf("tickets %v: %w", missingTicketIDs(ticketIDs, snapshots), ErrNotFound)
+	}
+
+	report.Checked = len(snapshots)
+	for _, snapshot := range snapshots {
+		drift := checkStock(snapshot)
+		if ticket := seen[drift.TicketID]; drift.Status != util.INVENTORY_STATUS_CONSISTENT &&
+			(drift.Current != ticket.Current || drift.Expected != ticket.Expected) {
+			// the ticket is no longer what the dry run reported, so the
+			// operator has not seen the counters a repair would set.
+			report.Drifted++
+			drift.Status = util.INVENTORY_STATUS_CHANGED
+		} else if drift.Status == util.INVENTORY_STATUS_DRIFTED {
+			report.Drifted++
+			err := uc.ledgerRepo.RepairStock(ctx, snapshot, drift.Expected)
+			switch {
+			case errors.Is(err, repository.ErrStockChanged):
+				drift.Status = util.INVENTORY_STATUS_CHANGED
+			case err != nil:
+				uc.logger.WithContext(ctx).Error("Error when repairing ticket stock", zap.Int("ticket_id", drift.TicketID), zap.Error(err))
+				return report, err
+			default:
+				drift.Status = util.INVENTORY_STATUS_REPAIRED
+				report.Repaired++
+				uc.logger.WithContext(ctx).Info("Repaired ticket stock", zap.Int("ticket_id", drift.TicketID),
+					zap.Any("from", drift.Current), zap.Any("to", drift.Expected))
+			}
+		} else if drift.Status == util.INVENTORY_STATUS_UNREPAIRABLE {
+			report.Drifted++
+		}
+		report.Tickets = append(report.Tickets, drift)
+	}
+
+	return report, nil
+}
+
+// checkStock checks snapshot against the stock invariants of
+// model.StockCounters and against the orders and offers behind the counts:
+// held must be what unpaid orders and open offers take and sold what paid
+// orders took, counting orders without a reservation by their ledger. The
+// repair keeps the total, so what is neither held nor sold is available. The
+// ledger must add up to the counters as well.
+func checkStock(snapshot model.StockSnapshot) model.InventoryDrift {
+	current := snapshot.Current
+	held := snapshot.OrdersHeld + snapshot.LegacyHeld
+	sold := snapshot.OrdersSold + snapshot.LegacySold
+	drift := model.InventoryDrift{
+		StockSnapshot: snapshot,
+		Status:        util.INVENTORY_STATUS_CONSISTENT,
+		Expected: model.StockCounters{
+			Total:     current.Total,
+			Available: current.Total - held - sold,
+			Held:      held,
+			Sold:      sold,
+		},
+	}
+
+	if current.Available < 0 || current.Held < 0 || current.Sold < 0 {
+		drift.Violations = append(drift.Violations, model.InventoryViolation{
+			Invariant: util.INVENTORY_INVARIANT_NON_NEGATIVE,
+			Detail: fmt.Sprintf("available %d, held %d and sold %d can not be negative",
+				current.Available, current.Held, current.Sold),
+		})
+	}
+	if !current.Balanced() {
+		drift.Violations = append(drift.Violations, model.InventoryViolation{
+			Invariant: util.INVENTORY_INVARIANT_BALANCED,
+			Detail: fmt.Sprintf("total is %d but available %d, held %d and sold %d add up to %d",
+				current.Total, current.Available, current.Held, current.Sold, current.Available+current.Held+current.Sold),
+		})
+	}
+	if current.Held != held {
+		drift.Violations = append(drift.Violations, model.InventoryViolation{
+			Invariant: util.INVENTORY_INVARIANT_HELD,
+			Detail:    fmt.Sprintf("held is %d but orders and offers hold %d", current.Held, held),
+		})
+	}
+	if current.Sold != sold {
+		drift.Violations = append(drift.Violations, model.InventoryViolation{
+			Invariant: util.INVENTORY_INVARIANT_SOLD,
+			Detail:    fmt.Sprintf("sold is %d but orders sold %d", current.Sold, sold),
+		})
+	}
+	if current != snapshot.Ledger {
+		drift.Violations = append(drift.Violations, model.InventoryViolation{
+			Invariant: util.INVENTORY_INVARIANT_LEDGER,
+			Detail: fmt.Sprintf("ledger adds up to total %d, available %d, held %d and sold %d",
+				snapshot.Ledger.Total, snapshot.Ledger.Available, snapshot.Ledger.Held, snapshot.Ledger.Sold),
+		})
+	}
+
+	switch {
+	case len(drift.Violations) == 0:
+	case !drift.Expected.Valid():
+		// orders take more than the total, which only a person can sort out.
+		drift.Status = util.INVENTORY_STATUS_UNREPAIRABLE
+	case snapshot.OpeningHeld > 0 && held < current.Held, snapshot.OpeningSold > 0 && sold < current.Sold:
+		// the stock the ledger opened with may be taken by orders from before
+		// it that have no reservation, so it is not freed without a person.
+		drift.Status = util.INVENTORY_STATUS_UNREPAIRABLE
+	default:
+		drift.Status = util.INVENTORY_STATUS_DRIFTED
+	}
+	return drift
+}
+
+// seenTickets indexes the tickets of a repair request by id. A ticket may be
+// named twice, but only with the same counters.
+func seenTickets(tickets []model.InventoryRepairTicket) (map[int]model.InventoryRepairTicket, error) {
+	seen := make(map[int]model.InventoryRepairTicket, len(tickets))
+	for _, ticket := range tickets {
+		if other, ok := seen[ticket.TicketID]; ok && other != ticket {
+			return nil, fmt.Errorf("ticket %d is named with different counters: %w", ticket.TicketID, ErrInvalidParam)
+		}
+		seen[ticket.TicketID] = ticket
+	}
+	return seen, nil
+}
+
+func missingTicketIDs(ticketIDs []int, snapshots []model.StockSnapshot) []int {
+	found := make(map[int]bool, len(snapshots))
+	for _, snapshot := range snapshots {
+		found[snapshot.TicketID] = true
+	}
+	var missing []int
+	for _, ticketID := range ticketIDs {
+		if !found[ticketID] {
+			missing = append(missing, ticketID)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,365 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"testing/quick"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/repository"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCheckStock(t *testing.T) {
+	tests := []struct {
+		name       string
+		snapshot   model.StockSnapshot
+		status     string
+		invariants []string
+		expected   model.StockCounters
+	}{
+		{
+			name: "counts match the orders",
+			snapshot: model.StockSnapshot{
+				Current:    model.StockCounters{Total: 100, Available: 93, Held: 2, Sold: 5},
+				Ledger:     model.StockCounters{Total: 100, Available: 93, Held: 2, Sold: 5},
+				OrdersHeld: 2, OrdersSold: 5,
+			},
+			status:   "consistent",
+			expected: model.StockCounters{Total: 100, Available: 93, Held: 2, Sold: 5},
+		},
+		{
+			name: "a release applied twice",
+			snapshot: model.StockSnapshot{
+				Current: model.StockCounters{Total: 100, Available: 102, Held: -2},
+				Ledger:  model.StockCounters{Total: 100, Available: 102, Held: -2},
+			},
+			status:     "drifted",
+			invariants: []string{"non_negative", "held_matches_orders"},
+			expected:   model.StockCounters{Total: 100, Available: 100},
+		},
+		{
+			name: "counts changed by hand",
+			snapshot: model.StockSnapshot{
+				Current: model.StockCounters{Total: 100, Available: 98, Sold: 2},
+				Ledger:  model.StockCounters{Total: 100, Available: 100},
+			},
+			status:     "drifted",
+			invariants: []string{"sold_matches_orders", "ledger_matches_counters"},
+			expected:   model.StockCounters{Total: 100, Available: 100},
+		},
+		{
+			name: "a sale that did not leave available",
+			snapshot: model.StockSnapshot{
+				Current:    model.StockCounters{Total: 100, Available: 100, Sold: 2},
+				Ledger:     model.StockCounters{Total: 100, Available: 100, Sold: 2},
+				OrdersSold: 2,
+			},
+			status:     "drifted",
+			invariants: []string{"total_matches_counts"},
+			expected:   model.StockCounters{Total: 100, Available: 98, Sold: 2},
+		},
+		{
+			name: "a paid legacy order without a reservation",
+			snapshot: model.StockSnapshot{
+				Current:    model.StockCounters{Total: 100, Available: 95, Held: 1, Sold: 4},
+				Ledger:     model.StockCounters{Total: 100, Available: 95, Held: 1, Sold: 4},
+				OrdersHeld: 1, OrdersSold: 1, LegacySold: 3,
+			},
+			status:   "consistent",
+			expected: model.StockCounters{Total: 100, Available: 95, Held: 1, Sold: 4},
+		},
+		{
+			name: "a legacy order released by hand",
+			snapshot: model.StockSnapshot{
+				Current:    model.StockCounters{Total: 100, Available: 100},
+				Ledger:     model.StockCounters{Total: 100, Available: 100},
+				LegacySold: 3,
+			},
+			status:     "drifted",
+			invariants: []string{"sold_matches_orders"},
+			expected:   model.StockCounters{Total: 100, Available: 97, Sold: 3},
+		},
+		{
+			name: "sold stock migrated without its orders",
+			snapshot: model.StockSnapshot{
+				Current:     model.StockCounters{Total: 100, Available: 90, Sold: 10},
+				Ledger:      model.StockCounters{Total: 100, Available: 90, Sold: 10},
+				OrdersSold:  2,
+				OpeningSold: 8,
+			},
+			status:     "unrepairable",
+			invariants: []string{"sold_matches_orders"},
+			expected:   model.StockCounters{Total: 100, Available: 98, Sold: 2},
+		},
+		{
+			name: "held stock migrated without its orders",
+			snapshot: model.StockSnapshot{
+				Current:     model.StockCounters{Total: 100, Available: 96, Held: 4},
+				Ledger:      model.StockCounters{Total: 100, Available: 96, Held: 4},
+				OpeningHeld: 4,
+			},
+			status:     "unrepairable",
+			invariants: []string{"held_matches_orders"},
+			expected:   model.StockCounters{Total: 100, Available: 100},
+		},
+		{
+			name: "migrated stock short of what orders sold",
+			snapshot: model.StockSnapshot{
+				Current:     model.StockCounters{Total: 100, Available: 92, Sold: 8},
+				Ledger:      model.StockCounters{Total: 100, Available: 92, Sold: 8},
+				OrdersSold:  10,
+				OpeningSold: 8,
+			},
+			status:     "drifted",
+			invariants: []string{"sold_matches_orders"},
+			expected:   model.StockCounters{Total: 100, Available: 90, Sold: 10},
+		},
+		{
+			name: "more held than the total",
+			snapshot: model.StockSnapshot{
+				Current:    model.StockCounters{Total: 1, Held: 1},
+				Ledger:     model.StockCounters{Total: 1, Held: 1},
+				OrdersHeld: 3,
+			},
+			status:     "unrepairable",
+			invariants: []string{"held_matches_orders"},
+			expected:   model.StockCounters{Total: 1, Available: -2, Held: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drift := checkStock(tt.snapshot)
+			assert.Equal(t, tt.status, drift.Status)
+			assert.Equal(t, tt.expected, drift.Expected)
+			var invariants []string
+			for _, violation := range drift.Violations {
+				invariants = append(invariants, violation.Invariant)
+			}
+			assert.Equal(t, tt.invariants, invariants)
+		})
+	}
+}
+
+// orderStep is one random step of the orders of a ticket: a new order of
+// Quantity, or the payment or failure of the oldest unpaid one.
+type orderStep struct {
+	Kind     uint8
+	Quantity uint8
+}
+
+func TestCheckStockProperties(t *testing.T) {
+	// replay runs steps against a ticket of total the way the repository
+	// does, refusing what the counts do not allow, and returns its snapshot.
+	replay := func(total uint8, steps []orderStep) model.StockSnapshot {
+		counts := model.StockCounters{Total: int(total), Available: int(total)}
+		snapshot := model.StockSnapshot{TicketID: 1, Ledger: counts}
+		var unpaid []int
+		for _, step := range steps {
+			var change model.StockCounters
+			switch step.Kind % 3 {
+			case 0:
+				change = model.ReserveStock(int(step.Quantity%5) + 1)
+			case 1, 2:
+				if len(unpaid) == 0 {
+					continue
+				}
+				change = model.ConfirmStock(unpaid[0])
+				if step.Kind%3 == 2 {
+					change = model.ReleaseStock(unpaid[0])
+				}
+			}
+			next, ok := counts.Apply(change)
+			if !ok {
+				continue
+			}
+			switch step.Kind % 3 {
+			case 0:
+				unpaid = append(unpaid, change.Held)
+			case 1:
+				snapshot.OrdersSold += unpaid[0]
+				unpaid = unpaid[1:]
+			case 2:
+				unpaid = unpaid[1:]
+			}
+			counts = next
+			snapshot.Ledger = snapshot.Ledger.Add(change)
+		}
+		for _, quantity := range unpaid {
+			snapshot.OrdersHeld += quantity
+		}
+		snapshot.Current = counts
+		return snapshot
+	}
+
+	t.Run("should find any history consistent", func(t *testing.T) {
+		property := func(total uint8, steps []orderStep) bool {
+			drift := checkStock(replay(total, steps))
+			return drift.Status == util.INVENTORY_STATUS_CONSISTENT && drift.Expected == drift.Current
+		}
+		assert.NoError(t, quick.Check(property, nil))
+	})
+
+	t.Run("should repair counts moved between available, held and sold", func(t *testing.T) {
+		property := func(total uint8, steps []orderStep, toHeld, toSold int8) bool {
+			snapshot := replay(total, steps)
+			counts := snapshot.Current
+			snapshot.Current.Held += int(toHeld)
+			snapshot.Current.Sold += int(toSold)
+			snapshot.Current.Available -= int(toHeld) + int(toSold)
+
+			drift := checkStock(snapshot)
+			if snapshot.Current == counts {
+				return drift.Status == util.INVENTORY_STATUS_CONSISTENT
+			}
+			return drift.Status == util.INVENTORY_STATUS_DRIFTED && drift.Expected == counts
+		}
+		assert.NoError(t, quick.Check(property, nil))
+	})
+}
+
+func TestCheckInventory(t *testing.T) {
+	consistent := model.StockSnapshot{TicketID: 1, Current: model.StockCounters{Total: 10, Available: 10},
+		Ledger: model.StockCounters{Total: 10, Available: 10}}
+	drifted := model.StockSnapshot{TicketID: 2, Current: model.StockCounters{Total: 10, Available: 8, Held: 2},
+		Ledger: model.StockCounters{Total: 10, Available: 8, Held: 2}}
+
+	ledgerRepo := new(MockInventoryLedgerPersister)
+	ledgerRepo.On("GetStockSnapshots", mock.Anything, []int(nil)).Return([]model.StockSnapshot{consistent, drifted}, nil)
+	uc := NewInventoryReconciliationUsecase(ledgerRepo, config.NewNopLogger())
+
+	report, err := uc.CheckInventory(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 2, report.Checked)
+	assert.Equal(t, 1, report.Drifted)
+	assert.Len(t, report.Tickets, 1)
+	assert.Equal(t, 2, report.Tickets[0].TicketID)
+	ledgerRepo.AssertNotCalled(t, "RepairStock", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRepairInventory(t *testing.T) {
+	drifted := model.StockSnapshot{TicketID: 2, Current: model.StockCounters{Total: 10, Available: 8, Held: 2},
+		Ledger: model.StockCounters{Total: 10, Available: 8, Held: 2}}
+	consistent := model.StockSnapshot{TicketID: 3, Current: model.StockCounters{Total: 10, Available: 10},
+		Ledger: model.StockCounters{Total: 10, Available: 10}}
+
+	// seenAs is a ticket of a repair request as the dry run reported it.
+	seenAs := func(snapshot model.StockSnapshot) model.InventoryRepairTicket {
+		drift := checkStock(snapshot)
+		return model.InventoryRepairTicket{TicketID: drift.TicketID, Current: drift.Current, Expected: drift.Expected}
+	}
+
+	t.Run("should repair the drifted tickets only", func(t *testing.T) {
+		ledgerRepo := new(MockInventoryLedgerPersister)
+		ledgerRepo.On("GetStockSnapshots", mock.Anything, []int{2, 3}).Return([]model.StockSnapshot{drifted, consistent}, nil)
+		ledgerRepo.On("RepairStock", mock.Anything, drifted, model.StockCounters{Total: 10, Available: 10}).Return(nil)
+		uc := NewInventoryReconciliationUsecase(ledgerRepo, config.NewNopLogger())
+
+		report, err := uc.RepairInventory(context.Background(), model.InventoryRepairRequest{
+			Tickets: []model.InventoryRepairTicket{seenAs(consistent), seenAs(drifted), seenAs(consistent)},
+		})
+		assert.NoError(t, err)
+		assert.False(t, report.DryRun)
+		assert.Equal(t, 1, report.Repaired)
+		assert.Equal(t, "repaired", report.Tickets[0].Status)
+		assert.Equal(t, "consistent", report.Tickets[1].Status)
+		ledgerRepo.AssertExpectations(t)
+	})
+
+	t.Run("should leave tickets that changed since the check", func(t *testing.T) {
+		ledgerRepo := new(MockInventoryLedgerPersister)
+		ledgerRepo.On("GetStockSnapshots", mock.Anything, []int{2}).Return([]model.StockSnapshot{drifted}, nil)
+		ledgerRepo.On("RepairStock", mock.Anything, drifted, mock.Anything).Return(repository.ErrStockChanged)
+		uc := NewInventoryReconciliationUsecase(ledgerRepo, config.NewNopLogger())
+
+		report, err := uc.RepairInventory(context.Background(), model.InventoryRepairRequest{
+			Tickets: []model.InventoryRepairTicket{seenAs(drifted)},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, report.Repaired)
+		assert.Equal(t, "changed", report.Tickets[0].Status)
+	})
+
+	t.Run("should leave tickets that no longer match the dry run", func(t *testing.T) {
+		seen := seenAs(drifted)
+		seen.Current.Held = 3
+		seen.Current.Available = 7
+		ledgerRepo := new(MockInventoryLedgerPersister)
+		ledgerRepo.On("GetStockSnapshots", mock.Anything, []int{2}).Return([]model.StockSnapshot{drifted}, nil)
+		uc := NewInventoryReconciliationUsecase(ledgerRepo, config.NewNopLogger())
+
+		report, err := uc.RepairInventory(context.Background(), model.InventoryRepairRequest{
+			Tickets: []model.InventoryRepairTicket{seen},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Drifted)
+		assert.Equal(t, 0, report.Repaired)
+		assert.Equal(t, "changed", report.Tickets[0].Status)
+		ledgerRepo.AssertNotCalled(t, "RepairStock", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject a ticket named with different counters", func(t *testing.T) {
+		seen := seenAs(drifted)
+		seen.Expected.Held = 1
+		seen.Expected.Available = 9
+		ledgerRepo := new(MockInventoryLedgerPersister)
+		uc := NewInventoryReconciliationUsecase(ledgerRepo, config.NewNopLogger())
+
+		_, err := uc.RepairInventory(context.Background(), model.InventoryRepairRequest{
+			Tickets: []model.InventoryRepairTicket{seenAs(drifted), seen},
+		})
+		assert.ErrorIs(t, err, ErrInvalidParam)
+		ledgerRepo.AssertNotCalled(t, "GetStockSnapshots", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should leave sold stock the ledger opened with", func(t *testing.T) {
+		migrated := model.StockSnapshot{TicketID: 4, Current: model.StockCounters{Total: 10, Available: 5, Sold: 5},
+			Ledger: model.StockCounters{Total: 10, Available: 5, Sold: 5}, OpeningSold: 5}
+		ledgerRepo := new(MockInventoryLedgerPersister)
+		ledgerRepo.On("GetStockSnapshots", mock.Anything, []int{4}).Return([]model.StockSnapshot{migrated}, nil)
+		uc := NewInventoryReconciliationUsecase(ledgerRepo, config.NewNopLogger())
+
+		report, err := uc.RepairInventory(context.Background(), model.InventoryRepairRequest{
+			Tickets: []model.InventoryRepairTicket{seenAs(migrated)},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Drifted)
+		assert.Equal(t, 0, report.Repaired)
+		assert.Equal(t, "unrepairable", report.Tickets[0].Status)
+		ledgerRepo.AssertNotCalled(t, "RepairStock", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should keep the sold stock of a legacy order", func(t *testing.T) {
+		legacy := model.StockSnapshot{TicketID: 5, Current: model.StockCounters{Total: 10, Available: 6, Held: 2, Sold: 2},
+			Ledger: model.StockCounters{Total: 10, Available: 6, Held: 2, Sold: 2}, LegacySold: 2}
+		ledgerRepo := new(MockInventoryLedgerPersister)
+		ledgerRepo.On("GetStockSnapshots", mock.Anything, []int{5}).Return([]model.StockSnapshot{legacy}, nil)
+		ledgerRepo.On("RepairStock", mock.Anything, legacy, model.StockCounters{Total: 10, Available: 8, Sold: 2}).Return(nil)
+		uc := NewInventoryReconciliationUsecase(ledgerRepo, config.NewNopLogger())
+
+		report, err := uc.RepairInventory(context.Background(), model.InventoryRepairRequest{
+			Tickets: []model.InventoryRepairTicket{seenAs(legacy)},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Repaired)
+		ledgerRepo.AssertExpectations(t)
+	})
+
+	t.Run("should report unknown tickets as not found", func(t *testing.T) {
+		ledgerRepo := new(MockInventoryLedgerPersister)
+		ledgerRepo.On("GetStockSnapshots", mock.Anything, []int{2, 9}).Return([]model.StockSnapshot{drifted}, nil)
+		uc := NewInventoryReconciliationUsecase(ledgerRepo, config.NewNopLogger())
+
+		_, err := uc.RepairInventory(context.Background(), model.InventoryRepairRequest{
+			Tickets: []model.InventoryRepairTicket{seenAs(drifted), {TicketID: 9}},
+		})
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.ErrorContains(t, err, "[9]")
+		ledgerRepo.AssertNotCalled(t, "RepairStock", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/repository"
+	"github.com/SyamSolution/ticket-management-service/internal/ticketcode"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"go.uber.org/zap"
+)
+
+type issuedTicketUsecase struct {
+	issuedTicketRepo repository.IssuedTicketPersister
+	signer           *ticketcode.Signer
+	logger           config.Logger
+}
+
+// IssuedTicketExecutor serves the tickets issued for paid orders to their
+// owners, each with a signed QR payload the gate scanners verify with the key
+// from GetVerificationKey, and checks the tickets in at the gates.
+type IssuedTicketExecutor interface {
+	GetMyTickets(ctx context.Context, email string) ([]model.IssuedTicket, error)
+	GetVerificationKey(ctx context.Context) model.VerificationKey
+	CheckIn(ctx context.Context, eventID int, request model.CheckInRequest) (model.TicketScan, error)
+	SyncCheckIns(ctx context.Context, eventID int, request model.CheckInSyncRequest) ([]model.TicketScan, error)
+}
+
+func NewIssuedTicketUsecase(issuedTicketRepo repository.IssuedTicketPersister, signer *ticketcode.Signer, logger config.Logger) IssuedTicketExecutor {
+	return &issuedTicketUsecase{issuedTicketRepo: issuedTicketRepo, signer: signer, logger: logger}
+}
+
+func (uc *issuedTicketUsecase) GetMyTickets(ctx context.Context, email string) ([]model.IssuedTicket, error) {
+	ctx, span := tracer.Start(ctx, "IssuedTicketUsecase.GetMyTickets")
+	defer span.End()
+
+	tickets, err := uc.issuedTicketRepo.GetIssuedTicketsByEmail(ctx, email)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting issued tickets by email", zap.Error(err))
+		return tickets, err
+	}
+
+	for i, ticket := range tickets {
+		if ticket.Status != util.ISSUED_TICKET_STATUS_VALID {
+			continue
+		}
+		payload, err := uc.signer.Sign(ticketcode.Claims{
+			Code:     ticket.Code,
+			EventID:  ticket.EventID,
+			TicketID: ticket.TicketID,
+			IssuedAt: ticket.IssuedAt.Unix(),
+		})
+		if err != nil {
+			uc.logger.WithContext(ctx).Error("Error when signing ticket payload", zap.Error(err))
+			return tickets, err
+		}
+		tickets[i].Payload = payload
+	}
+
+	return tickets, nil
+}
+
+func (uc *issuedTicketUsecase) GetVerificationKey(ctx context.Context) model.VerificationKey {
+	return model.VerificationKey{Algorithm: "Ed25519", PublicKey: uc.signer.PublicKey()}
+}
+
+// CheckIn lets the holder of the ticket in payload into event eventID through
+// request.Gate. Tickets that are forged, voided, for another event or checked
+// in before are rejected with a business error; the scan is recorded either
+// way.
+func (uc *issuedTicketUsecase) CheckIn(ctx context.Context, eventID int, request model.CheckInRequest) (model.TicketScan, error) {
+	ctx, span := tracer.Start(ctx, "IssuedTicketUsecase.CheckIn")
+	defer span.End()
+
+	scan, err := uc.recordScan(ctx, model.TicketScan{EventID: eventID, Gate: request.Gate, ScannedAt: util.TimeNow()}, request.Payload)
+	if err != nil {
+		return scan, err
+	}
+	return scan, checkInError(scan)
+}
+
+// SyncCheckIns applies the scans a scanner took while offline, oldest first,
+// so when the same ticket was scanned twice the earlier scan lets it in.
+// Scans uploaded before are returned as recorded the first time.
+func (uc *issuedTicketUsecase) SyncCheckIns(ctx context.Context, eventID int, request model.CheckInSyncRequest) ([]model.TicketScan, error) {
+	ctx, span := tracer.Start(ctx, "IssuedTicketUsecase.SyncCheckIns")
+	defer span.End()
+
+	offline := make([]model.OfflineScan, len(request.Scans))
+	copy(offline, request.Scans)
+	sort.SliceStable(offline, func(i, j int) bool { return offline[i].ScannedAt.Before(offline[j].ScannedAt) })
+
+	scans := make([]model.TicketScan, 0, len(offline))
+	for _, item := range offline {
+		scan, err := uc.recordScan(ctx, model.TicketScan{
+			ScanRef:   item.ScanRef,
+			EventID:   eventID,
+			Gate:      request.Gate,
+			Offline:   true,
+			ScannedAt: item.ScannedAt,
+		}, item.Payload)
+		if err != nil {
+			return scans, err
+		}
+		scans = append(scans, scan)
+	}
+
+	return scans, nil
+}
+
+// recordScan decides what scan of payload comes to and records it. The
+// signature proves the ticket was issued here; the stored ticket decides
+// whether it is still good for this event.
+func (uc *issuedTicketUsecase) recordScan(ctx context.Context, scan model.TicketScan, payload string) (model.TicketScan, error) {
+	scan.Result = util.SCAN_RESULT_ACCEPTED
+
+	claims, err := uc.signer.Verify(payload)
+	switch {
+	case err != nil:
+		scan.Result = util.SCAN_RESULT_INVALID
+	case claims.EventID != scan.EventID:
+		scan.Code = claims.Code
+		scan.Result = util.SCAN_RESULT_WRONG_EVENT
+	default:
+		scan.Code = claims.Code
+	}
+
+	if scan.Result == util.SCAN_RESULT_ACCEPTED {
+		ticket, err := uc.issuedTicketRepo.GetIssuedTicketByCode(ctx, claims.Code)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			scan.Result = util.SCAN_RESULT_INVALID
+		case err != nil:
+			uc.logger.WithContext(ctx).Error("Error when getting issued ticket by code", zap.Error(err))
+			return scan, err
+		case ticket.EventID != scan.EventID:
+			scan.Result = util.SCAN_RESULT_WRONG_EVENT
+		case ticket.Status != util.ISSUED_TICKET_STATUS_VALID:
+			scan.Result = util.SCAN_RESULT_VOID
+		}
+	}
+
+	recorded, err := uc.issuedTicketRepo.RecordScan(ctx, scan)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when recording ticket scan", zap.Error(err))
+		return scan, err
+	}
+	return recorded, nil
+}
+
+// checkInError tells the gate why scan did not let the ticket in, or returns
+// nil when it did.
+func checkInError(scan model.TicketScan) error {
+	var message string
+	switch scan.Result {
+	case util.SCAN_RESULT_ACCEPTED:
+		return nil
+	case util.SCAN_RESULT_DUPLICATE:
+		var at string
+		if scan.CheckedInAt != nil {
+			at = scan.CheckedInAt.Format(util.TIMESTAMP_DEFAULT_FORMAT)
+		}
+		message = fmt.Sprintf(util.ERROR_TICKET_CHECKED_IN_MSG, scan.CheckedInGate, at)
+	case util.SCAN_RESULT_WRONG_EVENT:
+		message = util.ERROR_TICKET_WRONG_EVENT_MSG
+	case util.SCAN_RESULT_VOID:
+		message = util.ERROR_TICKET_VOID_MSG
+	default:
+		message = util.ERROR_TICKET_INVALID_MSG
+	}
+	return &model.BusinessError{Code: util.ERROR_CHECK_IN_REJECTED_CODE, Message: message}
+}
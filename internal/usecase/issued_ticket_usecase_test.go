@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/ticketcode"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockIssuedTicketPersister struct {
+	mock.Mock
+}
+
+func (m *MockIssuedTicketPersister) CreateIssuedTickets(ctx context.Context, tickets []model.IssuedTicket) error {
+	args := m.Called(ctx, tickets)
+	return args.Error(0)
+}
+
+func (m *MockIssuedTicketPersister) GetIssuedTicketsByReservationID(ctx context.Context, reservationID int) ([]model.IssuedTicket, error) {
+	args := m.Called(ctx, reservationID)
+	return args.Get(0).([]model.IssuedTicket), args.Error(1)
+}
+
+func (m *MockIssuedTicketPersister) GetIssuedTicketsByEmail(ctx context.Context, email string) ([]model.IssuedTicket, error) {
+	args := m.Called(ctx, email)
+	return args.Get(0).([]model.IssuedTicket), args.Error(1)
+}
+
+func (m *MockIssuedTicketPersister) GetIssuedTicketByID(ctx context.Context, issuedTicketID int) (model.IssuedTicket, error) {
+	args := m.Called(ctx, issuedTicketID)
+	return args.Get(0).(model.IssuedTicket), args.Error(1)
+}
+
+func (m *MockIssuedTicketPersister) GetIssuedTicketByCode(ctx context.Context, code string) (model.IssuedTicket, error) {
+	args := m.Called(ctx, code)
+	return args.Get(0).(model.IssuedTicket), args.Error(1)
+}
+
+func (m *MockIssuedTicketPersister) VoidIssuedTicketsByReservationID(ctx context.Context, reservationID int) error {
+	args := m.Called(ctx, reservationID)
+	return args.Error(0)
+}
+
+func (m *MockIssuedTicketPersister) VoidIssuedTicket(ctx context.Context, issuedTicketID int) error {
+	args := m.Called(ctx, issuedTicketID)
+	return args.Error(0)
+}
+
+// RecordScan returns the scan it is given unless the test sets a result.
+func (m *MockIssuedTicketPersister) RecordScan(ctx context.Context, scan model.TicketScan) (model.TicketScan, error) {
+	args := m.Called(ctx, scan)
+	if recorded, ok := args.Get(0).(model.TicketScan); ok {
+		return recorded, args.Error(1)
+	}
+	return scan, args.Error(1)
+}
+
+const testSigningKey = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+
+func TestGetMyTickets(t *testing.T) {
+	signer, err := ticketcode.NewSigner(testSigningKey)
+	assert.NoError(t, err)
+
+	issuedTicketRepo := new(MockIssuedTicketPersister)
+	uc := NewIssuedTicketUsecase(issuedTicketRepo, signer, config.NewNopLogger())
+
+	issuedAt := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	issuedTicketRepo.On("GetIssuedTicketsByEmail", mock.Anything, "user@mail.com").Return([]model.IssuedTicket{
+		{IssuedTicketID: 1, Code: "CODEONE", TicketID: 2, EventID: 3, Status: "valid", IssuedAt: issuedAt},
+		{IssuedTicketID: 2, Code: "CODETWO", TicketID: 2, EventID: 3, Status: "void", IssuedAt: issuedAt},
+	}, nil)
+
+	tickets, err := uc.GetMyTickets(context.Background(), "user@mail.com")
+	assert.NoError(t, err)
+	assert.Len(t, tickets, 2)
+	assert.Empty(t, tickets[1].Payload)
+
+	claims, err := signer.Verify(tickets[0].Payload)
+	assert.NoError(t, err)
+	assert.Equal(t, ticketcode.Claims{Code: "CODEONE", EventID: 3, TicketID: 2, IssuedAt: issuedAt.Unix()}, claims)
+}
+
+func TestCheckIn(t *testing.T) {
+	now := time.Date(2026, 12, 1, 18, 30, 0, 0, time.UTC)
+	util.TimeNow = func() time.Time { return now }
+	defer func() { util.TimeNow = time.Now }()
+
+	signer, err := ticketcode.NewSigner(testSigningKey)
+	assert.NoError(t, err)
+	payload, err := signer.Sign(ticketcode.Claims{Code: "CODEONE", EventID: 3, TicketID: 2})
+	assert.NoError(t, err)
+
+	setup := func() (*MockIssuedTicketPersister, IssuedTicketExecutor) {
+		issuedTicketRepo := new(MockIssuedTicketPersister)
+		return issuedTicketRepo, NewIssuedTicketUsecase(issuedTicketRepo, signer, config.NewNopLogger())
+	}
+	accepted := model.TicketScan{Code: "CODEONE", EventID: 3, Gate: "A", Result: "accepted", ScannedAt: now}
+
+	t.Run("should check a valid ticket in", func(t *testing.T) {
+		issuedTicketRepo, uc := setup()
+		issuedTicketRepo.On("GetIssuedTicketByCode", mock.Anything, "CODEONE").Return(model.IssuedTicket{Code: "CODEONE", EventID: 3, Status: "valid"}, nil)
+		issuedTicketRepo.On("RecordScan", mock.Anything, accepted).Return(nil, nil)
+
+		scan, err := uc.CheckIn(context.Background(), 3, model.CheckInRequest{Gate: "A", Payload: payload})
+		assert.NoError(t, err)
+		assert.Equal(t, "accepted", scan.Result)
+	})
+
+	t.Run("should reject a ticket checked in before", func(t *testing.T) {
+		issuedTicketRepo, uc := setup()
+		issuedTicketRepo.On("GetIssuedTicketByCode", mock.Anything, "CODEONE").Return(model.IssuedTicket{Code: "CODEONE", EventID: 3, Status: "valid"}, nil)
+		earlier := now.Add(-time.Hour)
+		issuedTicketRepo.On("RecordScan", mock.Anything, accepted).
+			Return(model.TicketScan{Code: "CODEONE", EventID: 3, Gate: "A", Result: "duplicate", CheckedInAt: &earlier, CheckedInGate: "B"}, nil)
+
+		_, err := uc.CheckIn(context.Background(), 3, model.CheckInRequest{Gate: "A", Payload: payload})
+		var businessErr *model.BusinessError
+		assert.ErrorAs(t, err, &businessErr)
+		assert.Equal(t, 4112, businessErr.Code)
+		assert.Contains(t, businessErr.Message, "gate B")
+	})
+
+	t.Run("should reject a ticket for another event without looking it up", func(t *testing.T) {
+		issuedTicketRepo, uc := setup()
+		issuedTicketRepo.On("RecordScan", mock.Anything, mock.MatchedBy(func(scan model.TicketScan) bool {
+			return scan.Result == "wrong_event" && scan.EventID == 4
+		})).Return(nil, nil)
+
+		scan, err := uc.CheckIn(context.Background(), 4, model.CheckInRequest{Gate: "A", Payload: payload})
+		assert.Error(t, err)
+		assert.Equal(t, "wrong_event", scan.Result)
+		issuedTicketRepo.AssertNotCalled(t, "GetIssuedTicketByCode", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject a forged ticket", func(t *testing.T) {
+		issuedTicketRepo, uc := setup()
+		issuedTicketRepo.On("RecordScan", mock.Anything, mock.MatchedBy(func(scan model.TicketScan) bool {
+			return scan.Result == "invalid" && scan.Code == ""
+		})).Return(nil, nil)
+
+		scan, err := uc.CheckIn(context.Background(), 3, model.CheckInRequest{Gate: "A", Payload: payload + "x"})
+		assert.Error(t, err)
+		assert.Equal(t, "invalid", scan.Result)
+		issuedTicketRepo.AssertNotCalled(t, "GetIssuedTicketByCode", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject a voided ticket", func(t *testing.T) {
+		issuedTicketRepo, uc := setup()
+		issuedTicketRepo.On("GetIssuedTicketByCode", mock.Anything, "CODEONE").Return(model.IssuedTicket{Code: "CODEONE", EventID: 3, Status: "void"}, nil)
+		issuedTicketRepo.On("RecordScan", mock.Anything, mock.Anything).Return(nil, nil)
+
+		scan, err := uc.CheckIn(context.Background(), 3, model.CheckInRequest{Gate: "A", Payload: payload})
+		assert.Error(t, err)
+		assert.Equal(t, "void", scan.Result)
+	})
+}
+
+func TestSyncCheckIns(t *testing.T) {
+	signer, err := ticketcode.NewSigner(testSigningKey)
+	assert.NoError(t, err)
+	payload, err := signer.Sign(ticketcode.Claims{Code: "CODEONE", EventID: 3, TicketID: 2})
+	assert.NoError(t, err)
+
+	issuedTicketRepo := new(MockIssuedTicketPersister)
+	uc := NewIssuedTicketUsecase(issuedTicketRepo, signer, config.NewNopLogger())
+
+	first := time.Date(2026, 12, 1, 18, 0, 0, 0, time.UTC)
+	issuedTicketRepo.On("GetIssuedTicketByCode", mock.Anything, "CODEONE").Return(model.IssuedTicket{Code: "CODEONE", EventID: 3, Status: "valid"}, nil)
+	issuedTicketRepo.On("RecordScan", mock.Anything, mock.MatchedBy(func(scan model.TicketScan) bool { return scan.ScanRef == "ref-1" })).Return(nil, nil)
+	issuedTicketRepo.On("RecordScan", mock.Anything, mock.MatchedBy(func(scan model.TicketScan) bool { return scan.ScanRef == "ref-2" })).
+		Return(model.TicketScan{ScanRef: "ref-2", Result: "duplicate"}, nil)
+
+	scans, err := uc.SyncCheckIns(context.Background(), 3, model.CheckInSyncRequest{Gate: "A", Scans: []model.OfflineScan{
+		{ScanRef: "ref-2", Payload: payload, ScannedAt: first.Add(time.Minute)},
+		{ScanRef: "ref-1", Payload: payload, ScannedAt: first},
+	}})
+	assert.NoError(t, err)
+	if assert.Len(t, scans, 2) {
+		assert.Equal(t, "ref-1", scans[0].ScanRef)
+		assert.Equal(t, "accepted", scans[0].Result)
+		assert.True(t, scans[0].Offline)
+		assert.Equal(t, "duplicate", scans[1].Result)
+	}
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"errors"
+
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/repository"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// stockOperationsTotal counts reservations, confirmations and releases by
+// outcome.
+var stockOperationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "ticket",
+	Name:      "stock_operations_total",
+	Help:      "Count of stock reservations, confirmations and releases by outcome.",
+}, []string{"operation", "outcome"})
+
+// inventoryDriftedTickets is the number of tickets the last inventory check
+// found breaking a stock invariant.
+var inventoryDriftedTickets = prometheus.NewGauge(prometheus.GaugeOpts{
+	Namespace: "ticket",
+	Name:      "inventory_drifted_tickets",
+	Help:      "Number of tickets the last inventory check found breaking a stock invariant.",
+})
+
+// RegisterMetrics registers the usecase business metrics on registry.
+func RegisterMetrics(registry prometheus.Registerer) {
+	registry.MustRegister(stockOperationsTotal, inventoryDriftedTickets)
+}
+
+func observeStockOperation(operation string, err error) {
+	stockOperationsTotal.WithLabelValues(operation, errorOutcome(err)).Inc()
+}
+
+// errorOutcome names the outcome of an operation for metric labels.
+func errorOutcome(err error) string {
+	var businessErr *model.BusinessError
+	switch {
+	case err == nil:
+		return "success"
+	case errors.As(err, &businessErr):
+		return "rejected"
+	case errors.Is(err, repository.ErrOrderSettled):
+		return "settled"
+	case errors.Is(err, ErrInsufficientStock):
+		return "insufficient_stock"
+	case errors.Is(err, ErrNotFound):
+		return "not_found"
+	case errors.Is(err, ErrConflict):
+		return "conflict"
+	case errors.Is(err, ErrInvalidParam):
+		return "invalid"
+	}
+	return "error"
+}
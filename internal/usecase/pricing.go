@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/currency"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/repository"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"go.uber.org/zap"
+)
+
+// pricer works out what tickets cost a buyer; it is shared by the usecases
+// that show or charge prices so they always agree.
+type pricer struct {
+	ticketRepo repository.TicketPersister
+	rates      currency.RateProvider
+	logger     config.Logger
+}
+
+func (uc *ticketUsecase) ticketResponses(ctx context.Context, tickets []model.Ticket, opts model.PriceOptions) ([]model.TicketResponse, error) {
+	var TicketsResponse []model.TicketResponse
+
+	if err := uc.pricer.resolvePrices(ctx, tickets, opts); err != nil {
+		return TicketsResponse, err
+	}
+
+	for _, ticket := range tickets {
+		TicketsResponse = append(TicketsResponse, model.TicketResponse{
+			TicketID:      ticket.TicketID,
+			Type:          ticket.Type,
+			Price:         ticket.Price,
+			PriceTier:     ticket.PriceTier,
+			ContinentName: ticket.ContinentName,
+			Stock:         ticket.Available,
+			CountryName:   ticket.CountryName,
+			CountryCity:   ticket.CountryCity,
+			CountryPlace:  ticket.CountryPlace,
+		})
+	}
+	return TicketsResponse, nil
+}
+
+// resolvePrices replaces each ticket's list price with what a buyer described
+// by opts pays right now: the active price tier of the ticket, or else its list
+// price. A ticket with a price list entry for opts.Region charges that entry
+// instead, moved by the same proportion as the tier moves the list price. The
+// result is converted to opts.Currency when that is set.
+func (p pricer) resolvePrices(ctx context.Context, tickets []model.Ticket, opts model.PriceOptions) error {
+	if len(tickets) == 0 {
+		return nil
+	}
+
+	target := ""
+	if opts.Currency != "" {
+		code, err := currency.Normalize(opts.Currency)
+		if err != nil {
+			return fmt.Errorf("%v: %w", err, ErrInvalidParam)
+		}
+		target = code
+	}
+
+	ticketIDs := make([]int, len(tickets))
+	for i, ticket := range tickets {
+		ticketIDs[i] = ticket.TicketID
+	}
+
+	schedules, err := p.ticketRepo.GetTicketPriceSchedules(ctx, ticketIDs)
+	if err != nil {
+		p.logger.WithContext(ctx).Error("Error when getting ticket price schedules", zap.Error(err))
+		return err
+	}
+
+	listPrices := make([]int64, len(tickets))
+	now := util.TimeNow()
+	for i := range tickets {
+		listPrices[i] = tickets[i].Price.Amount
+		if schedule, ok := activeSchedule(schedules[tickets[i].TicketID], now, tickets[i].Available); ok {
+			tickets[i].Price.Amount = schedule.Price
+			tickets[i].PriceTier = schedule.Name
+		}
+	}
+
+	if region := strings.ToUpper(strings.TrimSpace(opts.Region)); region != "" {
+		prices, err := p.ticketRepo.GetTicketPricesByRegion(ctx, ticketIDs, region)
+		if err != nil {
+			p.logger.WithContext(ctx).Error("Error when getting ticket prices by region", zap.Error(err))
+			return err
+		}
+		for i := range tickets {
+			price, ok := prices[tickets[i].TicketID]
+			if !ok {
+				continue
+			}
+			if tickets[i].PriceTier != "" {
+				if listPrices[i] == 0 {
+					err := fmt.Errorf("ticket %d has price tier %q and a %s price but no list price to scale it by",
+						tickets[i].TicketID, tickets[i].PriceTier, region)
+					p.logger.WithContext(ctx).Error("Error when applying ticket price tier to region", zap.Error(err))
+					return err
+				}
+				price = currency.Scale(price, tickets[i].Price.Amount, listPrices[i])
+			}
+			tickets[i].Price = price
+		}
+	}
+
+	if target == "" {
+		return nil
+	}
+
+	for i := range tickets {
+		price, err := currency.Convert(ctx, p.rates, tickets[i].Price, target)
+		if err != nil {
+			if errors.Is(err, currency.ErrUnsupported) {
+				return fmt.Errorf("%v: %w", err, ErrInvalidParam)
+			}
+			p.logger.WithContext(ctx).Error("Error when converting ticket price", zap.Error(err))
+			return err
+		}
+		tickets[i].Price = price
+	}
+	return nil
+}
+
+// activeSchedule picks the first of schedules, ordered by priority, that
+// applies at now with stock tickets left.
+func activeSchedule(schedules []model.PriceSchedule, now time.Time, stock int) (model.PriceSchedule, bool) {
+	for _, schedule := range schedules {
+		if schedule.EffectiveFrom != nil && now.Before(*schedule.EffectiveFrom) {
+			continue
+		}
+		if schedule.EffectiveTo != nil && !now.Before(*schedule.EffectiveTo) {
+			continue
+		}
+		if schedule.MinStock != nil && stock < *schedule.MinStock {
+			continue
+		}
+		if schedule.MaxStock != nil && stock > *schedule.MaxStock {
+			continue
+		}
+		return schedule, true
+	}
+	return model.PriceSchedule{}, false
+}
+
+// discount is what promo takes off eligible, the part of an order it applies
+// to. Percentages round half up to the minor unit; fixed amounts are
+// converted to the currency of eligible and never exceed it.
+func (p pricer) discount(ctx context.Context, promo model.Promo, eligible model.Money) (model.Money, error) {
+	if promo.DiscountType == util.PROMO_DISCOUNT_PERCENT {
+		return model.Money{Amount: (eligible.Amount*promo.DiscountValue + 50) / 100, Currency: eligible.Currency}, nil
+	}
+
+	amount, err := currency.Convert(ctx, p.rates, model.Money{Amount: promo.DiscountValue, Currency: promo.Currency}, eligible.Currency)
+	if err != nil {
+		if errors.Is(err, currency.ErrUnsupported) {
+			return amount, fmt.Errorf("%v: %w", err, ErrInvalidParam)
+		}
+		p.logger.WithContext(ctx).Error("Error when converting promo discount", zap.Error(err))
+		return amount, err
+	}
+	if amount.Amount > eligible.Amount {
+		amount.Amount = eligible.Amount
+	}
+	return amount, nil
+}
@@ -0,0 +1,335 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/currency"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/repository"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"go.uber.org/zap"
+)
+
+type promoUsecase struct {
+	promoRepo  repository.PromoPersister
+	ticketRepo repository.TicketPersister
+	pricer     pricer
+	logger     config.Logger
+}
+
+// PromoExecutor manages promo codes and prices baskets of tickets with them.
+// Codes are case-insensitive and stored upper-cased.
+type PromoExecutor interface {
+	CreatePromo(ctx context.Context, request model.PromoRequest) (model.Promo, error)
+	GetPromos(ctx context.Context) ([]model.Promo, error)
+	GetPromo(ctx context.Context, promoID int) (model.Promo, error)
+	UpdatePromo(ctx context.Context, promoID int, request model.PromoRequest) (model.Promo, error)
+	DeletePromo(ctx context.Context, promoID int) error
+	Quote(ctx context.Context, email string, request model.QuoteRequest) (model.Quote, error)
+}
+
+func NewPromoUsecase(promoRepo repository.PromoPersister, ticketRepo repository.TicketPersister,
+	rates currency.RateProvider, logger config.Logger) PromoExecutor {
+	return &promoUsecase{
+		promoRepo:  promoRepo,
+		ticketRepo: ticketRepo,
+		pricer:     pricer{ticketRepo: ticketRepo, rates: rates, logger: logger},
+		logger:     logger,
+	}
+}
+
+func (uc *promoUsecase) CreatePromo(ctx context.Context, request model.PromoRequest) (model.Promo, error) {
+	ctx, span := tracer.Start(ctx, "PromoUsecase.CreatePromo")
+	defer span.End()
+
+	promo, err := promoFromRequest(request)
+	if err != nil {
+		return promo, err
+	}
+
+	if err := uc.checkCodeFree(ctx, promo.Code, 0); err != nil {
+		return promo, err
+	}
+
+	promoID, err := uc.promoRepo.CreatePromo(ctx, promo)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when creating promo", zap.Error(err))
+		return promo, err
+	}
+
+	return uc.GetPromo(ctx, promoID)
+}
+
+func (uc *promoUsecase) GetPromos(ctx context.Context) ([]model.Promo, error) {
+	ctx, span := tracer.Start(ctx, "PromoUsecase.GetPromos")
+	defer span.End()
+
+	promos, err := uc.promoRepo.GetPromos(ctx)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting promos", zap.Error(err))
+		return promos, err
+	}
+
+	return promos, nil
+}
+
+func (uc *promoUsecase) GetPromo(ctx context.Context, promoID int) (model.Promo, error) {
+	ctx, span := tracer.Start(ctx, "PromoUsecase.GetPromo")
+	defer span.End()
+
+	promo, err := uc.promoRepo.GetPromoByID(ctx, promoID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return promo, fmt.Errorf("promo %d: %w", promoID, ErrNotFound)
+		}
+		uc.logger.WithContext(ctx).Error("Error when getting promo by id", zap.Error(err))
+		return promo, err
+	}
+
+	return promo, nil
+}
+
+func (uc *promoUsecase) UpdatePromo(ctx context.Context, promoID int, request model.PromoRequest) (model.Promo, error) {
+	ctx, span := tracer.Start(ctx, "PromoUsecase.UpdatePromo")
+	defer span.End()
+
+	if _, err := uc.GetPromo(ctx, promoID); err != nil {
+		return model.Promo{}, err
+	}
+
+	promo, err := promoFromRequest(request)
+	if err != nil {
+		return promo, err
+	}
+	promo.PromoID = promoID
+
+	if err := uc.checkCodeFree(ctx, promo.Code, promoID); err != nil {
+		return promo, err
+	}
+
+	if err := uc.promoRepo.UpdatePromo(ctx, promo); err != nil {
+		uc.logger.WithContext(ctx).Error("Error when updating promo", zap.Error(err))
+		return promo, err
+	}
+
+	return uc.GetPromo(ctx, promoID)
+}
+
+// DeletePromo removes a promo code that was never used; codes with
+// redemptions are kept for the order history and can only be deactivated.
+func (uc *promoUsecase) DeletePromo(ctx context.Context, promoID int) error {
+	ctx, span := tracer.Start(ctx, "PromoUsecase.DeletePromo")
+	defer span.End()
+
+	if _, err := uc.GetPromo(ctx, promoID); err != nil {
+		return err
+	}
+
+	if err := uc.promoRepo.DeletePromo(ctx, promoID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("promo %d has redemptions, deactivate it instead: %w", promoID, ErrConflict)
+		}
+		uc.logger.WithContext(ctx).Error("Error when deleting promo", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// Quote prices the basket in request for email the way a reservation would,
+// applying request.PromoCode to the lines it is valid for. Nothing is held.
+func (uc *promoUsecase) Quote(ctx context.Context, email string, request model.QuoteRequest) (model.Quote, error) {
+	ctx, span := tracer.Start(ctx, "PromoUsecase.Quote")
+	defer span.End()
+
+	var quote model.Quote
+
+	tickets := make([]model.Ticket, 0, len(request.Items))
+	eventIDs := make([]int, 0, len(request.Items))
+	seen := make(map[int]bool, len(request.Items))
+	for _, item := range request.Items {
+		if seen[item.TicketID] {
+			return quote, fmt.Errorf("ticket %d is listed more than once: %w", item.TicketID, ErrInvalidParam)
+		}
+		seen[item.TicketID] = true
+
+		ticket, err := uc.ticketRepo.GetTicketByID(ctx, item.TicketID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return quote, fmt.Errorf("ticket %d: %w", item.TicketID, ErrNotFound)
+			}
+			uc.logger.WithContext(ctx).Error("Error when getting ticket by id", zap.Error(err))
+			return quote, err
+		}
+
+		limit, err := uc.ticketRepo.GetTicketPurchaseLimit(ctx, item.TicketID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return quote, fmt.Errorf("ticket %d: %w", item.TicketID, ErrNotFound)
+			}
+			uc.logger.WithContext(ctx).Error("Error when getting ticket purchase limit", zap.Error(err))
+			return quote, err
+		}
+		if err := saleError(limit.EventStatus, limit.SaleStartsAt, limit.SaleEndsAt, util.TimeNow()); err != nil {
+			return quote, err
+		}
+
+		tickets = append(tickets, ticket)
+		eventIDs = append(eventIDs, limit.EventID)
+	}
+
+	opts := model.PriceOptions{Currency: request.Currency, Region: request.Region}
+	if err := uc.pricer.resolvePrices(ctx, tickets, opts); err != nil {
+		return quote, err
+	}
+
+	code := tickets[0].Price.Currency
+	quote.Subtotal.Currency = code
+	for i, ticket := range tickets {
+		if ticket.Price.Currency != code {
+			return quote, fmt.Errorf("tickets are priced in %s and %s, pick a currency: %w",
+				code, ticket.Price.Currency, ErrInvalidParam)
+		}
+		amount := ticket.Price.Amount * int64(request.Items[i].Quantity)
+		quote.Lines = append(quote.Lines, model.QuoteLine{
+			TicketID:  ticket.TicketID,
+			EventID:   eventIDs[i],
+			Quantity:  request.Items[i].Quantity,
+			UnitPrice: ticket.Price,
+			PriceTier: ticket.PriceTier,
+			Amount:    model.Money{Amount: amount, Currency: code},
+		})
+		quote.Subtotal.Amount += amount
+	}
+	quote.Discount = model.Money{Currency: code}
+
+	if request.PromoCode != "" {
+		promo, err := usablePromo(ctx, uc.promoRepo, uc.logger, request.PromoCode, email, util.TimeNow())
+		if err != nil {
+			return quote, err
+		}
+
+		eligible := model.Money{Currency: code}
+		for _, line := range quote.Lines {
+			if promoApplies(promo, line.EventID) {
+				eligible.Amount += line.Amount.Amount
+			}
+		}
+		if eligible.Amount == 0 {
+			return quote, promoError(util.ERROR_PROMO_NOT_APPLICABLE_CODE, util.ERROR_PROMO_NOT_APPLICABLE_MSG, promo.Code)
+		}
+
+		quote.Discount, err = uc.pricer.discount(ctx, promo, eligible)
+		if err != nil {
+			return quote, err
+		}
+		quote.PromoCode = promo.Code
+	}
+
+	quote.Total = model.Money{Amount: quote.Subtotal.Amount - quote.Discount.Amount, Currency: code}
+	return quote, nil
+}
+
+// checkCodeFree fails with ErrConflict when code belongs to a promo other
+// than promoID.
+func (uc *promoUsecase) checkCodeFree(ctx context.Context, code string, promoID int) error {
+	existing, err := uc.promoRepo.GetPromoByCode(ctx, code)
+	if err == nil {
+		if existing.PromoID == promoID {
+			return nil
+		}
+		return fmt.Errorf("promo code %s already exists: %w", code, ErrConflict)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		uc.logger.WithContext(ctx).Error("Error when getting promo by code", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func promoFromRequest(request model.PromoRequest) (model.Promo, error) {
+	promo := model.Promo{
+		Code:          strings.ToUpper(request.Code),
+		DiscountType:  request.DiscountType,
+		DiscountValue: request.DiscountValue,
+		EventID:       request.EventID,
+		MaxUses:       request.MaxUses,
+		OncePerUser:   request.OncePerUser,
+		StartsAt:      request.StartsAt,
+		ExpiresAt:     request.ExpiresAt,
+		Active:        request.Active,
+	}
+
+	switch promo.DiscountType {
+	case util.PROMO_DISCOUNT_PERCENT:
+		if promo.DiscountValue > 100 {
+			return promo, fmt.Errorf("percent discount must be at most 100: %w", ErrInvalidParam)
+		}
+	case util.PROMO_DISCOUNT_FIXED:
+		code, err := currency.Normalize(request.Currency)
+		if err != nil {
+			return promo, fmt.Errorf("%v: %w", err, ErrInvalidParam)
+		}
+		promo.Currency = code
+	}
+
+	if promo.StartsAt != nil && promo.ExpiresAt != nil && !promo.StartsAt.Before(*promo.ExpiresAt) {
+		return promo, fmt.Errorf("starts_at must be before expires_at: %w", ErrInvalidParam)
+	}
+	return promo, nil
+}
+
+// usablePromo looks code up and checks that email may use it at now. The
+// usage cap is checked again, under a lock, when the promo is held.
+func usablePromo(ctx context.Context, promoRepo repository.PromoPersister, logger config.Logger,
+	code, email string, now time.Time) (model.Promo, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	promo, err := promoRepo.GetPromoByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return promo, promoError(util.ERROR_PROMO_INVALID_CODE, util.ERROR_PROMO_INVALID_MSG, code)
+		}
+		logger.WithContext(ctx).Error("Error when getting promo by code", zap.Error(err))
+		return promo, err
+	}
+
+	if !promo.Active ||
+		(promo.StartsAt != nil && now.Before(*promo.StartsAt)) ||
+		(promo.ExpiresAt != nil && !now.Before(*promo.ExpiresAt)) {
+		return promo, promoError(util.ERROR_PROMO_INVALID_CODE, util.ERROR_PROMO_INVALID_MSG, promo.Code)
+	}
+
+	if promo.MaxUses != nil && promo.UsedCount >= *promo.MaxUses {
+		return promo, promoError(util.ERROR_PROMO_EXHAUSTED_CODE, util.ERROR_PROMO_EXHAUSTED_MSG, promo.Code)
+	}
+
+	if promo.OncePerUser && email != "" {
+		used, err := promoRepo.CountActiveRedemptions(ctx, promo.PromoID, email)
+		if err != nil {
+			logger.WithContext(ctx).Error("Error when counting promo redemptions", zap.Error(err))
+			return promo, err
+		}
+		if used > 0 {
+			return promo, promoError(util.ERROR_PROMO_ALREADY_USED_CODE, util.ERROR_PROMO_ALREADY_USED_MSG, promo.Code)
+		}
+	}
+
+	return promo, nil
+}
+
+// promoApplies reports whether promo covers tickets of eventID; promos
+// without an event are global.
+func promoApplies(promo model.Promo, eventID int) bool {
+	return promo.EventID == nil || *promo.EventID == eventID
+}
+
+func promoError(code int, message, promoCode string) *model.BusinessError {
+	return &model.BusinessError{Code: code, Message: fmt.Sprintf(message, promoCode)}
+}
@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/currency"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPromoPersister struct {
+	mock.Mock
+}
+
+func (m *MockPromoPersister) CreatePromo(ctx context.Context, promo model.Promo) (int, error) {
+	args := m.Called(ctx, promo)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockPromoPersister) GetPromos(ctx context.Context) ([]model.Promo, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.Promo), args.Error(1)
+}
+
+func (m *MockPromoPersister) GetPromoByID(ctx context.Context, promoID int) (model.Promo, error) {
+	args := m.Called(ctx, promoID)
+	return args.Get(0).(model.Promo), args.Error(1)
+}
+
+func (m *MockPromoPersister) GetPromoByCode(ctx context.Context, code string) (model.Promo, error) {
+	args := m.Called(ctx, code)
+	return args.Get(0).(model.Promo), args.Error(1)
+}
+
+func (m *MockPromoPersister) UpdatePromo(ctx context.Context, promo model.Promo) error {
+	args := m.Called(ctx, promo)
+	return args.Error(0)
+}
+
+func (m *MockPromoPersister) DeletePromo(ctx context.Context, promoID int) error {
+	args := m.Called(ctx, promoID)
+	return args.Error(0)
+}
+
+func (m *MockPromoPersister) CountActiveRedemptions(ctx context.Context, promoID int, email string) (int, error) {
+	args := m.Called(ctx, promoID, email)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockPromoPersister) HoldPromo(ctx context.Context, redemption model.PromoRedemption) error {
+	args := m.Called(ctx, redemption)
+	return args.Error(0)
+}
+
+func (m *MockPromoPersister) RedeemPromoByOrderID(ctx context.Context, orderID string) error {
+	args := m.Called(ctx, orderID)
+	return args.Error(0)
+}
+
+func (m *MockPromoPersister) ReleasePromoByOrderID(ctx context.Context, orderID string) error {
+	args := m.Called(ctx, orderID)
+	return args.Error(0)
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func TestCreatePromo(t *testing.T) {
+	t.Run("should upper-case the code and create the promo", func(t *testing.T) {
+		promoRepo := new(MockPromoPersister)
+		uc := NewPromoUsecase(promoRepo, new(MockTicketPersister), currency.NewStaticRates("", nil), config.NewNopLogger())
+
+		promoRepo.On("GetPromoByCode", mock.Anything, "EARLY10").Return(model.Promo{}, sql.ErrNoRows)
+		promoRepo.On("CreatePromo", mock.Anything, mock.MatchedBy(func(promo model.Promo) bool {
+			return promo.Code == "EARLY10" && promo.Currency == ""
+		})).Return(4, nil)
+		promoRepo.On("GetPromoByID", mock.Anything, 4).Return(model.Promo{PromoID: 4, Code: "EARLY10"}, nil)
+
+		promo, err := uc.CreatePromo(context.Background(), model.PromoRequest{Code: "early10", DiscountType: "percent", DiscountValue: 10, Active: true})
+		assert.NoError(t, err)
+		assert.Equal(t, 4, promo.PromoID)
+		promoRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject a duplicate code", func(t *testing.T) {
+		promoRepo := new(MockPromoPersister)
+		uc := NewPromoUsecase(promoRepo, new(MockTicketPersister), currency.NewStaticRates("", nil), config.NewNopLogger())
+
+		promoRepo.On("GetPromoByCode", mock.Anything, "EARLY10").Return(model.Promo{PromoID: 1, Code: "EARLY10"}, nil)
+
+		_, err := uc.CreatePromo(context.Background(), model.PromoRequest{Code: "EARLY10", DiscountType: "percent", DiscountValue: 10})
+		assert.ErrorIs(t, err, ErrConflict)
+		promoRepo.AssertNotCalled(t, "CreatePromo", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject a percentage above 100", func(t *testing.T) {
+		uc := NewPromoUsecase(new(MockPromoPersister), new(MockTicketPersister), currency.NewStaticRates("", nil), config.NewNopLogger())
+
+		_, err := uc.CreatePromo(context.Background(), model.PromoRequest{Code: "ALL", DiscountType: "percent", DiscountValue: 150})
+		assert.ErrorIs(t, err, ErrInvalidParam)
+	})
+}
+
+func TestDeletePromo(t *testing.T) {
+	promoRepo := new(MockPromoPersister)
+	uc := NewPromoUsecase(promoRepo, new(MockTicketPersister), currency.NewStaticRates("", nil), config.NewNopLogger())
+
+	promoRepo.On("GetPromoByID", mock.Anything, 4).Return(model.Promo{PromoID: 4}, nil)
+	promoRepo.On("DeletePromo", mock.Anything, 4).Return(sql.ErrNoRows)
+
+	err := uc.DeletePromo(context.Background(), 4)
+	assert.ErrorIs(t, err, ErrConflict)
+}
+
+func TestQuote(t *testing.T) {
+	setup := func(promo model.Promo) (*MockPromoPersister, PromoExecutor) {
+		promoRepo := new(MockPromoPersister)
+		ticketRepo := new(MockTicketPersister)
+		rates := currency.NewStaticRates("USD", map[string]*big.Rat{"IDR": big.NewRat(16000, 1)})
+		uc := NewPromoUsecase(promoRepo, ticketRepo, rates, config.NewNopLogger())
+
+		ticketRepo.On("GetTicketByID", mock.Anything, 1).Return(model.Ticket{TicketID: 1, Price: model.Money{Amount: 10000000, Currency: "IDR"}, Available: 10}, nil)
+		ticketRepo.On("GetTicketByID", mock.Anything, 2).Return(model.Ticket{TicketID: 2, Price: model.Money{Amount: 5000000, Currency: "IDR"}, Available: 10}, nil)
+		ticketRepo.On("GetTicketPurchaseLimit", mock.Anything, 1).Return(model.TicketPurchaseLimit{TicketID: 1, EventID: 3, EventStatus: "on_sale"}, nil)
+		ticketRepo.On("GetTicketPurchaseLimit", mock.Anything, 2).Return(model.TicketPurchaseLimit{TicketID: 2, EventID: 4, EventStatus: "on_sale"}, nil)
+		ticketRepo.On("GetTicketPriceSchedules", mock.Anything, []int{1, 2}).Return(map[int][]model.PriceSchedule{}, nil)
+		promoRepo.On("GetPromoByCode", mock.Anything, promo.Code).Return(promo, nil)
+		return promoRepo, uc
+	}
+	request := model.QuoteRequest{
+		Items:     []model.QuoteItem{{TicketID: 1, Quantity: 2}, {TicketID: 2, Quantity: 1}},
+		PromoCode: "save",
+	}
+
+	t.Run("should apply a percent discount to the promo event only", func(t *testing.T) {
+		_, uc := setup(model.Promo{PromoID: 1, Code: "SAVE", DiscountType: "percent", DiscountValue: 15, EventID: intPtr(3), Active: true})
+
+		quote, err := uc.Quote(context.Background(), "user@mail.com", request)
+		assert.NoError(t, err)
+		assert.Equal(t, model.Money{Amount: 25000000, Currency: "IDR"}, quote.Subtotal)
+		assert.Equal(t, model.Money{Amount: 3000000, Currency: "IDR"}, quote.Discount)
+		assert.Equal(t, model.Money{Amount: 22000000, Currency: "IDR"}, quote.Total)
+		assert.Equal(t, "SAVE", quote.PromoCode)
+	})
+
+	t.Run("should convert a fixed discount into the basket currency", func(t *testing.T) {
+		_, uc := setup(model.Promo{PromoID: 1, Code: "SAVE", DiscountType: "fixed", DiscountValue: 500, Currency: "USD", Active: true})
+
+		quote, err := uc.Quote(context.Background(), "user@mail.com", request)
+		assert.NoError(t, err)
+		assert.Equal(t, model.Money{Amount: 8000000, Currency: "IDR"}, quote.Discount)
+		assert.Equal(t, model.Money{Amount: 17000000, Currency: "IDR"}, quote.Total)
+	})
+
+	t.Run("should reject a code already used by the user", func(t *testing.T) {
+		promoRepo, uc := setup(model.Promo{PromoID: 1, Code: "SAVE", DiscountType: "percent", DiscountValue: 15, OncePerUser: true, Active: true})
+		promoRepo.On("CountActiveRedemptions", mock.Anything, 1, "user@mail.com").Return(1, nil)
+
+		_, err := uc.Quote(context.Background(), "user@mail.com", request)
+		var businessErr *model.BusinessError
+		assert.ErrorAs(t, err, &businessErr)
+		assert.Equal(t, 4109, businessErr.Code)
+	})
+
+	t.Run("should reject a code for another event", func(t *testing.T) {
+		_, uc := setup(model.Promo{PromoID: 1, Code: "SAVE", DiscountType: "percent", DiscountValue: 15, EventID: intPtr(9), Active: true})
+
+		_, err := uc.Quote(context.Background(), "user@mail.com", request)
+		var businessErr *model.BusinessError
+		assert.ErrorAs(t, err, &businessErr)
+		assert.Equal(t, 4110, businessErr.Code)
+	})
+}
+
+func TestReserveTicketWithPromo(t *testing.T) {
+	message := model.MessageOrderTicket{TicketID: 1, Order: 2, OrderID: "order-1", Email: "user@mail.com", PromoCode: "save"}
+	promo := model.Promo{PromoID: 7, Code: "SAVE", DiscountType: "percent", DiscountValue: 10, Active: true}
+
+	setup := func() (*MockTicketPersister, *MockReservationPersister, *MockPromoPersister, TicketExecutor) {
+		ticketRepo := new(MockTicketPersister)
+		reservationRepo := new(MockReservationPersister)
+		promoRepo := new(MockPromoPersister)
+		waitlist := new(MockWaitlistOfferer)
+		uc := NewTicketUsecase(ticketRepo, reservationRepo, new(MockSeatPersister), promoRepo, new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), waitlist, currency.NewStaticRates("", nil), config.NewNopLogger(), 0, 0)
+
+		waitlist.On("OfferFreedStock", mock.Anything, 1).Return(0, nil)
+		reservationRepo.On("GetReservationByOrderID", mock.Anything, "order-1").Return(model.Reservation{}, sql.ErrNoRows)
+		ticketRepo.On("GetTicketPurchaseLimit", mock.Anything, 1).Return(model.TicketPurchaseLimit{TicketID: 1, EventID: 3, EventStatus: "on_sale"}, nil)
+		ticketRepo.On("GetTicketByID", mock.Anything, 1).Return(model.Ticket{TicketID: 1, Price: model.Money{Amount: 200000, Currency: "IDR"}, Available: 10}, nil)
+		ticketRepo.On("GetTicketPriceSchedules", mock.Anything, []int{1}).Return(map[int][]model.PriceSchedule{}, nil)
+		promoRepo.On("GetPromoByCode", mock.Anything, "SAVE").Return(promo, nil)
+		return ticketRepo, reservationRepo, promoRepo, uc
+	}
+	redemption := model.PromoRedemption{PromoID: 7, OrderID: "order-1", Email: "user@mail.com", Discount: model.Money{Amount: 40000, Currency: "IDR"}}
+
+	t.Run("should hold the promo and record the discount", func(t *testing.T) {
+		ticketRepo, reservationRepo, promoRepo, uc := setup()
+		promoRepo.On("HoldPromo", mock.Anything, redemption).Return(nil)
+		ticketRepo.On("UpdateStockCreateOrderTicket", mock.Anything, 1, 2, "order-1").Return(nil)
+		reservationRepo.On("CreateReservation", mock.Anything, mock.AnythingOfType("model.Reservation")).Return(9, nil)
+
+		reservation, err := uc.ReserveTicket(context.Background(), message)
+		assert.NoError(t, err)
+		assert.Equal(t, "SAVE", reservation.PromoCode)
+		assert.Equal(t, model.Money{Amount: 40000, Currency: "IDR"}, reservation.Discount)
+		promoRepo.AssertExpectations(t)
+	})
+
+	t.Run("should map an exhausted hold to a business error", func(t *testing.T) {
+		ticketRepo, _, promoRepo, uc := setup()
+		promoRepo.On("HoldPromo", mock.Anything, redemption).Return(repository.ErrPromoExhausted)
+
+		_, err := uc.ReserveTicket(context.Background(), message)
+		var businessErr *model.BusinessError
+		assert.ErrorAs(t, err, &businessErr)
+		assert.Equal(t, 4108, businessErr.Code)
+		ticketRepo.AssertNotCalled(t, "UpdateStockCreateOrderTicket", mock.Anything, 1, 2, mock.Anything)
+	})
+
+	t.Run("should release the promo when stock runs out", func(t *testing.T) {
+		ticketRepo, _, promoRepo, uc := setup()
+		promoRepo.On("HoldPromo", mock.Anything, redemption).Return(nil)
+		promoRepo.On("ReleasePromoByOrderID", mock.Anything, "order-1").Return(nil)
+		ticketRepo.On("UpdateStockCreateOrderTicket", mock.Anything, 1, 2, "order-1").Return(sql.ErrNoRows)
+
+		_, err := uc.ReserveTicket(context.Background(), message)
+		assert.ErrorIs(t, err, ErrInsufficientStock)
+		promoRepo.AssertExpectations(t)
+	})
+
+	t.Run("should give back the stock and promo when the reservation is not recorded", func(t *testing.T) {
+		ticketRepo, reservationRepo, promoRepo, uc := setup()
+		promoRepo.On("HoldPromo", mock.Anything, redemption).Return(nil)
+		promoRepo.On("ReleasePromoByOrderID", mock.Anything, "order-1").Return(nil)
+		ticketRepo.On("UpdateStockCreateOrderTicket", mock.Anything, 1, 2, "order-1").Return(nil)
+		reservationRepo.On("CreateReservation", mock.Anything, mock.AnythingOfType("model.Reservation")).Return(0, sql.ErrConnDone)
+		ticketRepo.On("UpdateStockFailOrderTicket", mock.Anything, 1, 2, "order-1").Return(nil)
+
+		_, err := uc.ReserveTicket(context.Background(), message)
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+		ticketRepo.AssertExpectations(t)
+		promoRepo.AssertExpectations(t)
+	})
+
+	t.Run("should require an order id", func(t *testing.T) {
+		_, _, _, uc := setup()
+		withoutOrder := message
+		withoutOrder.OrderID = ""
+
+		_, err := uc.ReserveTicket(context.Background(), withoutOrder)
+		assert.True(t, errors.Is(err, ErrInvalidParam))
+	})
+}
@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/repository"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"go.uber.org/zap"
+)
+
+type resaleUsecase struct {
+	resaleRepo       repository.ResalePersister
+	issuedTicketRepo repository.IssuedTicketPersister
+	reservationRepo  repository.ReservationPersister
+	eventRepo        repository.EventPersister
+	ticketUsecase    TicketExecutor
+	logger           config.Logger
+	feeBasisPoints   int64
+}
+
+// ResaleExecutor runs the official resale marketplace. Holders list issued
+// tickets within the price cap of the event; buyers purchase a listing
+// through the usual reservation and order flow, and the ticket changes
+// hands once the order is paid.
+type ResaleExecutor interface {
+	CreateListing(ctx context.Context, email string, issuedTicketID int, request model.ResaleListingRequest) (model.ResaleListing, error)
+	WithdrawListing(ctx context.Context, email string, listingID int) (model.ResaleListing, error)
+	GetEventListings(ctx context.Context, eventID int) ([]model.ResaleListing, error)
+	GetMyListings(ctx context.Context, email string) ([]model.ResaleListing, error)
+	PurchaseListing(ctx context.Context, email string, listingID int, request model.ResalePurchaseRequest) (model.Reservation, error)
+}
+
+// NewResaleUsecase builds the resale usecase. feeBasisPoints is the platform
+// fee added to every listing, in hundredths of a percent of its price.
+func NewResaleUsecase(resaleRepo repository.ResalePersister, issuedTicketRepo repository.IssuedTicketPersister,
+	reservationRepo repository.ReservationPersister, eventRepo repository.EventPersister, ticketUsecase TicketExecutor,
+	logger config.Logger, feeBasisPoints int) ResaleExecutor {
+	return &resaleUsecase{
+		resaleRepo:       resaleRepo,
+		issuedTicketRepo: issuedTicketRepo,
+		reservationRepo:  reservationRepo,
+		eventRepo:        eventRepo,
+		ticketUsecase:    ticketUsecase,
+		logger:           logger,
+		feeBasisPoints:   int64(feeBasisPoints),
+	}
+}
+
+// CreateListing lists a ticket of email for resale. The price is in the
+// currency the ticket was first sold in and may not exceed the resale cap of
+// the event, a percentage of that first price, so reselling a resold ticket
+// does not raise the cap.
+func (uc *resaleUsecase) CreateListing(ctx context.Context, email string, issuedTicketID int, request model.ResaleListingRequest) (model.ResaleListing, error) {
+	ctx, span := tracer.Start(ctx, "ResaleUsecase.CreateListing")
+	defer span.End()
+
+	var listing model.ResaleListing
+
+	ticket, err := uc.issuedTicketRepo.GetIssuedTicketByID(ctx, issuedTicketID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return listing, fmt.Errorf("ticket %d: %w", issuedTicketID, ErrNotFound)
+		}
+		uc.logger.WithContext(ctx).Error("Error when getting issued ticket by id", zap.Error(err))
+		return listing, err
+	}
+	if !strings.EqualFold(ticket.Email, email) {
+		return listing, fmt.Errorf("ticket %d: %w", issuedTicketID, ErrNotFound)
+	}
+	if err := transferable(ticket, util.TimeNow()); err != nil {
+		return listing, err
+	}
+
+	event, err := uc.eventRepo.GetEventByID(ctx, ticket.EventID)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting event by id", zap.Error(err))
+		return listing, err
+	}
+	if event.ResaleCapPercent == nil || event.Status == util.EVENT_STATUS_CANCELLED || event.Status == util.EVENT_STATUS_PAST {
+		return listing, &model.BusinessError{Code: util.ERROR_RESALE_CLOSED_CODE, Message: util.ERROR_RESALE_CLOSED_MSG}
+	}
+
+	reservation, err := uc.reservationRepo.GetReservationByOrderID(ctx, ticket.OrderID)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting reservation of order", zap.Error(err))
+		return listing, err
+	}
+	faceValue := reservation.UnitPrice
+	priceCap := faceValue.Amount * int64(*event.ResaleCapPercent) / 100
+	if request.Price > priceCap {
+		return listing, &model.BusinessError{
+			Code:    util.ERROR_RESALE_PRICE_CAP_CODE,
+			Message: fmt.Sprintf(util.ERROR_RESALE_PRICE_CAP_MSG, priceCap, faceValue.Currency),
+		}
+	}
+
+	listing = model.ResaleListing{
+		IssuedTicketID: ticket.IssuedTicketID,
+		EventID:        ticket.EventID,
+		TicketID:       ticket.TicketID,
+		SellerEmail:    ticket.Email,
+		Price:          model.Money{Amount: request.Price, Currency: faceValue.Currency},
+		Fee:            model.Money{Amount: (request.Price*uc.feeBasisPoints + 5000) / 10000, Currency: faceValue.Currency},
+		Status:         util.RESALE_LISTING_STATUS_ACTIVE,
+	}
+	listingID, err := uc.resaleRepo.CreateListing(ctx, listing)
+	if err != nil {
+		if errors.Is(err, repository.ErrTicketUnavailable) {
+			return listing, fmt.Errorf("ticket %d: %s: %w", issuedTicketID, err.Error(), ErrConflict)
+		}
+		uc.logger.WithContext(ctx).Error("Error when creating resale listing", zap.Error(err))
+		return listing, err
+	}
+
+	return uc.getListing(ctx, listingID)
+}
+
+// WithdrawListing takes a listing of email off sale while no buyer holds it.
+func (uc *resaleUsecase) WithdrawListing(ctx context.Context, email string, listingID int) (model.ResaleListing, error) {
+	ctx, span := tracer.Start(ctx, "ResaleUsecase.WithdrawListing")
+	defer span.End()
+
+	listing, err := uc.getListing(ctx, listingID)
+	if err != nil {
+		return listing, err
+	}
+	if !strings.EqualFold(listing.SellerEmail, email) {
+		return model.ResaleListing{}, fmt.Errorf("listing %d: %w", listingID, ErrNotFound)
+	}
+
+	if err := uc.resaleRepo.WithdrawListing(ctx, listingID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return listing, fmt.Errorf("listing %d is %s: %w", listingID, listing.Status, ErrConflict)
+		}
+		uc.logger.WithContext(ctx).Error("Error when withdrawing resale listing", zap.Error(err))
+		return listing, err
+	}
+
+	return uc.getListing(ctx, listingID)
+}
+
+func (uc *resaleUsecase) GetEventListings(ctx context.Context, eventID int) ([]model.ResaleListing, error) {
+	ctx, span := tracer.Start(ctx, "ResaleUsecase.GetEventListings")
+	defer span.End()
+
+	listings, err := uc.resaleRepo.GetActiveListingsByEventID(ctx, eventID)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting resale listings of event", zap.Error(err))
+		return listings, err
+	}
+	return listings, nil
+}
+
+func (uc *resaleUsecase) GetMyListings(ctx context.Context, email string) ([]model.ResaleListing, error) {
+	ctx, span := tracer.Start(ctx, "ResaleUsecase.GetMyListings")
+	defer span.End()
+
+	listings, err := uc.resaleRepo.GetListingsBySellerEmail(ctx, email)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting resale listings by seller", zap.Error(err))
+		return listings, err
+	}
+	return listings, nil
+}
+
+// PurchaseListing reserves a listing for email under the order of request.
+// The order is then paid or fails like any other, and its confirmation moves
+// the ticket to email.
+func (uc *resaleUsecase) PurchaseListing(ctx context.Context, email string, listingID int, request model.ResalePurchaseRequest) (model.Reservation, error) {
+	ctx, span := tracer.Start(ctx, "ResaleUsecase.PurchaseListing")
+	defer span.End()
+
+	return uc.ticketUsecase.ReserveTicket(ctx, model.MessageOrderTicket{
+		Order:     1,
+		OrderID:   request.OrderID,
+		Email:     email,
+		ListingID: listingID,
+	})
+}
+
+func (uc *resaleUsecase) getListing(ctx context.Context, listingID int) (model.ResaleListing, error) {
+	listing, err := uc.resaleRepo.GetListingByID(ctx, listingID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return listing, fmt.Errorf("listing %d: %w", listingID, ErrNotFound)
+		}
+		uc.logger.WithContext(ctx).Error("Error when getting resale listing by id", zap.Error(err))
+		return listing, err
+	}
+	return listing, nil
+}
@@ -0,0 +1,264 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/currency"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/repository"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockResalePersister struct {
+	mock.Mock
+}
+
+func (m *MockResalePersister) CreateListing(ctx context.Context, listing model.ResaleListing) (int, error) {
+	args := m.Called(ctx, listing)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockResalePersister) GetListingByID(ctx context.Context, listingID int) (model.ResaleListing, error) {
+	args := m.Called(ctx, listingID)
+	return args.Get(0).(model.ResaleListing), args.Error(1)
+}
+
+func (m *MockResalePersister) GetListingByOrderID(ctx context.Context, orderID string) (model.ResaleListing, error) {
+	args := m.Called(ctx, orderID)
+	return args.Get(0).(model.ResaleListing), args.Error(1)
+}
+
+func (m *MockResalePersister) GetSoldListingsByReservationID(ctx context.Context, reservationID int) ([]model.ResaleListing, error) {
+	args := m.Called(ctx, reservationID)
+	return args.Get(0).([]model.ResaleListing), args.Error(1)
+}
+
+func (m *MockResalePersister) GetActiveListingsByEventID(ctx context.Context, eventID int) ([]model.ResaleListing, error) {
+	args := m.Called(ctx, eventID)
+	return args.Get(0).([]model.ResaleListing), args.Error(1)
+}
+
+func (m *MockResalePersister) GetListingsBySellerEmail(ctx context.Context, email string) ([]model.ResaleListing, error) {
+	args := m.Called(ctx, email)
+	return args.Get(0).([]model.ResaleListing), args.Error(1)
+}
+
+func (m *MockResalePersister) WithdrawListing(ctx context.Context, listingID int) error {
+	args := m.Called(ctx, listingID)
+	return args.Error(0)
+}
+
+func (m *MockResalePersister) ReserveListing(ctx context.Context, listingID int, orderID, buyerEmail string) error {
+	args := m.Called(ctx, listingID, orderID, buyerEmail)
+	return args.Error(0)
+}
+
+func (m *MockResalePersister) ReleaseListing(ctx context.Context, listingID int, orderID string) error {
+	args := m.Called(ctx, listingID, orderID)
+	return args.Error(0)
+}
+
+func (m *MockResalePersister) CancelListing(ctx context.Context, listingID int, orderID string) error {
+	args := m.Called(ctx, listingID, orderID)
+	return args.Error(0)
+}
+
+func (m *MockResalePersister) SellListing(ctx context.Context, listing model.ResaleListing, code string, at time.Time) error {
+	args := m.Called(ctx, listing, code, at)
+	return args.Error(0)
+}
+
+func TestCreateListing(t *testing.T) {
+	now := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+	util.TimeNow = func() time.Time { return now }
+	defer func() { util.TimeNow = time.Now }()
+
+	capPercent := 110
+	ticket := model.IssuedTicket{IssuedTicketID: 1, OrderID: "order-1", TicketID: 2, EventID: 3, Email: "seller@mail.com",
+		Status: "valid", EventDate: now.Add(30 * 24 * time.Hour)}
+	event := model.Event{EventID: 3, Status: "sold_out", ResaleCapPercent: &capPercent}
+	reservation := model.Reservation{OrderID: "order-1", UnitPrice: model.Money{Amount: 200000, Currency: "IDR"}}
+
+	setup := func(event model.Event) (*MockResalePersister, ResaleExecutor) {
+		resaleRepo := new(MockResalePersister)
+		issuedTicketRepo := new(MockIssuedTicketPersister)
+		reservationRepo := new(MockReservationPersister)
+		eventRepo := new(MockEventPersister)
+		issuedTicketRepo.On("GetIssuedTicketByID", mock.Anything, 1).Return(ticket, nil)
+		eventRepo.On("GetEventByID", mock.Anything, 3).Return(event, nil)
+		reservationRepo.On("GetReservationByOrderID", mock.Anything, "order-1").Return(reservation, nil)
+		return resaleRepo, NewResaleUsecase(resaleRepo, issuedTicketRepo, reservationRepo, eventRepo, nil, config.NewNopLogger(), 500)
+	}
+
+	t.Run("should list the ticket with the platform fee", func(t *testing.T) {
+		resaleRepo, uc := setup(event)
+		resaleRepo.On("CreateListing", mock.Anything, model.ResaleListing{IssuedTicketID: 1, EventID: 3, TicketID: 2,
+			SellerEmail: "seller@mail.com", Price: model.Money{Amount: 220000, Currency: "IDR"},
+			Fee: model.Money{Amount: 11000, Currency: "IDR"}, Status: "active"}).Return(5, nil)
+		resaleRepo.On("GetListingByID", mock.Anything, 5).Return(model.ResaleListing{ListingID: 5, Status: "active"}, nil)
+
+		listing, err := uc.CreateListing(context.Background(), "seller@mail.com", 1, model.ResaleListingRequest{Price: 220000})
+		assert.NoError(t, err)
+		assert.Equal(t, 5, listing.ListingID)
+		resaleRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject a price above the cap of the event", func(t *testing.T) {
+		_, uc := setup(event)
+
+		_, err := uc.CreateListing(context.Background(), "seller@mail.com", 1, model.ResaleListingRequest{Price: 220001})
+		var businessErr *model.BusinessError
+		assert.ErrorAs(t, err, &businessErr)
+		assert.Equal(t, util.ERROR_RESALE_PRICE_CAP_CODE, businessErr.Code)
+		assert.Equal(t, "resale price can not exceed 220000 IDR", businessErr.Message)
+	})
+
+	t.Run("should reject events without resale", func(t *testing.T) {
+		_, uc := setup(model.Event{EventID: 3, Status: "on_sale"})
+
+		_, err := uc.CreateListing(context.Background(), "seller@mail.com", 1, model.ResaleListingRequest{Price: 100000})
+		var businessErr *model.BusinessError
+		assert.ErrorAs(t, err, &businessErr)
+		assert.Equal(t, util.ERROR_RESALE_CLOSED_CODE, businessErr.Code)
+	})
+
+	t.Run("should hide tickets of other users", func(t *testing.T) {
+		_, uc := setup(event)
+
+		_, err := uc.CreateListing(context.Background(), "stranger@mail.com", 1, model.ResaleListingRequest{Price: 100000})
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("should reject a ticket already listed or being transferred", func(t *testing.T) {
+		resaleRepo, uc := setup(event)
+		resaleRepo.On("CreateListing", mock.Anything, mock.Anything).Return(0, repository.ErrTicketUnavailable)
+
+		_, err := uc.CreateListing(context.Background(), "seller@mail.com", 1, model.ResaleListingRequest{Price: 100000})
+		assert.ErrorIs(t, err, ErrConflict)
+	})
+}
+
+func TestPurchaseListing(t *testing.T) {
+	listing := model.ResaleListing{ListingID: 5, IssuedTicketID: 1, EventID: 3, TicketID: 2, SellerEmail: "seller@mail.com",
+		Price: model.Money{Amount: 220000, Currency: "IDR"}, Fee: model.Money{Amount: 11000, Currency: "IDR"},
+		Total: model.Money{Amount: 231000, Currency: "IDR"}, Status: "active"}
+
+	setup := func() (*MockTicketPersister, *MockReservationPersister, *MockResalePersister, ResaleExecutor) {
+		ticketRepo := new(MockTicketPersister)
+		reservationRepo := new(MockReservationPersister)
+		resaleRepo := new(MockResalePersister)
+		ticketUsecase := NewTicketUsecase(ticketRepo, reservationRepo, new(MockSeatPersister), new(MockPromoPersister),
+			new(MockIssuedTicketPersister), resaleRepo, new(MockWaitlistPersister), new(MockWaitlistOfferer), currency.NewStaticRates("", nil),
+			config.NewNopLogger(), 0, 0)
+		reservationRepo.On("GetReservationByOrderID", mock.Anything, "order-9").Return(model.Reservation{}, sql.ErrNoRows)
+		return ticketRepo, reservationRepo, resaleRepo, NewResaleUsecase(resaleRepo, new(MockIssuedTicketPersister),
+			reservationRepo, new(MockEventPersister), ticketUsecase, config.NewNopLogger(), 500)
+	}
+
+	t.Run("should reserve the listing without moving stock", func(t *testing.T) {
+		ticketRepo, reservationRepo, resaleRepo, uc := setup()
+		resaleRepo.On("GetListingByID", mock.Anything, 5).Return(listing, nil)
+		ticketRepo.On("GetTicketPurchaseLimit", mock.Anything, 2).Return(model.TicketPurchaseLimit{TicketID: 2, EventID: 3, EventStatus: "sold_out"}, nil)
+		resaleRepo.On("ReserveListing", mock.Anything, 5, "order-9", "buyer@mail.com").Return(nil)
+		reservationRepo.On("CreateReservation", mock.Anything, model.Reservation{OrderID: "order-9", TicketID: 2, EventID: 3,
+			Email: "buyer@mail.com", Quantity: 1, UnitPrice: listing.Total, PriceTier: "resale",
+			Discount: model.Money{Currency: "IDR"}, Status: "pending"}).Return(11, nil)
+
+		reservation, err := uc.PurchaseListing(context.Background(), "buyer@mail.com", 5, model.ResalePurchaseRequest{OrderID: "order-9"})
+		assert.NoError(t, err)
+		assert.Equal(t, 11, reservation.ReservationID)
+		ticketRepo.AssertNotCalled(t, "UpdateStockCreateOrderTicket", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		reservationRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject buying your own listing", func(t *testing.T) {
+		_, _, resaleRepo, uc := setup()
+		resaleRepo.On("GetListingByID", mock.Anything, 5).Return(listing, nil)
+
+		_, err := uc.PurchaseListing(context.Background(), "Seller@mail.com", 5, model.ResalePurchaseRequest{OrderID: "order-9"})
+		assert.ErrorIs(t, err, ErrInvalidParam)
+	})
+
+	t.Run("should report a listing taken meanwhile as conflict", func(t *testing.T) {
+		ticketRepo, _, resaleRepo, uc := setup()
+		resaleRepo.On("GetListingByID", mock.Anything, 5).Return(listing, nil)
+		ticketRepo.On("GetTicketPurchaseLimit", mock.Anything, 2).Return(model.TicketPurchaseLimit{TicketID: 2, EventID: 3, EventStatus: "on_sale"}, nil)
+		resaleRepo.On("ReserveListing", mock.Anything, 5, "order-9", "buyer@mail.com").Return(sql.ErrNoRows)
+
+		_, err := uc.PurchaseListing(context.Background(), "buyer@mail.com", 5, model.ResalePurchaseRequest{OrderID: "order-9"})
+		assert.ErrorIs(t, err, ErrConflict)
+	})
+
+	t.Run("should close resale once the event is over", func(t *testing.T) {
+		ticketRepo, _, resaleRepo, uc := setup()
+		resaleRepo.On("GetListingByID", mock.Anything, 5).Return(listing, nil)
+		ticketRepo.On("GetTicketPurchaseLimit", mock.Anything, 2).Return(model.TicketPurchaseLimit{TicketID: 2, EventID: 3, EventStatus: "past"}, nil)
+
+		_, err := uc.PurchaseListing(context.Background(), "buyer@mail.com", 5, model.ResalePurchaseRequest{OrderID: "order-9"})
+		var businessErr *model.BusinessError
+		assert.ErrorAs(t, err, &businessErr)
+		assert.Equal(t, util.ERROR_RESALE_CLOSED_CODE, businessErr.Code)
+	})
+}
+
+func TestSettleListing(t *testing.T) {
+	now := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+	util.TimeNow = func() time.Time { return now }
+	defer func() { util.TimeNow = time.Now }()
+
+	listing := model.ResaleListing{ListingID: 5, IssuedTicketID: 1, SellerEmail: "seller@mail.com", BuyerEmail: "buyer@mail.com",
+		OrderID: "order-9", Status: "reserved"}
+	message := model.MessageOrderTicket{TicketID: 2, Order: 1, OrderID: "order-9", ListingID: 5}
+
+	setup := func() (*MockTicketPersister, *MockReservationPersister, *MockResalePersister, TicketExecutor) {
+		ticketRepo := new(MockTicketPersister)
+		reservationRepo := new(MockReservationPersister)
+		resaleRepo := new(MockResalePersister)
+		resaleRepo.On("GetListingByID", mock.Anything, 5).Return(listing, nil)
+		return ticketRepo, reservationRepo, resaleRepo, NewTicketUsecase(ticketRepo, reservationRepo, new(MockSeatPersister),
+			new(MockPromoPersister), new(MockIssuedTicketPersister), resaleRepo, new(MockWaitlistPersister), new(MockWaitlistOfferer),
+			currency.NewStaticRates("", nil), config.NewNopLogger(), 0, 0)
+	}
+
+	t.Run("should hand the ticket to the buyer on success", func(t *testing.T) {
+		ticketRepo, reservationRepo, resaleRepo, uc := setup()
+		resaleRepo.On("SellListing", mock.Anything, listing, mock.AnythingOfType("string"), now).Return(nil)
+		reservationRepo.On("UpdateReservationStatusByOrderID", mock.Anything, "order-9", "confirmed").Return(nil)
+
+		err := uc.UpdateStockTicket(context.Background(), message, "success")
+		assert.NoError(t, err)
+		resaleRepo.AssertExpectations(t)
+		reservationRepo.AssertExpectations(t)
+		ticketRepo.AssertNotCalled(t, "UpdateStockSuccessOrderTicket", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should refund the buyer when the ticket can no longer change hands", func(t *testing.T) {
+		_, reservationRepo, resaleRepo, uc := setup()
+		resaleRepo.On("SellListing", mock.Anything, listing, mock.Anything, now).Return(sql.ErrNoRows)
+		resaleRepo.On("CancelListing", mock.Anything, 5, "order-9").Return(nil)
+		reservationRepo.On("UpdateReservationStatusByOrderID", mock.Anything, "order-9", "refund_pending").Return(nil)
+
+		err := uc.UpdateStockTicket(context.Background(), message, "success")
+		assert.NoError(t, err)
+		resaleRepo.AssertExpectations(t)
+		reservationRepo.AssertExpectations(t)
+	})
+
+	t.Run("should put the listing back on sale on failed", func(t *testing.T) {
+		ticketRepo, reservationRepo, resaleRepo, uc := setup()
+		resaleRepo.On("ReleaseListing", mock.Anything, 5, "order-9").Return(nil)
+		reservationRepo.On("UpdateReservationStatusByOrderID", mock.Anything, "order-9", "released").Return(nil)
+
+		err := uc.UpdateStockTicket(context.Background(), message, "failed")
+		assert.NoError(t, err)
+		resaleRepo.AssertExpectations(t)
+		reservationRepo.AssertExpectations(t)
+		ticketRepo.AssertNotCalled(t, "UpdateStockFailOrderTicket", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
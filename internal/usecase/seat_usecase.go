@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/repository"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"go.uber.org/zap"
+)
+
+type seatUsecase struct {
+	seatRepo      repository.SeatPersister
+	ticketUsecase TicketExecutor
+	logger        config.Logger
+	holdDuration  time.Duration
+}
+
+// SeatExecutor sells specific seats of seated events. Seats are priced and
+// counted under a ticket tier, so a seat reservation is an ordinary
+// reservation of that tier plus a hold on the chosen seats; the order's
+// success or failure message then sells or frees the seats.
+type SeatExecutor interface {
+	GetSeatMap(ctx context.Context, eventID int) (model.SeatMap, error)
+	ReserveSeats(ctx context.Context, eventID int, email string, request model.ReserveSeatsRequest) (model.SeatReservation, error)
+	ReleaseExpiredHolds(ctx context.Context) (int, error)
+}
+
+// NewSeatUsecase builds the seat usecase; holds that are not paid within
+// holdDuration are released by ReleaseExpiredHolds.
+func NewSeatUsecase(seatRepo repository.SeatPersister, ticketUsecase TicketExecutor, logger config.Logger,
+	holdDuration time.Duration) SeatExecutor {
+	return &seatUsecase{
+		seatRepo:      seatRepo,
+		ticketUsecase: ticketUsecase,
+		logger:        logger,
+		holdDuration:  holdDuration,
+	}
+}
+
+func (uc *seatUsecase) GetSeatMap(ctx context.Context, eventID int) (model.SeatMap, error) {
+	ctx, span := tracer.Start(ctx, "SeatUsecase.GetSeatMap")
+	defer span.End()
+
+	seatMap := model.SeatMap{EventID: eventID}
+
+	seats, err := uc.seatRepo.GetEventSeats(ctx, eventID)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting event seats", zap.Error(err))
+		return seatMap, err
+	}
+	if len(seats) == 0 {
+		return seatMap, fmt.Errorf("seat map of event %d: %w", eventID, ErrNotFound)
+	}
+
+	// seats come ordered by section, row and number.
+	for _, seat := range seats {
+		sections := len(seatMap.Sections)
+		if sections == 0 || seatMap.Sections[sections-1].SectionID != seat.SectionID {
+			seatMap.Sections = append(seatMap.Sections, model.SeatMapSection{SectionID: seat.SectionID, Name: seat.SectionName})
+			sections++
+		}
+		section := &seatMap.Sections[sections-1]
+
+		rows := len(section.Rows)
+		if rows == 0 || section.Rows[rows-1].Row != seat.Row {
+			section.Rows = append(section.Rows, model.SeatMapRow{Row: seat.Row})
+			rows++
+		}
+		section.Rows[rows-1].Seats = append(section.Rows[rows-1].Seats, seat)
+	}
+
+	return seatMap, nil
+}
+
+func (uc *seatUsecase) ReserveSeats(ctx context.Context, eventID int, email string, request model.ReserveSeatsRequest) (model.SeatReservation, error) {
+	ctx, span := tracer.Start(ctx, "SeatUsecase.ReserveSeats")
+	defer span.End()
+
+	var seatReservation model.SeatReservation
+
+	requested := make(map[int]bool, len(request.SeatIDs))
+	for _, seatID := range request.SeatIDs {
+		if requested[seatID] {
+			return seatReservation, fmt.Errorf("seat %d requested twice: %w", seatID, ErrInvalidParam)
+		}
+		requested[seatID] = true
+	}
+
+	seats, err := uc.seatRepo.GetEventSeatsByIDs(ctx, eventID, request.SeatIDs)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting event seats", zap.Error(err))
+		return seatReservation, err
+	}
+	if len(seats) != len(request.SeatIDs) {
+		return seatReservation, fmt.Errorf("seats of event %d: %w", eventID, ErrNotFound)
+	}
+
+	ticketID := seats[0].TicketID
+	for _, seat := range seats {
+		if seat.TicketID != ticketID {
+			return seatReservation, fmt.Errorf("seats span several ticket types: %w", ErrInvalidParam)
+		}
+		if seat.Status != util.SEAT_STATUS_AVAILABLE {
+			return seatReservation, fmt.Errorf("seat %d is %s: %w", seat.SeatID, seat.Status, ErrConflict)
+		}
+	}
+
+	expiresAt := util.TimeNow().Add(uc.holdDuration)
+	err = uc.seatRepo.HoldSeats(ctx, eventID, request.SeatIDs, request.OrderID, email, expiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return seatReservation, fmt.Errorf("seats of event %d are no longer available: %w", eventID, ErrConflict)
+		}
+		uc.logger.WithContext(ctx).Error("Error when holding seats", zap.Error(err))
+		return seatReservation, err
+	}
+
+	reservation, err := uc.ticketUsecase.ReserveTicket(ctx, model.MessageOrderTicket{
+		TicketID: ticketID,
+		Order:    len(request.SeatIDs),
+		OrderID:  request.OrderID,
+		Email:    email,
+	})
+	if err != nil {
+		if releaseErr := uc.seatRepo.ReleaseSeats(ctx, eventID, request.SeatIDs, request.OrderID); releaseErr != nil {
+			uc.logger.WithContext(ctx).Error("Error when releasing seats of rejected reservation", zap.Error(releaseErr))
+		}
+		return seatReservation, err
+	}
+
+	return model.SeatReservation{
+		Reservation:   reservation,
+		SeatIDs:       request.SeatIDs,
+		HoldExpiresAt: expiresAt,
+	}, nil
+}
+
+// ReleaseExpiredHolds releases every order whose seats are still held past
+// their expiry. Each hold is claimed with its seats, the tier's stock and the
+// reservation in one transaction, so sweeps running on several replicas
+// release it once. The order is then failed, which finds it settled and only
+// gives back its promo and offers the freed stock to the waitlist. It returns
+// how many orders were released.
+func (uc *seatUsecase) ReleaseExpiredHolds(ctx context.Context) (int, error) {
+	ctx, span := tracer.Start(ctx, "SeatUsecase.ReleaseExpiredHolds")
+	defer span.End()
+
+	now := util.TimeNow()
+	holds, err := uc.seatRepo.GetExpiredSeatHolds(ctx, now)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting expired seat holds", zap.Error(err))
+		return 0, err
+	}
+
+	var (
+		released int
+		errs     []error
+	)
+	for _, hold := range holds {
+		claimed, err := uc.seatRepo.ClaimExpiredSeatHold(ctx, hold, now)
+		if err != nil && !errors.Is(err, repository.ErrOrderSettled) {
+			uc.logger.WithContext(ctx).Error("Error when releasing expired seat hold", zap.String("order_id", hold.OrderID), zap.Error(err))
+			errs = append(errs, heldStockError(hold.TicketID, hold.Quantity, err))
+			continue
+		}
+		if err == nil && !claimed {
+			continue
+		}
+
+		message := model.MessageOrderTicket{TicketID: hold.TicketID, Order: hold.Quantity, OrderID: hold.OrderID}
+		if err := uc.ticketUsecase.UpdateStockTicket(ctx, message, "failed"); err != nil {
+			uc.logger.WithContext(ctx).Error("Error when failing order of expired seat hold", zap.String("order_id", hold.OrderID), zap.Error(err))
+			errs = append(errs, err)
+			continue
+		}
+		if claimed {
+			released++
+		}
+	}
+
+	return released, errors.Join(errs...)
+}
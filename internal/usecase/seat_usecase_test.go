@@ -0,0 +1,225 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/currency"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/repository"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSeatPersister struct {
+	mock.Mock
+}
+
+func (m *MockSeatPersister) GetEventSeats(ctx context.Context, eventID int) ([]model.EventSeat, error) {
+	args := m.Called(ctx, eventID)
+	return args.Get(0).([]model.EventSeat), args.Error(1)
+}
+
+func (m *MockSeatPersister) GetEventSeatsByIDs(ctx context.Context, eventID int, seatIDs []int) ([]model.EventSeat, error) {
+	args := m.Called(ctx, eventID, seatIDs)
+	return args.Get(0).([]model.EventSeat), args.Error(1)
+}
+
+func (m *MockSeatPersister) HoldSeats(ctx context.Context, eventID int, seatIDs []int, orderID, email string, expiresAt time.Time) error {
+	args := m.Called(ctx, eventID, seatIDs, orderID, email, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockSeatPersister) SellSeatsByOrderID(ctx context.Context, orderID string) error {
+	args := m.Called(ctx, orderID)
+	return args.Error(0)
+}
+
+func (m *MockSeatPersister) ReleaseSeatsByOrderID(ctx context.Context, orderID string) error {
+	args := m.Called(ctx, orderID)
+	return args.Error(0)
+}
+
+func (m *MockSeatPersister) ReleaseSeats(ctx context.Context, eventID int, seatIDs []int, orderID string) error {
+	args := m.Called(ctx, eventID, seatIDs, orderID)
+	return args.Error(0)
+}
+
+func (m *MockSeatPersister) GetExpiredSeatHolds(ctx context.Context, now time.Time) ([]model.SeatHold, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).([]model.SeatHold), args.Error(1)
+}
+
+func (m *MockSeatPersister) ClaimExpiredSeatHold(ctx context.Context, hold model.SeatHold, now time.Time) (bool, error) {
+	args := m.Called(ctx, hold, now)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestGetSeatMap(t *testing.T) {
+	t.Run("groups seats by section and row", func(t *testing.T) {
+		seatRepo := new(MockSeatPersister)
+		uc := NewSeatUsecase(seatRepo, nil, config.NewNopLogger(), time.Minute)
+
+		seatRepo.On("GetEventSeats", mock.Anything, 9).Return([]model.EventSeat{
+			{SeatID: 1, SectionID: 1, SectionName: "Floor", Row: "A", Number: 1},
+			{SeatID: 2, SectionID: 1, SectionName: "Floor", Row: "A", Number: 2},
+			{SeatID: 3, SectionID: 1, SectionName: "Floor", Row: "B", Number: 1},
+			{SeatID: 4, SectionID: 2, SectionName: "Balcony", Row: "A", Number: 1},
+		}, nil)
+
+		seatMap, err := uc.GetSeatMap(context.Background(), 9)
+		assert.NoError(t, err)
+		assert.Len(t, seatMap.Sections, 2)
+		assert.Len(t, seatMap.Sections[0].Rows, 2)
+		assert.Len(t, seatMap.Sections[0].Rows[0].Seats, 2)
+		assert.Equal(t, "Balcony", seatMap.Sections[1].Name)
+	})
+
+	t.Run("not found for event without seats", func(t *testing.T) {
+		seatRepo := new(MockSeatPersister)
+		uc := NewSeatUsecase(seatRepo, nil, config.NewNopLogger(), time.Minute)
+
+		seatRepo.On("GetEventSeats", mock.Anything, 9).Return([]model.EventSeat(nil), nil)
+
+		_, err := uc.GetSeatMap(context.Background(), 9)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestReserveSeats(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	util.TimeNow = func() time.Time { return now }
+	defer func() { util.TimeNow = time.Now }()
+
+	request := model.ReserveSeatsRequest{SeatIDs: []int{1, 2}, OrderID: "order-1"}
+	seats := []model.EventSeat{
+		{SeatID: 1, TicketID: 3, Status: util.SEAT_STATUS_AVAILABLE},
+		{SeatID: 2, TicketID: 3, Status: util.SEAT_STATUS_AVAILABLE},
+	}
+
+	t.Run("holds seats and reserves their ticket", func(t *testing.T) {
+		ticketRepo := new(MockTicketPersister)
+		reservationRepo := new(MockReservationPersister)
+		seatRepo := new(MockSeatPersister)
+		ticketUsecase := NewTicketUsecase(ticketRepo, reservationRepo, seatRepo, new(MockPromoPersister), new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), new(MockWaitlistOfferer), currency.NewStaticRates("", nil), config.NewNopLogger(), 0, 0)
+		uc := NewSeatUsecase(seatRepo, ticketUsecase, config.NewNopLogger(), 10*time.Minute)
+
+		seatRepo.On("GetEventSeatsByIDs", mock.Anything, 9, []int{1, 2}).Return(seats, nil)
+		seatRepo.On("HoldSeats", mock.Anything, 9, []int{1, 2}, "order-1", "user@mail.com", now.Add(10*time.Minute)).Return(nil)
+		reservationRepo.On("GetReservationByOrderID", mock.Anything, "order-1").Return(model.Reservation{}, sql.ErrNoRows)
+		ticketRepo.On("GetTicketPurchaseLimit", mock.Anything, 3).Return(model.TicketPurchaseLimit{TicketID: 3, EventID: 9, EventStatus: "on_sale"}, nil)
+		ticketRepo.On("GetTicketByID", mock.Anything, 3).Return(model.Ticket{TicketID: 3, Price: model.Money{Amount: 500000, Currency: "IDR"}, Available: 20}, nil)
+		ticketRepo.On("GetTicketPriceSchedules", mock.Anything, []int{3}).Return(map[int][]model.PriceSchedule{}, nil)
+		ticketRepo.On("UpdateStockCreateOrderTicket", mock.Anything, 3, 2, "order-1").Return(nil)
+		reservationRepo.On("CreateReservation", mock.Anything, mock.AnythingOfType("model.Reservation")).Return(5, nil)
+
+		reservation, err := uc.ReserveSeats(context.Background(), 9, "user@mail.com", request)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, reservation.Reservation.ReservationID)
+		assert.Equal(t, 2, reservation.Reservation.Quantity)
+		assert.Equal(t, now.Add(10*time.Minute), reservation.HoldExpiresAt)
+		seatRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects duplicate seats", func(t *testing.T) {
+		uc := NewSeatUsecase(new(MockSeatPersister), nil, config.NewNopLogger(), time.Minute)
+
+		_, err := uc.ReserveSeats(context.Background(), 9, "user@mail.com", model.ReserveSeatsRequest{SeatIDs: []int{1, 1}, OrderID: "order-1"})
+		assert.ErrorIs(t, err, ErrInvalidParam)
+	})
+
+	t.Run("rejects seats taken by another order", func(t *testing.T) {
+		seatRepo := new(MockSeatPersister)
+		uc := NewSeatUsecase(seatRepo, nil, config.NewNopLogger(), time.Minute)
+
+		seatRepo.On("GetEventSeatsByIDs", mock.Anything, 9, []int{1, 2}).Return(seats, nil)
+		seatRepo.On("HoldSeats", mock.Anything, 9, []int{1, 2}, "order-1", "user@mail.com", mock.Anything).Return(sql.ErrNoRows)
+
+		_, err := uc.ReserveSeats(context.Background(), 9, "user@mail.com", request)
+		assert.ErrorIs(t, err, ErrConflict)
+	})
+
+	t.Run("releases seats when the reservation is refused", func(t *testing.T) {
+		ticketRepo := new(MockTicketPersister)
+		reservationRepo := new(MockReservationPersister)
+		seatRepo := new(MockSeatPersister)
+		ticketUsecase := NewTicketUsecase(ticketRepo, reservationRepo, seatRepo, new(MockPromoPersister), new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), new(MockWaitlistOfferer), currency.NewStaticRates("", nil), config.NewNopLogger(), 0, 0)
+		uc := NewSeatUsecase(seatRepo, ticketUsecase, config.NewNopLogger(), time.Minute)
+
+		seatRepo.On("GetEventSeatsByIDs", mock.Anything, 9, []int{1, 2}).Return(seats, nil)
+		seatRepo.On("HoldSeats", mock.Anything, 9, []int{1, 2}, "order-1", "user@mail.com", mock.Anything).Return(nil)
+		reservationRepo.On("GetReservationByOrderID", mock.Anything, "order-1").Return(model.Reservation{OrderID: "order-1"}, nil)
+		seatRepo.On("ReleaseSeats", mock.Anything, 9, []int{1, 2}, "order-1").Return(nil)
+
+		_, err := uc.ReserveSeats(context.Background(), 9, "user@mail.com", request)
+		assert.ErrorIs(t, err, ErrConflict)
+		seatRepo.AssertExpectations(t)
+	})
+}
+
+func TestReleaseExpiredHolds(t *testing.T) {
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	util.TimeNow = func() time.Time { return now }
+	defer func() { util.TimeNow = time.Now }()
+
+	hold := model.SeatHold{OrderID: "order-1", TicketID: 3, Quantity: 2}
+	setup := func() (*MockTicketPersister, *MockReservationPersister, *MockSeatPersister, *MockPromoPersister, *MockWaitlistOfferer, SeatExecutor) {
+		ticketRepo := new(MockTicketPersister)
+		reservationRepo := new(MockReservationPersister)
+		seatRepo := new(MockSeatPersister)
+		promoRepo := new(MockPromoPersister)
+		waitlist := new(MockWaitlistOfferer)
+		ticketUsecase := NewTicketUsecase(ticketRepo, reservationRepo, seatRepo, promoRepo, new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), waitlist, currency.NewStaticRates("", nil), config.NewNopLogger(), 0, 0)
+		uc := NewSeatUsecase(seatRepo, ticketUsecase, config.NewNopLogger(), time.Minute)
+
+		seatRepo.On("GetExpiredSeatHolds", mock.Anything, now).Return([]model.SeatHold{hold}, nil)
+		return ticketRepo, reservationRepo, seatRepo, promoRepo, waitlist, uc
+	}
+
+	t.Run("should release a claimed hold and give back its promo", func(t *testing.T) {
+		ticketRepo, reservationRepo, seatRepo, promoRepo, waitlist, uc := setup()
+		seatRepo.On("ClaimExpiredSeatHold", mock.Anything, hold, now).Return(true, nil)
+		ticketRepo.On("UpdateStockFailOrderTicket", mock.Anything, 3, 2, "order-1").Return(repository.ErrOrderSettled)
+		reservationRepo.On("GetReservationByOrderID", mock.Anything, "order-1").
+			Return(model.Reservation{ReservationID: 1, OrderID: "order-1", Status: "released"}, nil)
+		seatRepo.On("ReleaseSeatsByOrderID", mock.Anything, "order-1").Return(nil)
+		promoRepo.On("ReleasePromoByOrderID", mock.Anything, "order-1").Return(nil)
+		waitlist.On("OfferFreedStock", mock.Anything, 3).Return(1, nil)
+
+		released, err := uc.ReleaseExpiredHolds(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, released)
+		seatRepo.AssertExpectations(t)
+		promoRepo.AssertExpectations(t)
+		waitlist.AssertExpectations(t)
+	})
+
+	t.Run("should skip a hold claimed by another sweep", func(t *testing.T) {
+		ticketRepo, _, seatRepo, _, waitlist, uc := setup()
+		seatRepo.On("ClaimExpiredSeatHold", mock.Anything, hold, now).Return(false, nil)
+
+		released, err := uc.ReleaseExpiredHolds(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 0, released)
+		ticketRepo.AssertNotCalled(t, "UpdateStockFailOrderTicket", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		waitlist.AssertNotCalled(t, "OfferFreedStock", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should leave the seats of an order paid meanwhile", func(t *testing.T) {
+		ticketRepo, reservationRepo, seatRepo, _, waitlist, uc := setup()
+		seatRepo.On("ClaimExpiredSeatHold", mock.Anything, hold, now).Return(false, repository.ErrOrderSettled)
+		ticketRepo.On("UpdateStockFailOrderTicket", mock.Anything, 3, 2, "order-1").Return(repository.ErrOrderSettled)
+		reservationRepo.On("GetReservationByOrderID", mock.Anything, "order-1").
+			Return(model.Reservation{ReservationID: 1, OrderID: "order-1", Status: "confirmed"}, nil)
+
+		released, err := uc.ReleaseExpiredHolds(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 0, released)
+		seatRepo.AssertNotCalled(t, "ReleaseSeatsByOrderID", mock.Anything, mock.Anything)
+		waitlist.AssertNotCalled(t, "OfferFreedStock", mock.Anything, mock.Anything)
+	})
+}
@@ -1,147 +1,893 @@
 package usecase
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/currency"
 	"github.com/SyamSolution/ticket-management-service/internal/model"
 	"github.com/SyamSolution/ticket-management-service/internal/repository"
+	"github.com/SyamSolution/ticket-management-service/internal/ticketcode"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
 	"go.uber.org/zap"
 )
 
 type ticketUsecase struct {
-	ticketRepo repository.TicketPersister
-	logger     config.Logger
+	ticketRepo       repository.TicketPersister
+	reservationRepo  repository.ReservationPersister
+	seatRepo         repository.SeatPersister
+	promoRepo        repository.PromoPersister
+	issuedTicketRepo repository.IssuedTicketPersister
+	resaleRepo       repository.ResalePersister
+	waitlistRepo     repository.WaitlistPersister
+	waitlist         WaitlistOfferer
+	pricer           pricer
+	logger           config.Logger
+	maxTicketPerUser int
+	holdDuration     time.Duration
 }
 
 type TicketExecutor interface {
-	GetAvailableTicketByContinent(continent string) ([]model.TicketResponse, error)
-	GetAvailableTicketByType(ticketType string) ([]model.TicketResponse, error)
-	GetTicketByContinent(continent string) ([]model.TicketResponse, error)
-	UpdateStockTicket(ticketID, order int, typeStock string) error
-	GetStockTicketGroupByContinent() ([]model.StockTicket, error)
-	GetTicketEventByTicketID(TicketID int) (model.TicketEvent, error)
+	GetAvailableTicketByContinent(ctx context.Context, continent string, opts model.PriceOptions) ([]model.TicketResponse, error)
+	GetAvailableTicketByType(ctx context.Context, ticketType string, opts model.PriceOptions) ([]model.TicketResponse, error)
+	GetTicketByContinent(ctx context.Context, continent string, opts model.PriceOptions) ([]model.TicketResponse, error)
+	UpdateStockTicket(ctx context.Context, message model.MessageOrderTicket, typeStock string) error
+	ReserveTicket(ctx context.Context, message model.MessageOrderTicket) (model.Reservation, error)
+	ReleaseReservation(ctx context.Context, reservation model.Reservation) error
+	ReleaseExpiredReservations(ctx context.Context) (int, error)
+	GetStockTicketGroupByContinent(ctx context.Context) ([]model.StockTicket, error)
+	GetTicketEventByTicketID(ctx context.Context, TicketID int, opts model.PriceOptions) (model.TicketEvent, error)
 }
 
-func NewTicketUsecase(ticketRepo repository.TicketPersister, logger config.Logger) TicketExecutor {
-	return &ticketUsecase{ticketRepo: ticketRepo, logger: logger}
+// NewTicketUsecase builds the ticket usecase. maxTicketPerUser is the service wide
+// limit of tickets a single user may reserve per event, applied when the event
+// itself has no limit configured. Zero means unlimited. rates converts prices
+// to the currency a buyer asks for. Stock that orders give back is offered to
+// the ticket's waitlist through waitlist. Reservations of unpaid orders hold
+// their tickets for holdDuration and are then released by
+// ReleaseExpiredReservations; zero keeps them until the order settles.
+func NewTicketUsecase(ticketRepo repository.TicketPersister, reservationRepo repository.ReservationPersister,
+	seatRepo repository.SeatPersister, promoRepo repository.PromoPersister, issuedTicketRepo repository.IssuedTicketPersister,
+	resaleRepo repository.ResalePersister, waitlistRepo repository.WaitlistPersister, waitlist WaitlistOfferer,
+	rates currency.RateProvider, logger config.Logger, maxTicketPerUser int, holdDuration time.Duration) TicketExecutor {
+	return &ticketUsecase{
+		ticketRepo:       ticketRepo,
+		reservationRepo:  reservationRepo,
+		seatRepo:         seatRepo,
+		promoRepo:        promoRepo,
+		issuedTicketRepo: issuedTicketRepo,
+		resaleRepo:       resaleRepo,
+		waitlistRepo:     waitlistRepo,
+		waitlist:         waitlist,
+		pricer:           pricer{ticketRepo: ticketRepo, rates: rates, logger: logger},
+		logger:           logger,
+		maxTicketPerUser: maxTicketPerUser,
+		holdDuration:     holdDuration,
+	}
 }
 
-func (uc *ticketUsecase) GetAvailableTicketByContinent(continent string) ([]model.TicketResponse, error) {
+func (uc *ticketUsecase) GetAvailableTicketByContinent(ctx context.Context, continent string, opts model.PriceOptions) ([]model.TicketResponse, error) {
+	ctx, span := tracer.Start(ctx, "TicketUsecase.GetAvailableTicketByContinent")
+	defer span.End()
+
 	var TicketsResponse []model.TicketResponse
 
-	tickets, err := uc.ticketRepo.GetAvailableTicketByContinent(continent)
+	tickets, err := uc.ticketRepo.GetAvailableTicketByContinent(ctx, continent)
 	if err != nil {
-		uc.logger.Error("Error when getting available ticket by continent", zap.Error(err))
+		uc.logger.WithContext(ctx).Error("Error when getting available ticket by continent", zap.Error(err))
 		return TicketsResponse, err
 	}
 
-	for _, ticket := range tickets {
-		TicketsResponse = append(TicketsResponse, model.TicketResponse{
-			TicketID:      ticket.TicketID,
-			Type:          ticket.Type,
-			Price:         ticket.Price,
-			ContinentName: ticket.ContinentName,
-			Stock:         ticket.Stock,
-			CountryName:   ticket.CountryName,
-			CountryCity:   ticket.CountryCity,
-			CountryPlace:  ticket.CountryPlace,
-		})
-	}
-	return TicketsResponse, nil
+	return uc.ticketResponses(ctx, tickets, opts)
 }
 
-func (uc *ticketUsecase) GetAvailableTicketByType(ticketType string) ([]model.TicketResponse, error) {
+func (uc *ticketUsecase) GetAvailableTicketByType(ctx context.Context, ticketType string, opts model.PriceOptions) ([]model.TicketResponse, error) {
+	ctx, span := tracer.Start(ctx, "TicketUsecase.GetAvailableTicketByType")
+	defer span.End()
+
 	var TicketsResponse []model.TicketResponse
 
-	tickets, err := uc.ticketRepo.GetAvailableTicketByType(ticketType)
+	tickets, err := uc.ticketRepo.GetAvailableTicketByType(ctx, ticketType)
 	if err != nil {
-		uc.logger.Error("Error when getting available ticket by type", zap.Error(err))
+		uc.logger.WithContext(ctx).Error("Error when getting available ticket by type", zap.Error(err))
 		return TicketsResponse, err
 	}
 
-	for _, ticket := range tickets {
-		TicketsResponse = append(TicketsResponse, model.TicketResponse{
-			TicketID:      ticket.TicketID,
-			Type:          ticket.Type,
-			Price:         ticket.Price,
-			ContinentName: ticket.ContinentName,
-			Stock:         ticket.Stock,
-			CountryName:   ticket.CountryName,
-			CountryCity:   ticket.CountryCity,
-			CountryPlace:  ticket.CountryPlace,
-		})
-	}
-	return TicketsResponse, nil
+	return uc.ticketResponses(ctx, tickets, opts)
 }
 
-func (uc *ticketUsecase) GetTicketByContinent(continent string) ([]model.TicketResponse, error) {
+func (uc *ticketUsecase) GetTicketByContinent(ctx context.Context, continent string, opts model.PriceOptions) ([]model.TicketResponse, error) {
+	ctx, span := tracer.Start(ctx, "TicketUsecase.GetTicketByContinent")
+	defer span.End()
+
 	var TicketsResponse []model.TicketResponse
 
-	tickets, err := uc.ticketRepo.GetTicketByContinent(continent)
+	tickets, err := uc.ticketRepo.GetTicketByContinent(ctx, continent)
 	if err != nil {
-		uc.logger.Error("Error when getting ticket by continent", zap.Error(err))
+		uc.logger.WithContext(ctx).Error("Error when getting ticket by continent", zap.Error(err))
 		return TicketsResponse, err
 	}
 
-	for _, ticket := range tickets {
-		TicketsResponse = append(TicketsResponse, model.TicketResponse{
-			TicketID:      ticket.TicketID,
-			Type:          ticket.Type,
-			Price:         ticket.Price,
-			ContinentName: ticket.ContinentName,
-			Stock:         ticket.Stock,
-			CountryName:   ticket.CountryName,
-			CountryCity:   ticket.CountryCity,
-			CountryPlace:  ticket.CountryPlace,
-		})
-	}
-	return TicketsResponse, nil
+	return uc.ticketResponses(ctx, tickets, opts)
 }
 
-func (uc *ticketUsecase) UpdateStockTicket(ticketID, order int, typeStock string) error {
+func (uc *ticketUsecase) UpdateStockTicket(ctx context.Context, message model.MessageOrderTicket, typeStock string) error {
+	ctx, span := tracer.Start(ctx, "TicketUsecase.UpdateStockTicket")
+	defer span.End()
+
 	switch typeStock {
 	case "create":
-		if err := uc.ticketRepo.UpdateStockCreateOrderTicket(ticketID, order); err != nil {
-			uc.logger.Error("Error when updating stock ticket", zap.Error(err))
+		if message.Email != "" || message.ListingID != 0 {
+			_, err := uc.ReserveTicket(ctx, message)
 			return err
 		}
-		return nil
+		err := uc.ticketRepo.UpdateStockCreateOrderTicket(ctx, message.TicketID, message.Order, message.OrderID)
+		if err != nil {
+			uc.logger.WithContext(ctx).Error("Error when updating stock ticket", zap.Error(err))
+			err = stockError(message.TicketID, err)
+		}
+		observeStockOperation("reserve", err)
+		return err
 	case "success":
-		if err := uc.ticketRepo.UpdateStockSuccessOrderTicket(ticketID, order); err != nil {
-			uc.logger.Error("Error when updating stock ticket", zap.Error(err))
+		if message.ListingID != 0 {
+			err := uc.settleListing(ctx, message, true)
+			observeStockOperation("confirm", err)
 			return err
 		}
-		return nil
+		err := uc.ticketRepo.UpdateStockSuccessOrderTicket(ctx, message.TicketID, message.Order, message.OrderID)
+		if errors.Is(err, repository.ErrOrderSettled) {
+			var settled bool
+			if settled, err = uc.settledAs(ctx, message.OrderID, util.RESERVATION_STATUS_CONFIRMED); err == nil && !settled {
+				// the order was released before its payment came in; the
+				// message stays for a person to sell or refund it.
+				uc.logger.WithContext(ctx).Error("Paid order holds no stock to confirm", zap.String("order_id", message.OrderID))
+				err = fmt.Errorf("order %s released before it was paid: %w", message.OrderID, ErrConflict)
+				observeStockOperation("confirm", err)
+				return err
+			}
+		} else if err != nil {
+			uc.logger.WithContext(ctx).Error("Error when updating stock ticket", zap.Error(err))
+			err = heldStockError(message.TicketID, message.Order, err)
+		}
+		if err == nil {
+			err = uc.settleSeats(ctx, message.OrderID, true)
+		}
+		if err == nil {
+			err = uc.settlePromo(ctx, message.OrderID, true)
+		}
+		if err == nil {
+			err = uc.issueTickets(ctx, message.OrderID)
+		}
+		observeStockOperation("confirm", err)
+		return err
 	case "failed":
-		if err := uc.ticketRepo.UpdateStockFailOrderTicket(ticketID, order); err != nil {
-			uc.logger.Error("Error when updating stock ticket", zap.Error(err))
+		if message.ListingID != 0 {
+			err := uc.settleListing(ctx, message, false)
+			observeStockOperation("release", err)
 			return err
 		}
+		err := uc.ticketRepo.UpdateStockFailOrderTicket(ctx, message.TicketID, message.Order, message.OrderID)
+		if errors.Is(err, repository.ErrOrderSettled) {
+			var settled bool
+			if settled, err = uc.settledAs(ctx, message.OrderID, util.RESERVATION_STATUS_RELEASED); err == nil && !settled {
+				uc.logger.WithContext(ctx).Info("Order holds no stock to release", zap.String("order_id", message.OrderID))
+				observeStockOperation("release", repository.ErrOrderSettled)
+				return nil
+			}
+		} else if err != nil {
+			uc.logger.WithContext(ctx).Error("Error when updating stock ticket", zap.Error(err))
+			err = heldStockError(message.TicketID, message.Order, err)
+		}
+		if err == nil {
+			err = uc.settleSeats(ctx, message.OrderID, false)
+		}
+		if err == nil {
+			err = uc.settlePromo(ctx, message.OrderID, false)
+		}
+		if err == nil {
+			// the release is done by now; failing here would have a redelivery
+			// return the stock a second time, so the sweep retries instead.
+			if _, offerErr := uc.waitlist.OfferFreedStock(ctx, message.TicketID); offerErr != nil {
+				uc.logger.WithContext(ctx).Error("Error when offering freed stock", zap.Error(offerErr))
+			}
+		}
+		observeStockOperation("release", err)
+		return err
+	}
+
+	return fmt.Errorf("stock update type %q: %w", typeStock, ErrInvalidParam)
+}
+
+// ReleaseReservation gives back the stock of an unpaid reservation the way a
+// failed payment of its order would. A reservation settled meanwhile, by its
+// order's message or an earlier call, is left alone. A resale reservation
+// holds a listing rather than stock, so its listing goes back on sale.
+func (uc *ticketUsecase) ReleaseReservation(ctx context.Context, reservation model.Reservation) error {
+	ctx, span := tracer.Start(ctx, "TicketUsecase.ReleaseReservation")
+	defer span.End()
+
+	if reservation.PriceTier == util.RESALE_PRICE_TIER {
+		return uc.releaseListingReservation(ctx, reservation)
+	}
+	if reservation.OrderID != "" {
+		return uc.UpdateStockTicket(ctx, model.MessageOrderTicket{
+			TicketID: reservation.TicketID,
+			Order:    reservation.Quantity,
+			OrderID:  reservation.OrderID,
+		}, "failed")
+	}
+
+	err := uc.ticketRepo.UpdateStockFailReservation(ctx, reservation.ReservationID, reservation.TicketID, reservation.Quantity)
+	if errors.Is(err, repository.ErrOrderSettled) {
+		observeStockOperation("release", err)
+		return nil
+	}
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when releasing reservation", zap.Error(err))
+		err = heldStockError(reservation.TicketID, reservation.Quantity, err)
+	} else if _, offerErr := uc.waitlist.OfferFreedStock(ctx, reservation.TicketID); offerErr != nil {
+		uc.logger.WithContext(ctx).Error("Error when offering freed stock", zap.Error(offerErr))
+	}
+	observeStockOperation("release", err)
+	return err
+}
+
+// ReleaseExpiredReservations releases every pending reservation whose hold
+// ran out, the way ReleaseReservation does, so sweeps running on several
+// replicas release each of them once. It returns how many were released.
+func (uc *ticketUsecase) ReleaseExpiredReservations(ctx context.Context) (int, error) {
+	ctx, span := tracer.Start(ctx, "TicketUsecase.ReleaseExpiredReservations")
+	defer span.End()
+
+	reservations, err := uc.reservationRepo.GetExpiredReservations(ctx, util.TimeNow())
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting expired reservations", zap.Error(err))
+		return 0, err
+	}
+
+	var (
+		released int
+		errs     []error
+	)
+	for _, reservation := range reservations {
+		if err := uc.ReleaseReservation(ctx, reservation); err != nil {
+			uc.logger.WithContext(ctx).Error("Error when releasing expired reservation",
+				zap.Int("reservation_id", reservation.ReservationID), zap.Error(err))
+			errs = append(errs, err)
+			continue
+		}
+		released++
+	}
+
+	return released, errors.Join(errs...)
+}
+
+// holdExpiry returns when a reservation placed now stops holding its
+// tickets, or nil when holds do not expire.
+func (uc *ticketUsecase) holdExpiry() *time.Time {
+	if uc.holdDuration <= 0 {
 		return nil
 	}
+	expiresAt := util.TimeNow().Add(uc.holdDuration)
+	return &expiresAt
+}
+
+// releaseListingReservation puts the listing held by an unpaid resale
+// reservation back on sale and releases the reservation. A listing sold or
+// released meanwhile is left as it is.
+func (uc *ticketUsecase) releaseListingReservation(ctx context.Context, reservation model.Reservation) error {
+	listing, err := uc.resaleRepo.GetListingByOrderID(ctx, reservation.OrderID)
+	switch {
+	case err == nil:
+		err := uc.resaleRepo.ReleaseListing(ctx, listing.ListingID, reservation.OrderID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			uc.logger.WithContext(ctx).Error("Error when releasing resale listing", zap.Error(err))
+			return err
+		}
+	case !errors.Is(err, sql.ErrNoRows):
+		uc.logger.WithContext(ctx).Error("Error when getting resale listing by order id", zap.Error(err))
+		return err
+	}
 
+	err = uc.reservationRepo.UpdateReservationStatus(ctx, reservation.ReservationID, util.RESERVATION_STATUS_PENDING,
+		util.RESERVATION_STATUS_RELEASED)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		uc.logger.WithContext(ctx).Error("Error when releasing reservation", zap.Error(err))
+		return err
+	}
 	return nil
 }
 
-func (uc *ticketUsecase) GetStockTicketGroupByContinent() ([]model.StockTicket, error) {
+// ReserveTicket holds the ordered quantity of the stock on behalf of the
+// user identified by message.Email, rejecting the order with a business error
+// when the ticket's event is not on sale or the order would exceed the
+// per-user limit of the event. A promo code on the message is held for the
+// order until it is paid or fails. A message with a listing reserves that
+// resale listing instead, and one with a waitlist entry claims the stock
+// offered to it.
+func (uc *ticketUsecase) ReserveTicket(ctx context.Context, message model.MessageOrderTicket) (model.Reservation, error) {
+	ctx, span := tracer.Start(ctx, "TicketUsecase.ReserveTicket")
+	defer span.End()
+
+	reservation, err := uc.reserveTicket(ctx, message)
+	observeStockOperation("reserve", err)
+	return reservation, err
+}
+
+func (uc *ticketUsecase) reserveTicket(ctx context.Context, message model.MessageOrderTicket) (model.Reservation, error) {
+	var reservation model.Reservation
+
+	if message.Order <= 0 {
+		return reservation, fmt.Errorf("order must be greater than zero: %w", ErrInvalidParam)
+	}
+
+	if message.OrderID != "" {
+		_, err := uc.reservationRepo.GetReservationByOrderID(ctx, message.OrderID)
+		if err == nil {
+			return reservation, fmt.Errorf("order %s already reserved: %w", message.OrderID, ErrConflict)
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			uc.logger.WithContext(ctx).Error("Error when getting reservation by order id", zap.Error(err))
+			return reservation, err
+		}
+	}
+
+	if message.ListingID != 0 {
+		return uc.reserveListing(ctx, message)
+	}
+
+	limit, err := uc.ticketRepo.GetTicketPurchaseLimit(ctx, message.TicketID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return reservation, fmt.Errorf("ticket %d: %w", message.TicketID, ErrNotFound)
+		}
+		uc.logger.WithContext(ctx).Error("Error when getting ticket purchase limit", zap.Error(err))
+		return reservation, err
+	}
+
+	eventStatus := limit.EventStatus
+	if message.WaitlistID != 0 && eventStatus == util.EVENT_STATUS_SOLD_OUT {
+		// the offered stock is what sold the event out.
+		eventStatus = util.EVENT_STATUS_ON_SALE
+	}
+	if err := saleError(eventStatus, limit.SaleStartsAt, limit.SaleEndsAt, util.TimeNow()); err != nil {
+		return reservation, err
+	}
+
+	if err := uc.checkPurchaseLimit(ctx, limit, message.Email, message.Order); err != nil {
+		return reservation, err
+	}
+
+	// the price is locked in from the stock left before this order, so the
+	// order that crosses a stock threshold still gets the tier it saw.
+	ticket, err := uc.ticketRepo.GetTicketByID(ctx, message.TicketID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return reservation, fmt.Errorf("ticket %d: %w", message.TicketID, ErrNotFound)
+		}
+		uc.logger.WithContext(ctx).Error("Error when getting ticket by id", zap.Error(err))
+		return reservation, err
+	}
+	tickets := []model.Ticket{ticket}
+	if err := uc.pricer.resolvePrices(ctx, tickets, model.PriceOptions{Region: message.Region}); err != nil {
+		return reservation, err
+	}
+
+	unitPrice := tickets[0].Price
+	promo, discount, err := uc.holdPromo(ctx, message, limit.EventID,
+		model.Money{Amount: unitPrice.Amount * int64(message.Order), Currency: unitPrice.Currency})
+	if err != nil {
+		return reservation, err
+	}
+
+	if err := uc.takeStock(ctx, message); err != nil {
+		uc.releaseHeldPromo(ctx, message.OrderID, promo)
+		return reservation, err
+	}
+
+	reservation = model.Reservation{
+		OrderID:   message.OrderID,
+		TicketID:  message.TicketID,
+		EventID:   limit.EventID,
+		Email:     message.Email,
+		Quantity:  message.Order,
+		UnitPrice: unitPrice,
+		PriceTier: tickets[0].PriceTier,
+		PromoCode: promo.Code,
+		Discount:  discount,
+		Status:    util.RESERVATION_STATUS_PENDING,
+		ExpiresAt: uc.holdExpiry(),
+	}
+	reservation.ReservationID, err = uc.createReservation(ctx, limit, reservation)
+	if errors.Is(err, repository.ErrDuplicateOrder) {
+		// a concurrent delivery of the order reserved it first. Only the stock
+		// taken here goes back; the reservation and promo of the order are the
+		// other delivery's.
+		uc.giveBackStock(ctx, model.MessageOrderTicket{TicketID: message.TicketID, Order: message.Order})
+		return reservation, fmt.Errorf("order %s already reserved: %w", message.OrderID, ErrConflict)
+	}
+	if err != nil {
+		uc.giveBackStock(ctx, message)
+		uc.releaseHeldPromo(ctx, message.OrderID, promo)
+		return reservation, err
+	}
+
+	return reservation, nil
+}
+
+// giveBackStock returns the stock taken for message when its reservation
+// could not be recorded. Stock claimed from a waitlist offer was held for the
+// offer rather than the order, so it is released without claiming the order.
+func (uc *ticketUsecase) giveBackStock(ctx context.Context, message model.MessageOrderTicket) {
+	orderID := message.OrderID
+	if message.WaitlistID != 0 {
+		orderID = ""
+	}
+	if err := uc.ticketRepo.UpdateStockFailOrderTicket(ctx, message.TicketID, message.Order, orderID); err != nil {
+		uc.logger.WithContext(ctx).Error("Error when giving back stock of order", zap.Error(err))
+		return
+	}
+	if _, err := uc.waitlist.OfferFreedStock(ctx, message.TicketID); err != nil {
+		uc.logger.WithContext(ctx).Error("Error when offering freed stock", zap.Error(err))
+	}
+}
+
+// releaseHeldPromo gives back the use of promo held for orderID by an order
+// that could not be reserved.
+func (uc *ticketUsecase) releaseHeldPromo(ctx context.Context, orderID string, promo model.Promo) {
+	if promo.PromoID == 0 {
+		return
+	}
+	if err := uc.promoRepo.ReleasePromoByOrderID(ctx, orderID); err != nil {
+		uc.logger.WithContext(ctx).Error("Error when releasing promo of order", zap.Error(err))
+	}
+}
+
+// takeStock holds the ordered quantity of the stock for message. A claim
+// of a waitlist offer takes over the stock set aside for the offer instead,
+// which must still be unexpired and match the order exactly.
+func (uc *ticketUsecase) takeStock(ctx context.Context, message model.MessageOrderTicket) error {
+	if message.WaitlistID != 0 {
+		entry := model.WaitlistEntry{
+			WaitlistID: message.WaitlistID,
+			TicketID:   message.TicketID,
+			Email:      message.Email,
+			Quantity:   message.Order,
+		}
+		err := uc.waitlistRepo.ClaimOffer(ctx, entry, message.OrderID, util.TimeNow())
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("waitlist offer %d is not claimable: %w", message.WaitlistID, ErrConflict)
+		}
+		if err != nil {
+			uc.logger.WithContext(ctx).Error("Error when claiming waitlist offer", zap.Error(err))
+		}
+		return err
+	}
+
+	if err := uc.ticketRepo.UpdateStockCreateOrderTicket(ctx, message.TicketID, message.Order, message.OrderID); err != nil {
+		uc.logger.WithContext(ctx).Error("Error when updating stock ticket", zap.Error(err))
+		return stockError(message.TicketID, err)
+	}
+	return nil
+}
+
+// checkPurchaseLimit rejects with a business error an order of quantity
+// tickets that would take email over the per-user limit of the event.
+func (uc *ticketUsecase) checkPurchaseLimit(ctx context.Context, limit model.TicketPurchaseLimit, email string, quantity int) error {
+	maxTicket := uc.purchaseLimit(limit)
+	if maxTicket <= 0 {
+		return nil
+	}
+
+	reserved, err := uc.reservationRepo.GetReservedQuantityByEventAndEmail(ctx, limit.EventID, email)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting reserved quantity", zap.Error(err))
+		return err
+	}
+
+	if reserved+quantity > maxTicket {
+		return &model.BusinessError{
+			Code:    util.ERROR_PURCHASE_LIMIT_CODE,
+			Message: fmt.Sprintf(util.ERROR_PURCHASE_LIMIT_MSG, maxTicket, reserved),
+		}
+	}
+	return nil
+}
+
+// purchaseLimit returns how many tickets of the event of limit one user may
+// reserve, or zero when there is no limit.
+func (uc *ticketUsecase) purchaseLimit(limit model.TicketPurchaseLimit) int {
+	if limit.MaxTicketPerUser != 0 {
+		return limit.MaxTicketPerUser
+	}
+	return uc.maxTicketPerUser
+}
+
+// createReservation records reservation. Under a per-user limit the tickets
+// of the buyer are counted again in the same transaction, as checkPurchaseLimit
+// does not see orders of the same buyer racing with this one.
+func (uc *ticketUsecase) createReservation(ctx context.Context, limit model.TicketPurchaseLimit,
+	reservation model.Reservation) (int, error) {
+	maxTicket := uc.purchaseLimit(limit)
+	if maxTicket <= 0 {
+		reservationID, err := uc.reservationRepo.CreateReservation(ctx, reservation)
+		if err != nil && !errors.Is(err, repository.ErrDuplicateOrder) {
+			uc.logger.WithContext(ctx).Error("Error when creating reservation", zap.Error(err))
+		}
+		return reservationID, err
+	}
+
+	reservationID, err := uc.reservationRepo.CreateReservationWithinLimit(ctx, reservation, maxTicket)
+	if errors.Is(err, repository.ErrPurchaseLimit) {
+		if err := uc.checkPurchaseLimit(ctx, limit, reservation.Email, reservation.Quantity); err != nil {
+			return 0, err
+		}
+		return 0, fmt.Errorf("purchase limit of event %d: %w", reservation.EventID, ErrConflict)
+	}
+	if err != nil && !errors.Is(err, repository.ErrDuplicateOrder) {
+		uc.logger.WithContext(ctx).Error("Error when creating reservation", zap.Error(err))
+	}
+	return reservationID, err
+}
+
+// reserveListing reserves the resale listing of message for the buyer
+// message.Email under message.OrderID. No stock moves: the ticket exists
+// already and only changes hands once the order is paid. The listing counts
+// as one ticket towards the per-user limit of its event.
+func (uc *ticketUsecase) reserveListing(ctx context.Context, message model.MessageOrderTicket) (model.Reservation, error) {
+	var reservation model.Reservation
+
+	if message.OrderID == "" || message.Email == "" {
+		return reservation, fmt.Errorf("resale order needs an order id and a buyer: %w", ErrInvalidParam)
+	}
+
+	listing, err := uc.resaleRepo.GetListingByID(ctx, message.ListingID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return reservation, fmt.Errorf("listing %d: %w", message.ListingID, ErrNotFound)
+		}
+		uc.logger.WithContext(ctx).Error("Error when getting resale listing by id", zap.Error(err))
+		return reservation, err
+	}
+	if listing.Status != util.RESALE_LISTING_STATUS_ACTIVE {
+		return reservation, fmt.Errorf("listing %d is %s: %w", listing.ListingID, listing.Status, ErrConflict)
+	}
+	if strings.EqualFold(listing.SellerEmail, message.Email) {
+		return reservation, fmt.Errorf("listing %d is your own: %w", listing.ListingID, ErrInvalidParam)
+	}
+
+	limit, err := uc.ticketRepo.GetTicketPurchaseLimit(ctx, listing.TicketID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return reservation, fmt.Errorf("ticket %d: %w", listing.TicketID, ErrNotFound)
+		}
+		uc.logger.WithContext(ctx).Error("Error when getting ticket purchase limit", zap.Error(err))
+		return reservation, err
+	}
+	if limit.EventStatus == util.EVENT_STATUS_CANCELLED || limit.EventStatus == util.EVENT_STATUS_PAST {
+		return reservation, &model.BusinessError{Code: util.ERROR_RESALE_CLOSED_CODE, Message: util.ERROR_RESALE_CLOSED_MSG}
+	}
+	if err := uc.checkPurchaseLimit(ctx, limit, message.Email, 1); err != nil {
+		return reservation, err
+	}
+
+	if err := uc.resaleRepo.ReserveListing(ctx, listing.ListingID, message.OrderID, message.Email); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return reservation, fmt.Errorf("listing %d is no longer available: %w", listing.ListingID, ErrConflict)
+		}
+		uc.logger.WithContext(ctx).Error("Error when reserving resale listing", zap.Error(err))
+		return reservation, err
+	}
+
+	reservation = model.Reservation{
+		OrderID:   message.OrderID,
+		TicketID:  listing.TicketID,
+		EventID:   listing.EventID,
+		Email:     message.Email,
+		Quantity:  1,
+		UnitPrice: listing.Total,
+		PriceTier: util.RESALE_PRICE_TIER,
+		Discount:  model.Money{Currency: listing.Total.Currency},
+		Status:    util.RESERVATION_STATUS_PENDING,
+		ExpiresAt: uc.holdExpiry(),
+	}
+	reservation.ReservationID, err = uc.createReservation(ctx, limit, reservation)
+	if err != nil {
+		if releaseErr := uc.resaleRepo.ReleaseListing(ctx, listing.ListingID, message.OrderID); releaseErr != nil {
+			uc.logger.WithContext(ctx).Error("Error when releasing resale listing", zap.Error(releaseErr))
+		}
+		if errors.Is(err, repository.ErrDuplicateOrder) {
+			err = fmt.Errorf("order %s already reserved: %w", message.OrderID, ErrConflict)
+		}
+		return reservation, err
+	}
+
+	return reservation, nil
+}
+
+// holdPromo applies the promo code of message to amount, the price of the
+// order, and holds one use of it for the order. Without a code it returns the
+// zero promo and no discount.
+func (uc *ticketUsecase) holdPromo(ctx context.Context, message model.MessageOrderTicket, eventID int,
+	amount model.Money) (model.Promo, model.Money, error) {
+	discount := model.Money{Currency: amount.Currency}
+	if message.PromoCode == "" {
+		return model.Promo{}, discount, nil
+	}
+
+	if message.OrderID == "" {
+		return model.Promo{}, discount, fmt.Errorf("promo code needs an order id: %w", ErrInvalidParam)
+	}
+
+	promo, err := usablePromo(ctx, uc.promoRepo, uc.logger, message.PromoCode, message.Email, util.TimeNow())
+	if err != nil {
+		return model.Promo{}, discount, err
+	}
+	if !promoApplies(promo, eventID) {
+		return model.Promo{}, discount, promoError(util.ERROR_PROMO_NOT_APPLICABLE_CODE, util.ERROR_PROMO_NOT_APPLICABLE_MSG, promo.Code)
+	}
+
+	discount, err = uc.pricer.discount(ctx, promo, amount)
+	if err != nil {
+		return model.Promo{}, discount, err
+	}
+
+	err = uc.promoRepo.HoldPromo(ctx, model.PromoRedemption{
+		PromoID:  promo.PromoID,
+		OrderID:  message.OrderID,
+		Email:    message.Email,
+		Discount: discount,
+	})
+	switch {
+	case errors.Is(err, repository.ErrPromoExhausted):
+		return model.Promo{}, discount, promoError(util.ERROR_PROMO_EXHAUSTED_CODE, util.ERROR_PROMO_EXHAUSTED_MSG, promo.Code)
+	case errors.Is(err, repository.ErrPromoAlreadyUsed):
+		return model.Promo{}, discount, promoError(util.ERROR_PROMO_ALREADY_USED_CODE, util.ERROR_PROMO_ALREADY_USED_MSG, promo.Code)
+	case err != nil:
+		uc.logger.WithContext(ctx).Error("Error when holding promo", zap.Error(err))
+		return model.Promo{}, discount, err
+	}
+
+	return promo, discount, nil
+}
+
+func (uc *ticketUsecase) updateReservationStatus(ctx context.Context, orderID, status string) error {
+	if orderID == "" {
+		return nil
+	}
+
+	if err := uc.reservationRepo.UpdateReservationStatusByOrderID(ctx, orderID, status); err != nil {
+		uc.logger.WithContext(ctx).Error("Error when updating reservation status", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// settledAs tells whether the reservation of orderID already moved to
+// status. The seats, promo and tickets of such an order are settled again,
+// which changes nothing once they are, so a message that failed after the
+// stock moved completes on redelivery.
+func (uc *ticketUsecase) settledAs(ctx context.Context, orderID, status string) (bool, error) {
+	reservation, err := uc.reservationRepo.GetReservationByOrderID(ctx, orderID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting reservation by order id", zap.Error(err))
+		return false, err
+	}
+	return reservation.Status == status, nil
+}
+
+// settleSeats sells the seats held by orderID once the order is paid, or
+// returns them to sale when it failed. Orders without seats are unaffected.
+func (uc *ticketUsecase) settleSeats(ctx context.Context, orderID string, sold bool) error {
+	if orderID == "" {
+		return nil
+	}
+
+	var err error
+	if sold {
+		err = uc.seatRepo.SellSeatsByOrderID(ctx, orderID)
+	} else {
+		err = uc.seatRepo.ReleaseSeatsByOrderID(ctx, orderID)
+	}
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when updating seats of order", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// settlePromo counts the promo held by orderID as used once the order is
+// paid, or gives the use back when it failed.
+func (uc *ticketUsecase) settlePromo(ctx context.Context, orderID string, redeemed bool) error {
+	if orderID == "" {
+		return nil
+	}
+
+	var err error
+	if redeemed {
+		err = uc.promoRepo.RedeemPromoByOrderID(ctx, orderID)
+	} else {
+		err = uc.promoRepo.ReleasePromoByOrderID(ctx, orderID)
+	}
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when updating promo of order", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// settleListing completes the resale order of message once it is paid: the
+// ticket moves to the buyer under a new code, so the seller's QR stops
+// working. When the ticket can no longer change hands, e.g. because the
+// seller was let in with it meanwhile, the sale is called off and the paid
+// reservation waits for its refund. A failed order puts the listing back on
+// sale.
+func (uc *ticketUsecase) settleListing(ctx context.Context, message model.MessageOrderTicket, paid bool) error {
+	listing, err := uc.resaleRepo.GetListingByID(ctx, message.ListingID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("listing %d: %w", message.ListingID, ErrNotFound)
+		}
+		uc.logger.WithContext(ctx).Error("Error when getting resale listing by id", zap.Error(err))
+		return err
+	}
+
+	if !paid {
+		err := uc.resaleRepo.ReleaseListing(ctx, listing.ListingID, message.OrderID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			uc.logger.WithContext(ctx).Error("Error when releasing resale listing", zap.Error(err))
+			return err
+		}
+		return uc.updateReservationStatus(ctx, message.OrderID, util.RESERVATION_STATUS_RELEASED)
+	}
+
+	if listing.Status == util.RESALE_LISTING_STATUS_SOLD && listing.OrderID == message.OrderID {
+		return nil
+	}
+
+	code, err := ticketcode.NewCode()
+	if err != nil {
+		return err
+	}
+	listing.OrderID = message.OrderID
+	err = uc.resaleRepo.SellListing(ctx, listing, code, util.TimeNow())
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		uc.logger.WithContext(ctx).Info("Resale listing can no longer be sold, order waits for refund",
+			zap.Int("listing_id", listing.ListingID), zap.String("order_id", message.OrderID))
+		err := uc.resaleRepo.CancelListing(ctx, listing.ListingID, message.OrderID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			uc.logger.WithContext(ctx).Error("Error when cancelling resale listing", zap.Error(err))
+			return err
+		}
+		return uc.updateReservationStatus(ctx, message.OrderID, util.RESERVATION_STATUS_REFUND_PENDING)
+	case err != nil:
+		uc.logger.WithContext(ctx).Error("Error when selling resale listing", zap.Error(err))
+		return err
+	}
+
+	return uc.updateReservationStatus(ctx, message.OrderID, util.RESERVATION_STATUS_CONFIRMED)
+}
+
+// issueTickets issues one ticket with its own code per unit of the paid
+// reservation of orderID. Units issued before are skipped, so a confirmation
+// delivered twice does not issue twice. Orders placed without a reservation
+// have no known owner and get no tickets.
+func (uc *ticketUsecase) issueTickets(ctx context.Context, orderID string) error {
+	if orderID == "" {
+		return nil
+	}
+
+	reservation, err := uc.reservationRepo.GetReservationByOrderID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		uc.logger.WithContext(ctx).Error("Error when getting reservation of order", zap.Error(err))
+		return err
+	}
+
+	existing, err := uc.issuedTicketRepo.GetIssuedTicketsByReservationID(ctx, reservation.ReservationID)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting issued tickets of reservation", zap.Error(err))
+		return err
+	}
+	issued := make(map[int]bool, len(existing))
+	for _, ticket := range existing {
+		issued[ticket.UnitNo] = true
+	}
+
+	var tickets []model.IssuedTicket
+	for unit := 1; unit <= reservation.Quantity; unit++ {
+		if issued[unit] {
+			continue
+		}
+		code, err := ticketcode.NewCode()
+		if err != nil {
+			return err
+		}
+		tickets = append(tickets, model.IssuedTicket{
+			Code:          code,
+			ReservationID: reservation.ReservationID,
+			UnitNo:        unit,
+			OrderID:       orderID,
+			TicketID:      reservation.TicketID,
+			EventID:       reservation.EventID,
+			Email:         reservation.Email,
+			Status:        util.ISSUED_TICKET_STATUS_VALID,
+		})
+	}
+
+	if err := uc.issuedTicketRepo.CreateIssuedTickets(ctx, tickets); err != nil {
+		uc.logger.WithContext(ctx).Error("Error when issuing tickets", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (uc *ticketUsecase) GetStockTicketGroupByContinent(ctx context.Context) ([]model.StockTicket, error) {
+	ctx, span := tracer.Start(ctx, "TicketUsecase.GetStockTicketGroupByContinent")
+	defer span.End()
+
 	var stockTickets []model.StockTicket
 
-	stockTickets, err := uc.ticketRepo.GetStockTicketGroupByContinent()
+	stockTickets, err := uc.ticketRepo.GetStockTicketGroupByContinent(ctx)
 	if err != nil {
-		uc.logger.Error("Error when getting stock ticket group by continent", zap.Error(err))
+		uc.logger.WithContext(ctx).Error("Error when getting stock ticket group by continent", zap.Error(err))
 		return stockTickets, err
 	}
 
 	return stockTickets, nil
 }
 
-func (uc *ticketUsecase) GetTicketEventByTicketID(TicketID int) (model.TicketEvent, error) {
+func (uc *ticketUsecase) GetTicketEventByTicketID(ctx context.Context, TicketID int, opts model.PriceOptions) (model.TicketEvent, error) {
+	ctx, span := tracer.Start(ctx, "TicketUsecase.GetTicketEventByTicketID")
+	defer span.End()
+
 	var ticketEvent model.TicketEvent
 
-	ticketEvent, err := uc.ticketRepo.GetTicketEventByTicketID(TicketID)
+	ticketEvent, err := uc.ticketRepo.GetTicketEventByTicketID(ctx, TicketID)
 	if err != nil {
-		uc.logger.Error("Error when getting ticket event by continent", zap.Error(err))
+		if errors.Is(err, sql.ErrNoRows) {
+			return ticketEvent, fmt.Errorf("ticket %d: %w", TicketID, ErrNotFound)
+		}
+		uc.logger.WithContext(ctx).Error("Error when getting ticket event by continent", zap.Error(err))
 		return ticketEvent, err
 	}
 
+	tickets := []model.Ticket{{TicketID: ticketEvent.TicketID, Price: ticketEvent.Price, Available: ticketEvent.Stock}}
+	if err := uc.pricer.resolvePrices(ctx, tickets, opts); err != nil {
+		return ticketEvent, err
+	}
+	ticketEvent.Price = tickets[0].Price
+	ticketEvent.PriceTier = tickets[0].PriceTier
+
 	return ticketEvent, nil
 }
+
+// stockError translates the sql.ErrNoRows returned by a reservation of stock
+// into ErrInsufficientStock.
+func stockError(ticketID int, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("ticket %d: %w", ticketID, ErrInsufficientStock)
+	}
+	return err
+}
+
+// heldStockError translates the sql.ErrNoRows returned by a confirmation or
+// release of more stock than the ticket holds into ErrConflict. The counts
+// are left alone, for inventory reconciliation to find out why.
+func heldStockError(ticketID, quantity int, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("ticket %d holds less than %d: %w", ticketID, quantity, ErrConflict)
+	}
+	return err
+}
@@ -1,76 +1,154 @@
 package usecase
 
 import (
+	"context"
+	"database/sql"
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/currency"
 	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/repository"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
-	"go.uber.org/zap"
+	"math/big"
 	"testing"
+	"time"
 )
 
 type MockTicketPersister struct {
 	mock.Mock
 }
 
-func (m *MockTicketPersister) GetAvailableTicketByContinent(continent string) ([]model.Ticket, error) {
-	args := m.Called(continent)
+func (m *MockTicketPersister) GetAvailableTicketByContinent(ctx context.Context, continent string) ([]model.Ticket, error) {
+	args := m.Called(ctx, continent)
 	return args.Get(0).([]model.Ticket), args.Error(1)
 }
 
-func (m *MockTicketPersister) GetTicketByContinent(continent string) ([]model.Ticket, error) {
-	args := m.Called(continent)
+func (m *MockTicketPersister) GetTicketByContinent(ctx context.Context, continent string) ([]model.Ticket, error) {
+	args := m.Called(ctx, continent)
 	return args.Get(0).([]model.Ticket), args.Error(1)
 }
 
-func (m *MockTicketPersister) UpdateStockCreateOrderTicket(ticketID, order int) error {
-	args := m.Called(ticketID, order)
+func (m *MockTicketPersister) UpdateStockCreateOrderTicket(ctx context.Context, ticketID, order int, orderID string) error {
+	args := m.Called(ctx, ticketID, order, orderID)
 	return args.Error(0)
 }
 
-func (m *MockTicketPersister) UpdateStockSuccessOrderTicket(ticketID, order int) error {
-	args := m.Called(ticketID, order)
+func (m *MockTicketPersister) UpdateStockSuccessOrderTicket(ctx context.Context, ticketID, order int, orderID string) error {
+	args := m.Called(ctx, ticketID, order, orderID)
 	return args.Error(0)
 }
 
-func (m *MockTicketPersister) UpdateStockFailOrderTicket(ticketID, order int) error {
-	args := m.Called(ticketID, order)
+func (m *MockTicketPersister) UpdateStockFailOrderTicket(ctx context.Context, ticketID, order int, orderID string) error {
+	args := m.Called(ctx, ticketID, order, orderID)
 	return args.Error(0)
 }
 
-func (m *MockTicketPersister) GetStockTicketGroupByContinent() ([]model.StockTicket, error) {
-	args := m.Called()
+func (m *MockTicketPersister) UpdateStockFailReservation(ctx context.Context, reservationID, ticketID, quantity int) error {
+	args := m.Called(ctx, reservationID, ticketID, quantity)
+	return args.Error(0)
+}
+
+func (m *MockTicketPersister) GetStockTicketGroupByContinent(ctx context.Context) ([]model.StockTicket, error) {
+	args := m.Called(ctx)
 	return args.Get(0).([]model.StockTicket), args.Error(1)
 }
 
-func (m *MockTicketPersister) GetTicketEventByTicketID(ticketID int) (model.TicketEvent, error) {
-	args := m.Called(ticketID)
+func (m *MockTicketPersister) GetTicketEventByTicketID(ctx context.Context, ticketID int) (model.TicketEvent, error) {
+	args := m.Called(ctx, ticketID)
 	return args.Get(0).(model.TicketEvent), args.Error(1)
 }
 
-func (m *MockTicketPersister) GetAvailableTicketByType(ticketType string) ([]model.Ticket, error) {
-	args := m.Called(ticketType)
+func (m *MockTicketPersister) GetAvailableTicketByType(ctx context.Context, ticketType string) ([]model.Ticket, error) {
+	args := m.Called(ctx, ticketType)
 	return args.Get(0).([]model.Ticket), args.Error(1)
 }
 
-func (m *MockTicketPersister) GetTicketByID(ticketID int) (model.Ticket, error) {
-	args := m.Called(ticketID)
+func (m *MockTicketPersister) GetTicketByID(ctx context.Context, ticketID int) (model.Ticket, error) {
+	args := m.Called(ctx, ticketID)
 	return args.Get(0).(model.Ticket), args.Error(1)
 
 }
 
+func (m *MockTicketPersister) GetTicketPurchaseLimit(ctx context.Context, ticketID int) (model.TicketPurchaseLimit, error) {
+	args := m.Called(ctx, ticketID)
+	return args.Get(0).(model.TicketPurchaseLimit), args.Error(1)
+}
+
+func (m *MockTicketPersister) GetTicketInventory(ctx context.Context) ([]model.Ticket, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.Ticket), args.Error(1)
+}
+
+func (m *MockTicketPersister) GetTicketPricesByRegion(ctx context.Context, ticketIDs []int, region string) (map[int]model.Money, error) {
+	args := m.Called(ctx, ticketIDs, region)
+	return args.Get(0).(map[int]model.Money), args.Error(1)
+}
+
+func (m *MockTicketPersister) GetTicketPriceSchedules(ctx context.Context, ticketIDs []int) (map[int][]model.PriceSchedule, error) {
+	args := m.Called(ctx, ticketIDs)
+	return args.Get(0).(map[int][]model.PriceSchedule), args.Error(1)
+}
+
+type MockReservationPersister struct {
+	mock.Mock
+}
+
+func (m *MockReservationPersister) CreateReservation(ctx context.Context, reservation model.Reservation) (int, error) {
+	args := m.Called(ctx, reservation)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockReservationPersister) CreateReservationWithinLimit(ctx context.Context, reservation model.Reservation, maxQuantity int) (int, error) {
+	args := m.Called(ctx, reservation, maxQuantity)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockReservationPersister) GetExpiredReservations(ctx context.Context, now time.Time) ([]model.Reservation, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).([]model.Reservation), args.Error(1)
+}
+
+func (m *MockReservationPersister) GetReservationByOrderID(ctx context.Context, orderID string) (model.Reservation, error) {
+	args := m.Called(ctx, orderID)
+	return args.Get(0).(model.Reservation), args.Error(1)
+}
+
+func (m *MockReservationPersister) GetReservedQuantityByEventAndEmail(ctx context.Context, eventID int, email string) (int, error) {
+	args := m.Called(ctx, eventID, email)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockReservationPersister) UpdateReservationStatusByOrderID(ctx context.Context, orderID, status string) error {
+	args := m.Called(ctx, orderID, status)
+	return args.Error(0)
+}
+
+func (m *MockReservationPersister) GetReservationsByEventAndStatus(ctx context.Context, eventID int, status string) ([]model.Reservation, error) {
+	args := m.Called(ctx, eventID, status)
+	return args.Get(0).([]model.Reservation), args.Error(1)
+}
+
+func (m *MockReservationPersister) UpdateReservationStatus(ctx context.Context, reservationID int, from, to string) error {
+	args := m.Called(ctx, reservationID, from, to)
+	return args.Error(0)
+}
+
 func TestTicketUsecase(t *testing.T) {
 	mockRepo := new(MockTicketPersister)
-	mockLogger := zap.NewNop()
-	ticketUsecase := NewTicketUsecase(mockRepo, mockLogger)
+	mockReservationRepo := new(MockReservationPersister)
+	mockLogger := config.NewNopLogger()
+	ticketUsecase := NewTicketUsecase(mockRepo, mockReservationRepo, new(MockSeatPersister), new(MockPromoPersister), new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), new(MockWaitlistOfferer), currency.NewStaticRates("", nil), mockLogger, 0, 0)
 
 	// Define your mock data here
 	mockTickets := []model.Ticket{
 		{
 			TicketID:      1,
 			Type:          "Type1",
-			Price:         100,
+			Price:         model.Money{Amount: 10000, Currency: "IDR"},
 			ContinentName: "Asia",
-			Stock:         10,
+			Available:     10,
 			CountryName:   "Country1",
 			CountryCity:   "City1",
 			CountryPlace:  "Place1",
@@ -78,33 +156,561 @@ func TestTicketUsecase(t *testing.T) {
 	}
 
 	// Set up the mock responses
-	mockRepo.On("GetAvailableTicketByContinent", "Asia").Return(mockTickets, nil)
-	mockRepo.On("GetTicketByContinent", "Asia").Return(mockTickets, nil)
-	mockRepo.On("GetStockTicketGroupByContinent").Return([]model.StockTicket{}, nil)
-	mockRepo.On("GetTicketEventByTicketID", 1).Return(model.TicketEvent{}, nil)
-	mockRepo.On("GetAvailableTicketByType", "Type1").Return(mockTickets, nil)
+	mockRepo.On("GetAvailableTicketByContinent", mock.Anything, "Asia").Return(mockTickets, nil)
+	mockRepo.On("GetTicketByContinent", mock.Anything, "Asia").Return(mockTickets, nil)
+	mockRepo.On("GetStockTicketGroupByContinent", mock.Anything).Return([]model.StockTicket{}, nil)
+	mockRepo.On("GetTicketEventByTicketID", mock.Anything, 1).Return(model.TicketEvent{TicketID: 1}, nil)
+	mockRepo.On("GetAvailableTicketByType", mock.Anything, "Type1").Return(mockTickets, nil)
+	mockRepo.On("GetTicketPriceSchedules", mock.Anything, []int{1}).Return(map[int][]model.PriceSchedule{}, nil)
 
 	// Call the methods and assert the results
-	tickets, err := ticketUsecase.GetAvailableTicketByContinent("Asia")
+	tickets, err := ticketUsecase.GetAvailableTicketByContinent(context.Background(), "Asia", model.PriceOptions{})
 	assert.NoError(t, err)
 	assert.NotNil(t, tickets)
 
-	tickets, err = ticketUsecase.GetTicketByContinent("Asia")
+	tickets, err = ticketUsecase.GetTicketByContinent(context.Background(), "Asia", model.PriceOptions{})
 	assert.NoError(t, err)
 	assert.NotNil(t, tickets)
 
-	stockTickets, err := ticketUsecase.GetStockTicketGroupByContinent()
+	stockTickets, err := ticketUsecase.GetStockTicketGroupByContinent(context.Background())
 	assert.NoError(t, err)
 	assert.NotNil(t, stockTickets)
 
-	ticketEvent, err := ticketUsecase.GetTicketEventByTicketID(1)
+	ticketEvent, err := ticketUsecase.GetTicketEventByTicketID(context.Background(), 1, model.PriceOptions{})
 	assert.NoError(t, err)
 	assert.NotNil(t, ticketEvent)
 
-	tickets, err = ticketUsecase.GetAvailableTicketByType("Type1")
+	tickets, err = ticketUsecase.GetAvailableTicketByType(context.Background(), "Type1", model.PriceOptions{})
 	assert.NoError(t, err)
 	assert.NotNil(t, tickets)
 
 	// Assert that the mock expectations were met
 	mockRepo.AssertExpectations(t)
 }
+
+func TestReserveTicket(t *testing.T) {
+	message := model.MessageOrderTicket{TicketID: 1, Order: 2, OrderID: "order-1", Email: "user@mail.com"}
+
+	t.Run("should reserve ticket within purchase limit", func(t *testing.T) {
+		mockRepo := new(MockTicketPersister)
+		mockReservationRepo := new(MockReservationPersister)
+		ticketUsecase := NewTicketUsecase(mockRepo, mockReservationRepo, new(MockSeatPersister), new(MockPromoPersister), new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), new(MockWaitlistOfferer), currency.NewStaticRates("", nil), config.NewNopLogger(), 4, 0)
+
+		mockReservationRepo.On("GetReservationByOrderID", mock.Anything, "order-1").Return(model.Reservation{}, sql.ErrNoRows)
+		mockRepo.On("GetTicketPurchaseLimit", mock.Anything, 1).Return(model.TicketPurchaseLimit{TicketID: 1, EventID: 3, EventStatus: "on_sale"}, nil)
+		mockReservationRepo.On("GetReservedQuantityByEventAndEmail", mock.Anything, 3, "user@mail.com").Return(2, nil)
+		mockRepo.On("GetTicketByID", mock.Anything, 1).Return(model.Ticket{TicketID: 1, Price: model.Money{Amount: 200000, Currency: "IDR"}, Available: 10}, nil)
+		mockRepo.On("GetTicketPriceSchedules", mock.Anything, []int{1}).Return(map[int][]model.PriceSchedule{}, nil)
+		mockRepo.On("UpdateStockCreateOrderTicket", mock.Anything, 1, 2, "order-1").Return(nil)
+		mockReservationRepo.On("CreateReservationWithinLimit", mock.Anything, mock.AnythingOfType("model.Reservation"), 4).Return(9, nil)
+
+		reservation, err := ticketUsecase.ReserveTicket(context.Background(), message)
+		assert.NoError(t, err)
+		assert.Equal(t, 9, reservation.ReservationID)
+		assert.Equal(t, 3, reservation.EventID)
+		assert.Equal(t, "pending", reservation.Status)
+
+		mockRepo.AssertExpectations(t)
+		mockReservationRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return business error when purchase limit exceeded", func(t *testing.T) {
+		mockRepo := new(MockTicketPersister)
+		mockReservationRepo := new(MockReservationPersister)
+		ticketUsecase := NewTicketUsecase(mockRepo, mockReservationRepo, new(MockSeatPersister), new(MockPromoPersister), new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), new(MockWaitlistOfferer), currency.NewStaticRates("", nil), config.NewNopLogger(), 10, 0)
+
+		mockReservationRepo.On("GetReservationByOrderID", mock.Anything, "order-1").Return(model.Reservation{}, sql.ErrNoRows)
+		mockRepo.On("GetTicketPurchaseLimit", mock.Anything, 1).Return(model.TicketPurchaseLimit{TicketID: 1, EventID: 3, MaxTicketPerUser: 3, EventStatus: "on_sale"}, nil)
+		mockReservationRepo.On("GetReservedQuantityByEventAndEmail", mock.Anything, 3, "user@mail.com").Return(2, nil)
+
+		rejected := testutil.ToFloat64(stockOperationsTotal.WithLabelValues("reserve", "rejected"))
+
+		_, err := ticketUsecase.ReserveTicket(context.Background(), message)
+
+		assert.Equal(t, rejected+1, testutil.ToFloat64(stockOperationsTotal.WithLabelValues("reserve", "rejected")))
+		var businessErr *model.BusinessError
+		assert.ErrorAs(t, err, &businessErr)
+		assert.Equal(t, 4104, businessErr.Code)
+		mockRepo.AssertNotCalled(t, "UpdateStockCreateOrderTicket", mock.Anything, 1, 2, mock.Anything)
+	})
+
+	t.Run("should give back the stock of an order that lost the race for the purchase limit", func(t *testing.T) {
+		mockRepo := new(MockTicketPersister)
+		mockReservationRepo := new(MockReservationPersister)
+		waitlist := new(MockWaitlistOfferer)
+		ticketUsecase := NewTicketUsecase(mockRepo, mockReservationRepo, new(MockSeatPersister), new(MockPromoPersister), new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), waitlist, currency.NewStaticRates("", nil), config.NewNopLogger(), 4, 0)
+
+		mockReservationRepo.On("GetReservationByOrderID", mock.Anything, "order-1").Return(model.Reservation{}, sql.ErrNoRows)
+		mockRepo.On("GetTicketPurchaseLimit", mock.Anything, 1).Return(model.TicketPurchaseLimit{TicketID: 1, EventID: 3, EventStatus: "on_sale"}, nil)
+		mockReservationRepo.On("GetReservedQuantityByEventAndEmail", mock.Anything, 3, "user@mail.com").Return(2, nil).Once()
+		mockRepo.On("GetTicketByID", mock.Anything, 1).Return(model.Ticket{TicketID: 1, Price: model.Money{Amount: 200000, Currency: "IDR"}, Available: 10}, nil)
+		mockRepo.On("GetTicketPriceSchedules", mock.Anything, []int{1}).Return(map[int][]model.PriceSchedule{}, nil)
+		mockRepo.On("UpdateStockCreateOrderTicket", mock.Anything, 1, 2, "order-1").Return(nil)
+		mockReservationRepo.On("CreateReservationWithinLimit", mock.Anything, mock.AnythingOfType("model.Reservation"), 4).
+			Return(0, repository.ErrPurchaseLimit)
+		mockReservationRepo.On("GetReservedQuantityByEventAndEmail", mock.Anything, 3, "user@mail.com").Return(4, nil).Once()
+		mockRepo.On("UpdateStockFailOrderTicket", mock.Anything, 1, 2, "order-1").Return(nil)
+		waitlist.On("OfferFreedStock", mock.Anything, 1).Return(0, nil)
+
+		_, err := ticketUsecase.ReserveTicket(context.Background(), message)
+		var businessErr *model.BusinessError
+		assert.ErrorAs(t, err, &businessErr)
+		assert.Equal(t, 4104, businessErr.Code)
+		assert.Equal(t, "maximum 4 tickets per user for this event, 4 already reserved", businessErr.Message)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should give back only its own stock when a concurrent delivery reserved the order", func(t *testing.T) {
+		mockRepo := new(MockTicketPersister)
+		mockReservationRepo := new(MockReservationPersister)
+		mockPromoRepo := new(MockPromoPersister)
+		waitlist := new(MockWaitlistOfferer)
+		ticketUsecase := NewTicketUsecase(mockRepo, mockReservationRepo, new(MockSeatPersister), mockPromoRepo, new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), waitlist, currency.NewStaticRates("", nil), config.NewNopLogger(), 0, 0)
+
+		mockReservationRepo.On("GetReservationByOrderID", mock.Anything, "order-1").Return(model.Reservation{}, sql.ErrNoRows)
+		mockRepo.On("GetTicketPurchaseLimit", mock.Anything, 1).Return(model.TicketPurchaseLimit{TicketID: 1, EventID: 3, EventStatus: "on_sale"}, nil)
+		mockRepo.On("GetTicketByID", mock.Anything, 1).Return(model.Ticket{TicketID: 1, Price: model.Money{Amount: 200000, Currency: "IDR"}, Available: 10}, nil)
+		mockRepo.On("GetTicketPriceSchedules", mock.Anything, []int{1}).Return(map[int][]model.PriceSchedule{}, nil)
+		mockRepo.On("UpdateStockCreateOrderTicket", mock.Anything, 1, 2, "order-1").Return(nil)
+		mockReservationRepo.On("CreateReservation", mock.Anything, mock.AnythingOfType("model.Reservation")).Return(0, repository.ErrDuplicateOrder)
+		mockRepo.On("UpdateStockFailOrderTicket", mock.Anything, 1, 2, "").Return(nil)
+		waitlist.On("OfferFreedStock", mock.Anything, 1).Return(0, nil)
+
+		_, err := ticketUsecase.ReserveTicket(context.Background(), message)
+		assert.ErrorIs(t, err, ErrConflict)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "UpdateStockFailOrderTicket", mock.Anything, 1, 2, "order-1")
+		mockPromoRepo.AssertNotCalled(t, "ReleasePromoByOrderID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should skip limit check when no limit configured", func(t *testing.T) {
+		mockRepo := new(MockTicketPersister)
+		mockReservationRepo := new(MockReservationPersister)
+		ticketUsecase := NewTicketUsecase(mockRepo, mockReservationRepo, new(MockSeatPersister), new(MockPromoPersister), new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), new(MockWaitlistOfferer), currency.NewStaticRates("", nil), config.NewNopLogger(), 0, 0)
+
+		mockReservationRepo.On("GetReservationByOrderID", mock.Anything, "order-1").Return(model.Reservation{}, sql.ErrNoRows)
+		mockRepo.On("GetTicketPurchaseLimit", mock.Anything, 1).Return(model.TicketPurchaseLimit{TicketID: 1, EventID: 3, EventStatus: "on_sale"}, nil)
+		mockRepo.On("GetTicketByID", mock.Anything, 1).Return(model.Ticket{TicketID: 1, Price: model.Money{Amount: 200000, Currency: "IDR"}, Available: 10}, nil)
+		mockRepo.On("GetTicketPriceSchedules", mock.Anything, []int{1}).Return(map[int][]model.PriceSchedule{}, nil)
+		mockRepo.On("UpdateStockCreateOrderTicket", mock.Anything, 1, 2, "order-1").Return(nil)
+		mockReservationRepo.On("CreateReservation", mock.Anything, mock.AnythingOfType("model.Reservation")).Return(1, nil)
+
+		_, err := ticketUsecase.ReserveTicket(context.Background(), message)
+		assert.NoError(t, err)
+		mockReservationRepo.AssertNotCalled(t, "GetReservedQuantityByEventAndEmail", mock.Anything, 3, "user@mail.com")
+	})
+
+	t.Run("should lock in the active price tier", func(t *testing.T) {
+		mockRepo := new(MockTicketPersister)
+		mockReservationRepo := new(MockReservationPersister)
+		ticketUsecase := NewTicketUsecase(mockRepo, mockReservationRepo, new(MockSeatPersister), new(MockPromoPersister), new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), new(MockWaitlistOfferer), currency.NewStaticRates("", nil), config.NewNopLogger(), 0, 0)
+
+		minStock := 5
+		mockReservationRepo.On("GetReservationByOrderID", mock.Anything, "order-1").Return(model.Reservation{}, sql.ErrNoRows)
+		mockRepo.On("GetTicketPurchaseLimit", mock.Anything, 1).Return(model.TicketPurchaseLimit{TicketID: 1, EventID: 3, EventStatus: "on_sale"}, nil)
+		mockRepo.On("GetTicketByID", mock.Anything, 1).Return(model.Ticket{TicketID: 1, Price: model.Money{Amount: 200000, Currency: "IDR"}, Available: 5}, nil)
+		mockRepo.On("GetTicketPriceSchedules", mock.Anything, []int{1}).Return(map[int][]model.PriceSchedule{
+			1: {{ScheduleID: 1, TicketID: 1, Name: "early-bird", Price: 150000, MinStock: &minStock}},
+		}, nil)
+		mockRepo.On("UpdateStockCreateOrderTicket", mock.Anything, 1, 2, "order-1").Return(nil)
+		mockReservationRepo.On("CreateReservation", mock.Anything, mock.MatchedBy(func(reservation model.Reservation) bool {
+			return reservation.UnitPrice == model.Money{Amount: 150000, Currency: "IDR"} && reservation.PriceTier == "early-bird"
+		})).Return(9, nil)
+
+		reservation, err := ticketUsecase.ReserveTicket(context.Background(), message)
+		assert.NoError(t, err)
+		assert.Equal(t, "early-bird", reservation.PriceTier)
+		mockReservationRepo.AssertExpectations(t)
+	})
+
+	t.Run("should hold the reservation for the hold duration", func(t *testing.T) {
+		now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+		util.TimeNow = func() time.Time { return now }
+		defer func() { util.TimeNow = time.Now }()
+
+		mockRepo := new(MockTicketPersister)
+		mockReservationRepo := new(MockReservationPersister)
+		ticketUsecase := NewTicketUsecase(mockRepo, mockReservationRepo, new(MockSeatPersister), new(MockPromoPersister), new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), new(MockWaitlistOfferer), currency.NewStaticRates("", nil), config.NewNopLogger(), 0, 15*time.Minute)
+
+		mockReservationRepo.On("GetReservationByOrderID", mock.Anything, "order-1").Return(model.Reservation{}, sql.ErrNoRows)
+		mockRepo.On("GetTicketPurchaseLimit", mock.Anything, 1).Return(model.TicketPurchaseLimit{TicketID: 1, EventID: 3, EventStatus: "on_sale"}, nil)
+		mockRepo.On("GetTicketByID", mock.Anything, 1).Return(model.Ticket{TicketID: 1, Price: model.Money{Amount: 200000, Currency: "IDR"}, Available: 10}, nil)
+		mockRepo.On("GetTicketPriceSchedules", mock.Anything, []int{1}).Return(map[int][]model.PriceSchedule{}, nil)
+		mockRepo.On("UpdateStockCreateOrderTicket", mock.Anything, 1, 2, "order-1").Return(nil)
+		mockReservationRepo.On("CreateReservation", mock.Anything, mock.MatchedBy(func(reservation model.Reservation) bool {
+			return reservation.ExpiresAt != nil && reservation.ExpiresAt.Equal(now.Add(15*time.Minute))
+		})).Return(9, nil)
+
+		reservation, err := ticketUsecase.ReserveTicket(context.Background(), message)
+		assert.NoError(t, err)
+		assert.Equal(t, now.Add(15*time.Minute), *reservation.ExpiresAt)
+		mockReservationRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return conflict when order already reserved", func(t *testing.T) {
+		mockRepo := new(MockTicketPersister)
+		mockReservationRepo := new(MockReservationPersister)
+		ticketUsecase := NewTicketUsecase(mockRepo, mockReservationRepo, new(MockSeatPersister), new(MockPromoPersister), new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), new(MockWaitlistOfferer), currency.NewStaticRates("", nil), config.NewNopLogger(), 0, 0)
+
+		mockReservationRepo.On("GetReservationByOrderID", mock.Anything, "order-1").Return(model.Reservation{ReservationID: 1}, nil)
+
+		_, err := ticketUsecase.ReserveTicket(context.Background(), message)
+		assert.ErrorIs(t, err, ErrConflict)
+	})
+
+	t.Run("should return not found for unknown ticket", func(t *testing.T) {
+		mockRepo := new(MockTicketPersister)
+		mockReservationRepo := new(MockReservationPersister)
+		ticketUsecase := NewTicketUsecase(mockRepo, mockReservationRepo, new(MockSeatPersister), new(MockPromoPersister), new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), new(MockWaitlistOfferer), currency.NewStaticRates("", nil), config.NewNopLogger(), 0, 0)
+
+		mockReservationRepo.On("GetReservationByOrderID", mock.Anything, "order-1").Return(model.Reservation{}, sql.ErrNoRows)
+		mockRepo.On("GetTicketPurchaseLimit", mock.Anything, 1).Return(model.TicketPurchaseLimit{}, sql.ErrNoRows)
+
+		_, err := ticketUsecase.ReserveTicket(context.Background(), message)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("should return insufficient stock when stock update is rejected", func(t *testing.T) {
+		mockRepo := new(MockTicketPersister)
+		mockReservationRepo := new(MockReservationPersister)
+		ticketUsecase := NewTicketUsecase(mockRepo, mockReservationRepo, new(MockSeatPersister), new(MockPromoPersister), new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), new(MockWaitlistOfferer), currency.NewStaticRates("", nil), config.NewNopLogger(), 0, 0)
+
+		mockReservationRepo.On("GetReservationByOrderID", mock.Anything, "order-1").Return(model.Reservation{}, sql.ErrNoRows)
+		mockRepo.On("GetTicketPurchaseLimit", mock.Anything, 1).Return(model.TicketPurchaseLimit{TicketID: 1, EventID: 3, EventStatus: "on_sale"}, nil)
+		mockRepo.On("GetTicketByID", mock.Anything, 1).Return(model.Ticket{TicketID: 1, Price: model.Money{Amount: 200000, Currency: "IDR"}, Available: 10}, nil)
+		mockRepo.On("GetTicketPriceSchedules", mock.Anything, []int{1}).Return(map[int][]model.PriceSchedule{}, nil)
+		mockRepo.On("UpdateStockCreateOrderTicket", mock.Anything, 1, 2, "order-1").Return(sql.ErrNoRows)
+
+		_, err := ticketUsecase.ReserveTicket(context.Background(), message)
+		assert.ErrorIs(t, err, ErrInsufficientStock)
+	})
+
+	t.Run("should return invalid param for non positive order", func(t *testing.T) {
+		ticketUsecase := NewTicketUsecase(new(MockTicketPersister), new(MockReservationPersister), new(MockSeatPersister), new(MockPromoPersister), new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), new(MockWaitlistOfferer), currency.NewStaticRates("", nil), config.NewNopLogger(), 0, 0)
+
+		_, err := ticketUsecase.ReserveTicket(context.Background(), model.MessageOrderTicket{TicketID: 1, Order: 0, Email: "user@mail.com"})
+		assert.ErrorIs(t, err, ErrInvalidParam)
+	})
+}
+
+func TestReleaseExpiredReservations(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	util.TimeNow = func() time.Time { return now }
+	defer func() { util.TimeNow = time.Now }()
+
+	setup := func() (TicketExecutor, *MockTicketPersister, *MockReservationPersister, *MockWaitlistOfferer) {
+		mockRepo := new(MockTicketPersister)
+		mockReservationRepo := new(MockReservationPersister)
+		waitlist := new(MockWaitlistOfferer)
+		ticketUsecase := NewTicketUsecase(mockRepo, mockReservationRepo, new(MockSeatPersister), new(MockPromoPersister), new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), waitlist, currency.NewStaticRates("", nil), config.NewNopLogger(), 0, 15*time.Minute)
+		return ticketUsecase, mockRepo, mockReservationRepo, waitlist
+	}
+
+	t.Run("should release every expired reservation", func(t *testing.T) {
+		ticketUsecase, mockRepo, mockReservationRepo, waitlist := setup()
+
+		mockReservationRepo.On("GetExpiredReservations", mock.Anything, now).Return([]model.Reservation{
+			{ReservationID: 1, TicketID: 2, Quantity: 3, Status: "pending"},
+			{ReservationID: 4, TicketID: 2, Quantity: 1, Status: "pending"},
+		}, nil)
+		mockRepo.On("UpdateStockFailReservation", mock.Anything, 1, 2, 3).Return(nil)
+		mockRepo.On("UpdateStockFailReservation", mock.Anything, 4, 2, 1).Return(nil)
+		waitlist.On("OfferFreedStock", mock.Anything, 2).Return(0, nil)
+
+		released, err := ticketUsecase.ReleaseExpiredReservations(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 2, released)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should not offer the stock of a reservation that already settled", func(t *testing.T) {
+		ticketUsecase, mockRepo, mockReservationRepo, waitlist := setup()
+
+		mockReservationRepo.On("GetExpiredReservations", mock.Anything, now).Return([]model.Reservation{
+			{ReservationID: 1, TicketID: 2, Quantity: 3, Status: "pending"},
+		}, nil)
+		mockRepo.On("UpdateStockFailReservation", mock.Anything, 1, 2, 3).Return(repository.ErrOrderSettled)
+
+		_, err := ticketUsecase.ReleaseExpiredReservations(context.Background())
+		assert.NoError(t, err)
+		waitlist.AssertNotCalled(t, "OfferFreedStock", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should keep releasing when one reservation fails", func(t *testing.T) {
+		ticketUsecase, mockRepo, mockReservationRepo, waitlist := setup()
+
+		mockReservationRepo.On("GetExpiredReservations", mock.Anything, now).Return([]model.Reservation{
+			{ReservationID: 1, TicketID: 2, Quantity: 3, Status: "pending"},
+			{ReservationID: 4, TicketID: 2, Quantity: 1, Status: "pending"},
+		}, nil)
+		mockRepo.On("UpdateStockFailReservation", mock.Anything, 1, 2, 3).Return(sql.ErrConnDone)
+		mockRepo.On("UpdateStockFailReservation", mock.Anything, 4, 2, 1).Return(nil)
+		waitlist.On("OfferFreedStock", mock.Anything, 2).Return(0, nil)
+
+		released, err := ticketUsecase.ReleaseExpiredReservations(context.Background())
+		assert.Error(t, err)
+		assert.Equal(t, 1, released)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestTicketPrices(t *testing.T) {
+	rates := currency.NewStaticRates("USD", map[string]*big.Rat{"IDR": big.NewRat(16000, 1)})
+	tickets := []model.Ticket{
+		{TicketID: 1, Type: "VIP", Price: model.Money{Amount: 320000000, Currency: "IDR"}},
+		{TicketID: 2, Type: "Regular", Price: model.Money{Amount: 160000000, Currency: "IDR"}},
+	}
+
+	t.Run("should use regional price list and convert", func(t *testing.T) {
+		mockRepo := new(MockTicketPersister)
+		ticketUsecase := NewTicketUsecase(mockRepo, new(MockReservationPersister), new(MockSeatPersister), new(MockPromoPersister), new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), new(MockWaitlistOfferer), rates, config.NewNopLogger(), 0, 0)
+
+		mockRepo.On("GetAvailableTicketByType", mock.Anything, "VIP").Return(append([]model.Ticket(nil), tickets...), nil)
+		mockRepo.On("GetTicketPriceSchedules", mock.Anything, []int{1, 2}).Return(map[int][]model.PriceSchedule{}, nil)
+		mockRepo.On("GetTicketPricesByRegion", mock.Anything, []int{1, 2}, "SG").
+			Return(map[int]model.Money{1: {Amount: 15000, Currency: "USD"}}, nil)
+
+		responses, err := ticketUsecase.GetAvailableTicketByType(context.Background(), "VIP", model.PriceOptions{Currency: "usd", Region: "sg"})
+		assert.NoError(t, err)
+		assert.Equal(t, model.Money{Amount: 15000, Currency: "USD"}, responses[0].Price)
+		assert.Equal(t, model.Money{Amount: 10000, Currency: "USD"}, responses[1].Price)
+	})
+
+	t.Run("should apply the price tier to the regional price", func(t *testing.T) {
+		mockRepo := new(MockTicketPersister)
+		ticketUsecase := NewTicketUsecase(mockRepo, new(MockReservationPersister), new(MockSeatPersister), new(MockPromoPersister), new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), new(MockWaitlistOfferer), rates, config.NewNopLogger(), 0, 0)
+
+		mockRepo.On("GetAvailableTicketByType", mock.Anything, "VIP").Return(append([]model.Ticket(nil), tickets...), nil)
+		mockRepo.On("GetTicketPriceSchedules", mock.Anything, []int{1, 2}).Return(map[int][]model.PriceSchedule{
+			1: {{ScheduleID: 1, TicketID: 1, Name: "early-bird", Price: 240000000}},
+		}, nil)
+		mockRepo.On("GetTicketPricesByRegion", mock.Anything, []int{1, 2}, "SG").
+			Return(map[int]model.Money{1: {Amount: 15000, Currency: "USD"}}, nil)
+
+		responses, err := ticketUsecase.GetAvailableTicketByType(context.Background(), "VIP", model.PriceOptions{Region: "SG"})
+		assert.NoError(t, err)
+		assert.Equal(t, model.Money{Amount: 11250, Currency: "USD"}, responses[0].Price)
+		assert.Equal(t, "early-bird", responses[0].PriceTier)
+		assert.Equal(t, model.Money{Amount: 160000000, Currency: "IDR"}, responses[1].Price)
+	})
+
+	t.Run("should reject a price tier over a free ticket with a regional price", func(t *testing.T) {
+		mockRepo := new(MockTicketPersister)
+		ticketUsecase := NewTicketUsecase(mockRepo, new(MockReservationPersister), new(MockSeatPersister), new(MockPromoPersister), new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), new(MockWaitlistOfferer), rates, config.NewNopLogger(), 0, 0)
+
+		mockRepo.On("GetAvailableTicketByType", mock.Anything, "VIP").
+			Return([]model.Ticket{{TicketID: 1, Type: "VIP", Price: model.Money{Currency: "IDR"}}}, nil)
+		mockRepo.On("GetTicketPriceSchedules", mock.Anything, []int{1}).Return(map[int][]model.PriceSchedule{
+			1: {{ScheduleID: 1, TicketID: 1, Name: "early-bird", Price: 100000}},
+		}, nil)
+		mockRepo.On("GetTicketPricesByRegion", mock.Anything, []int{1}, "SG").
+			Return(map[int]model.Money{1: {Amount: 15000, Currency: "USD"}}, nil)
+
+		_, err := ticketUsecase.GetAvailableTicketByType(context.Background(), "VIP", model.PriceOptions{Region: "SG"})
+		assert.Error(t, err)
+	})
+
+	t.Run("should reject unknown currency", func(t *testing.T) {
+		mockRepo := new(MockTicketPersister)
+		ticketUsecase := NewTicketUsecase(mockRepo, new(MockReservationPersister), new(MockSeatPersister), new(MockPromoPersister), new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), new(MockWaitlistOfferer), rates, config.NewNopLogger(), 0, 0)
+
+		mockRepo.On("GetAvailableTicketByType", mock.Anything, "VIP").Return(append([]model.Ticket(nil), tickets...), nil)
+		mockRepo.On("GetTicketPriceSchedules", mock.Anything, []int{1, 2}).Return(map[int][]model.PriceSchedule{}, nil)
+
+		_, err := ticketUsecase.GetAvailableTicketByType(context.Background(), "VIP", model.PriceOptions{Currency: "GBP"})
+		assert.ErrorIs(t, err, ErrInvalidParam)
+	})
+}
+
+func TestActiveSchedule(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	before, after := now.Add(-time.Hour), now.Add(time.Hour)
+	lowStock := 50
+
+	schedules := []model.PriceSchedule{
+		{Name: "last-minute", MaxStock: &lowStock, Priority: 10},
+		{Name: "expired", EffectiveTo: &before, Priority: 8},
+		{Name: "not-yet", EffectiveFrom: &after, Priority: 7},
+		{Name: "regular", EffectiveFrom: &before, EffectiveTo: &after, Priority: 1},
+	}
+
+	schedule, ok := activeSchedule(schedules, now, 40)
+	assert.True(t, ok)
+	assert.Equal(t, "last-minute", schedule.Name)
+
+	schedule, ok = activeSchedule(schedules, now, 500)
+	assert.True(t, ok)
+	assert.Equal(t, "regular", schedule.Name)
+
+	schedule, ok = activeSchedule(schedules, after, 500)
+	assert.True(t, ok)
+	assert.Equal(t, "not-yet", schedule.Name)
+
+	_, ok = activeSchedule(schedules[1:2], now, 500)
+	assert.False(t, ok)
+}
+
+func TestGetTicketEventByTicketIDNotFound(t *testing.T) {
+	mockRepo := new(MockTicketPersister)
+	ticketUsecase := NewTicketUsecase(mockRepo, new(MockReservationPersister), new(MockSeatPersister), new(MockPromoPersister), new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), new(MockWaitlistOfferer), currency.NewStaticRates("", nil), config.NewNopLogger(), 0, 0)
+
+	mockRepo.On("GetTicketEventByTicketID", mock.Anything, 99).Return(model.TicketEvent{}, sql.ErrNoRows)
+
+	_, err := ticketUsecase.GetTicketEventByTicketID(context.Background(), 99, model.PriceOptions{})
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestUpdateStockTicket(t *testing.T) {
+	t.Run("should confirm reservation on success", func(t *testing.T) {
+		mockRepo := new(MockTicketPersister)
+		mockReservationRepo := new(MockReservationPersister)
+		mockSeatRepo := new(MockSeatPersister)
+		mockPromoRepo := new(MockPromoPersister)
+		mockIssuedTicketRepo := new(MockIssuedTicketPersister)
+		ticketUsecase := NewTicketUsecase(mockRepo, mockReservationRepo, mockSeatRepo, mockPromoRepo, mockIssuedTicketRepo, new(MockResalePersister), new(MockWaitlistPersister), new(MockWaitlistOfferer), currency.NewStaticRates("", nil), config.NewNopLogger(), 0, 0)
+
+		mockRepo.On("UpdateStockSuccessOrderTicket", mock.Anything, 1, 2, "order-1").Return(nil)
+		mockSeatRepo.On("SellSeatsByOrderID", mock.Anything, "order-1").Return(nil)
+		mockPromoRepo.On("RedeemPromoByOrderID", mock.Anything, "order-1").Return(nil)
+		mockReservationRepo.On("GetReservationByOrderID", mock.Anything, "order-1").Return(model.Reservation{}, sql.ErrNoRows)
+
+		err := ticketUsecase.UpdateStockTicket(context.Background(), model.MessageOrderTicket{TicketID: 1, Order: 2, OrderID: "order-1"}, "success")
+		assert.NoError(t, err)
+		mockReservationRepo.AssertExpectations(t)
+		mockSeatRepo.AssertExpectations(t)
+		mockPromoRepo.AssertExpectations(t)
+		mockIssuedTicketRepo.AssertNotCalled(t, "CreateIssuedTickets", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should issue one ticket per unit not issued yet on success", func(t *testing.T) {
+		mockRepo := new(MockTicketPersister)
+		mockReservationRepo := new(MockReservationPersister)
+		mockSeatRepo := new(MockSeatPersister)
+		mockPromoRepo := new(MockPromoPersister)
+		mockIssuedTicketRepo := new(MockIssuedTicketPersister)
+		ticketUsecase := NewTicketUsecase(mockRepo, mockReservationRepo, mockSeatRepo, mockPromoRepo, mockIssuedTicketRepo, new(MockResalePersister), new(MockWaitlistPersister), new(MockWaitlistOfferer), currency.NewStaticRates("", nil), config.NewNopLogger(), 0, 0)
+
+		mockRepo.On("UpdateStockSuccessOrderTicket", mock.Anything, 1, 3, "order-1").Return(nil)
+		mockSeatRepo.On("SellSeatsByOrderID", mock.Anything, "order-1").Return(nil)
+		mockPromoRepo.On("RedeemPromoByOrderID", mock.Anything, "order-1").Return(nil)
+		mockReservationRepo.On("GetReservationByOrderID", mock.Anything, "order-1").
+			Return(model.Reservation{ReservationID: 9, OrderID: "order-1", TicketID: 1, EventID: 3, Email: "user@mail.com", Quantity: 3}, nil)
+		mockIssuedTicketRepo.On("GetIssuedTicketsByReservationID", mock.Anything, 9).Return([]model.IssuedTicket{{UnitNo: 2}}, nil)
+		mockIssuedTicketRepo.On("CreateIssuedTickets", mock.Anything, mock.AnythingOfType("[]model.IssuedTicket")).Return(nil)
+
+		err := ticketUsecase.UpdateStockTicket(context.Background(), model.MessageOrderTicket{TicketID: 1, Order: 3, OrderID: "order-1"}, "success")
+		assert.NoError(t, err)
+
+		tickets := mockIssuedTicketRepo.Calls[1].Arguments.Get(1).([]model.IssuedTicket)
+		if assert.Len(t, tickets, 2) {
+			assert.Equal(t, []int{1, 3}, []int{tickets[0].UnitNo, tickets[1].UnitNo})
+			assert.NotEqual(t, tickets[0].Code, tickets[1].Code)
+			assert.Equal(t, "user@mail.com", tickets[0].Email)
+			assert.Equal(t, "valid", tickets[0].Status)
+		}
+	})
+
+	t.Run("should not confirm more than the ticket holds", func(t *testing.T) {
+		mockRepo := new(MockTicketPersister)
+		mockReservationRepo := new(MockReservationPersister)
+		ticketUsecase := NewTicketUsecase(mockRepo, mockReservationRepo, new(MockSeatPersister), new(MockPromoPersister), new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), new(MockWaitlistOfferer), currency.NewStaticRates("", nil), config.NewNopLogger(), 0, 0)
+
+		mockRepo.On("UpdateStockSuccessOrderTicket", mock.Anything, 1, 2, "order-1").Return(sql.ErrNoRows)
+
+		err := ticketUsecase.UpdateStockTicket(context.Background(), model.MessageOrderTicket{TicketID: 1, Order: 2, OrderID: "order-1"}, "success")
+		assert.ErrorIs(t, err, ErrConflict)
+		mockReservationRepo.AssertNotCalled(t, "UpdateReservationStatusByOrderID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return conflict for a success after the order was released", func(t *testing.T) {
+		mockRepo := new(MockTicketPersister)
+		mockReservationRepo := new(MockReservationPersister)
+		mockSeatRepo := new(MockSeatPersister)
+		mockPromoRepo := new(MockPromoPersister)
+		mockIssuedTicketRepo := new(MockIssuedTicketPersister)
+		ticketUsecase := NewTicketUsecase(mockRepo, mockReservationRepo, mockSeatRepo, mockPromoRepo, mockIssuedTicketRepo, new(MockResalePersister), new(MockWaitlistPersister), new(MockWaitlistOfferer), currency.NewStaticRates("", nil), config.NewNopLogger(), 0, 0)
+
+		mockRepo.On("UpdateStockSuccessOrderTicket", mock.Anything, 1, 2, "order-1").Return(repository.ErrOrderSettled)
+		mockReservationRepo.On("GetReservationByOrderID", mock.Anything, "order-1").
+			Return(model.Reservation{ReservationID: 9, OrderID: "order-1", Status: "released"}, nil)
+
+		err := ticketUsecase.UpdateStockTicket(context.Background(), model.MessageOrderTicket{TicketID: 1, Order: 2, OrderID: "order-1"}, "success")
+		assert.ErrorIs(t, err, ErrConflict)
+		mockSeatRepo.AssertNotCalled(t, "SellSeatsByOrderID", mock.Anything, mock.Anything)
+		mockPromoRepo.AssertNotCalled(t, "RedeemPromoByOrderID", mock.Anything, mock.Anything)
+		mockIssuedTicketRepo.AssertNotCalled(t, "GetIssuedTicketsByReservationID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should finish settling a confirmed order delivered again", func(t *testing.T) {
+		mockRepo := new(MockTicketPersister)
+		mockReservationRepo := new(MockReservationPersister)
+		mockSeatRepo := new(MockSeatPersister)
+		mockPromoRepo := new(MockPromoPersister)
+		mockIssuedTicketRepo := new(MockIssuedTicketPersister)
+		ticketUsecase := NewTicketUsecase(mockRepo, mockReservationRepo, mockSeatRepo, mockPromoRepo, mockIssuedTicketRepo, new(MockResalePersister), new(MockWaitlistPersister), new(MockWaitlistOfferer), currency.NewStaticRates("", nil), config.NewNopLogger(), 0, 0)
+
+		mockRepo.On("UpdateStockSuccessOrderTicket", mock.Anything, 1, 2, "order-1").Return(repository.ErrOrderSettled)
+		mockReservationRepo.On("GetReservationByOrderID", mock.Anything, "order-1").
+			Return(model.Reservation{ReservationID: 9, OrderID: "order-1", Quantity: 2, Status: "confirmed"}, nil)
+		mockSeatRepo.On("SellSeatsByOrderID", mock.Anything, "order-1").Return(nil)
+		mockPromoRepo.On("RedeemPromoByOrderID", mock.Anything, "order-1").Return(nil)
+		mockIssuedTicketRepo.On("GetIssuedTicketsByReservationID", mock.Anything, 9).Return([]model.IssuedTicket{{UnitNo: 1}, {UnitNo: 2}}, nil)
+		mockIssuedTicketRepo.On("CreateIssuedTickets", mock.Anything, []model.IssuedTicket(nil)).Return(nil)
+
+		err := ticketUsecase.UpdateStockTicket(context.Background(), model.MessageOrderTicket{TicketID: 1, Order: 2, OrderID: "order-1"}, "success")
+		assert.NoError(t, err)
+		mockSeatRepo.AssertExpectations(t)
+		mockPromoRepo.AssertExpectations(t)
+		mockIssuedTicketRepo.AssertExpectations(t)
+	})
+
+	t.Run("should do nothing for an order settled meanwhile on failed", func(t *testing.T) {
+		mockRepo := new(MockTicketPersister)
+		mockReservationRepo := new(MockReservationPersister)
+		mockSeatRepo := new(MockSeatPersister)
+		mockWaitlist := new(MockWaitlistOfferer)
+		ticketUsecase := NewTicketUsecase(mockRepo, mockReservationRepo, mockSeatRepo, new(MockPromoPersister), new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), mockWaitlist, currency.NewStaticRates("", nil), config.NewNopLogger(), 0, 0)
+
+		mockRepo.On("UpdateStockFailOrderTicket", mock.Anything, 1, 2, "order-1").Return(repository.ErrOrderSettled)
+		mockReservationRepo.On("GetReservationByOrderID", mock.Anything, "order-1").
+			Return(model.Reservation{ReservationID: 9, OrderID: "order-1", Status: "confirmed"}, nil)
+
+		err := ticketUsecase.UpdateStockTicket(context.Background(), model.MessageOrderTicket{TicketID: 1, Order: 2, OrderID: "order-1"}, "failed")
+		assert.NoError(t, err)
+		mockSeatRepo.AssertNotCalled(t, "ReleaseSeatsByOrderID", mock.Anything, mock.Anything)
+		mockWaitlist.AssertNotCalled(t, "OfferFreedStock", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should release reservation, seats and promo on failed", func(t *testing.T) {
+		mockRepo := new(MockTicketPersister)
+		mockReservationRepo := new(MockReservationPersister)
+		mockSeatRepo := new(MockSeatPersister)
+		mockPromoRepo := new(MockPromoPersister)
+		mockWaitlist := new(MockWaitlistOfferer)
+		ticketUsecase := NewTicketUsecase(mockRepo, mockReservationRepo, mockSeatRepo, mockPromoRepo, new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), mockWaitlist, currency.NewStaticRates("", nil), config.NewNopLogger(), 0, 0)
+
+		mockRepo.On("UpdateStockFailOrderTicket", mock.Anything, 1, 2, "order-1").Return(nil)
+		mockSeatRepo.On("ReleaseSeatsByOrderID", mock.Anything, "order-1").Return(nil)
+		mockPromoRepo.On("ReleasePromoByOrderID", mock.Anything, "order-1").Return(nil)
+		mockWaitlist.On("OfferFreedStock", mock.Anything, 1).Return(0, assert.AnError)
+
+		err := ticketUsecase.UpdateStockTicket(context.Background(), model.MessageOrderTicket{TicketID: 1, Order: 2, OrderID: "order-1"}, "failed")
+		assert.NoError(t, err)
+		mockSeatRepo.AssertExpectations(t)
+		mockPromoRepo.AssertExpectations(t)
+		mockWaitlist.AssertExpectations(t)
+	})
+
+	t.Run("should only update stock for message without identity", func(t *testing.T) {
+		mockRepo := new(MockTicketPersister)
+		mockReservationRepo := new(MockReservationPersister)
+		ticketUsecase := NewTicketUsecase(mockRepo, mockReservationRepo, new(MockSeatPersister), new(MockPromoPersister), new(MockIssuedTicketPersister), new(MockResalePersister), new(MockWaitlistPersister), new(MockWaitlistOfferer), currency.NewStaticRates("", nil), config.NewNopLogger(), 0, 0)
+
+		mockRepo.On("UpdateStockCreateOrderTicket", mock.Anything, 1, 2, "").Return(nil)
+
+		err := ticketUsecase.UpdateStockTicket(context.Background(), model.MessageOrderTicket{TicketID: 1, Order: 2}, "create")
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockReservationRepo.AssertNotCalled(t, "CreateReservation", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,7 @@
+package usecase
+
+import "go.opentelemetry.io/otel"
+
+// tracer starts one span per usecase call; the spans nest under the HTTP or
+// consumer span carried by the caller's context.
+var tracer = otel.Tracer("github.com/SyamSolution/ticket-management-service/internal/usecase")
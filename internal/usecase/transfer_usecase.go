@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/repository"
+	"github.com/SyamSolution/ticket-management-service/internal/ticketcode"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"go.uber.org/zap"
+)
+
+type transferUsecase struct {
+	transferRepo     repository.TransferPersister
+	issuedTicketRepo repository.IssuedTicketPersister
+	logger           config.Logger
+	transferTTL      time.Duration
+}
+
+// TransferExecutor hands issued tickets from one user to another. The owner
+// offers a ticket to a recipient, who accepts or declines it before the offer
+// expires; the owner may withdraw it until then.
+type TransferExecutor interface {
+	InitiateTransfer(ctx context.Context, email string, issuedTicketID int, request model.TransferRequest) (model.TicketTransfer, error)
+	AcceptTransfer(ctx context.Context, email string, transferID int) (model.TicketTransfer, error)
+	DeclineTransfer(ctx context.Context, email string, transferID int) (model.TicketTransfer, error)
+	CancelTransfer(ctx context.Context, email string, transferID int) (model.TicketTransfer, error)
+	GetMyTransfers(ctx context.Context, email string) ([]model.TicketTransfer, error)
+	GetTicketTransfers(ctx context.Context, issuedTicketID int) ([]model.TicketTransfer, error)
+	ExpireTransfers(ctx context.Context) (int64, error)
+}
+
+// NewTransferUsecase builds the transfer usecase. transferTTL is how long a
+// recipient has to answer a transfer.
+func NewTransferUsecase(transferRepo repository.TransferPersister, issuedTicketRepo repository.IssuedTicketPersister,
+	logger config.Logger, transferTTL time.Duration) TransferExecutor {
+	return &transferUsecase{transferRepo: transferRepo, issuedTicketRepo: issuedTicketRepo, logger: logger, transferTTL: transferTTL}
+}
+
+func (uc *transferUsecase) InitiateTransfer(ctx context.Context, email string, issuedTicketID int, request model.TransferRequest) (model.TicketTransfer, error) {
+	ctx, span := tracer.Start(ctx, "TransferUsecase.InitiateTransfer")
+	defer span.End()
+
+	var transfer model.TicketTransfer
+	toEmail := strings.TrimSpace(request.ToEmail)
+	if strings.EqualFold(toEmail, email) {
+		return transfer, fmt.Errorf("ticket can not be transferred to its owner: %w", ErrInvalidParam)
+	}
+
+	ticket, err := uc.ownedTicket(ctx, email, issuedTicketID)
+	if err != nil {
+		return transfer, err
+	}
+	now := util.TimeNow()
+	if err := transferable(ticket, now); err != nil {
+		return transfer, err
+	}
+
+	transfer = model.TicketTransfer{
+		IssuedTicketID: issuedTicketID,
+		FromEmail:      email,
+		ToEmail:        toEmail,
+		Status:         util.TRANSFER_STATUS_PENDING,
+		ExpiresAt:      now.Add(uc.transferTTL),
+	}
+	transferID, err := uc.transferRepo.CreateTransfer(ctx, transfer)
+	if err != nil {
+		if errors.Is(err, repository.ErrTransferPending) {
+			return transfer, fmt.Errorf("ticket %d: %s: %w", issuedTicketID, err.Error(), ErrConflict)
+		}
+		uc.logger.WithContext(ctx).Error("Error when creating ticket transfer", zap.Error(err))
+		return transfer, err
+	}
+
+	return uc.getTransfer(ctx, transferID)
+}
+
+// AcceptTransfer moves the ticket to the recipient under a new code, so QR
+// payloads the sender still holds stop working.
+func (uc *transferUsecase) AcceptTransfer(ctx context.Context, email string, transferID int) (model.TicketTransfer, error) {
+	ctx, span := tracer.Start(ctx, "TransferUsecase.AcceptTransfer")
+	defer span.End()
+
+	transfer, err := uc.incomingTransfer(ctx, email, transferID)
+	if err != nil {
+		return transfer, err
+	}
+	now := util.TimeNow()
+	if !now.Before(transfer.ExpiresAt) {
+		return transfer, fmt.Errorf("transfer %d has expired: %w", transferID, ErrConflict)
+	}
+
+	code, err := ticketcode.NewCode()
+	if err != nil {
+		return transfer, err
+	}
+	if err := uc.transferRepo.AcceptTransfer(ctx, transfer, code, now); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return transfer, fmt.Errorf("transfer %d can no longer be accepted: %w", transferID, ErrConflict)
+		}
+		uc.logger.WithContext(ctx).Error("Error when accepting ticket transfer", zap.Error(err))
+		return transfer, err
+	}
+
+	return uc.getTransfer(ctx, transferID)
+}
+
+func (uc *transferUsecase) DeclineTransfer(ctx context.Context, email string, transferID int) (model.TicketTransfer, error) {
+	ctx, span := tracer.Start(ctx, "TransferUsecase.DeclineTransfer")
+	defer span.End()
+
+	transfer, err := uc.incomingTransfer(ctx, email, transferID)
+	if err != nil {
+		return transfer, err
+	}
+	return uc.closeTransfer(ctx, transfer, util.TRANSFER_STATUS_DECLINED)
+}
+
+func (uc *transferUsecase) CancelTransfer(ctx context.Context, email string, transferID int) (model.TicketTransfer, error) {
+	ctx, span := tracer.Start(ctx, "TransferUsecase.CancelTransfer")
+	defer span.End()
+
+	transfer, err := uc.getTransfer(ctx, transferID)
+	if err != nil {
+		return transfer, err
+	}
+	if !strings.EqualFold(transfer.FromEmail, email) {
+		return model.TicketTransfer{}, fmt.Errorf("transfer %d: %w", transferID, ErrNotFound)
+	}
+	return uc.closeTransfer(ctx, transfer, util.TRANSFER_STATUS_CANCELLED)
+}
+
+func (uc *transferUsecase) GetMyTransfers(ctx context.Context, email string) ([]model.TicketTransfer, error) {
+	ctx, span := tracer.Start(ctx, "TransferUsecase.GetMyTransfers")
+	defer span.End()
+
+	transfers, err := uc.transferRepo.GetTransfersByEmail(ctx, email)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting transfers by email", zap.Error(err))
+		return transfers, err
+	}
+	return transfers, nil
+}
+
+// GetTicketTransfers returns the full ownership history of a ticket, transfers
+// and resales alike, oldest first.
+func (uc *transferUsecase) GetTicketTransfers(ctx context.Context, issuedTicketID int) ([]model.TicketTransfer, error) {
+	ctx, span := tracer.Start(ctx, "TransferUsecase.GetTicketTransfers")
+	defer span.End()
+
+	transfers, err := uc.transferRepo.GetTransfersByIssuedTicketID(ctx, issuedTicketID)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting transfers of ticket", zap.Error(err))
+		return transfers, err
+	}
+	return transfers, nil
+}
+
+// ExpireTransfers marks the transfers that were not answered in time as
+// expired. Accepting checks the expiry itself, so this only keeps the
+// statuses tidy.
+func (uc *transferUsecase) ExpireTransfers(ctx context.Context) (int64, error) {
+	ctx, span := tracer.Start(ctx, "TransferUsecase.ExpireTransfers")
+	defer span.End()
+
+	expired, err := uc.transferRepo.ExpireTransfers(ctx, util.TimeNow())
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when expiring transfers", zap.Error(err))
+		return expired, err
+	}
+	if expired > 0 {
+		uc.logger.WithContext(ctx).Info("Expired ticket transfers", zap.Int64("count", expired))
+	}
+	return expired, nil
+}
+
+// ownedTicket returns the ticket issuedTicketID when email owns it. Tickets of
+// other users are reported as not found.
+func (uc *transferUsecase) ownedTicket(ctx context.Context, email string, issuedTicketID int) (model.IssuedTicket, error) {
+	ticket, err := uc.issuedTicketRepo.GetIssuedTicketByID(ctx, issuedTicketID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ticket, fmt.Errorf("ticket %d: %w", issuedTicketID, ErrNotFound)
+		}
+		uc.logger.WithContext(ctx).Error("Error when getting issued ticket by id", zap.Error(err))
+		return ticket, err
+	}
+	if !strings.EqualFold(ticket.Email, email) {
+		return model.IssuedTicket{}, fmt.Errorf("ticket %d: %w", issuedTicketID, ErrNotFound)
+	}
+	return ticket, nil
+}
+
+// transferable tells why ticket can not change hands at now, or returns nil
+// when it can.
+func transferable(ticket model.IssuedTicket, now time.Time) error {
+	switch {
+	case ticket.Status != util.ISSUED_TICKET_STATUS_VALID:
+		return fmt.Errorf("ticket %d is %s: %w", ticket.IssuedTicketID, ticket.Status, ErrConflict)
+	case ticket.CheckedInAt != nil:
+		return fmt.Errorf("ticket %d is already checked in: %w", ticket.IssuedTicketID, ErrConflict)
+	case !ticket.EventDate.IsZero() && !now.Before(ticket.EventDate):
+		return fmt.Errorf("event of ticket %d has already taken place: %w", ticket.IssuedTicketID, ErrConflict)
+	}
+	return nil
+}
+
+func (uc *transferUsecase) getTransfer(ctx context.Context, transferID int) (model.TicketTransfer, error) {
+	transfer, err := uc.transferRepo.GetTransferByID(ctx, transferID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return transfer, fmt.Errorf("transfer %d: %w", transferID, ErrNotFound)
+		}
+		uc.logger.WithContext(ctx).Error("Error when getting transfer by id", zap.Error(err))
+		return transfer, err
+	}
+	return transfer, nil
+}
+
+// incomingTransfer returns transfer transferID when it was sent to email.
+func (uc *transferUsecase) incomingTransfer(ctx context.Context, email string, transferID int) (model.TicketTransfer, error) {
+	transfer, err := uc.getTransfer(ctx, transferID)
+	if err != nil {
+		return transfer, err
+	}
+	if !strings.EqualFold(transfer.ToEmail, email) {
+		return model.TicketTransfer{}, fmt.Errorf("transfer %d: %w", transferID, ErrNotFound)
+	}
+	return transfer, nil
+}
+
+func (uc *transferUsecase) closeTransfer(ctx context.Context, transfer model.TicketTransfer, status string) (model.TicketTransfer, error) {
+	if err := uc.transferRepo.UpdateTransferStatus(ctx, transfer.TransferID, status, util.TimeNow()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return transfer, fmt.Errorf("transfer %d is %s: %w", transfer.TransferID, transfer.Status, ErrConflict)
+		}
+		uc.logger.WithContext(ctx).Error("Error when updating transfer status", zap.Error(err))
+		return transfer, err
+	}
+	return uc.getTransfer(ctx, transfer.TransferID)
+}
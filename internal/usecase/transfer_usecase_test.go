@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/repository"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTransferPersister struct {
+	mock.Mock
+}
+
+func (m *MockTransferPersister) CreateTransfer(ctx context.Context, transfer model.TicketTransfer) (int, error) {
+	args := m.Called(ctx, transfer)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTransferPersister) GetTransferByID(ctx context.Context, transferID int) (model.TicketTransfer, error) {
+	args := m.Called(ctx, transferID)
+	return args.Get(0).(model.TicketTransfer), args.Error(1)
+}
+
+func (m *MockTransferPersister) GetTransfersByEmail(ctx context.Context, email string) ([]model.TicketTransfer, error) {
+	args := m.Called(ctx, email)
+	return args.Get(0).([]model.TicketTransfer), args.Error(1)
+}
+
+func (m *MockTransferPersister) GetTransfersByIssuedTicketID(ctx context.Context, issuedTicketID int) ([]model.TicketTransfer, error) {
+	args := m.Called(ctx, issuedTicketID)
+	return args.Get(0).([]model.TicketTransfer), args.Error(1)
+}
+
+func (m *MockTransferPersister) AcceptTransfer(ctx context.Context, transfer model.TicketTransfer, code string, at time.Time) error {
+	args := m.Called(ctx, transfer, code, at)
+	return args.Error(0)
+}
+
+func (m *MockTransferPersister) UpdateTransferStatus(ctx context.Context, transferID int, status string, at time.Time) error {
+	args := m.Called(ctx, transferID, status, at)
+	return args.Error(0)
+}
+
+func (m *MockTransferPersister) ExpireTransfers(ctx context.Context, now time.Time) (int64, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestInitiateTransfer(t *testing.T) {
+	now := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+	util.TimeNow = func() time.Time { return now }
+	defer func() { util.TimeNow = time.Now }()
+
+	ticket := model.IssuedTicket{IssuedTicketID: 1, Code: "CODEONE", Email: "owner@mail.com", Status: "valid", EventDate: now.Add(30 * 24 * time.Hour)}
+	setup := func() (*MockTransferPersister, *MockIssuedTicketPersister, TransferExecutor) {
+		transferRepo := new(MockTransferPersister)
+		issuedTicketRepo := new(MockIssuedTicketPersister)
+		return transferRepo, issuedTicketRepo, NewTransferUsecase(transferRepo, issuedTicketRepo, config.NewNopLogger(), 48*time.Hour)
+	}
+
+	t.Run("should offer the ticket to the recipient", func(t *testing.T) {
+		transferRepo, issuedTicketRepo, uc := setup()
+		issuedTicketRepo.On("GetIssuedTicketByID", mock.Anything, 1).Return(ticket, nil)
+		transferRepo.On("CreateTransfer", mock.Anything, model.TicketTransfer{IssuedTicketID: 1, FromEmail: "owner@mail.com",
+			ToEmail: "friend@mail.com", Status: "pending", ExpiresAt: now.Add(48 * time.Hour)}).Return(7, nil)
+		transferRepo.On("GetTransferByID", mock.Anything, 7).Return(model.TicketTransfer{TransferID: 7, Status: "pending"}, nil)
+
+		transfer, err := uc.InitiateTransfer(context.Background(), "owner@mail.com", 1, model.TransferRequest{ToEmail: "friend@mail.com"})
+		assert.NoError(t, err)
+		assert.Equal(t, 7, transfer.TransferID)
+	})
+
+	t.Run("should hide tickets of other users", func(t *testing.T) {
+		_, issuedTicketRepo, uc := setup()
+		issuedTicketRepo.On("GetIssuedTicketByID", mock.Anything, 1).Return(ticket, nil)
+
+		_, err := uc.InitiateTransfer(context.Background(), "stranger@mail.com", 1, model.TransferRequest{ToEmail: "friend@mail.com"})
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("should reject a transfer to the owner", func(t *testing.T) {
+		_, _, uc := setup()
+
+		_, err := uc.InitiateTransfer(context.Background(), "owner@mail.com", 1, model.TransferRequest{ToEmail: "Owner@mail.com"})
+		assert.ErrorIs(t, err, ErrInvalidParam)
+	})
+
+	t.Run("should reject a checked in ticket", func(t *testing.T) {
+		_, issuedTicketRepo, uc := setup()
+		checkedIn := ticket
+		checkedIn.CheckedInAt = &now
+		issuedTicketRepo.On("GetIssuedTicketByID", mock.Anything, 1).Return(checkedIn, nil)
+
+		_, err := uc.InitiateTransfer(context.Background(), "owner@mail.com", 1, model.TransferRequest{ToEmail: "friend@mail.com"})
+		assert.ErrorIs(t, err, ErrConflict)
+	})
+
+	t.Run("should reject a second pending transfer", func(t *testing.T) {
+		transferRepo, issuedTicketRepo, uc := setup()
+		issuedTicketRepo.On("GetIssuedTicketByID", mock.Anything, 1).Return(ticket, nil)
+		transferRepo.On("CreateTransfer", mock.Anything, mock.Anything).Return(0, repository.ErrTransferPending)
+
+		_, err := uc.InitiateTransfer(context.Background(), "owner@mail.com", 1, model.TransferRequest{ToEmail: "friend@mail.com"})
+		assert.ErrorIs(t, err, ErrConflict)
+	})
+}
+
+func TestAcceptTransfer(t *testing.T) {
+	now := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+	util.TimeNow = func() time.Time { return now }
+	defer func() { util.TimeNow = time.Now }()
+
+	transfer := model.TicketTransfer{TransferID: 7, IssuedTicketID: 1, FromEmail: "owner@mail.com", ToEmail: "friend@mail.com",
+		Status: "pending", ExpiresAt: now.Add(time.Hour)}
+
+	t.Run("should re-key the ticket under a new code", func(t *testing.T) {
+		transferRepo := new(MockTransferPersister)
+		uc := NewTransferUsecase(transferRepo, new(MockIssuedTicketPersister), config.NewNopLogger(), time.Hour)
+		transferRepo.On("GetTransferByID", mock.Anything, 7).Return(transfer, nil)
+		transferRepo.On("AcceptTransfer", mock.Anything, transfer, mock.AnythingOfType("string"), now).Return(nil)
+
+		_, err := uc.AcceptTransfer(context.Background(), "friend@mail.com", 7)
+		assert.NoError(t, err)
+		code := transferRepo.Calls[1].Arguments.String(2)
+		assert.Len(t, code, 26)
+	})
+
+	t.Run("should only let the recipient accept", func(t *testing.T) {
+		transferRepo := new(MockTransferPersister)
+		uc := NewTransferUsecase(transferRepo, new(MockIssuedTicketPersister), config.NewNopLogger(), time.Hour)
+		transferRepo.On("GetTransferByID", mock.Anything, 7).Return(transfer, nil)
+
+		_, err := uc.AcceptTransfer(context.Background(), "owner@mail.com", 7)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("should reject an expired transfer", func(t *testing.T) {
+		transferRepo := new(MockTransferPersister)
+		uc := NewTransferUsecase(transferRepo, new(MockIssuedTicketPersister), config.NewNopLogger(), time.Hour)
+		expired := transfer
+		expired.ExpiresAt = now
+		transferRepo.On("GetTransferByID", mock.Anything, 7).Return(expired, nil)
+
+		_, err := uc.AcceptTransfer(context.Background(), "friend@mail.com", 7)
+		assert.ErrorIs(t, err, ErrConflict)
+		transferRepo.AssertNotCalled(t, "AcceptTransfer", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should report a transfer answered meanwhile as conflict", func(t *testing.T) {
+		transferRepo := new(MockTransferPersister)
+		uc := NewTransferUsecase(transferRepo, new(MockIssuedTicketPersister), config.NewNopLogger(), time.Hour)
+		transferRepo.On("GetTransferByID", mock.Anything, 7).Return(transfer, nil)
+		transferRepo.On("AcceptTransfer", mock.Anything, transfer, mock.Anything, now).Return(sql.ErrNoRows)
+
+		_, err := uc.AcceptTransfer(context.Background(), "friend@mail.com", 7)
+		assert.ErrorIs(t, err, ErrConflict)
+	})
+}
+
+func TestDeclineAndCancelTransfer(t *testing.T) {
+	now := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+	util.TimeNow = func() time.Time { return now }
+	defer func() { util.TimeNow = time.Now }()
+
+	transfer := model.TicketTransfer{TransferID: 7, IssuedTicketID: 1, FromEmail: "owner@mail.com", ToEmail: "friend@mail.com",
+		Status: "pending", ExpiresAt: now.Add(time.Hour)}
+
+	t.Run("should let the recipient decline", func(t *testing.T) {
+		transferRepo := new(MockTransferPersister)
+		uc := NewTransferUsecase(transferRepo, new(MockIssuedTicketPersister), config.NewNopLogger(), time.Hour)
+		transferRepo.On("GetTransferByID", mock.Anything, 7).Return(transfer, nil)
+		transferRepo.On("UpdateTransferStatus", mock.Anything, 7, "declined", now).Return(nil)
+
+		_, err := uc.DeclineTransfer(context.Background(), "friend@mail.com", 7)
+		assert.NoError(t, err)
+		transferRepo.AssertExpectations(t)
+	})
+
+	t.Run("should only let the sender cancel", func(t *testing.T) {
+		transferRepo := new(MockTransferPersister)
+		uc := NewTransferUsecase(transferRepo, new(MockIssuedTicketPersister), config.NewNopLogger(), time.Hour)
+		transferRepo.On("GetTransferByID", mock.Anything, 7).Return(transfer, nil)
+
+		_, err := uc.CancelTransfer(context.Background(), "friend@mail.com", 7)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("should reject cancelling an answered transfer", func(t *testing.T) {
+		transferRepo := new(MockTransferPersister)
+		uc := NewTransferUsecase(transferRepo, new(MockIssuedTicketPersister), config.NewNopLogger(), time.Hour)
+		transferRepo.On("GetTransferByID", mock.Anything, 7).Return(transfer, nil)
+		transferRepo.On("UpdateTransferStatus", mock.Anything, 7, "cancelled", now).Return(sql.ErrNoRows)
+
+		_, err := uc.CancelTransfer(context.Background(), "owner@mail.com", 7)
+		assert.ErrorIs(t, err, ErrConflict)
+	})
+}
@@ -0,0 +1,268 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/publisher"
+	"github.com/SyamSolution/ticket-management-service/internal/repository"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"go.uber.org/zap"
+)
+
+// WaitlistOfferer offers the stock of a ticket that came back to the users
+// waiting for it.
+type WaitlistOfferer interface {
+	OfferFreedStock(ctx context.Context, ticketID int) (int, error)
+}
+
+type waitlistOfferer struct {
+	waitlistRepo repository.WaitlistPersister
+	publisher    publisher.Publisher
+	logger       config.Logger
+	offerTTL     time.Duration
+}
+
+// NewWaitlistOfferer builds the offerer. Every offer may be claimed for
+// offerTTL before its stock goes to the next user in line.
+func NewWaitlistOfferer(waitlistRepo repository.WaitlistPersister, publisher publisher.Publisher, logger config.Logger,
+	offerTTL time.Duration) WaitlistOfferer {
+	return &waitlistOfferer{waitlistRepo: waitlistRepo, publisher: publisher, logger: logger, offerTTL: offerTTL}
+}
+
+// OfferFreedStock sets the stock of the ticket aside for the head of its
+// waitlist and notifies every user offered tickets. It returns the number of
+// offers made. A notification that fails to publish is logged only: the offer
+// stands and is listed at /me/waitlist either way.
+func (o *waitlistOfferer) OfferFreedStock(ctx context.Context, ticketID int) (int, error) {
+	ctx, span := tracer.Start(ctx, "WaitlistOfferer.OfferFreedStock")
+	defer span.End()
+
+	now := util.TimeNow()
+	offers, err := o.waitlistRepo.OfferStock(ctx, ticketID, now, now.Add(o.offerTTL))
+	if err != nil {
+		o.logger.WithContext(ctx).Error("Error when offering stock to waitlist", zap.Error(err))
+		return 0, err
+	}
+
+	for _, offer := range offers {
+		message := model.MessageWaitlistOffer{
+			WaitlistID:     offer.WaitlistID,
+			TicketID:       offer.TicketID,
+			Email:          offer.Email,
+			Quantity:       offer.Quantity,
+			OfferExpiresAt: *offer.OfferExpiresAt,
+		}
+		if err := o.publisher.Publish(ctx, util.TOPIC_WAITLIST_OFFER, message); err != nil {
+			o.logger.WithContext(ctx).Error("Error when publishing waitlist offer", zap.Error(err))
+		}
+	}
+	return len(offers), nil
+}
+
+type waitlistUsecase struct {
+	waitlistRepo  repository.WaitlistPersister
+	ticketRepo    repository.TicketPersister
+	ticketUsecase TicketExecutor
+	offerer       WaitlistOfferer
+	logger        config.Logger
+}
+
+// WaitlistExecutor queues users for sold out tickets. Stock that comes back
+// is offered to them in the order they joined, and an offer is claimed
+// through the usual reservation and order flow.
+type WaitlistExecutor interface {
+	JoinWaitlist(ctx context.Context, email string, ticketID int, request model.WaitlistRequest) (model.WaitlistEntry, error)
+	LeaveWaitlist(ctx context.Context, email string, waitlistID int) (model.WaitlistEntry, error)
+	GetMyWaitlist(ctx context.Context, email string) ([]model.WaitlistEntry, error)
+	ClaimOffer(ctx context.Context, email string, waitlistID int, request model.WaitlistClaimRequest) (model.Reservation, error)
+	ProcessWaitlists(ctx context.Context) error
+}
+
+func NewWaitlistUsecase(waitlistRepo repository.WaitlistPersister, ticketRepo repository.TicketPersister,
+	ticketUsecase TicketExecutor, offerer WaitlistOfferer, logger config.Logger) WaitlistExecutor {
+	return &waitlistUsecase{
+		waitlistRepo:  waitlistRepo,
+		ticketRepo:    ticketRepo,
+		ticketUsecase: ticketUsecase,
+		offerer:       offerer,
+		logger:        logger,
+	}
+}
+
+// JoinWaitlist queues email for the ticket. Only sold out tickets of events
+// that may still sell have a waitlist; anything else can be bought directly
+// or not at all.
+func (uc *waitlistUsecase) JoinWaitlist(ctx context.Context, email string, ticketID int, request model.WaitlistRequest) (model.WaitlistEntry, error) {
+	ctx, span := tracer.Start(ctx, "WaitlistUsecase.JoinWaitlist")
+	defer span.End()
+
+	var entry model.WaitlistEntry
+
+	limit, err := uc.ticketRepo.GetTicketPurchaseLimit(ctx, ticketID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entry, fmt.Errorf("ticket %d: %w", ticketID, ErrNotFound)
+		}
+		uc.logger.WithContext(ctx).Error("Error when getting ticket purchase limit", zap.Error(err))
+		return entry, err
+	}
+	switch limit.EventStatus {
+	case util.EVENT_STATUS_ON_SALE, util.EVENT_STATUS_SOLD_OUT, util.EVENT_STATUS_PAUSED:
+	default:
+		return entry, fmt.Errorf("event of ticket %d is %s: %w", ticketID, limit.EventStatus, ErrConflict)
+	}
+
+	ticket, err := uc.ticketRepo.GetTicketByID(ctx, ticketID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entry, fmt.Errorf("ticket %d: %w", ticketID, ErrNotFound)
+		}
+		uc.logger.WithContext(ctx).Error("Error when getting ticket by id", zap.Error(err))
+		return entry, err
+	}
+	if ticket.Available > 0 {
+		return entry, fmt.Errorf("ticket %d is not sold out: %w", ticketID, ErrConflict)
+	}
+
+	entry = model.WaitlistEntry{
+		TicketID: ticketID,
+		Email:    email,
+		Quantity: request.Quantity,
+		Status:   util.WAITLIST_STATUS_WAITING,
+	}
+	waitlistID, err := uc.waitlistRepo.JoinWaitlist(ctx, entry)
+	if err != nil {
+		if errors.Is(err, repository.ErrAlreadyWaitlisted) {
+			return entry, fmt.Errorf("ticket %d: %s: %w", ticketID, err.Error(), ErrConflict)
+		}
+		uc.logger.WithContext(ctx).Error("Error when joining waitlist", zap.Error(err))
+		return entry, err
+	}
+
+	return uc.getEntry(ctx, waitlistID)
+}
+
+// LeaveWaitlist takes an entry of email off the waitlist. Leaving with an
+// offer passes its stock on to the next user in line straight away.
+func (uc *waitlistUsecase) LeaveWaitlist(ctx context.Context, email string, waitlistID int) (model.WaitlistEntry, error) {
+	ctx, span := tracer.Start(ctx, "WaitlistUsecase.LeaveWaitlist")
+	defer span.End()
+
+	entry, err := uc.getEntry(ctx, waitlistID)
+	if err != nil {
+		return entry, err
+	}
+	if !strings.EqualFold(entry.Email, email) {
+		return model.WaitlistEntry{}, fmt.Errorf("waitlist entry %d: %w", waitlistID, ErrNotFound)
+	}
+	if entry.Status != util.WAITLIST_STATUS_WAITING && entry.Status != util.WAITLIST_STATUS_OFFERED {
+		return entry, fmt.Errorf("waitlist entry %d is %s: %w", waitlistID, entry.Status, ErrConflict)
+	}
+
+	if err := uc.waitlistRepo.LeaveWaitlist(ctx, entry); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entry, fmt.Errorf("waitlist entry %d changed meanwhile: %w", waitlistID, ErrConflict)
+		}
+		uc.logger.WithContext(ctx).Error("Error when leaving waitlist", zap.Error(err))
+		return entry, err
+	}
+
+	if entry.Status == util.WAITLIST_STATUS_OFFERED {
+		if _, err := uc.offerer.OfferFreedStock(ctx, entry.TicketID); err != nil {
+			uc.logger.WithContext(ctx).Error("Error when offering freed stock", zap.Error(err))
+		}
+	}
+
+	return uc.getEntry(ctx, waitlistID)
+}
+
+func (uc *waitlistUsecase) GetMyWaitlist(ctx context.Context, email string) ([]model.WaitlistEntry, error) {
+	ctx, span := tracer.Start(ctx, "WaitlistUsecase.GetMyWaitlist")
+	defer span.End()
+
+	entries, err := uc.waitlistRepo.GetWaitlistEntriesByEmail(ctx, email)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting waitlist entries by email", zap.Error(err))
+		return entries, err
+	}
+	return entries, nil
+}
+
+// ClaimOffer reserves the tickets offered to email under the order of
+// request. The order is then paid or fails like any other.
+func (uc *waitlistUsecase) ClaimOffer(ctx context.Context, email string, waitlistID int, request model.WaitlistClaimRequest) (model.Reservation, error) {
+	ctx, span := tracer.Start(ctx, "WaitlistUsecase.ClaimOffer")
+	defer span.End()
+
+	entry, err := uc.getEntry(ctx, waitlistID)
+	if err != nil {
+		return model.Reservation{}, err
+	}
+	if !strings.EqualFold(entry.Email, email) {
+		return model.Reservation{}, fmt.Errorf("waitlist entry %d: %w", waitlistID, ErrNotFound)
+	}
+
+	return uc.ticketUsecase.ReserveTicket(ctx, model.MessageOrderTicket{
+		TicketID:   entry.TicketID,
+		Order:      entry.Quantity,
+		OrderID:    request.OrderID,
+		Email:      entry.Email,
+		Region:     request.Region,
+		PromoCode:  request.PromoCode,
+		WaitlistID: waitlistID,
+	})
+}
+
+// ProcessWaitlists expires the offers that were not claimed in time and
+// offers the stock of every ticket with users waiting, which also picks up
+// stock that came back without an offer being made, e.g. because the
+// offering failed.
+func (uc *waitlistUsecase) ProcessWaitlists(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "WaitlistUsecase.ProcessWaitlists")
+	defer span.End()
+
+	expired, err := uc.waitlistRepo.ExpireOffers(ctx, util.TimeNow())
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when expiring waitlist offers", zap.Error(err))
+		return err
+	}
+
+	ticketIDs, err := uc.waitlistRepo.GetTicketIDsToOffer(ctx)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("Error when getting tickets to offer", zap.Error(err))
+		return err
+	}
+
+	offered := 0
+	for _, ticketID := range ticketIDs {
+		count, err := uc.offerer.OfferFreedStock(ctx, ticketID)
+		if err != nil {
+			return err
+		}
+		offered += count
+	}
+
+	if expired > 0 || offered > 0 {
+		uc.logger.WithContext(ctx).Info("Processed ticket waitlists", zap.Int64("expired", expired), zap.Int("offered", offered))
+	}
+	return nil
+}
+
+func (uc *waitlistUsecase) getEntry(ctx context.Context, waitlistID int) (model.WaitlistEntry, error) {
+	entry, err := uc.waitlistRepo.GetWaitlistEntryByID(ctx, waitlistID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entry, fmt.Errorf("waitlist entry %d: %w", waitlistID, ErrNotFound)
+		}
+		uc.logger.WithContext(ctx).Error("Error when getting waitlist entry by id", zap.Error(err))
+		return entry, err
+	}
+	return entry, nil
+}
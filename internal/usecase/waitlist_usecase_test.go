@@ -0,0 +1,266 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/SyamSolution/ticket-management-service/config"
+	"github.com/SyamSolution/ticket-management-service/internal/currency"
+	"github.com/SyamSolution/ticket-management-service/internal/model"
+	"github.com/SyamSolution/ticket-management-service/internal/repository"
+	"github.com/SyamSolution/ticket-management-service/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWaitlistPersister struct {
+	mock.Mock
+}
+
+func (m *MockWaitlistPersister) JoinWaitlist(ctx context.Context, entry model.WaitlistEntry) (int, error) {
+	args := m.Called(ctx, entry)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockWaitlistPersister) GetWaitlistEntryByID(ctx context.Context, waitlistID int) (model.WaitlistEntry, error) {
+	args := m.Called(ctx, waitlistID)
+	return args.Get(0).(model.WaitlistEntry), args.Error(1)
+}
+
+func (m *MockWaitlistPersister) GetWaitlistEntriesByEmail(ctx context.Context, email string) ([]model.WaitlistEntry, error) {
+	args := m.Called(ctx, email)
+	return args.Get(0).([]model.WaitlistEntry), args.Error(1)
+}
+
+func (m *MockWaitlistPersister) LeaveWaitlist(ctx context.Context, entry model.WaitlistEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockWaitlistPersister) OfferStock(ctx context.Context, ticketID int, offeredAt, expiresAt time.Time) ([]model.WaitlistEntry, error) {
+	args := m.Called(ctx, ticketID, offeredAt, expiresAt)
+	return args.Get(0).([]model.WaitlistEntry), args.Error(1)
+}
+
+func (m *MockWaitlistPersister) ClaimOffer(ctx context.Context, entry model.WaitlistEntry, orderID string, now time.Time) error {
+	args := m.Called(ctx, entry, orderID, now)
+	return args.Error(0)
+}
+
+func (m *MockWaitlistPersister) ExpireOffers(ctx context.Context, now time.Time) (int64, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockWaitlistPersister) GetTicketIDsToOffer(ctx context.Context) ([]int, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+type MockWaitlistOfferer struct {
+	mock.Mock
+}
+
+func (m *MockWaitlistOfferer) OfferFreedStock(ctx context.Context, ticketID int) (int, error) {
+	args := m.Called(ctx, ticketID)
+	return args.Int(0), args.Error(1)
+}
+
+func TestOfferFreedStock(t *testing.T) {
+	now := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+	util.TimeNow = func() time.Time { return now }
+	defer func() { util.TimeNow = time.Now }()
+
+	expiresAt := now.Add(30 * time.Minute)
+	offers := []model.WaitlistEntry{
+		{WaitlistID: 1, TicketID: 2, Email: "first@mail.com", Quantity: 2, Status: "offered", OfferedAt: &now, OfferExpiresAt: &expiresAt},
+		{WaitlistID: 3, TicketID: 2, Email: "second@mail.com", Quantity: 1, Status: "offered", OfferedAt: &now, OfferExpiresAt: &expiresAt},
+	}
+
+	t.Run("should notify every user offered tickets", func(t *testing.T) {
+		waitlistRepo := new(MockWaitlistPersister)
+		publisher := new(MockPublisher)
+		waitlistRepo.On("OfferStock", mock.Anything, 2, now, expiresAt).Return(offers, nil)
+		publisher.On("Publish", mock.Anything, util.TOPIC_WAITLIST_OFFER, model.MessageWaitlistOffer{WaitlistID: 1, TicketID: 2,
+			Email: "first@mail.com", Quantity: 2, OfferExpiresAt: expiresAt}).Return(nil)
+		publisher.On("Publish", mock.Anything, util.TOPIC_WAITLIST_OFFER, model.MessageWaitlistOffer{WaitlistID: 3, TicketID: 2,
+			Email: "second@mail.com", Quantity: 1, OfferExpiresAt: expiresAt}).Return(nil)
+		offerer := NewWaitlistOfferer(waitlistRepo, publisher, config.NewNopLogger(), 30*time.Minute)
+
+		count, err := offerer.OfferFreedStock(context.Background(), 2)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+		publisher.AssertExpectations(t)
+	})
+
+	t.Run("should keep the offers when the notification fails", func(t *testing.T) {
+		waitlistRepo := new(MockWaitlistPersister)
+		publisher := new(MockPublisher)
+		waitlistRepo.On("OfferStock", mock.Anything, 2, now, expiresAt).Return(offers[:1], nil)
+		publisher.On("Publish", mock.Anything, util.TOPIC_WAITLIST_OFFER, mock.Anything).Return(assert.AnError)
+		offerer := NewWaitlistOfferer(waitlistRepo, publisher, config.NewNopLogger(), 30*time.Minute)
+
+		count, err := offerer.OfferFreedStock(context.Background(), 2)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+}
+
+func TestJoinWaitlist(t *testing.T) {
+	setup := func(status string, stock int) (*MockWaitlistPersister, WaitlistExecutor) {
+		waitlistRepo := new(MockWaitlistPersister)
+		ticketRepo := new(MockTicketPersister)
+		ticketRepo.On("GetTicketPurchaseLimit", mock.Anything, 2).Return(model.TicketPurchaseLimit{TicketID: 2, EventID: 3, EventStatus: status}, nil)
+		ticketRepo.On("GetTicketByID", mock.Anything, 2).Return(model.Ticket{TicketID: 2, Available: stock}, nil)
+		return waitlistRepo, NewWaitlistUsecase(waitlistRepo, ticketRepo, nil, new(MockWaitlistOfferer), config.NewNopLogger())
+	}
+
+	t.Run("should queue the user for a sold out ticket", func(t *testing.T) {
+		waitlistRepo, uc := setup("sold_out", 0)
+		waitlistRepo.On("JoinWaitlist", mock.Anything, model.WaitlistEntry{TicketID: 2, Email: "user@mail.com", Quantity: 2,
+			Status: "waiting"}).Return(7, nil)
+		waitlistRepo.On("GetWaitlistEntryByID", mock.Anything, 7).Return(model.WaitlistEntry{WaitlistID: 7, Status: "waiting"}, nil)
+
+		entry, err := uc.JoinWaitlist(context.Background(), "user@mail.com", 2, model.WaitlistRequest{Quantity: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, 7, entry.WaitlistID)
+		waitlistRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject a ticket still in stock", func(t *testing.T) {
+		_, uc := setup("on_sale", 4)
+
+		_, err := uc.JoinWaitlist(context.Background(), "user@mail.com", 2, model.WaitlistRequest{Quantity: 2})
+		assert.ErrorIs(t, err, ErrConflict)
+	})
+
+	t.Run("should reject events that no longer sell", func(t *testing.T) {
+		_, uc := setup("cancelled", 0)
+
+		_, err := uc.JoinWaitlist(context.Background(), "user@mail.com", 2, model.WaitlistRequest{Quantity: 2})
+		assert.ErrorIs(t, err, ErrConflict)
+	})
+
+	t.Run("should reject joining twice", func(t *testing.T) {
+		waitlistRepo, uc := setup("sold_out", 0)
+		waitlistRepo.On("JoinWaitlist", mock.Anything, mock.Anything).Return(0, repository.ErrAlreadyWaitlisted)
+
+		_, err := uc.JoinWaitlist(context.Background(), "user@mail.com", 2, model.WaitlistRequest{Quantity: 2})
+		assert.ErrorIs(t, err, ErrConflict)
+	})
+}
+
+func TestLeaveWaitlist(t *testing.T) {
+	t.Run("should pass an offer on to the next user", func(t *testing.T) {
+		waitlistRepo := new(MockWaitlistPersister)
+		offerer := new(MockWaitlistOfferer)
+		entry := model.WaitlistEntry{WaitlistID: 7, TicketID: 2, Email: "user@mail.com", Quantity: 2, Status: "offered"}
+		waitlistRepo.On("GetWaitlistEntryByID", mock.Anything, 7).Return(entry, nil)
+		waitlistRepo.On("LeaveWaitlist", mock.Anything, entry).Return(nil)
+		offerer.On("OfferFreedStock", mock.Anything, 2).Return(1, nil)
+		uc := NewWaitlistUsecase(waitlistRepo, new(MockTicketPersister), nil, offerer, config.NewNopLogger())
+
+		_, err := uc.LeaveWaitlist(context.Background(), "user@mail.com", 7)
+		assert.NoError(t, err)
+		waitlistRepo.AssertExpectations(t)
+		offerer.AssertExpectations(t)
+	})
+
+	t.Run("should hide entries of other users", func(t *testing.T) {
+		waitlistRepo := new(MockWaitlistPersister)
+		waitlistRepo.On("GetWaitlistEntryByID", mock.Anything, 7).Return(model.WaitlistEntry{WaitlistID: 7, Email: "user@mail.com",
+			Status: "waiting"}, nil)
+		uc := NewWaitlistUsecase(waitlistRepo, new(MockTicketPersister), nil, new(MockWaitlistOfferer), config.NewNopLogger())
+
+		_, err := uc.LeaveWaitlist(context.Background(), "stranger@mail.com", 7)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestClaimOffer(t *testing.T) {
+	now := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+	util.TimeNow = func() time.Time { return now }
+	defer func() { util.TimeNow = time.Now }()
+
+	entry := model.WaitlistEntry{WaitlistID: 7, TicketID: 2, Email: "user@mail.com", Quantity: 2, Status: "offered"}
+
+	setup := func() (*MockTicketPersister, *MockReservationPersister, *MockWaitlistPersister, WaitlistExecutor) {
+		ticketRepo := new(MockTicketPersister)
+		reservationRepo := new(MockReservationPersister)
+		waitlistRepo := new(MockWaitlistPersister)
+		offerer := new(MockWaitlistOfferer)
+		ticketUsecase := NewTicketUsecase(ticketRepo, reservationRepo, new(MockSeatPersister), new(MockPromoPersister),
+			new(MockIssuedTicketPersister), new(MockResalePersister), waitlistRepo, offerer,
+			currency.NewStaticRates("", nil), config.NewNopLogger(), 0, 0)
+		offerer.On("OfferFreedStock", mock.Anything, 2).Return(0, nil)
+		waitlistRepo.On("GetWaitlistEntryByID", mock.Anything, 7).Return(entry, nil)
+		reservationRepo.On("GetReservationByOrderID", mock.Anything, "order-9").Return(model.Reservation{}, sql.ErrNoRows)
+		ticketRepo.On("GetTicketPurchaseLimit", mock.Anything, 2).Return(model.TicketPurchaseLimit{TicketID: 2, EventID: 3, EventStatus: "sold_out"}, nil)
+		ticketRepo.On("GetTicketByID", mock.Anything, 2).Return(model.Ticket{TicketID: 2, Price: model.Money{Amount: 100000, Currency: "IDR"}}, nil)
+		ticketRepo.On("GetTicketPriceSchedules", mock.Anything, []int{2}).Return(map[int][]model.PriceSchedule{}, nil)
+		return ticketRepo, reservationRepo, waitlistRepo, NewWaitlistUsecase(waitlistRepo, ticketRepo, ticketUsecase,
+			new(MockWaitlistOfferer), config.NewNopLogger())
+	}
+
+	t.Run("should reserve the offered stock of a sold out event", func(t *testing.T) {
+		ticketRepo, reservationRepo, waitlistRepo, uc := setup()
+		waitlistRepo.On("ClaimOffer", mock.Anything, model.WaitlistEntry{WaitlistID: 7, TicketID: 2, Email: "user@mail.com",
+			Quantity: 2}, "order-9", now).Return(nil)
+		reservationRepo.On("CreateReservation", mock.Anything, mock.MatchedBy(func(reservation model.Reservation) bool {
+			return reservation.OrderID == "order-9" && reservation.Quantity == 2 && reservation.Status == "pending"
+		})).Return(11, nil)
+
+		reservation, err := uc.ClaimOffer(context.Background(), "user@mail.com", 7, model.WaitlistClaimRequest{OrderID: "order-9"})
+		assert.NoError(t, err)
+		assert.Equal(t, 11, reservation.ReservationID)
+		ticketRepo.AssertNotCalled(t, "UpdateStockCreateOrderTicket", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		waitlistRepo.AssertExpectations(t)
+	})
+
+	t.Run("should report an expired offer as conflict", func(t *testing.T) {
+		_, reservationRepo, waitlistRepo, uc := setup()
+		waitlistRepo.On("ClaimOffer", mock.Anything, mock.Anything, "order-9", now).Return(sql.ErrNoRows)
+
+		_, err := uc.ClaimOffer(context.Background(), "user@mail.com", 7, model.WaitlistClaimRequest{OrderID: "order-9"})
+		assert.ErrorIs(t, err, ErrConflict)
+		reservationRepo.AssertNotCalled(t, "CreateReservation", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should give back the offered stock when the reservation is not recorded", func(t *testing.T) {
+		ticketRepo, reservationRepo, waitlistRepo, uc := setup()
+		waitlistRepo.On("ClaimOffer", mock.Anything, mock.Anything, "order-9", now).Return(nil)
+		reservationRepo.On("CreateReservation", mock.Anything, mock.AnythingOfType("model.Reservation")).Return(0, sql.ErrConnDone)
+		ticketRepo.On("UpdateStockFailOrderTicket", mock.Anything, 2, 2, "").Return(nil)
+
+		_, err := uc.ClaimOffer(context.Background(), "user@mail.com", 7, model.WaitlistClaimRequest{OrderID: "order-9"})
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+		ticketRepo.AssertExpectations(t)
+	})
+
+	t.Run("should hide offers of other users", func(t *testing.T) {
+		_, _, _, uc := setup()
+
+		_, err := uc.ClaimOffer(context.Background(), "stranger@mail.com", 7, model.WaitlistClaimRequest{OrderID: "order-9"})
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestProcessWaitlists(t *testing.T) {
+	now := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+	util.TimeNow = func() time.Time { return now }
+	defer func() { util.TimeNow = time.Now }()
+
+	waitlistRepo := new(MockWaitlistPersister)
+	offerer := new(MockWaitlistOfferer)
+	waitlistRepo.On("ExpireOffers", mock.Anything, now).Return(int64(1), nil)
+	waitlistRepo.On("GetTicketIDsToOffer", mock.Anything).Return([]int{2, 4}, nil)
+	offerer.On("OfferFreedStock", mock.Anything, 2).Return(1, nil)
+	offerer.On("OfferFreedStock", mock.Anything, 4).Return(0, nil)
+	uc := NewWaitlistUsecase(waitlistRepo, new(MockTicketPersister), nil, offerer, config.NewNopLogger())
+
+	err := uc.ProcessWaitlists(context.Background())
+	assert.NoError(t, err)
+	offerer.AssertExpectations(t)
+}
@@ -15,6 +15,37 @@ const (
 	ERROR_NOTACCEPTABLE_CODE = 4103
 	ERROR_NOTACCEPTABLE_MSG  = "not accetable value"
 	ERROR_DELETED_POST_MSG   = "Sorry, the post is deleted. Explore other interesting content!"
+
+	ERROR_PURCHASE_LIMIT_CODE = 4104
+	ERROR_PURCHASE_LIMIT_MSG  = "maximum %d tickets per user for this event, %d already reserved"
+
+	ERROR_CONFLICT_CODE           = 4105
+	ERROR_INSUFFICIENT_STOCK_CODE = 4106
+
+	ERROR_PROMO_INVALID_CODE        = 4107
+	ERROR_PROMO_INVALID_MSG         = "promo code %s is not valid or has expired"
+	ERROR_PROMO_EXHAUSTED_CODE      = 4108
+	ERROR_PROMO_EXHAUSTED_MSG       = "promo code %s has reached its usage limit"
+	ERROR_PROMO_ALREADY_USED_CODE   = 4109
+	ERROR_PROMO_ALREADY_USED_MSG    = "promo code %s has already been used"
+	ERROR_PROMO_NOT_APPLICABLE_CODE = 4110
+	ERROR_PROMO_NOT_APPLICABLE_MSG  = "promo code %s does not apply to these tickets"
+
+	ERROR_EVENT_NOT_ON_SALE_CODE = 4111
+	ERROR_EVENT_NOT_ON_SALE_MSG  = "tickets for this event are not on sale, the event is %s"
+	ERROR_SALE_NOT_STARTED_MSG   = "ticket sales for this event open at %s"
+	ERROR_SALE_ENDED_MSG         = "ticket sales for this event closed at %s"
+
+	ERROR_CHECK_IN_REJECTED_CODE = 4112
+	ERROR_TICKET_INVALID_MSG     = "ticket is not valid"
+	ERROR_TICKET_WRONG_EVENT_MSG = "ticket is for another event"
+	ERROR_TICKET_VOID_MSG        = "ticket has been voided"
+	ERROR_TICKET_CHECKED_IN_MSG  = "ticket was already checked in at gate %s at %s"
+
+	ERROR_RESALE_CLOSED_CODE    = 4113
+	ERROR_RESALE_CLOSED_MSG     = "resale is closed for this event"
+	ERROR_RESALE_PRICE_CAP_CODE = 4114
+	ERROR_RESALE_PRICE_CAP_MSG  = "resale price can not exceed %d %s"
 )
 
 const DEFAULT_BUSINESS_ERROR_CODE = ERROR_BASE_CODE
@@ -22,6 +53,142 @@ const DEFAULT_BUSINESS_ERROR_MESSAGE = ERROR_BASE_MSG
 
 const DEFAULT_LIMIT_PAGINATION = 10
 
+// reservation status
+const (
+	RESERVATION_STATUS_PENDING   = "pending"
+	RESERVATION_STATUS_CONFIRMED = "confirmed"
+	RESERVATION_STATUS_RELEASED  = "released"
+	// RESERVATION_STATUS_REFUND_PENDING is a paid reservation voided by the
+	// cancellation of its event and waiting for its refund.
+	RESERVATION_STATUS_REFUND_PENDING = "refund_pending"
+)
+
+// seat status
+const (
+	SEAT_STATUS_AVAILABLE = "available"
+	SEAT_STATUS_HELD      = "held"
+	SEAT_STATUS_SOLD      = "sold"
+)
+
+// event status
+const (
+	EVENT_STATUS_DRAFT     = "draft"
+	EVENT_STATUS_ON_SALE   = "on_sale"
+	EVENT_STATUS_PAUSED    = "paused"
+	EVENT_STATUS_SOLD_OUT  = "sold_out"
+	EVENT_STATUS_CANCELLED = "cancelled"
+	EVENT_STATUS_PAST      = "past"
+)
+
+// event cancellation action sent per order
+const (
+	CANCELLATION_ACTION_RELEASED = "released"
+	CANCELLATION_ACTION_REFUND   = "refund"
+)
+
+// message topics published to the other services
+const (
+	TOPIC_EVENT_CANCELLED = "event-cancelled"
+	TOPIC_WAITLIST_OFFER  = "waitlist-offer"
+)
+
+// promo discount type
+const (
+	PROMO_DISCOUNT_PERCENT = "percent"
+	PROMO_DISCOUNT_FIXED   = "fixed"
+)
+
+// promo redemption status
+const (
+	PROMO_REDEMPTION_HELD     = "held"
+	PROMO_REDEMPTION_REDEEMED = "redeemed"
+	PROMO_REDEMPTION_RELEASED = "released"
+)
+
+// issued ticket status
+const (
+	ISSUED_TICKET_STATUS_VALID = "valid"
+	ISSUED_TICKET_STATUS_VOID  = "void"
+)
+
+// ticket transfer status
+const (
+	TRANSFER_STATUS_PENDING   = "pending"
+	TRANSFER_STATUS_ACCEPTED  = "accepted"
+	TRANSFER_STATUS_DECLINED  = "declined"
+	TRANSFER_STATUS_CANCELLED = "cancelled"
+	TRANSFER_STATUS_EXPIRED   = "expired"
+)
+
+// resale listing status
+const (
+	RESALE_LISTING_STATUS_ACTIVE    = "active"
+	RESALE_LISTING_STATUS_RESERVED  = "reserved"
+	RESALE_LISTING_STATUS_SOLD      = "sold"
+	RESALE_LISTING_STATUS_CANCELLED = "cancelled"
+	// RESALE_PRICE_TIER marks the reservations that buy a resale listing.
+	RESALE_PRICE_TIER = "resale"
+)
+
+// UNKNOWN_CONTINENT groups the tickets not linked to a continent.
+const UNKNOWN_CONTINENT = "Unknown"
+
+// ticket waitlist status
+const (
+	WAITLIST_STATUS_WAITING = "waiting"
+	WAITLIST_STATUS_OFFERED = "offered"
+	WAITLIST_STATUS_CLAIMED = "claimed"
+	WAITLIST_STATUS_EXPIRED = "expired"
+	WAITLIST_STATUS_LEFT    = "left"
+)
+
+// inventory ledger reason
+const (
+	LEDGER_REASON_OPENING         = "opening"
+	LEDGER_REASON_RESERVE         = "reserve"
+	LEDGER_REASON_CONFIRM         = "confirm"
+	LEDGER_REASON_RELEASE         = "release"
+	LEDGER_REASON_WAITLIST_OFFER  = "waitlist_offer"
+	LEDGER_REASON_WAITLIST_EXPIRE = "waitlist_expire"
+	LEDGER_REASON_WAITLIST_LEAVE  = "waitlist_leave"
+	// LEDGER_REASON_UNTRACKED records a change of the counters found by
+	// reconciliation that was made without a ledger entry, e.g. by hand.
+	LEDGER_REASON_UNTRACKED = "untracked"
+	LEDGER_REASON_RECONCILE = "reconcile"
+	// LEDGER_ACTOR_SYSTEM is recorded for changes made outside a request,
+	// message or job, whose context carries no actor.
+	LEDGER_ACTOR_SYSTEM = "system"
+)
+
+// stock invariant checked by inventory reconciliation
+const (
+	INVENTORY_INVARIANT_NON_NEGATIVE = "non_negative"
+	INVENTORY_INVARIANT_BALANCED     = "total_matches_counts"
+	INVENTORY_INVARIANT_HELD         = "held_matches_orders"
+	INVENTORY_INVARIANT_SOLD         = "sold_matches_orders"
+	INVENTORY_INVARIANT_LEDGER       = "ledger_matches_counters"
+)
+
+// inventory reconciliation status of a ticket
+const (
+	INVENTORY_STATUS_CONSISTENT   = "consistent"
+	INVENTORY_STATUS_DRIFTED      = "drifted"
+	INVENTORY_STATUS_UNREPAIRABLE = "unrepairable"
+	INVENTORY_STATUS_REPAIRED     = "repaired"
+	// INVENTORY_STATUS_CHANGED is a ticket whose stock changed between the
+	// check and the repair, which is left for the next dry run.
+	INVENTORY_STATUS_CHANGED = "changed"
+)
+
+// ticket scan result
+const (
+	SCAN_RESULT_ACCEPTED    = "accepted"
+	SCAN_RESULT_DUPLICATE   = "duplicate"
+	SCAN_RESULT_WRONG_EVENT = "wrong_event"
+	SCAN_RESULT_INVALID     = "invalid"
+	SCAN_RESULT_VOID        = "void"
+)
+
 // date & time
 const (
 	TIMESTAMP_DEFAULT_FORMAT = "2006-01-02T15:04:05-0700"
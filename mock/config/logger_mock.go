@@ -10,8 +10,10 @@
 package mock_config
 
 import (
+	context "context"
 	reflect "reflect"
 
+	config "github.com/SyamSolution/ticket-management-service/config"
 	gomock "go.uber.org/mock/gomock"
 	zapcore "go.uber.org/zap/zapcore"
 )
@@ -72,3 +74,17 @@ func (mr *MockLoggerMockRecorder) Info(msg any, fields ...any) *gomock.Call {
 	varargs := append([]any{msg}, fields...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockLogger)(nil).Info), varargs...)
 }
+
+// WithContext mocks base method.
+func (m *MockLogger) WithContext(ctx context.Context) config.Logger {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithContext", ctx)
+	ret0, _ := ret[0].(config.Logger)
+	return ret0
+}
+
+// WithContext indicates an expected call of WithContext.
+func (mr *MockLoggerMockRecorder) WithContext(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithContext", reflect.TypeOf((*MockLogger)(nil).WithContext), ctx)
+}
@@ -0,0 +1,146 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/event_usecase.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/usecase/event_usecase.go -destination=mock/event_usecase_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/SyamSolution/ticket-management-service/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockEventExecutor is a mock of EventExecutor interface.
+type MockEventExecutor struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventExecutorMockRecorder
+}
+
+// MockEventExecutorMockRecorder is the mock recorder for MockEventExecutor.
+type MockEventExecutorMockRecorder struct {
+	mock *MockEventExecutor
+}
+
+// NewMockEventExecutor creates a new mock instance.
+func NewMockEventExecutor(ctrl *gomock.Controller) *MockEventExecutor {
+	mock := &MockEventExecutor{ctrl: ctrl}
+	mock.recorder = &MockEventExecutorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventExecutor) EXPECT() *MockEventExecutorMockRecorder {
+	return m.recorder
+}
+
+// CancelEvent mocks base method.
+func (m *MockEventExecutor) CancelEvent(ctx context.Context, eventID int, request model.EventCancelRequest) (model.EventCancellation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelEvent", ctx, eventID, request)
+	ret0, _ := ret[0].(model.EventCancellation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelEvent indicates an expected call of CancelEvent.
+func (mr *MockEventExecutorMockRecorder) CancelEvent(ctx, eventID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelEvent", reflect.TypeOf((*MockEventExecutor)(nil).CancelEvent), ctx, eventID, request)
+}
+
+// GetEvent mocks base method.
+func (m *MockEventExecutor) GetEvent(ctx context.Context, eventID int) (model.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEvent", ctx, eventID)
+	ret0, _ := ret[0].(model.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEvent indicates an expected call of GetEvent.
+func (mr *MockEventExecutorMockRecorder) GetEvent(ctx, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvent", reflect.TypeOf((*MockEventExecutor)(nil).GetEvent), ctx, eventID)
+}
+
+// GetEvents mocks base method.
+func (m *MockEventExecutor) GetEvents(ctx context.Context) ([]model.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEvents", ctx)
+	ret0, _ := ret[0].([]model.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEvents indicates an expected call of GetEvents.
+func (mr *MockEventExecutorMockRecorder) GetEvents(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvents", reflect.TypeOf((*MockEventExecutor)(nil).GetEvents), ctx)
+}
+
+// TransitionEvents mocks base method.
+func (m *MockEventExecutor) TransitionEvents(ctx context.Context) (model.EventTransitions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransitionEvents", ctx)
+	ret0, _ := ret[0].(model.EventTransitions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransitionEvents indicates an expected call of TransitionEvents.
+func (mr *MockEventExecutorMockRecorder) TransitionEvents(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionEvents", reflect.TypeOf((*MockEventExecutor)(nil).TransitionEvents), ctx)
+}
+
+// UpdateEventStatus mocks base method.
+func (m *MockEventExecutor) UpdateEventStatus(ctx context.Context, eventID int, request model.EventStatusRequest) (model.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEventStatus", ctx, eventID, request)
+	ret0, _ := ret[0].(model.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEventStatus indicates an expected call of UpdateEventStatus.
+func (mr *MockEventExecutorMockRecorder) UpdateEventStatus(ctx, eventID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEventStatus", reflect.TypeOf((*MockEventExecutor)(nil).UpdateEventStatus), ctx, eventID, request)
+}
+
+// UpdateResaleCap mocks base method.
+func (m *MockEventExecutor) UpdateResaleCap(ctx context.Context, eventID int, request model.EventResaleCapRequest) (model.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateResaleCap", ctx, eventID, request)
+	ret0, _ := ret[0].(model.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateResaleCap indicates an expected call of UpdateResaleCap.
+func (mr *MockEventExecutorMockRecorder) UpdateResaleCap(ctx, eventID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateResaleCap", reflect.TypeOf((*MockEventExecutor)(nil).UpdateResaleCap), ctx, eventID, request)
+}
+
+// UpdateSaleWindow mocks base method.
+func (m *MockEventExecutor) UpdateSaleWindow(ctx context.Context, eventID int, request model.EventSaleWindowRequest) (model.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSaleWindow", ctx, eventID, request)
+	ret0, _ := ret[0].(model.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSaleWindow indicates an expected call of UpdateSaleWindow.
+func (mr *MockEventExecutorMockRecorder) UpdateSaleWindow(ctx, eventID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSaleWindow", reflect.TypeOf((*MockEventExecutor)(nil).UpdateSaleWindow), ctx, eventID, request)
+}
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/geography_usecase.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/usecase/geography_usecase.go -destination=mock/geography_usecase_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/SyamSolution/ticket-management-service/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockGeographyExecutor is a mock of GeographyExecutor interface.
+type MockGeographyExecutor struct {
+	ctrl     *gomock.Controller
+	recorder *MockGeographyExecutorMockRecorder
+}
+
+// MockGeographyExecutorMockRecorder is the mock recorder for MockGeographyExecutor.
+type MockGeographyExecutorMockRecorder struct {
+	mock *MockGeographyExecutor
+}
+
+// NewMockGeographyExecutor creates a new mock instance.
+func NewMockGeographyExecutor(ctrl *gomock.Controller) *MockGeographyExecutor {
+	mock := &MockGeographyExecutor{ctrl: ctrl}
+	mock.recorder = &MockGeographyExecutorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGeographyExecutor) EXPECT() *MockGeographyExecutorMockRecorder {
+	return m.recorder
+}
+
+// GetCitiesByCountry mocks base method.
+func (m *MockGeographyExecutor) GetCitiesByCountry(ctx context.Context, countryCode string) ([]model.City, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCitiesByCountry", ctx, countryCode)
+	ret0, _ := ret[0].([]model.City)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCitiesByCountry indicates an expected call of GetCitiesByCountry.
+func (mr *MockGeographyExecutorMockRecorder) GetCitiesByCountry(ctx, countryCode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCitiesByCountry", reflect.TypeOf((*MockGeographyExecutor)(nil).GetCitiesByCountry), ctx, countryCode)
+}
+
+// GetContinents mocks base method.
+func (m *MockGeographyExecutor) GetContinents(ctx context.Context) ([]model.Continent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContinents", ctx)
+	ret0, _ := ret[0].([]model.Continent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContinents indicates an expected call of GetContinents.
+func (mr *MockGeographyExecutorMockRecorder) GetContinents(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContinents", reflect.TypeOf((*MockGeographyExecutor)(nil).GetContinents), ctx)
+}
+
+// GetCountriesByContinent mocks base method.
+func (m *MockGeographyExecutor) GetCountriesByContinent(ctx context.Context, continentCode string) ([]model.Country, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCountriesByContinent", ctx, continentCode)
+	ret0, _ := ret[0].([]model.Country)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCountriesByContinent indicates an expected call of GetCountriesByContinent.
+func (mr *MockGeographyExecutorMockRecorder) GetCountriesByContinent(ctx, continentCode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCountriesByContinent", reflect.TypeOf((*MockGeographyExecutor)(nil).GetCountriesByContinent), ctx, continentCode)
+}
+
+// GetVenuesByCity mocks base method.
+func (m *MockGeographyExecutor) GetVenuesByCity(ctx context.Context, cityID int) ([]model.Venue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVenuesByCity", ctx, cityID)
+	ret0, _ := ret[0].([]model.Venue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVenuesByCity indicates an expected call of GetVenuesByCity.
+func (mr *MockGeographyExecutorMockRecorder) GetVenuesByCity(ctx, cityID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVenuesByCity", reflect.TypeOf((*MockGeographyExecutor)(nil).GetVenuesByCity), ctx, cityID)
+}
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/inventory_ledger_usecase.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/usecase/inventory_ledger_usecase.go -destination=mock/inventory_ledger_usecase_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/SyamSolution/ticket-management-service/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockInventoryLedgerExecutor is a mock of InventoryLedgerExecutor interface.
+type MockInventoryLedgerExecutor struct {
+	ctrl     *gomock.Controller
+	recorder *MockInventoryLedgerExecutorMockRecorder
+}
+
+// MockInventoryLedgerExecutorMockRecorder is the mock recorder for MockInventoryLedgerExecutor.
+type MockInventoryLedgerExecutorMockRecorder struct {
+	mock *MockInventoryLedgerExecutor
+}
+
+// NewMockInventoryLedgerExecutor creates a new mock instance.
+func NewMockInventoryLedgerExecutor(ctrl *gomock.Controller) *MockInventoryLedgerExecutor {
+	mock := &MockInventoryLedgerExecutor{ctrl: ctrl}
+	mock.recorder = &MockInventoryLedgerExecutorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInventoryLedgerExecutor) EXPECT() *MockInventoryLedgerExecutorMockRecorder {
+	return m.recorder
+}
+
+// GetTicketLedger mocks base method.
+func (m *MockInventoryLedgerExecutor) GetTicketLedger(ctx context.Context, ticketID, limit, offset int) (model.InventoryLedger, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTicketLedger", ctx, ticketID, limit, offset)
+	ret0, _ := ret[0].(model.InventoryLedger)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTicketLedger indicates an expected call of GetTicketLedger.
+func (mr *MockInventoryLedgerExecutorMockRecorder) GetTicketLedger(ctx, ticketID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTicketLedger", reflect.TypeOf((*MockInventoryLedgerExecutor)(nil).GetTicketLedger), ctx, ticketID, limit, offset)
+}
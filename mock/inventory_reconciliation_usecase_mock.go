@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/inventory_reconciliation_usecase.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/usecase/inventory_reconciliation_usecase.go -destination=mock/inventory_reconciliation_usecase_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/SyamSolution/ticket-management-service/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockInventoryReconciliationExecutor is a mock of InventoryReconciliationExecutor interface.
+type MockInventoryReconciliationExecutor struct {
+	ctrl     *gomock.Controller
+	recorder *MockInventoryReconciliationExecutorMockRecorder
+}
+
+// MockInventoryReconciliationExecutorMockRecorder is the mock recorder for MockInventoryReconciliationExecutor.
+type MockInventoryReconciliationExecutorMockRecorder struct {
+	mock *MockInventoryReconciliationExecutor
+}
+
+// NewMockInventoryReconciliationExecutor creates a new mock instance.
+func NewMockInventoryReconciliationExecutor(ctrl *gomock.Controller) *MockInventoryReconciliationExecutor {
+	mock := &MockInventoryReconciliationExecutor{ctrl: ctrl}
+	mock.recorder = &MockInventoryReconciliationExecutorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInventoryReconciliationExecutor) EXPECT() *MockInventoryReconciliationExecutorMockRecorder {
+	return m.recorder
+}
+
+// CheckInventory mocks base method.
+func (m *MockInventoryReconciliationExecutor) CheckInventory(ctx context.Context) (model.InventoryReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckInventory", ctx)
+	ret0, _ := ret[0].(model.InventoryReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckInventory indicates an expected call of CheckInventory.
+func (mr *MockInventoryReconciliationExecutorMockRecorder) CheckInventory(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckInventory", reflect.TypeOf((*MockInventoryReconciliationExecutor)(nil).CheckInventory), ctx)
+}
+
+// RepairInventory mocks base method.
+func (m *MockInventoryReconciliationExecutor) RepairInventory(ctx context.Context, request model.InventoryRepairRequest) (model.InventoryReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RepairInventory", ctx, request)
+	ret0, _ := ret[0].(model.InventoryReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RepairInventory indicates an expected call of RepairInventory.
+func (mr *MockInventoryReconciliationExecutorMockRecorder) RepairInventory(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepairInventory", reflect.TypeOf((*MockInventoryReconciliationExecutor)(nil).RepairInventory), ctx, request)
+}
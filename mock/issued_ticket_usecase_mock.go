@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/issued_ticket_usecase.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/usecase/issued_ticket_usecase.go -destination=mock/issued_ticket_usecase_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/SyamSolution/ticket-management-service/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIssuedTicketExecutor is a mock of IssuedTicketExecutor interface.
+type MockIssuedTicketExecutor struct {
+	ctrl     *gomock.Controller
+	recorder *MockIssuedTicketExecutorMockRecorder
+}
+
+// MockIssuedTicketExecutorMockRecorder is the mock recorder for MockIssuedTicketExecutor.
+type MockIssuedTicketExecutorMockRecorder struct {
+	mock *MockIssuedTicketExecutor
+}
+
+// NewMockIssuedTicketExecutor creates a new mock instance.
+func NewMockIssuedTicketExecutor(ctrl *gomock.Controller) *MockIssuedTicketExecutor {
+	mock := &MockIssuedTicketExecutor{ctrl: ctrl}
+	mock.recorder = &MockIssuedTicketExecutorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIssuedTicketExecutor) EXPECT() *MockIssuedTicketExecutorMockRecorder {
+	return m.recorder
+}
+
+// CheckIn mocks base method.
+func (m *MockIssuedTicketExecutor) CheckIn(ctx context.Context, eventID int, request model.CheckInRequest) (model.TicketScan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckIn", ctx, eventID, request)
+	ret0, _ := ret[0].(model.TicketScan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckIn indicates an expected call of CheckIn.
+func (mr *MockIssuedTicketExecutorMockRecorder) CheckIn(ctx, eventID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIn", reflect.TypeOf((*MockIssuedTicketExecutor)(nil).CheckIn), ctx, eventID, request)
+}
+
+// GetMyTickets mocks base method.
+func (m *MockIssuedTicketExecutor) GetMyTickets(ctx context.Context, email string) ([]model.IssuedTicket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMyTickets", ctx, email)
+	ret0, _ := ret[0].([]model.IssuedTicket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMyTickets indicates an expected call of GetMyTickets.
+func (mr *MockIssuedTicketExecutorMockRecorder) GetMyTickets(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMyTickets", reflect.TypeOf((*MockIssuedTicketExecutor)(nil).GetMyTickets), ctx, email)
+}
+
+// GetVerificationKey mocks base method.
+func (m *MockIssuedTicketExecutor) GetVerificationKey(ctx context.Context) model.VerificationKey {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVerificationKey", ctx)
+	ret0, _ := ret[0].(model.VerificationKey)
+	return ret0
+}
+
+// GetVerificationKey indicates an expected call of GetVerificationKey.
+func (mr *MockIssuedTicketExecutorMockRecorder) GetVerificationKey(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVerificationKey", reflect.TypeOf((*MockIssuedTicketExecutor)(nil).GetVerificationKey), ctx)
+}
+
+// SyncCheckIns mocks base method.
+func (m *MockIssuedTicketExecutor) SyncCheckIns(ctx context.Context, eventID int, request model.CheckInSyncRequest) ([]model.TicketScan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncCheckIns", ctx, eventID, request)
+	ret0, _ := ret[0].([]model.TicketScan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncCheckIns indicates an expected call of SyncCheckIns.
+func (mr *MockIssuedTicketExecutorMockRecorder) SyncCheckIns(ctx, eventID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncCheckIns", reflect.TypeOf((*MockIssuedTicketExecutor)(nil).SyncCheckIns), ctx, eventID, request)
+}
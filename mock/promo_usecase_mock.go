@@ -0,0 +1,130 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/promo_usecase.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/usecase/promo_usecase.go -destination=mock/promo_usecase_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/SyamSolution/ticket-management-service/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPromoExecutor is a mock of PromoExecutor interface.
+type MockPromoExecutor struct {
+	ctrl     *gomock.Controller
+	recorder *MockPromoExecutorMockRecorder
+}
+
+// MockPromoExecutorMockRecorder is the mock recorder for MockPromoExecutor.
+type MockPromoExecutorMockRecorder struct {
+	mock *MockPromoExecutor
+}
+
+// NewMockPromoExecutor creates a new mock instance.
+func NewMockPromoExecutor(ctrl *gomock.Controller) *MockPromoExecutor {
+	mock := &MockPromoExecutor{ctrl: ctrl}
+	mock.recorder = &MockPromoExecutorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPromoExecutor) EXPECT() *MockPromoExecutorMockRecorder {
+	return m.recorder
+}
+
+// CreatePromo mocks base method.
+func (m *MockPromoExecutor) CreatePromo(ctx context.Context, request model.PromoRequest) (model.Promo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePromo", ctx, request)
+	ret0, _ := ret[0].(model.Promo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePromo indicates an expected call of CreatePromo.
+func (mr *MockPromoExecutorMockRecorder) CreatePromo(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePromo", reflect.TypeOf((*MockPromoExecutor)(nil).CreatePromo), ctx, request)
+}
+
+// DeletePromo mocks base method.
+func (m *MockPromoExecutor) DeletePromo(ctx context.Context, promoID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePromo", ctx, promoID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePromo indicates an expected call of DeletePromo.
+func (mr *MockPromoExecutorMockRecorder) DeletePromo(ctx, promoID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePromo", reflect.TypeOf((*MockPromoExecutor)(nil).DeletePromo), ctx, promoID)
+}
+
+// GetPromo mocks base method.
+func (m *MockPromoExecutor) GetPromo(ctx context.Context, promoID int) (model.Promo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPromo", ctx, promoID)
+	ret0, _ := ret[0].(model.Promo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPromo indicates an expected call of GetPromo.
+func (mr *MockPromoExecutorMockRecorder) GetPromo(ctx, promoID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPromo", reflect.TypeOf((*MockPromoExecutor)(nil).GetPromo), ctx, promoID)
+}
+
+// GetPromos mocks base method.
+func (m *MockPromoExecutor) GetPromos(ctx context.Context) ([]model.Promo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPromos", ctx)
+	ret0, _ := ret[0].([]model.Promo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPromos indicates an expected call of GetPromos.
+func (mr *MockPromoExecutorMockRecorder) GetPromos(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPromos", reflect.TypeOf((*MockPromoExecutor)(nil).GetPromos), ctx)
+}
+
+// Quote mocks base method.
+func (m *MockPromoExecutor) Quote(ctx context.Context, email string, request model.QuoteRequest) (model.Quote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Quote", ctx, email, request)
+	ret0, _ := ret[0].(model.Quote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Quote indicates an expected call of Quote.
+func (mr *MockPromoExecutorMockRecorder) Quote(ctx, email, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Quote", reflect.TypeOf((*MockPromoExecutor)(nil).Quote), ctx, email, request)
+}
+
+// UpdatePromo mocks base method.
+func (m *MockPromoExecutor) UpdatePromo(ctx context.Context, promoID int, request model.PromoRequest) (model.Promo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePromo", ctx, promoID, request)
+	ret0, _ := ret[0].(model.Promo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePromo indicates an expected call of UpdatePromo.
+func (mr *MockPromoExecutorMockRecorder) UpdatePromo(ctx, promoID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePromo", reflect.TypeOf((*MockPromoExecutor)(nil).UpdatePromo), ctx, promoID, request)
+}
@@ -0,0 +1,116 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/resale_usecase.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/usecase/resale_usecase.go -destination=mock/resale_usecase_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/SyamSolution/ticket-management-service/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockResaleExecutor is a mock of ResaleExecutor interface.
+type MockResaleExecutor struct {
+	ctrl     *gomock.Controller
+	recorder *MockResaleExecutorMockRecorder
+}
+
+// MockResaleExecutorMockRecorder is the mock recorder for MockResaleExecutor.
+type MockResaleExecutorMockRecorder struct {
+	mock *MockResaleExecutor
+}
+
+// NewMockResaleExecutor creates a new mock instance.
+func NewMockResaleExecutor(ctrl *gomock.Controller) *MockResaleExecutor {
+	mock := &MockResaleExecutor{ctrl: ctrl}
+	mock.recorder = &MockResaleExecutorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockResaleExecutor) EXPECT() *MockResaleExecutorMockRecorder {
+	return m.recorder
+}
+
+// CreateListing mocks base method.
+func (m *MockResaleExecutor) CreateListing(ctx context.Context, email string, issuedTicketID int, request model.ResaleListingRequest) (model.ResaleListing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateListing", ctx, email, issuedTicketID, request)
+	ret0, _ := ret[0].(model.ResaleListing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateListing indicates an expected call of CreateListing.
+func (mr *MockResaleExecutorMockRecorder) CreateListing(ctx, email, issuedTicketID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateListing", reflect.TypeOf((*MockResaleExecutor)(nil).CreateListing), ctx, email, issuedTicketID, request)
+}
+
+// GetEventListings mocks base method.
+func (m *MockResaleExecutor) GetEventListings(ctx context.Context, eventID int) ([]model.ResaleListing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventListings", ctx, eventID)
+	ret0, _ := ret[0].([]model.ResaleListing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventListings indicates an expected call of GetEventListings.
+func (mr *MockResaleExecutorMockRecorder) GetEventListings(ctx, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventListings", reflect.TypeOf((*MockResaleExecutor)(nil).GetEventListings), ctx, eventID)
+}
+
+// GetMyListings mocks base method.
+func (m *MockResaleExecutor) GetMyListings(ctx context.Context, email string) ([]model.ResaleListing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMyListings", ctx, email)
+	ret0, _ := ret[0].([]model.ResaleListing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMyListings indicates an expected call of GetMyListings.
+func (mr *MockResaleExecutorMockRecorder) GetMyListings(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMyListings", reflect.TypeOf((*MockResaleExecutor)(nil).GetMyListings), ctx, email)
+}
+
+// PurchaseListing mocks base method.
+func (m *MockResaleExecutor) PurchaseListing(ctx context.Context, email string, listingID int, request model.ResalePurchaseRequest) (model.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurchaseListing", ctx, email, listingID, request)
+	ret0, _ := ret[0].(model.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurchaseListing indicates an expected call of PurchaseListing.
+func (mr *MockResaleExecutorMockRecorder) PurchaseListing(ctx, email, listingID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurchaseListing", reflect.TypeOf((*MockResaleExecutor)(nil).PurchaseListing), ctx, email, listingID, request)
+}
+
+// WithdrawListing mocks base method.
+func (m *MockResaleExecutor) WithdrawListing(ctx context.Context, email string, listingID int) (model.ResaleListing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithdrawListing", ctx, email, listingID)
+	ret0, _ := ret[0].(model.ResaleListing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WithdrawListing indicates an expected call of WithdrawListing.
+func (mr *MockResaleExecutorMockRecorder) WithdrawListing(ctx, email, listingID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithdrawListing", reflect.TypeOf((*MockResaleExecutor)(nil).WithdrawListing), ctx, email, listingID)
+}
@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/seat_usecase.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/usecase/seat_usecase.go -destination=mock/seat_usecase_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/SyamSolution/ticket-management-service/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSeatExecutor is a mock of SeatExecutor interface.
+type MockSeatExecutor struct {
+	ctrl     *gomock.Controller
+	recorder *MockSeatExecutorMockRecorder
+}
+
+// MockSeatExecutorMockRecorder is the mock recorder for MockSeatExecutor.
+type MockSeatExecutorMockRecorder struct {
+	mock *MockSeatExecutor
+}
+
+// NewMockSeatExecutor creates a new mock instance.
+func NewMockSeatExecutor(ctrl *gomock.Controller) *MockSeatExecutor {
+	mock := &MockSeatExecutor{ctrl: ctrl}
+	mock.recorder = &MockSeatExecutorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSeatExecutor) EXPECT() *MockSeatExecutorMockRecorder {
+	return m.recorder
+}
+
+// GetSeatMap mocks base method.
+func (m *MockSeatExecutor) GetSeatMap(ctx context.Context, eventID int) (model.SeatMap, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeatMap", ctx, eventID)
+	ret0, _ := ret[0].(model.SeatMap)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeatMap indicates an expected call of GetSeatMap.
+func (mr *MockSeatExecutorMockRecorder) GetSeatMap(ctx, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeatMap", reflect.TypeOf((*MockSeatExecutor)(nil).GetSeatMap), ctx, eventID)
+}
+
+// ReleaseExpiredHolds mocks base method.
+func (m *MockSeatExecutor) ReleaseExpiredHolds(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseExpiredHolds", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseExpiredHolds indicates an expected call of ReleaseExpiredHolds.
+func (mr *MockSeatExecutorMockRecorder) ReleaseExpiredHolds(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseExpiredHolds", reflect.TypeOf((*MockSeatExecutor)(nil).ReleaseExpiredHolds), ctx)
+}
+
+// ReserveSeats mocks base method.
+func (m *MockSeatExecutor) ReserveSeats(ctx context.Context, eventID int, email string, request model.ReserveSeatsRequest) (model.SeatReservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveSeats", ctx, eventID, email, request)
+	ret0, _ := ret[0].(model.SeatReservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveSeats indicates an expected call of ReserveSeats.
+func (mr *MockSeatExecutorMockRecorder) ReserveSeats(ctx, eventID, email, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveSeats", reflect.TypeOf((*MockSeatExecutor)(nil).ReserveSeats), ctx, eventID, email, request)
+}
@@ -10,6 +10,7 @@
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
 
 	model "github.com/SyamSolution/ticket-management-service/internal/model"
@@ -40,90 +41,134 @@ func (m *MockTicketExecutor) EXPECT() *MockTicketExecutorMockRecorder {
 }
 
 // GetAvailableTicketByContinent mocks base method.
-func (m *MockTicketExecutor) GetAvailableTicketByContinent(continent string) ([]model.TicketResponse, error) {
+func (m *MockTicketExecutor) GetAvailableTicketByContinent(ctx context.Context, continent string, opts model.PriceOptions) ([]model.TicketResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAvailableTicketByContinent", continent)
+	ret := m.ctrl.Call(m, "GetAvailableTicketByContinent", ctx, continent, opts)
 	ret0, _ := ret[0].([]model.TicketResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAvailableTicketByContinent indicates an expected call of GetAvailableTicketByContinent.
-func (mr *MockTicketExecutorMockRecorder) GetAvailableTicketByContinent(continent any) *gomock.Call {
+func (mr *MockTicketExecutorMockRecorder) GetAvailableTicketByContinent(ctx, continent, opts any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailableTicketByContinent", reflect.TypeOf((*MockTicketExecutor)(nil).GetAvailableTicketByContinent), continent)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailableTicketByContinent", reflect.TypeOf((*MockTicketExecutor)(nil).GetAvailableTicketByContinent), ctx, continent, opts)
 }
 
 // GetAvailableTicketByType mocks base method.
-func (m *MockTicketExecutor) GetAvailableTicketByType(ticketType string) ([]model.TicketResponse, error) {
+func (m *MockTicketExecutor) GetAvailableTicketByType(ctx context.Context, ticketType string, opts model.PriceOptions) ([]model.TicketResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAvailableTicketByType", ticketType)
+	ret := m.ctrl.Call(m, "GetAvailableTicketByType", ctx, ticketType, opts)
 	ret0, _ := ret[0].([]model.TicketResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAvailableTicketByType indicates an expected call of GetAvailableTicketByType.
-func (mr *MockTicketExecutorMockRecorder) GetAvailableTicketByType(ticketType any) *gomock.Call {
+func (mr *MockTicketExecutorMockRecorder) GetAvailableTicketByType(ctx, ticketType, opts any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailableTicketByType", reflect.TypeOf((*MockTicketExecutor)(nil).GetAvailableTicketByType), ticketType)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailableTicketByType", reflect.TypeOf((*MockTicketExecutor)(nil).GetAvailableTicketByType), ctx, ticketType, opts)
 }
 
 // GetStockTicketGroupByContinent mocks base method.
-func (m *MockTicketExecutor) GetStockTicketGroupByContinent() ([]model.StockTicket, error) {
+func (m *MockTicketExecutor) GetStockTicketGroupByContinent(ctx context.Context) ([]model.StockTicket, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetStockTicketGroupByContinent")
+	ret := m.ctrl.Call(m, "GetStockTicketGroupByContinent", ctx)
 	ret0, _ := ret[0].([]model.StockTicket)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetStockTicketGroupByContinent indicates an expected call of GetStockTicketGroupByContinent.
-func (mr *MockTicketExecutorMockRecorder) GetStockTicketGroupByContinent() *gomock.Call {
+func (mr *MockTicketExecutorMockRecorder) GetStockTicketGroupByContinent(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockTicketGroupByContinent", reflect.TypeOf((*MockTicketExecutor)(nil).GetStockTicketGroupByContinent))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockTicketGroupByContinent", reflect.TypeOf((*MockTicketExecutor)(nil).GetStockTicketGroupByContinent), ctx)
 }
 
 // GetTicketByContinent mocks base method.
-func (m *MockTicketExecutor) GetTicketByContinent(continent string) ([]model.TicketResponse, error) {
+func (m *MockTicketExecutor) GetTicketByContinent(ctx context.Context, continent string, opts model.PriceOptions) ([]model.TicketResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTicketByContinent", continent)
+	ret := m.ctrl.Call(m, "GetTicketByContinent", ctx, continent, opts)
 	ret0, _ := ret[0].([]model.TicketResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTicketByContinent indicates an expected call of GetTicketByContinent.
-func (mr *MockTicketExecutorMockRecorder) GetTicketByContinent(continent any) *gomock.Call {
+func (mr *MockTicketExecutorMockRecorder) GetTicketByContinent(ctx, continent, opts any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTicketByContinent", reflect.TypeOf((*MockTicketExecutor)(nil).GetTicketByContinent), continent)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTicketByContinent", reflect.TypeOf((*MockTicketExecutor)(nil).GetTicketByContinent), ctx, continent, opts)
 }
 
 // GetTicketEventByTicketID mocks base method.
-func (m *MockTicketExecutor) GetTicketEventByTicketID(TicketID int) (model.TicketEvent, error) {
+func (m *MockTicketExecutor) GetTicketEventByTicketID(ctx context.Context, TicketID int, opts model.PriceOptions) (model.TicketEvent, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTicketEventByTicketID", TicketID)
+	ret := m.ctrl.Call(m, "GetTicketEventByTicketID", ctx, TicketID, opts)
 	ret0, _ := ret[0].(model.TicketEvent)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTicketEventByTicketID indicates an expected call of GetTicketEventByTicketID.
-func (mr *MockTicketExecutorMockRecorder) GetTicketEventByTicketID(TicketID any) *gomock.Call {
+func (mr *MockTicketExecutorMockRecorder) GetTicketEventByTicketID(ctx, TicketID, opts any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTicketEventByTicketID", reflect.TypeOf((*MockTicketExecutor)(nil).GetTicketEventByTicketID), TicketID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTicketEventByTicketID", reflect.TypeOf((*MockTicketExecutor)(nil).GetTicketEventByTicketID), ctx, TicketID, opts)
+}
+
+// ReleaseExpiredReservations mocks base method.
+func (m *MockTicketExecutor) ReleaseExpiredReservations(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseExpiredReservations", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseExpiredReservations indicates an expected call of ReleaseExpiredReservations.
+func (mr *MockTicketExecutorMockRecorder) ReleaseExpiredReservations(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseExpiredReservations", reflect.TypeOf((*MockTicketExecutor)(nil).ReleaseExpiredReservations), ctx)
+}
+
+// ReleaseReservation mocks base method.
+func (m *MockTicketExecutor) ReleaseReservation(ctx context.Context, reservation model.Reservation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseReservation", ctx, reservation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseReservation indicates an expected call of ReleaseReservation.
+func (mr *MockTicketExecutorMockRecorder) ReleaseReservation(ctx, reservation any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseReservation", reflect.TypeOf((*MockTicketExecutor)(nil).ReleaseReservation), ctx, reservation)
+}
+
+// ReserveTicket mocks base method.
+func (m *MockTicketExecutor) ReserveTicket(ctx context.Context, message model.MessageOrderTicket) (model.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveTicket", ctx, message)
+	ret0, _ := ret[0].(model.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveTicket indicates an expected call of ReserveTicket.
+func (mr *MockTicketExecutorMockRecorder) ReserveTicket(ctx, message any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveTicket", reflect.TypeOf((*MockTicketExecutor)(nil).ReserveTicket), ctx, message)
 }
 
 // UpdateStockTicket mocks base method.
-func (m *MockTicketExecutor) UpdateStockTicket(ticketID, order int, typeStock string) error {
+func (m *MockTicketExecutor) UpdateStockTicket(ctx context.Context, message model.MessageOrderTicket, typeStock string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateStockTicket", ticketID, order, typeStock)
+	ret := m.ctrl.Call(m, "UpdateStockTicket", ctx, message, typeStock)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateStockTicket indicates an expected call of UpdateStockTicket.
-func (mr *MockTicketExecutorMockRecorder) UpdateStockTicket(ticketID, order, typeStock any) *gomock.Call {
+func (mr *MockTicketExecutorMockRecorder) UpdateStockTicket(ctx, message, typeStock any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStockTicket", reflect.TypeOf((*MockTicketExecutor)(nil).UpdateStockTicket), ticketID, order, typeStock)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStockTicket", reflect.TypeOf((*MockTicketExecutor)(nil).UpdateStockTicket), ctx, message, typeStock)
 }
@@ -0,0 +1,146 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/transfer_usecase.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/usecase/transfer_usecase.go -destination=mock/transfer_usecase_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/SyamSolution/ticket-management-service/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTransferExecutor is a mock of TransferExecutor interface.
+type MockTransferExecutor struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransferExecutorMockRecorder
+}
+
+// MockTransferExecutorMockRecorder is the mock recorder for MockTransferExecutor.
+type MockTransferExecutorMockRecorder struct {
+	mock *MockTransferExecutor
+}
+
+// NewMockTransferExecutor creates a new mock instance.
+func NewMockTransferExecutor(ctrl *gomock.Controller) *MockTransferExecutor {
+	mock := &MockTransferExecutor{ctrl: ctrl}
+	mock.recorder = &MockTransferExecutorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransferExecutor) EXPECT() *MockTransferExecutorMockRecorder {
+	return m.recorder
+}
+
+// AcceptTransfer mocks base method.
+func (m *MockTransferExecutor) AcceptTransfer(ctx context.Context, email string, transferID int) (model.TicketTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptTransfer", ctx, email, transferID)
+	ret0, _ := ret[0].(model.TicketTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptTransfer indicates an expected call of AcceptTransfer.
+func (mr *MockTransferExecutorMockRecorder) AcceptTransfer(ctx, email, transferID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptTransfer", reflect.TypeOf((*MockTransferExecutor)(nil).AcceptTransfer), ctx, email, transferID)
+}
+
+// CancelTransfer mocks base method.
+func (m *MockTransferExecutor) CancelTransfer(ctx context.Context, email string, transferID int) (model.TicketTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelTransfer", ctx, email, transferID)
+	ret0, _ := ret[0].(model.TicketTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelTransfer indicates an expected call of CancelTransfer.
+func (mr *MockTransferExecutorMockRecorder) CancelTransfer(ctx, email, transferID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelTransfer", reflect.TypeOf((*MockTransferExecutor)(nil).CancelTransfer), ctx, email, transferID)
+}
+
+// DeclineTransfer mocks base method.
+func (m *MockTransferExecutor) DeclineTransfer(ctx context.Context, email string, transferID int) (model.TicketTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeclineTransfer", ctx, email, transferID)
+	ret0, _ := ret[0].(model.TicketTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeclineTransfer indicates an expected call of DeclineTransfer.
+func (mr *MockTransferExecutorMockRecorder) DeclineTransfer(ctx, email, transferID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclineTransfer", reflect.TypeOf((*MockTransferExecutor)(nil).DeclineTransfer), ctx, email, transferID)
+}
+
+// ExpireTransfers mocks base method.
+func (m *MockTransferExecutor) ExpireTransfers(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireTransfers", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireTransfers indicates an expected call of ExpireTransfers.
+func (mr *MockTransferExecutorMockRecorder) ExpireTransfers(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireTransfers", reflect.TypeOf((*MockTransferExecutor)(nil).ExpireTransfers), ctx)
+}
+
+// GetMyTransfers mocks base method.
+func (m *MockTransferExecutor) GetMyTransfers(ctx context.Context, email string) ([]model.TicketTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMyTransfers", ctx, email)
+	ret0, _ := ret[0].([]model.TicketTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMyTransfers indicates an expected call of GetMyTransfers.
+func (mr *MockTransferExecutorMockRecorder) GetMyTransfers(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMyTransfers", reflect.TypeOf((*MockTransferExecutor)(nil).GetMyTransfers), ctx, email)
+}
+
+// GetTicketTransfers mocks base method.
+func (m *MockTransferExecutor) GetTicketTransfers(ctx context.Context, issuedTicketID int) ([]model.TicketTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTicketTransfers", ctx, issuedTicketID)
+	ret0, _ := ret[0].([]model.TicketTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTicketTransfers indicates an expected call of GetTicketTransfers.
+func (mr *MockTransferExecutorMockRecorder) GetTicketTransfers(ctx, issuedTicketID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTicketTransfers", reflect.TypeOf((*MockTransferExecutor)(nil).GetTicketTransfers), ctx, issuedTicketID)
+}
+
+// InitiateTransfer mocks base method.
+func (m *MockTransferExecutor) InitiateTransfer(ctx context.Context, email string, issuedTicketID int, request model.TransferRequest) (model.TicketTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InitiateTransfer", ctx, email, issuedTicketID, request)
+	ret0, _ := ret[0].(model.TicketTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InitiateTransfer indicates an expected call of InitiateTransfer.
+func (mr *MockTransferExecutorMockRecorder) InitiateTransfer(ctx, email, issuedTicketID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitiateTransfer", reflect.TypeOf((*MockTransferExecutor)(nil).InitiateTransfer), ctx, email, issuedTicketID, request)
+}
@@ -0,0 +1,153 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/waitlist_usecase.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/usecase/waitlist_usecase.go -destination=mock/waitlist_usecase_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/SyamSolution/ticket-management-service/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWaitlistOfferer is a mock of WaitlistOfferer interface.
+type MockWaitlistOfferer struct {
+	ctrl     *gomock.Controller
+	recorder *MockWaitlistOffererMockRecorder
+}
+
+// MockWaitlistOffererMockRecorder is the mock recorder for MockWaitlistOfferer.
+type MockWaitlistOffererMockRecorder struct {
+	mock *MockWaitlistOfferer
+}
+
+// NewMockWaitlistOfferer creates a new mock instance.
+func NewMockWaitlistOfferer(ctrl *gomock.Controller) *MockWaitlistOfferer {
+	mock := &MockWaitlistOfferer{ctrl: ctrl}
+	mock.recorder = &MockWaitlistOffererMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWaitlistOfferer) EXPECT() *MockWaitlistOffererMockRecorder {
+	return m.recorder
+}
+
+// OfferFreedStock mocks base method.
+func (m *MockWaitlistOfferer) OfferFreedStock(ctx context.Context, ticketID int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OfferFreedStock", ctx, ticketID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OfferFreedStock indicates an expected call of OfferFreedStock.
+func (mr *MockWaitlistOffererMockRecorder) OfferFreedStock(ctx, ticketID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OfferFreedStock", reflect.TypeOf((*MockWaitlistOfferer)(nil).OfferFreedStock), ctx, ticketID)
+}
+
+// MockWaitlistExecutor is a mock of WaitlistExecutor interface.
+type MockWaitlistExecutor struct {
+	ctrl     *gomock.Controller
+	recorder *MockWaitlistExecutorMockRecorder
+}
+
+// MockWaitlistExecutorMockRecorder is the mock recorder for MockWaitlistExecutor.
+type MockWaitlistExecutorMockRecorder struct {
+	mock *MockWaitlistExecutor
+}
+
+// NewMockWaitlistExecutor creates a new mock instance.
+func NewMockWaitlistExecutor(ctrl *gomock.Controller) *MockWaitlistExecutor {
+	mock := &MockWaitlistExecutor{ctrl: ctrl}
+	mock.recorder = &MockWaitlistExecutorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWaitlistExecutor) EXPECT() *MockWaitlistExecutorMockRecorder {
+	return m.recorder
+}
+
+// ClaimOffer mocks base method.
+func (m *MockWaitlistExecutor) ClaimOffer(ctx context.Context, email string, waitlistID int, request model.WaitlistClaimRequest) (model.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimOffer", ctx, email, waitlistID, request)
+	ret0, _ := ret[0].(model.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimOffer indicates an expected call of ClaimOffer.
+func (mr *MockWaitlistExecutorMockRecorder) ClaimOffer(ctx, email, waitlistID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimOffer", reflect.TypeOf((*MockWaitlistExecutor)(nil).ClaimOffer), ctx, email, waitlistID, request)
+}
+
+// GetMyWaitlist mocks base method.
+func (m *MockWaitlistExecutor) GetMyWaitlist(ctx context.Context, email string) ([]model.WaitlistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMyWaitlist", ctx, email)
+	ret0, _ := ret[0].([]model.WaitlistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMyWaitlist indicates an expected call of GetMyWaitlist.
+func (mr *MockWaitlistExecutorMockRecorder) GetMyWaitlist(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMyWaitlist", reflect.TypeOf((*MockWaitlistExecutor)(nil).GetMyWaitlist), ctx, email)
+}
+
+// JoinWaitlist mocks base method.
+func (m *MockWaitlistExecutor) JoinWaitlist(ctx context.Context, email string, ticketID int, request model.WaitlistRequest) (model.WaitlistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JoinWaitlist", ctx, email, ticketID, request)
+	ret0, _ := ret[0].(model.WaitlistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JoinWaitlist indicates an expected call of JoinWaitlist.
+func (mr *MockWaitlistExecutorMockRecorder) JoinWaitlist(ctx, email, ticketID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JoinWaitlist", reflect.TypeOf((*MockWaitlistExecutor)(nil).JoinWaitlist), ctx, email, ticketID, request)
+}
+
+// LeaveWaitlist mocks base method.
+func (m *MockWaitlistExecutor) LeaveWaitlist(ctx context.Context, email string, waitlistID int) (model.WaitlistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeaveWaitlist", ctx, email, waitlistID)
+	ret0, _ := ret[0].(model.WaitlistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LeaveWaitlist indicates an expected call of LeaveWaitlist.
+func (mr *MockWaitlistExecutorMockRecorder) LeaveWaitlist(ctx, email, waitlistID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaveWaitlist", reflect.TypeOf((*MockWaitlistExecutor)(nil).LeaveWaitlist), ctx, email, waitlistID)
+}
+
+// ProcessWaitlists mocks base method.
+func (m *MockWaitlistExecutor) ProcessWaitlists(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessWaitlists", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessWaitlists indicates an expected call of ProcessWaitlists.
+func (mr *MockWaitlistExecutorMockRecorder) ProcessWaitlists(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessWaitlists", reflect.TypeOf((*MockWaitlistExecutor)(nil).ProcessWaitlists), ctx)
+}